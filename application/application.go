@@ -1,15 +1,21 @@
 package application
 
 import (
+	"crypto/rand"
 	"log"
 	"os"
+	"os/signal"
 	"path"
+	"syscall"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/metrics"
 	"github.com/cloudfoundry-incubator/notifications/postal"
+	"github.com/cloudfoundry-incubator/notifications/postal/v2"
 	"github.com/cloudfoundry-incubator/notifications/uaa"
+	"github.com/cloudfoundry-incubator/notifications/util"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	v2models "github.com/cloudfoundry-incubator/notifications/v2/models"
 	"github.com/cloudfoundry-incubator/notifications/web"
 	"github.com/pivotal-cf-experimental/warrant"
 	"github.com/pivotal-golang/lager"
@@ -54,14 +60,24 @@ func (app Application) Boot() {
 	app.migrator.Migrate()
 
 	app.StartQueueGauge()
-	app.StartWorkers(validator)
+	workers := app.StartWorkers(validator)
 	app.StartMessageGC()
+	bouncePoller := app.StartBouncePoller(session)
 	app.StartKeyRefresher(validator)
-	app.StartServer(session, validator)
+
+	server := web.NewServer()
+	shutdown := app.HandleShutdown(session, server, workers, bouncePoller)
+	app.StartServer(server, session, validator)
+	<-shutdown
 }
 
 func (app Application) ConfigureSMTP(logger lager.Logger) {
-	if app.env.TestMode {
+	if app.env.TestMode || app.env.MailTransport != MailTransportSMTP {
+		return
+	}
+
+	if len(app.env.SMTPRelays) > 0 {
+		app.checkSMTPRelays(logger)
 		return
 	}
 
@@ -79,6 +95,29 @@ func (app Application) ConfigureSMTP(logger lager.Logger) {
 	mailClient.Quit()
 }
 
+// checkSMTPRelays only fails startup when none of the relays can be
+// reached, since the transport fails over to whichever relays are up.
+func (app Application) checkSMTPRelays(logger lager.Logger) {
+	var err error
+	for i, mailClient := range app.mother.RelayClients() {
+		relay := app.env.SMTPRelays[i].Name()
+
+		err = mailClient.Connect(logger)
+		if err == nil {
+			err = mailClient.Hello()
+			mailClient.Quit()
+		}
+
+		if err == nil {
+			return
+		}
+
+		logger.Error("smtp-relay-unreachable", err, lager.Data{"relay": relay})
+	}
+
+	logger.Fatal("smtp-relays-unreachable", err)
+}
+
 func (app Application) StartQueueGauge() {
 	if app.env.VCAPApplication.InstanceIndex != 0 {
 		return
@@ -105,21 +144,22 @@ func (app Application) StartKeyRefresher(validator *uaa.TokenValidator) {
 	}()
 }
 
-func (app Application) StartWorkers(validator *uaa.TokenValidator) {
-	postal.Boot(app.mother, postal.Config{
-		UAAClientID:          app.env.UAAClientID,
-		UAAClientSecret:      app.env.UAAClientSecret,
-		UAATokenValidator:    validator,
-		UAAHost:              app.env.UAAHost,
-		VerifySSL:            app.env.VerifySSL,
-		InstanceIndex:        app.env.VCAPApplication.InstanceIndex,
-		WorkerCount:          WorkerCount,
-		EncryptionKey:        app.env.EncryptionKey,
-		DBLoggingEnabled:     app.env.DBLoggingEnabled,
-		Sender:               app.env.Sender,
-		Domain:               app.env.Domain,
-		QueueWaitMaxDuration: app.env.GobbleWaitMaxDuration,
-		CCHost:               app.env.CCHost,
+func (app Application) StartWorkers(validator *uaa.TokenValidator) postal.WorkerPool {
+	return postal.Boot(app.mother, postal.Config{
+		UAAClientID:       app.env.UAAClientID,
+		UAAClientSecret:   app.env.UAAClientSecret,
+		UAATokenValidator: validator,
+		UAAHost:           app.env.UAAHost,
+		VerifySSL:         app.env.VerifySSL,
+		InstanceIndex:     app.env.VCAPApplication.InstanceIndex,
+		WorkerCount:       WorkerCount,
+		EncryptionKey:     app.env.EncryptionKey,
+		DBLoggingEnabled:  app.env.DBLoggingEnabled,
+		Sender:            app.env.Sender,
+		Domain:            app.env.Domain,
+		PublicURL:         app.env.PublicURL,
+		CCHost:            app.env.CCHost,
+		RetryPolicies:     app.env.RetryPolicies,
 	})
 }
 
@@ -127,15 +167,37 @@ func (app Application) StartMessageGC() {
 	messageLifetime := 24 * time.Hour
 	db := app.mother.Database()
 	messagesRepo := app.mother.MessagesRepo()
+	attachmentsRepo := app.mother.AttachmentsRepo()
 	pollingInterval := 1 * time.Hour
 
 	logger := log.New(os.Stdout, "", 0)
-	messageGC := postal.NewMessageGC(messageLifetime, db, messagesRepo, pollingInterval, logger)
+	messageGC := postal.NewMessageGC(messageLifetime, db, messagesRepo, attachmentsRepo, pollingInterval, logger)
 	messageGC.Run()
 }
 
-func (app Application) StartServer(logger lager.Logger, validator *uaa.TokenValidator) {
-	web.NewServer().Run(app.mother, web.Config{
+// StartBouncePoller returns the poller it started, or nil when no bounce
+// maildir is configured.
+func (app Application) StartBouncePoller(logger lager.Logger) *postal.BouncePoller {
+	if app.env.BounceMaildir == "" {
+		return nil
+	}
+
+	clock := util.NewClock()
+	guidGenerator := util.NewIDGenerator(rand.Reader)
+	database := v2models.NewDatabase(app.mother.SQLDatabase(), v2models.Config{})
+	pollingInterval := time.Duration(app.env.BouncePollInterval) * time.Millisecond
+
+	processor := postal.NewBounceProcessor(app.mother.MessagesRepo(), v2models.NewSuppressionsRepository(guidGenerator.Generate, clock),
+		v2.NewV2MessageStatusUpdater(v2models.NewMessagesRepository(clock, guidGenerator.Generate)))
+
+	bouncePoller := postal.NewBouncePoller(app.env.BounceMaildir, processor, database, pollingInterval, logger)
+	bouncePoller.Run()
+
+	return &bouncePoller
+}
+
+func (app Application) StartServer(server *web.Server, logger lager.Logger, validator *uaa.TokenValidator) {
+	err := server.Run(app.mother, web.Config{
 		DBLoggingEnabled:     app.env.DBLoggingEnabled,
 		SkipVerifySSL:        !app.env.VerifySSL,
 		Port:                 app.env.Port,
@@ -143,6 +205,7 @@ func (app Application) StartServer(logger lager.Logger, validator *uaa.TokenVali
 		CORSOrigin:           app.env.CORSOrigin,
 		SQLDB:                app.mother.SQLDatabase(),
 		QueueWaitMaxDuration: app.env.GobbleWaitMaxDuration,
+		EncryptionKey:        app.env.EncryptionKey,
 
 		UAATokenValidator: validator,
 		UAAHost:           app.env.UAAHost,
@@ -150,7 +213,64 @@ func (app Application) StartServer(logger lager.Logger, validator *uaa.TokenVali
 		UAAClientSecret:   app.env.UAAClientSecret,
 		DefaultUAAScopes:  app.env.DefaultUAAScopes,
 		CCHost:            app.env.CCHost,
+		Sender:            app.env.Sender,
+		Domain:            app.env.Domain,
+
+		SenderAllowedDomains: app.env.SenderAllowedDomains,
 	})
+	if err != nil {
+		logger.Fatal("listen-and-serve-errored", err)
+	}
+}
+
+func (app Application) HandleShutdown(logger lager.Logger, server *web.Server, workers postal.WorkerPool, bouncePoller *postal.BouncePoller) <-chan struct{} {
+	timeout := time.Duration(app.env.ShutdownTimeout) * time.Millisecond
+	done := make(chan struct{})
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+
+	go func() {
+		sig := <-signals
+		logger.Info("shutdown-started", lager.Data{
+			"signal":  sig.String(),
+			"timeout": timeout.String(),
+		})
+
+		deadline := time.Now().Add(timeout)
+
+		serverStopped := make(chan bool)
+		go func() {
+			serverStopped <- server.Shutdown(timeout)
+		}()
+
+		pollerStopped := make(chan struct{})
+		go func() {
+			defer close(pollerStopped)
+			if bouncePoller != nil {
+				bouncePoller.Stop()
+			}
+		}()
+
+		if !workers.Shutdown(timeout) {
+			logger.Info("shutdown-released-unfinished-jobs")
+		}
+
+		select {
+		case <-pollerStopped:
+		case <-time.After(deadline.Sub(time.Now())):
+			logger.Info("shutdown-abandoned-bounce-poll")
+		}
+
+		if !<-serverStopped {
+			logger.Info("shutdown-abandoned-unfinished-requests")
+		}
+
+		logger.Info("shutdown-complete")
+		close(done)
+	}()
+
+	return done
 }
 
 // This is a hack to get the logs output to the loggregator before the process exits
@@ -1,12 +1,18 @@
 package application
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/url"
 	"os"
 	"path"
 	"strings"
+	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/ryanmoran/viron"
 )
 
@@ -14,31 +20,67 @@ const (
 	SMTPAuthNone    = "none"
 	SMTPAuthPlain   = "plain"
 	SMTPAuthCRAMMD5 = "cram-md5"
+	SMTPAuthLogin   = "login"
+	SMTPAuthXOAUTH2 = "xoauth2"
 )
 
-var SMTPAuthMechanisms = []string{SMTPAuthNone, SMTPAuthPlain, SMTPAuthCRAMMD5}
+var SMTPAuthMechanisms = []string{SMTPAuthNone, SMTPAuthPlain, SMTPAuthCRAMMD5, SMTPAuthLogin, SMTPAuthXOAUTH2}
+
+const (
+	QueueBackendMySQL  = "mysql"
+	QueueBackendMemory = "memory"
+)
+
+var QueueBackends = []string{QueueBackendMySQL, QueueBackendMemory}
+
+const (
+	MailTransportSMTP    = "smtp"
+	MailTransportFile    = "file"
+	MailTransportMaildir = "maildir"
+	MailTransportHTTP    = "http"
+)
+
+var MailTransports = []string{MailTransportSMTP, MailTransportFile, MailTransportMaildir, MailTransportHTTP}
 
 type Environment struct {
+	BounceMaildir         string `env:"BOUNCE_MAILDIR"`
+	BouncePollInterval    int    `env:"BOUNCE_POLL_INTERVAL"     env-default:"60000"`
 	CCHost                string `env:"CC_HOST"                  env-required:"true"`
 	CORSOrigin            string `env:"CORS_ORIGIN"              env-default:"*"`
 	DBLoggingEnabled      bool   `env:"DB_LOGGING_ENABLED"`
 	DBMaxOpenConns        int    `env:"DB_MAX_OPEN_CONNS"`
+	DKIMKeysJSON          string `env:"DKIM_KEYS"`
 	DatabaseURL           string `env:"DATABASE_URL"             env-required:"true"`
 	DefaultUAAScopesList  string `env:"DEFAULT_UAA_SCOPES"`
 	Domain                string `env:"DOMAIN"                   env-required:"true"`
 	EncryptionKey         []byte `env:"ENCRYPTION_KEY"           env-required:"true"`
 	GobbleWaitMaxDuration int    `env:"GOBBLE_WAIT_MAX_DURATION" env-default:"5000"`
+	MailFileDirectory     string `env:"MAIL_FILE_DIRECTORY"`
+	MailRelayToken        string `env:"MAIL_RELAY_TOKEN"`
+	MailRelayURL          string `env:"MAIL_RELAY_URL"`
+	MailTransport         string `env:"MAIL_TRANSPORT"           env-default:"smtp"`
 	Port                  int    `env:"PORT"                     env-default:"3000"`
+	PublicURL             string `env:"PUBLIC_URL"`
+	QueueBackend          string `env:"QUEUE_BACKEND"            env-default:"mysql"`
+	RetryPoliciesJSON     string `env:"RETRY_POLICIES"`
 	RootPath              string `env:"ROOT_PATH"`
-	SMTPAuthMechanism     string `env:"SMTP_AUTH_MECHANISM"      env-required:"true"`
+	SMTPAuthMechanism     string `env:"SMTP_AUTH_MECHANISM"`
 	SMTPCRAMMD5Secret     string `env:"SMTP_CRAMMD5_SECRET"`
-	SMTPHost              string `env:"SMTP_HOST"                env-required:"true"`
+	SMTPHost              string `env:"SMTP_HOST"`
+	SMTPImplicitTLS       bool   `env:"SMTP_IMPLICIT_TLS"        env-default:"false"`
 	SMTPLoggingEnabled    bool   `env:"SMTP_LOGGING_ENABLED"     env-default:"false"`
+	SMTPMaxConnMessages   int    `env:"SMTP_MAX_CONN_MESSAGES"   env-default:"100"`
 	SMTPPass              string `env:"SMTP_PASS"`
-	SMTPPort              string `env:"SMTP_PORT"                env-required:"true"`
+	SMTPPort              string `env:"SMTP_PORT"`
+	SMTPRelayCooldown     int    `env:"SMTP_RELAY_COOLDOWN"      env-default:"60000"`
+	SMTPRelayMaxFailures  int    `env:"SMTP_RELAY_MAX_FAILURES"  env-default:"3"`
+	SMTPRelaysJSON        string `env:"SMTP_RELAYS"`
 	SMTPTLS               bool   `env:"SMTP_TLS"                 env-default:"true"`
 	SMTPUser              string `env:"SMTP_USER"`
+	SMTPXOAuth2Token      string `env:"SMTP_XOAUTH2_TOKEN"`
 	Sender                string `env:"SENDER"                   env-required:"true"`
+	SenderDomainsList     string `env:"SENDER_ALLOWED_DOMAINS"`
+	ShutdownTimeout       int    `env:"SHUTDOWN_TIMEOUT"         env-default:"8000"`
 	TestMode              bool   `env:"TEST_MODE"                env-default:"false"`
 	UAAClientID           string `env:"UAA_CLIENT_ID"            env-required:"true"`
 	UAAClientSecret       string `env:"UAA_CLIENT_SECRET"        env-required:"true"`
@@ -46,6 +88,12 @@ type Environment struct {
 	UAAKeyRefreshInterval int    `env:"UAA_KEY_REFRESH_INTREVAL" env-default:"60000"`
 	VerifySSL             bool   `env:"VERIFY_SSL"               env-default:"true"`
 
+	SMTPXOAuth2TokenURL     string `env:"SMTP_XOAUTH2_TOKEN_URL"`
+	SMTPXOAuth2ClientID     string `env:"SMTP_XOAUTH2_CLIENT_ID"`
+	SMTPXOAuth2ClientSecret string `env:"SMTP_XOAUTH2_CLIENT_SECRET"`
+	SMTPXOAuth2RefreshToken string `env:"SMTP_XOAUTH2_REFRESH_TOKEN"`
+	SMTPXOAuth2Scope        string `env:"SMTP_XOAUTH2_SCOPE"`
+
 	VCAPApplication struct {
 		InstanceIndex int `json:"instance_index"`
 	} `env:"VCAP_APPLICATION" env-required:"true"`
@@ -53,6 +101,35 @@ type Environment struct {
 	ModelMigrationsPath  string
 	GobbleMigrationsPath string
 	DefaultUAAScopes     []string
+	RetryPolicies        common.RetryPolicies
+	DKIMSigners          mail.DKIMSigners
+	SMTPRelays           []SMTPRelay
+	SenderAllowedDomains []string
+}
+
+// SMTPRelay is one SMTP server from SMTP_RELAYS. Each relay carries its own
+// credentials and TLS settings.
+type SMTPRelay struct {
+	Host          string `json:"host"`
+	Port          string `json:"port"`
+	User          string `json:"user"`
+	Pass          string `json:"pass"`
+	AuthMechanism string `json:"auth_mechanism"`
+	CRAMMD5Secret string `json:"crammd5_secret"`
+	XOAuth2Token  string `json:"xoauth2_token"`
+	TLS           *bool  `json:"tls"`
+	ImplicitTLS   bool   `json:"implicit_tls"`
+	Weight        int    `json:"weight"`
+
+	XOAuth2TokenURL     string `json:"xoauth2_token_url"`
+	XOAuth2ClientID     string `json:"xoauth2_client_id"`
+	XOAuth2ClientSecret string `json:"xoauth2_client_secret"`
+	XOAuth2RefreshToken string `json:"xoauth2_refresh_token"`
+	XOAuth2Scope        string `json:"xoauth2_scope"`
+}
+
+func (r SMTPRelay) Name() string {
+	return net.JoinHostPort(r.Host, r.Port)
 }
 
 type EnvironmentError struct {
@@ -77,13 +154,34 @@ func NewEnvironment() (Environment, error) {
 
 	env.expandRoot()
 
-	err = env.validateSMTPAuthMechanism()
+	err = env.validateMailTransport()
+	if err != nil {
+		return env, EnvironmentError{err}
+	}
+
+	err = env.validateQueueBackend()
+	if err != nil {
+		return env, EnvironmentError{err}
+	}
+
+	err = env.validatePublicURL()
+	if err != nil {
+		return env, EnvironmentError{err}
+	}
+
+	err = env.parseRetryPolicies()
+	if err != nil {
+		return env, EnvironmentError{err}
+	}
+
+	err = env.parseDKIMKeys()
 	if err != nil {
 		return env, EnvironmentError{err}
 	}
 
 	env.inferMigrationsDirs()
 	env.parseDefaultUAAScopes()
+	env.parseSenderAllowedDomains()
 
 	return env, nil
 }
@@ -92,6 +190,15 @@ func (env *Environment) parseDefaultUAAScopes() {
 	env.DefaultUAAScopes = strings.Split(env.DefaultUAAScopesList, ",")
 }
 
+func (env *Environment) parseSenderAllowedDomains() {
+	for _, domain := range strings.Split(env.SenderDomainsList, ",") {
+		domain = strings.TrimSpace(domain)
+		if domain != "" {
+			env.SenderAllowedDomains = append(env.SenderAllowedDomains, domain)
+		}
+	}
+}
+
 func (env *Environment) expandRoot() {
 	env.RootPath = os.ExpandEnv(env.RootPath)
 }
@@ -118,6 +225,74 @@ func (env *Environment) parseDatabaseURL() error {
 	return nil
 }
 
+func (env *Environment) validateMailTransport() error {
+	switch env.MailTransport {
+	case MailTransportSMTP:
+		return env.validateSMTP()
+	case MailTransportFile, MailTransportMaildir:
+		if env.MailFileDirectory == "" {
+			return fmt.Errorf("MAIL_FILE_DIRECTORY is required when MAIL_TRANSPORT is %q", env.MailTransport)
+		}
+		return nil
+	case MailTransportHTTP:
+		if env.MailRelayURL == "" {
+			return fmt.Errorf("MAIL_RELAY_URL is required when MAIL_TRANSPORT is %q", env.MailTransport)
+		}
+
+		if _, err := url.ParseRequestURI(env.MailRelayURL); err != nil {
+			return fmt.Errorf("Could not parse MAIL_RELAY_URL %q, it is not a valid URL", env.MailRelayURL)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("Could not parse MAIL_TRANSPORT %q, it is not one of the allowed values: %+v", env.MailTransport, MailTransports)
+}
+
+func (env Environment) validatePublicURL() error {
+	if env.PublicURL == "" {
+		return nil
+	}
+
+	parsedURL, err := url.ParseRequestURI(env.PublicURL)
+	if err != nil || parsedURL.Host == "" {
+		return fmt.Errorf("Could not parse PUBLIC_URL %q, it is not a valid URL", env.PublicURL)
+	}
+
+	return nil
+}
+
+// validateSMTP enforces the SMTP settings that are only required when mail
+// is delivered over SMTP. When SMTP_RELAYS is set, it replaces the single
+// relay configured by SMTP_HOST and SMTP_PORT.
+func (env *Environment) validateSMTP() error {
+	if env.SMTPRelaysJSON != "" {
+		return env.parseSMTPRelays()
+	}
+
+	required := []struct {
+		name  string
+		value string
+	}{
+		{"SMTP_AUTH_MECHANISM", env.SMTPAuthMechanism},
+		{"SMTP_HOST", env.SMTPHost},
+		{"SMTP_PORT", env.SMTPPort},
+	}
+
+	for _, field := range required {
+		if field.value == "" {
+			return viron.NewRequiredFieldError(field.name)
+		}
+	}
+
+	if env.SMTPXOAuth2TokenURL != "" {
+		if _, err := url.ParseRequestURI(env.SMTPXOAuth2TokenURL); err != nil {
+			return fmt.Errorf("Could not parse SMTP_XOAUTH2_TOKEN_URL %q, it is not a valid URL", env.SMTPXOAuth2TokenURL)
+		}
+	}
+
+	return env.validateSMTPAuthMechanism()
+}
+
 func (env *Environment) validateSMTPAuthMechanism() error {
 	for _, mechanism := range SMTPAuthMechanisms {
 		if mechanism == env.SMTPAuthMechanism {
@@ -127,3 +302,183 @@ func (env *Environment) validateSMTPAuthMechanism() error {
 
 	return fmt.Errorf("Could not parse SMTP_AUTH_MECHANISM %q, it is not one of the allowed values: %+v", env.SMTPAuthMechanism, SMTPAuthMechanisms)
 }
+
+func (env *Environment) parseSMTPRelays() error {
+	err := json.Unmarshal([]byte(env.SMTPRelaysJSON), &env.SMTPRelays)
+	if err != nil {
+		return errors.New("Could not parse SMTP_RELAYS, it is not valid JSON")
+	}
+
+	if len(env.SMTPRelays) == 0 {
+		return errors.New("Could not parse SMTP_RELAYS, it does not list any relays")
+	}
+
+	for i, relay := range env.SMTPRelays {
+		if relay.Host == "" || relay.Port == "" {
+			return fmt.Errorf("Could not parse SMTP_RELAYS, relay %d is missing a host or port", i)
+		}
+
+		if relay.AuthMechanism == "" {
+			relay.AuthMechanism = SMTPAuthNone
+		}
+
+		if !contains(SMTPAuthMechanisms, relay.AuthMechanism) {
+			return fmt.Errorf("Could not parse SMTP_RELAYS, the auth_mechanism %q of relay %q is not one of the allowed values: %+v", relay.AuthMechanism, relay.Name(), SMTPAuthMechanisms)
+		}
+
+		if relay.XOAuth2TokenURL != "" {
+			if _, err := url.ParseRequestURI(relay.XOAuth2TokenURL); err != nil {
+				return fmt.Errorf("Could not parse SMTP_RELAYS, the xoauth2_token_url of relay %q is not a valid URL", relay.Name())
+			}
+		}
+
+		if relay.Weight < 0 {
+			return fmt.Errorf("Could not parse SMTP_RELAYS, the weight of relay %q must not be negative", relay.Name())
+		}
+
+		if relay.TLS == nil {
+			tls := true
+			relay.TLS = &tls
+		}
+
+		env.SMTPRelays[i] = relay
+	}
+
+	return nil
+}
+
+func (env *Environment) validateQueueBackend() error {
+	for _, backend := range QueueBackends {
+		if backend == env.QueueBackend {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Could not parse QUEUE_BACKEND %q, it is not one of the allowed values: %+v", env.QueueBackend, QueueBackends)
+}
+
+type retryPolicyConfig struct {
+	MaxAttempts *int                         `json:"max_attempts"`
+	BaseDelay   *int                         `json:"base_delay_ms"`
+	MaxDelay    *int                         `json:"max_delay_ms"`
+	Jitter      *float64                     `json:"jitter"`
+	Overrides   map[string]retryPolicyConfig `json:"overrides"`
+}
+
+func (config retryPolicyConfig) applyTo(policy common.RetryPolicy) (common.RetryPolicy, error) {
+	if config.MaxAttempts != nil {
+		if *config.MaxAttempts < 1 {
+			return policy, fmt.Errorf("Could not parse RETRY_POLICIES, max_attempts must be at least 1")
+		}
+		policy.MaxAttempts = *config.MaxAttempts
+	}
+
+	if config.BaseDelay != nil {
+		if *config.BaseDelay < 0 {
+			return policy, fmt.Errorf("Could not parse RETRY_POLICIES, base_delay_ms must not be negative")
+		}
+		policy.BaseDelay = time.Duration(*config.BaseDelay) * time.Millisecond
+	}
+
+	if config.MaxDelay != nil {
+		if *config.MaxDelay < 0 {
+			return policy, fmt.Errorf("Could not parse RETRY_POLICIES, max_delay_ms must not be negative")
+		}
+		policy.MaxDelay = time.Duration(*config.MaxDelay) * time.Millisecond
+	}
+
+	if config.Jitter != nil {
+		if *config.Jitter < 0 || *config.Jitter > 1 {
+			return policy, fmt.Errorf("Could not parse RETRY_POLICIES, jitter must be between 0 and 1")
+		}
+		policy.Jitter = *config.Jitter
+	}
+
+	return policy, nil
+}
+
+func (env *Environment) parseRetryPolicies() error {
+	env.RetryPolicies = common.RetryPolicies{}
+	if env.RetryPoliciesJSON == "" {
+		return nil
+	}
+
+	var configs map[string]retryPolicyConfig
+	err := json.Unmarshal([]byte(env.RetryPoliciesJSON), &configs)
+	if err != nil {
+		return fmt.Errorf("Could not parse RETRY_POLICIES %q, it is not valid JSON", env.RetryPoliciesJSON)
+	}
+
+	for jobType, config := range configs {
+		if !contains(common.JobTypes, jobType) {
+			return fmt.Errorf("Could not parse RETRY_POLICIES, %q is not one of the allowed job types: %+v", jobType, common.JobTypes)
+		}
+
+		policy, err := config.applyTo(common.DefaultRetryPolicy())
+		if err != nil {
+			return err
+		}
+
+		overrides := map[string]common.RetryPolicy{}
+		for errorClass, override := range config.Overrides {
+			if !contains(common.ErrorClasses, errorClass) {
+				return fmt.Errorf("Could not parse RETRY_POLICIES, %q is not one of the allowed error classes: %+v", errorClass, common.ErrorClasses)
+			}
+
+			overrides[errorClass], err = override.applyTo(policy)
+			if err != nil {
+				return err
+			}
+		}
+		policy.Overrides = overrides
+
+		env.RetryPolicies[jobType] = policy
+	}
+
+	return nil
+}
+
+type dkimKeyConfig struct {
+	Selector   string `json:"selector"`
+	PrivateKey string `json:"private_key"`
+}
+
+// parseDKIMKeys builds a signer for each sending domain. The private keys
+// are never included in the returned errors.
+func (env *Environment) parseDKIMKeys() error {
+	env.DKIMSigners = mail.DKIMSigners{}
+	if env.DKIMKeysJSON == "" {
+		return nil
+	}
+
+	var configs map[string]dkimKeyConfig
+	err := json.Unmarshal([]byte(env.DKIMKeysJSON), &configs)
+	if err != nil {
+		return errors.New("Could not parse DKIM_KEYS, it is not valid JSON")
+	}
+
+	for domain, config := range configs {
+		if config.Selector == "" {
+			return fmt.Errorf("Could not parse DKIM_KEYS, the key for %q is missing a selector", domain)
+		}
+
+		signer, err := mail.NewDKIMSigner(domain, config.Selector, []byte(config.PrivateKey))
+		if err != nil {
+			return fmt.Errorf("Could not parse DKIM_KEYS, the private key for %q is invalid: %s", domain, err)
+		}
+
+		env.DKIMSigners[signer.Domain] = signer
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,10 +1,17 @@
 package application_test
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"os"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/application"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/ryanmoran/viron"
 
 	. "github.com/onsi/ginkgo"
@@ -14,25 +21,48 @@ import (
 var _ = Describe("Environment", func() {
 	var variables = map[string]string{}
 	var envVars = []string{
+		"BOUNCE_MAILDIR",
+		"BOUNCE_POLL_INTERVAL",
 		"CC_HOST",
 		"CORS_ORIGIN",
 		"DATABASE_URL",
 		"DB_LOGGING_ENABLED",
 		"DB_MAX_OPEN_CONNS",
+		"DKIM_KEYS",
 		"DEFAULT_UAA_SCOPES",
 		"DOMAIN",
 		"ENCRYPTION_KEY",
 		"GOBBLE_WAIT_MAX_DURATION",
+		"MAIL_FILE_DIRECTORY",
+		"MAIL_RELAY_TOKEN",
+		"MAIL_RELAY_URL",
+		"MAIL_TRANSPORT",
 		"PORT",
+		"PUBLIC_URL",
+		"QUEUE_BACKEND",
+		"RETRY_POLICIES",
 		"ROOT_PATH",
 		"SENDER",
+		"SENDER_ALLOWED_DOMAINS",
+		"SHUTDOWN_TIMEOUT",
 		"SMTP_AUTH_MECHANISM",
 		"SMTP_CRAMMD5_SECRET",
 		"SMTP_HOST",
+		"SMTP_IMPLICIT_TLS",
 		"SMTP_LOGGING_ENABLED",
+		"SMTP_MAX_CONN_MESSAGES",
 		"SMTP_PASS",
 		"SMTP_PORT",
+		"SMTP_RELAYS",
+		"SMTP_RELAY_COOLDOWN",
+		"SMTP_RELAY_MAX_FAILURES",
 		"SMTP_USER",
+		"SMTP_XOAUTH2_CLIENT_ID",
+		"SMTP_XOAUTH2_CLIENT_SECRET",
+		"SMTP_XOAUTH2_REFRESH_TOKEN",
+		"SMTP_XOAUTH2_SCOPE",
+		"SMTP_XOAUTH2_TOKEN",
+		"SMTP_XOAUTH2_TOKEN_URL",
 		"TEST_MODE",
 		"UAA_CLIENT_ID",
 		"UAA_CLIENT_SECRET",
@@ -220,7 +250,7 @@ var _ = Describe("Environment", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
-		It("it errors if SMTP_AUTH_MECHANISM is not one of the three supported types", func() {
+		It("it errors if SMTP_AUTH_MECHANISM is not one of the supported types", func() {
 			os.Setenv("SMTP_AUTH_MECHANISM", "cram-md5")
 			_, err := application.NewEnvironment()
 			Expect(err).NotTo(HaveOccurred())
@@ -233,9 +263,58 @@ var _ = Describe("Environment", func() {
 			_, err = application.NewEnvironment()
 			Expect(err).NotTo(HaveOccurred())
 
+			os.Setenv("SMTP_AUTH_MECHANISM", "login")
+			_, err = application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+
+			os.Setenv("SMTP_AUTH_MECHANISM", "xoauth2")
+			_, err = application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+
 			os.Setenv("SMTP_AUTH_MECHANISM", "banana")
 			_, err = application.NewEnvironment()
-			Expect(err).To(MatchError(application.EnvironmentError{errors.New("Could not parse SMTP_AUTH_MECHANISM \"banana\", it is not one of the allowed values: [none plain cram-md5]")}))
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New("Could not parse SMTP_AUTH_MECHANISM \"banana\", it is not one of the allowed values: [none plain cram-md5 login xoauth2]")}))
+		})
+
+		It("loads the SMTP_IMPLICIT_TLS and SMTP_XOAUTH2_TOKEN variables", func() {
+			os.Setenv("SMTP_IMPLICIT_TLS", "true")
+			os.Setenv("SMTP_XOAUTH2_TOKEN", "some-access-token")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.SMTPImplicitTLS).To(BeTrue())
+			Expect(env.SMTPXOAuth2Token).To(Equal("some-access-token"))
+		})
+
+		It("loads the XOAUTH2 token refresh settings", func() {
+			os.Setenv("SMTP_XOAUTH2_TOKEN_URL", "https://login.example.com/oauth2/token")
+			os.Setenv("SMTP_XOAUTH2_CLIENT_ID", "some-client-id")
+			os.Setenv("SMTP_XOAUTH2_CLIENT_SECRET", "some-client-secret")
+			os.Setenv("SMTP_XOAUTH2_REFRESH_TOKEN", "some-refresh-token")
+			os.Setenv("SMTP_XOAUTH2_SCOPE", "some-scope")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.SMTPXOAuth2TokenURL).To(Equal("https://login.example.com/oauth2/token"))
+			Expect(env.SMTPXOAuth2ClientID).To(Equal("some-client-id"))
+			Expect(env.SMTPXOAuth2ClientSecret).To(Equal("some-client-secret"))
+			Expect(env.SMTPXOAuth2RefreshToken).To(Equal("some-refresh-token"))
+			Expect(env.SMTPXOAuth2Scope).To(Equal("some-scope"))
+		})
+
+		It("errors when SMTP_XOAUTH2_TOKEN_URL is not a URL", func() {
+			os.Setenv("SMTP_XOAUTH2_TOKEN_URL", "banana")
+
+			_, err := application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New(`Could not parse SMTP_XOAUTH2_TOKEN_URL "banana", it is not a valid URL`)}))
+		})
+
+		It("defaults SMTP_IMPLICIT_TLS to false", func() {
+			os.Setenv("SMTP_IMPLICIT_TLS", "")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.SMTPImplicitTLS).To(BeFalse())
 		})
 
 		It("errors when the values are missing", func() {
@@ -265,6 +344,106 @@ var _ = Describe("Environment", func() {
 		})
 	})
 
+	Describe("SMTP relays", func() {
+		BeforeEach(func() {
+			os.Setenv("SMTP_RELAYS", "")
+			os.Setenv("SMTP_RELAY_COOLDOWN", "")
+			os.Setenv("SMTP_RELAY_MAX_FAILURES", "")
+		})
+
+		It("parses the relays from SMTP_RELAYS", func() {
+			os.Setenv("SMTP_RELAYS", `[
+				{"host": "smtp1.example.com", "port": "587", "user": "user", "pass": "pass", "auth_mechanism": "plain", "weight": 2},
+				{"host": "smtp2.example.com", "port": "465", "auth_mechanism": "xoauth2", "xoauth2_token": "token", "implicit_tls": true, "tls": false,
+				 "xoauth2_token_url": "https://login.example.com/oauth2/token", "xoauth2_client_id": "client", "xoauth2_client_secret": "secret",
+				 "xoauth2_refresh_token": "refresh", "xoauth2_scope": "scope"}
+			]`)
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+
+			tlsEnabled := true
+			tlsDisabled := false
+			Expect(env.SMTPRelays).To(Equal([]application.SMTPRelay{
+				{
+					Host:          "smtp1.example.com",
+					Port:          "587",
+					User:          "user",
+					Pass:          "pass",
+					AuthMechanism: "plain",
+					TLS:           &tlsEnabled,
+					Weight:        2,
+				},
+				{
+					Host:          "smtp2.example.com",
+					Port:          "465",
+					AuthMechanism: "xoauth2",
+					XOAuth2Token:  "token",
+					TLS:           &tlsDisabled,
+					ImplicitTLS:   true,
+
+					XOAuth2TokenURL:     "https://login.example.com/oauth2/token",
+					XOAuth2ClientID:     "client",
+					XOAuth2ClientSecret: "secret",
+					XOAuth2RefreshToken: "refresh",
+					XOAuth2Scope:        "scope",
+				},
+			}))
+			Expect(env.SMTPRelays[0].Name()).To(Equal("smtp1.example.com:587"))
+		})
+
+		It("does not require SMTP_HOST, SMTP_PORT or SMTP_AUTH_MECHANISM", func() {
+			os.Setenv("SMTP_HOST", "")
+			os.Setenv("SMTP_PORT", "")
+			os.Setenv("SMTP_AUTH_MECHANISM", "")
+			os.Setenv("SMTP_RELAYS", `[{"host": "smtp1.example.com", "port": "25"}]`)
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.SMTPRelays[0].AuthMechanism).To(Equal("none"))
+		})
+
+		It("loads the failover settings", func() {
+			os.Setenv("SMTP_RELAY_COOLDOWN", "5000")
+			os.Setenv("SMTP_RELAY_MAX_FAILURES", "5")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.SMTPRelayCooldown).To(Equal(5000))
+			Expect(env.SMTPRelayMaxFailures).To(Equal(5))
+		})
+
+		It("defaults the failover settings", func() {
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.SMTPRelayCooldown).To(Equal(60000))
+			Expect(env.SMTPRelayMaxFailures).To(Equal(3))
+			Expect(env.SMTPRelays).To(BeEmpty())
+		})
+
+		It("errors when the relays are invalid", func() {
+			os.Setenv("SMTP_RELAYS", "banana")
+			_, err := application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New("Could not parse SMTP_RELAYS, it is not valid JSON")}))
+
+			os.Setenv("SMTP_RELAYS", "[]")
+			_, err = application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New("Could not parse SMTP_RELAYS, it does not list any relays")}))
+
+			os.Setenv("SMTP_RELAYS", `[{"host": "smtp1.example.com"}]`)
+			_, err = application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New("Could not parse SMTP_RELAYS, relay 0 is missing a host or port")}))
+
+			os.Setenv("SMTP_RELAYS", `[{"host": "smtp1.example.com", "port": "25", "auth_mechanism": "banana"}]`)
+			_, err = application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New("Could not parse SMTP_RELAYS, the auth_mechanism \"banana\" of relay \"smtp1.example.com:25\" is not one of the allowed values: [none plain cram-md5 login xoauth2]")}))
+
+			os.Setenv("SMTP_RELAYS", `[{"host": "smtp1.example.com", "port": "25", "weight": -1}]`)
+			_, err = application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New("Could not parse SMTP_RELAYS, the weight of relay \"smtp1.example.com:25\" must not be negative")}))
+		})
+	})
+
 	Describe("SMTP logging", func() {
 		It("loads the SMTP_LOGGING_ENABLED variable when it is present", func() {
 			os.Setenv("SMTP_LOGGING_ENABLED", "true")
@@ -475,6 +654,331 @@ var _ = Describe("Environment", func() {
 		})
 	})
 
+	Describe("Bounce maildir", func() {
+		It("sets the value if present", func() {
+			os.Setenv("BOUNCE_MAILDIR", "/var/vcap/store/bounces")
+			os.Setenv("BOUNCE_POLL_INTERVAL", "15000")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.BounceMaildir).To(Equal("/var/vcap/store/bounces"))
+			Expect(env.BouncePollInterval).To(Equal(15000))
+		})
+
+		It("polls every minute by default", func() {
+			os.Setenv("BOUNCE_POLL_INTERVAL", "")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.BouncePollInterval).To(Equal(60000))
+		})
+	})
+
+	Describe("Mail transport", func() {
+		It("defaults to smtp", func() {
+			os.Setenv("MAIL_TRANSPORT", "")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.MailTransport).To(Equal(application.MailTransportSMTP))
+		})
+
+		It("errors if it is not one of the supported transports", func() {
+			os.Setenv("MAIL_TRANSPORT", "banana")
+
+			_, err := application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New("Could not parse MAIL_TRANSPORT \"banana\", it is not one of the allowed values: [smtp file maildir http]")}))
+		})
+
+		Context("when writing mail to files", func() {
+			It("loads the directory", func() {
+				os.Setenv("MAIL_TRANSPORT", "maildir")
+				os.Setenv("MAIL_FILE_DIRECTORY", "/var/mail/notifications")
+
+				env, err := application.NewEnvironment()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(env.MailTransport).To(Equal(application.MailTransportMaildir))
+				Expect(env.MailFileDirectory).To(Equal("/var/mail/notifications"))
+			})
+
+			It("errors if the directory is missing", func() {
+				os.Setenv("MAIL_TRANSPORT", "file")
+				os.Setenv("MAIL_FILE_DIRECTORY", "")
+
+				_, err := application.NewEnvironment()
+				Expect(err).To(MatchError(application.EnvironmentError{errors.New("MAIL_FILE_DIRECTORY is required when MAIL_TRANSPORT is \"file\"")}))
+			})
+		})
+
+		Context("when relaying mail over HTTP", func() {
+			BeforeEach(func() {
+				os.Setenv("MAIL_TRANSPORT", "http")
+				os.Setenv("MAIL_RELAY_URL", "https://relay.example.com/messages")
+				os.Setenv("MAIL_RELAY_TOKEN", "some-token")
+			})
+
+			It("loads the relay configuration", func() {
+				env, err := application.NewEnvironment()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(env.MailRelayURL).To(Equal("https://relay.example.com/messages"))
+				Expect(env.MailRelayToken).To(Equal("some-token"))
+			})
+
+			It("does not require the SMTP configuration", func() {
+				os.Setenv("SMTP_HOST", "")
+				os.Setenv("SMTP_PORT", "")
+				os.Setenv("SMTP_AUTH_MECHANISM", "")
+
+				_, err := application.NewEnvironment()
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("errors if the relay url is missing", func() {
+				os.Setenv("MAIL_RELAY_URL", "")
+
+				_, err := application.NewEnvironment()
+				Expect(err).To(MatchError(application.EnvironmentError{errors.New("MAIL_RELAY_URL is required when MAIL_TRANSPORT is \"http\"")}))
+			})
+
+			It("errors if the relay url is not valid", func() {
+				os.Setenv("MAIL_RELAY_URL", "banana")
+
+				_, err := application.NewEnvironment()
+				Expect(err).To(MatchError(application.EnvironmentError{errors.New("Could not parse MAIL_RELAY_URL \"banana\", it is not a valid URL")}))
+			})
+		})
+	})
+
+	Describe("Queue backend", func() {
+		It("sets the value if present", func() {
+			os.Setenv("QUEUE_BACKEND", "memory")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.QueueBackend).To(Equal(application.QueueBackendMemory))
+		})
+
+		It("defaults to mysql", func() {
+			os.Setenv("QUEUE_BACKEND", "")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.QueueBackend).To(Equal(application.QueueBackendMySQL))
+		})
+
+		It("errors if it is not one of the supported backends", func() {
+			os.Setenv("QUEUE_BACKEND", "banana")
+
+			_, err := application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New("Could not parse QUEUE_BACKEND \"banana\", it is not one of the allowed values: [mysql memory]")}))
+		})
+	})
+
+	Describe("Public URL", func() {
+		It("sets the value if present", func() {
+			os.Setenv("PUBLIC_URL", "https://notifications.example.com")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.PublicURL).To(Equal("https://notifications.example.com"))
+		})
+
+		It("is optional", func() {
+			os.Setenv("PUBLIC_URL", "")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.PublicURL).To(BeEmpty())
+		})
+
+		It("errors if it is not a valid URL", func() {
+			os.Setenv("PUBLIC_URL", "banana")
+
+			_, err := application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New("Could not parse PUBLIC_URL \"banana\", it is not a valid URL")}))
+		})
+	})
+
+	Describe("DKIM keys", func() {
+		var privateKey string
+
+		BeforeEach(func() {
+			key, err := rsa.GenerateKey(rand.Reader, 1024)
+			Expect(err).NotTo(HaveOccurred())
+
+			privateKey = string(pem.EncodeToMemory(&pem.Block{
+				Type:  "RSA PRIVATE KEY",
+				Bytes: x509.MarshalPKCS1PrivateKey(key),
+			}))
+		})
+
+		It("builds a signer for each sending domain", func() {
+			keys, err := json.Marshal(map[string]interface{}{
+				"Example.com": map[string]string{
+					"selector":    "notifications",
+					"private_key": privateKey,
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			os.Setenv("DKIM_KEYS", string(keys))
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.DKIMSigners).To(HaveLen(1))
+			Expect(env.DKIMSigners["example.com"].Domain).To(Equal("example.com"))
+			Expect(env.DKIMSigners["example.com"].Selector).To(Equal("notifications"))
+		})
+
+		It("does not sign when no keys are configured", func() {
+			os.Setenv("DKIM_KEYS", "")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.DKIMSigners).To(BeEmpty())
+		})
+
+		It("errors if the value is not valid JSON", func() {
+			os.Setenv("DKIM_KEYS", "banana")
+
+			_, err := application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New("Could not parse DKIM_KEYS, it is not valid JSON")}))
+		})
+
+		It("errors if a selector is missing", func() {
+			os.Setenv("DKIM_KEYS", `{"example.com": {"private_key": "some-key"}}`)
+
+			_, err := application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New("Could not parse DKIM_KEYS, the key for \"example.com\" is missing a selector")}))
+		})
+
+		It("errors if a private key is invalid", func() {
+			os.Setenv("DKIM_KEYS", `{"example.com": {"selector": "notifications", "private_key": "some-key"}}`)
+
+			_, err := application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New("Could not parse DKIM_KEYS, the private key for \"example.com\" is invalid: private key is not PEM encoded")}))
+		})
+	})
+
+	Describe("Retry policies", func() {
+		It("parses a policy for each job type", func() {
+			os.Setenv("RETRY_POLICIES", `{
+				"campaign": {
+					"max_attempts": 5,
+					"base_delay_ms": 30000,
+					"max_delay_ms": 600000,
+					"jitter": 0.1,
+					"overrides": {
+						"cc-5xx": {"max_attempts": 20}
+					}
+				},
+				"v2": {
+					"overrides": {
+						"smtp-5xx": {"max_attempts": 1}
+					}
+				}
+			}`)
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+
+			campaignPolicy := common.RetryPolicy{
+				MaxAttempts: 5,
+				BaseDelay:   30 * time.Second,
+				MaxDelay:    10 * time.Minute,
+				Jitter:      0.1,
+			}
+			overridePolicy := campaignPolicy
+			overridePolicy.MaxAttempts = 20
+			campaignPolicy.Overrides = map[string]common.RetryPolicy{
+				common.ErrorClassCC5xx: overridePolicy,
+			}
+
+			v2Policy := common.DefaultRetryPolicy()
+			v2OverridePolicy := v2Policy
+			v2OverridePolicy.MaxAttempts = 1
+			v2Policy.Overrides = map[string]common.RetryPolicy{
+				common.ErrorClassSMTP5xx: v2OverridePolicy,
+			}
+
+			Expect(env.RetryPolicies).To(Equal(common.RetryPolicies{
+				common.JobTypeCampaign: campaignPolicy,
+				common.JobTypeV2:       v2Policy,
+			}))
+		})
+
+		It("defaults to no policies", func() {
+			os.Setenv("RETRY_POLICIES", "")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.RetryPolicies).To(BeEmpty())
+		})
+
+		It("errors if the value is not valid JSON", func() {
+			os.Setenv("RETRY_POLICIES", "banana")
+
+			_, err := application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New("Could not parse RETRY_POLICIES \"banana\", it is not valid JSON")}))
+		})
+
+		It("errors if the job type is not known", func() {
+			os.Setenv("RETRY_POLICIES", `{"banana": {}}`)
+
+			_, err := application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New("Could not parse RETRY_POLICIES, \"banana\" is not one of the allowed job types: [v1 v2 campaign]")}))
+		})
+
+		It("errors if the error class is not known", func() {
+			os.Setenv("RETRY_POLICIES", `{"v1": {"overrides": {"banana": {}}}}`)
+
+			_, err := application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New("Could not parse RETRY_POLICIES, \"banana\" is not one of the allowed error classes: [network smtp-4xx smtp-5xx cc-4xx cc-5xx uaa-4xx uaa-5xx]")}))
+		})
+
+		It("errors if the jitter is out of range", func() {
+			os.Setenv("RETRY_POLICIES", `{"v1": {"jitter": 1.5}}`)
+
+			_, err := application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New("Could not parse RETRY_POLICIES, jitter must be between 0 and 1")}))
+		})
+	})
+
+	Describe("Shutdown timeout", func() {
+		It("sets the value if present", func() {
+			os.Setenv("SHUTDOWN_TIMEOUT", "2500")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.ShutdownTimeout).To(Equal(2500))
+		})
+
+		It("defaults to 8000", func() {
+			os.Setenv("SHUTDOWN_TIMEOUT", "")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.ShutdownTimeout).To(Equal(8000))
+		})
+	})
+
+	Describe("SMTP max connection messages", func() {
+		It("sets the value if present", func() {
+			os.Setenv("SMTP_MAX_CONN_MESSAGES", "25")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.SMTPMaxConnMessages).To(Equal(25))
+		})
+
+		It("defaults to 100", func() {
+			os.Setenv("SMTP_MAX_CONN_MESSAGES", "")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.SMTPMaxConnMessages).To(Equal(100))
+		})
+	})
+
 	Describe("Default UAA scopes", func() {
 		It("sets the value if present", func() {
 			os.Setenv("DEFAULT_UAA_SCOPES", "my-scope,banana,foo,bar")
@@ -490,6 +994,27 @@ var _ = Describe("Environment", func() {
 		})
 	})
 
+	Describe("Sender allowed domains", func() {
+		It("sets the value if present", func() {
+			os.Setenv("SENDER_ALLOWED_DOMAINS", "example.com, mail.example.com,")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.SenderAllowedDomains).To(Equal([]string{
+				"example.com",
+				"mail.example.com",
+			}))
+		})
+
+		It("allows any domain when it is not set", func() {
+			os.Setenv("SENDER_ALLOWED_DOMAINS", "")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.SenderAllowedDomains).To(BeEmpty())
+		})
+	})
+
 	Describe("Domain", func() {
 		It("sets the Domain", func() {
 			os.Setenv("DOMAIN", "example.com")
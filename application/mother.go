@@ -12,15 +12,19 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/db"
 	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/metrics"
 	"github.com/cloudfoundry-incubator/notifications/util"
 	v1models "github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/pivotal-golang/lager"
 )
 
 type Mother struct {
-	sqlDB *sql.DB
-	mutex sync.Mutex
-	env   Environment
+	sqlDB       *sql.DB
+	memoryQueue *gobble.MemoryQueue
+	relayHealth *mail.RelayHealth
+	tokens      map[string]mail.TokenSource
+	mutex       sync.Mutex
+	env         Environment
 }
 
 func NewMother(env Environment) *Mother {
@@ -34,21 +38,63 @@ func (m *Mother) GobbleDatabase() gobble.DatabaseInterface {
 }
 
 func (m *Mother) Queue() gobble.QueueInterface {
-	return gobble.NewQueue(m.GobbleDatabase(), util.NewClock(), gobble.Config{
+	config := gobble.Config{
 		WaitMaxDuration: time.Duration(m.env.GobbleWaitMaxDuration) * time.Millisecond,
-	})
+	}
+
+	if m.env.QueueBackend == QueueBackendMemory {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+
+		if m.memoryQueue == nil {
+			m.memoryQueue = gobble.NewMemoryQueue(gobble.NewDatabase(m.SQLDatabase()).Connection, util.NewClock(), config)
+		}
+
+		return m.memoryQueue
+	}
+
+	return gobble.NewQueue(m.GobbleDatabase(), util.NewClock(), config)
 }
 
-func (m *Mother) MailClient() *mail.Client {
-	var authMechanism mail.AuthMechanism
-	switch m.env.SMTPAuthMechanism {
-	case SMTPAuthNone:
-		authMechanism = mail.AuthNone
-	case SMTPAuthPlain:
-		authMechanism = mail.AuthPlain
-	case SMTPAuthCRAMMD5:
-		authMechanism = mail.AuthCRAMMD5
+func (m *Mother) MailTransport() mail.Transport {
+	transport := m.mailTransport()
+	if len(m.env.DKIMSigners) > 0 {
+		return mail.NewSigningTransport(transport, m.env.DKIMSigners)
+	}
+
+	return transport
+}
+
+func (m *Mother) mailTransport() mail.Transport {
+	switch m.env.MailTransport {
+	case MailTransportFile:
+		return mail.NewFileTransport(m.env.MailFileDirectory)
+	case MailTransportMaildir:
+		return mail.NewMaildirTransport(m.env.MailFileDirectory)
+	case MailTransportHTTP:
+		return mail.NewHTTPTransport(mail.HTTPTransportConfig{
+			URL:           m.env.MailRelayURL,
+			Token:         m.env.MailRelayToken,
+			SkipVerifySSL: !m.env.VerifySSL,
+		})
+	default:
+		if len(m.env.SMTPRelays) > 0 {
+			return m.RelayTransport()
+		}
+
+		return m.MailClient()
 	}
+}
+
+func (m *Mother) MailClient() *mail.Client {
+	tokens := m.xoauth2TokenSource("", m.env.SMTPXOAuth2Token, mail.OAuth2Config{
+		TokenURL:      m.env.SMTPXOAuth2TokenURL,
+		ClientID:      m.env.SMTPXOAuth2ClientID,
+		ClientSecret:  m.env.SMTPXOAuth2ClientSecret,
+		RefreshToken:  m.env.SMTPXOAuth2RefreshToken,
+		Scope:         m.env.SMTPXOAuth2Scope,
+		SkipVerifySSL: !m.env.VerifySSL,
+	})
 
 	return mail.NewClient(mail.Config{
 		User:           m.env.SMTPUser,
@@ -56,14 +102,122 @@ func (m *Mother) MailClient() *mail.Client {
 		Host:           m.env.SMTPHost,
 		Port:           m.env.SMTPPort,
 		Secret:         m.env.SMTPCRAMMD5Secret,
+		TokenSource:    tokens,
+		EnvelopeFrom:   m.env.Sender,
 		TestMode:       m.env.TestMode,
 		SkipVerifySSL:  !m.env.VerifySSL,
 		DisableTLS:     !m.env.SMTPTLS,
+		ImplicitTLS:    m.env.SMTPImplicitTLS,
 		LoggingEnabled: m.env.SMTPLoggingEnabled,
-		AuthMechanism:  authMechanism,
+		AuthMechanism:  smtpAuthMechanism(m.env.SMTPAuthMechanism),
+
+		MaxMessagesPerConnection: m.env.SMTPMaxConnMessages,
 	})
 }
 
+// RelayClients builds a client for each relay listed in SMTP_RELAYS, in
+// the configured order.
+func (m *Mother) RelayClients() []*mail.Client {
+	var clients []*mail.Client
+	for _, relay := range m.env.SMTPRelays {
+		tokens := m.xoauth2TokenSource(relay.Name(), relay.XOAuth2Token, mail.OAuth2Config{
+			TokenURL:      relay.XOAuth2TokenURL,
+			ClientID:      relay.XOAuth2ClientID,
+			ClientSecret:  relay.XOAuth2ClientSecret,
+			RefreshToken:  relay.XOAuth2RefreshToken,
+			Scope:         relay.XOAuth2Scope,
+			SkipVerifySSL: !m.env.VerifySSL,
+		})
+
+		clients = append(clients, mail.NewClient(mail.Config{
+			User:           relay.User,
+			Pass:           relay.Pass,
+			Host:           relay.Host,
+			Port:           relay.Port,
+			Secret:         relay.CRAMMD5Secret,
+			TokenSource:    tokens,
+			EnvelopeFrom:   m.env.Sender,
+			TestMode:       m.env.TestMode,
+			SkipVerifySSL:  !m.env.VerifySSL,
+			DisableTLS:     !*relay.TLS,
+			ImplicitTLS:    relay.ImplicitTLS,
+			LoggingEnabled: m.env.SMTPLoggingEnabled,
+			AuthMechanism:  smtpAuthMechanism(relay.AuthMechanism),
+
+			MaxMessagesPerConnection: m.env.SMTPMaxConnMessages,
+		}))
+	}
+
+	return clients
+}
+
+func (m *Mother) RelayTransport() *mail.RelayTransport {
+	clients := m.RelayClients()
+
+	var relays []mail.Relay
+	for i, relay := range m.env.SMTPRelays {
+		relays = append(relays, mail.Relay{
+			Name:      relay.Name(),
+			Weight:    relay.Weight,
+			Transport: clients[i],
+		})
+	}
+
+	return mail.NewRelayTransport(relays, m.RelayHealth(), metrics.DefaultLogger)
+}
+
+// RelayHealth is shared by every relay transport so that all workers stop
+// using a relay once it has been cooled down.
+func (m *Mother) RelayHealth() *mail.RelayHealth {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.relayHealth == nil {
+		m.relayHealth = mail.NewRelayHealth(util.NewClock(), m.env.SMTPRelayMaxFailures,
+			time.Duration(m.env.SMTPRelayCooldown)*time.Millisecond)
+	}
+
+	return m.relayHealth
+}
+
+// xoauth2TokenSource refreshes access tokens from the token URL when one is
+// configured, and otherwise uses the static token. Sources are shared by
+// every client for the same server, so that all workers reuse one access
+// token and any refresh token the provider rotates.
+func (m *Mother) xoauth2TokenSource(server, token string, config mail.OAuth2Config) mail.TokenSource {
+	if config.TokenURL == "" {
+		return mail.StaticToken(token)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.tokens == nil {
+		m.tokens = map[string]mail.TokenSource{}
+	}
+
+	if _, ok := m.tokens[server]; !ok {
+		m.tokens[server] = mail.NewOAuth2TokenSource(config, util.NewClock())
+	}
+
+	return m.tokens[server]
+}
+
+func smtpAuthMechanism(mechanism string) mail.AuthMechanism {
+	switch mechanism {
+	case SMTPAuthPlain:
+		return mail.AuthPlain
+	case SMTPAuthCRAMMD5:
+		return mail.AuthCRAMMD5
+	case SMTPAuthLogin:
+		return mail.AuthLogin
+	case SMTPAuthXOAUTH2:
+		return mail.AuthXOAUTH2
+	default:
+		return mail.AuthNone
+	}
+}
+
 func (m *Mother) Logger() lager.Logger {
 	logger := lager.NewLogger("notifications")
 	logger.RegisterSink(lager.NewWriterSink(os.Stdout, lager.DEBUG))
@@ -109,3 +263,7 @@ func (m *Mother) Database() db.DatabaseInterface {
 func (m *Mother) MessagesRepo() v1models.MessagesRepo {
 	return v1models.NewMessagesRepo(util.NewIDGenerator(rand.Reader).Generate)
 }
+
+func (m *Mother) AttachmentsRepo() v1models.AttachmentsRepo {
+	return v1models.NewAttachmentsRepo(util.NewIDGenerator(rand.Reader).Generate)
+}
@@ -0,0 +1,16 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS `attachments` (
+      `id` varchar(36) NOT NULL,
+      `campaign_id` varchar(36) NOT NULL DEFAULT '',
+      `filename` varchar(255) NOT NULL,
+      `content_type` varchar(255) NOT NULL DEFAULT '',
+      `content` longblob NOT NULL,
+      `created_at` datetime NOT NULL,
+      PRIMARY KEY (`id`),
+      KEY `campaign_id` (`campaign_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE attachments;
@@ -0,0 +1,11 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `senders` ADD `from_address` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `senders` ADD `from_name` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `senders` ADD `reply_to` varchar(255) NOT NULL DEFAULT '';
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `senders` DROP COLUMN `from_address`;
+ALTER TABLE `senders` DROP COLUMN `from_name`;
+ALTER TABLE `senders` DROP COLUMN `reply_to`;
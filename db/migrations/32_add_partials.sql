@@ -0,0 +1,14 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS `partials` (
+      `id` varchar(36) NOT NULL,
+      `client_id` varchar(255) NOT NULL DEFAULT '',
+      `name` varchar(255) NOT NULL,
+      `body` longtext NOT NULL,
+      PRIMARY KEY (`id`),
+      UNIQUE KEY `client_id_name` (`client_id`, `name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE partials;
@@ -0,0 +1,12 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `templates` ADD `partials_client_id` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `v2_templates` ADD `partials_client_id` varchar(255) NOT NULL DEFAULT '';
+UPDATE `templates` SET `partials_client_id` = COALESCE((SELECT `client_id` FROM `template_versions` WHERE `template_versions`.`template_id` = `templates`.`id` ORDER BY `version` DESC LIMIT 1), '');
+UPDATE `v2_templates` SET `partials_client_id` = `client_id` WHERE `id` != 'default';
+UPDATE `v2_templates` SET `partials_client_id` = COALESCE((SELECT `client_id` FROM `v2_template_versions` WHERE `v2_template_versions`.`template_id` = `v2_templates`.`id` ORDER BY `version` DESC LIMIT 1), '') WHERE `id` = 'default';
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `templates` DROP COLUMN `partials_client_id`;
+ALTER TABLE `v2_templates` DROP COLUMN `partials_client_id`;
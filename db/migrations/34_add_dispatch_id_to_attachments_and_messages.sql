@@ -0,0 +1,9 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `attachments` ADD `dispatch_id` varchar(36) NOT NULL DEFAULT '', ADD KEY `dispatch_id` (`dispatch_id`);
+ALTER TABLE `messages` ADD `dispatch_id` varchar(36) NOT NULL DEFAULT '', ADD KEY `dispatch_id` (`dispatch_id`);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `attachments` DROP COLUMN `dispatch_id`;
+ALTER TABLE `messages` DROP COLUMN `dispatch_id`;
@@ -14,8 +14,9 @@ type TransactionInterface interface {
 }
 
 type Transaction struct {
-	txn  *gorp.Transaction
-	conn *Connection
+	txn         *gorp.Transaction
+	conn        *Connection
+	afterCommit []func()
 }
 
 func NewTransaction(conn *Connection) TransactionInterface {
@@ -30,16 +31,36 @@ func (transaction *Transaction) GetDbMap() *gorp.DbMap {
 
 func (transaction *Transaction) Begin() error {
 	var err error
+	transaction.afterCommit = nil
 	transaction.txn, err = transaction.conn.Begin()
 	return err
 }
 
+// AfterCommit registers a callback to run once the transaction has been
+// committed. Callbacks are dropped if the transaction is rolled back or
+// fails to commit.
+func (transaction *Transaction) AfterCommit(callback func()) {
+	transaction.afterCommit = append(transaction.afterCommit, callback)
+}
+
 func (transaction *Transaction) Transaction() TransactionInterface {
 	return transaction
 }
 
 func (transaction *Transaction) Commit() error {
-	return transaction.txn.Commit()
+	callbacks := transaction.afterCommit
+	transaction.afterCommit = nil
+
+	err := transaction.txn.Commit()
+	if err != nil {
+		return err
+	}
+
+	for _, callback := range callbacks {
+		callback()
+	}
+
+	return nil
 }
 
 func (transaction *Transaction) Delete(v ...interface{}) (int64, error) {
@@ -55,6 +76,7 @@ func (transaction *Transaction) Insert(v ...interface{}) error {
 }
 
 func (transaction *Transaction) Rollback() error {
+	transaction.afterCommit = nil
 	return transaction.txn.Rollback()
 }
 
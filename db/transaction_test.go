@@ -64,4 +64,40 @@ var _ = Describe("Transaction", func() {
 			Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
 		})
 	})
+
+	Describe("AfterCommit", func() {
+		It("runs the callbacks once the transaction commits", func() {
+			err := transaction.Begin()
+			Expect(err).NotTo(HaveOccurred())
+
+			called := false
+			transaction.(*db.Transaction).AfterCommit(func() {
+				called = true
+			})
+			Expect(called).To(BeFalse())
+
+			err = transaction.Commit()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(called).To(BeTrue())
+		})
+
+		It("drops the callbacks when the transaction is rolled back", func() {
+			err := transaction.Begin()
+			Expect(err).NotTo(HaveOccurred())
+
+			called := false
+			transaction.(*db.Transaction).AfterCommit(func() {
+				called = true
+			})
+
+			err = transaction.Rollback()
+			Expect(err).NotTo(HaveOccurred())
+
+			err = transaction.Begin()
+			Expect(err).NotTo(HaveOccurred())
+			err = transaction.Commit()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(called).To(BeFalse())
+		})
+	})
 })
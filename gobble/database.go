@@ -39,6 +39,7 @@ type Initializer struct{}
 
 func (Initializer) InitializeDBMap(dbMap *gorp.DbMap) {
 	dbMap.AddTableWithName(Job{}, "jobs").SetKeys(true, "ID").SetVersionCol("Version")
+	dbMap.AddTableWithName(DeadLetter{}, "dead_letters").SetKeys(true, "ID")
 }
 
 func (db DB) Migrate(migrationsPath string) {
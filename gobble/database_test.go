@@ -73,5 +73,41 @@ var _ = Describe("Database", func() {
 			Field: "active_at",
 			Type:  "timestamp",
 		}))
+		Expect(columns).To(ContainElement(Column{
+			Field: "priority",
+			Type:  "int",
+		}))
+	})
+
+	It("has a dead_letters table", func() {
+		database := gobble.NewDatabase(sqlDB)
+
+		rows, err := database.Connection.Db.Query("SELECT COLUMN_NAME, DATA_TYPE FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_NAME = 'dead_letters'")
+		Expect(err).NotTo(HaveOccurred())
+
+		defer rows.Close()
+		columns := []Column{}
+
+		for rows.Next() {
+			var Field, Type string
+			err := rows.Scan(&Field, &Type)
+			Expect(err).NotTo(HaveOccurred())
+
+			columns = append(columns, Column{
+				Field: Field,
+				Type:  Type,
+			})
+		}
+
+		Expect(columns).To(ConsistOf([]Column{
+			{Field: "id", Type: "int"},
+			{Field: "job_id", Type: "int"},
+			{Field: "payload", Type: "longtext"},
+			{Field: "last_error", Type: "text"},
+			{Field: "retry_count", Type: "int"},
+			{Field: "priority", Type: "int"},
+			{Field: "active_at", Type: "timestamp"},
+			{Field: "created_at", Type: "timestamp"},
+		}))
 	})
 })
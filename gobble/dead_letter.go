@@ -0,0 +1,35 @@
+package gobble
+
+import "time"
+
+type DeadLetter struct {
+	ID         int       `db:"id"`
+	JobID      int       `db:"job_id"`
+	Payload    string    `db:"payload"`
+	LastError  string    `db:"last_error"`
+	RetryCount int       `db:"retry_count"`
+	Priority   int       `db:"priority"`
+	ActiveAt   time.Time `db:"active_at"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+func NewDeadLetter(job *Job, createdAt time.Time) DeadLetter {
+	return DeadLetter{
+		JobID:      job.ID,
+		Payload:    job.Payload,
+		LastError:  job.LastError,
+		RetryCount: job.RetryCount,
+		Priority:   job.Priority,
+		ActiveAt:   job.ActiveAt,
+		CreatedAt:  createdAt,
+	}
+}
+
+// Job returns a fresh job with the payload and priority of the job that
+// was given up on, so that replaying a critical job keeps it critical.
+func (letter DeadLetter) Job() *Job {
+	return &Job{
+		Payload:  letter.Payload,
+		Priority: letter.Priority,
+	}
+}
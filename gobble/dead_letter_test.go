@@ -0,0 +1,55 @@
+package gobble_test
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeadLetter", func() {
+	Describe("NewDeadLetter", func() {
+		It("captures the state of the job when it was given up on", func() {
+			activeAt := time.Now().Add(-5 * time.Minute)
+			createdAt := time.Now()
+
+			job := gobble.NewJob("the data")
+			job.ID = 42
+			job.RetryCount = 10
+			job.Priority = gobble.PriorityCritical
+			job.ActiveAt = activeAt
+			job.DeadLetter("smtp is down")
+
+			deadLetter := gobble.NewDeadLetter(job, createdAt)
+			Expect(deadLetter).To(Equal(gobble.DeadLetter{
+				JobID:      42,
+				Payload:    `"the data"`,
+				LastError:  "smtp is down",
+				RetryCount: 10,
+				Priority:   gobble.PriorityCritical,
+				ActiveAt:   activeAt,
+				CreatedAt:  createdAt,
+			}))
+		})
+	})
+
+	Describe("Job", func() {
+		It("returns a fresh job with the dead letter payload and priority", func() {
+			deadLetter := gobble.DeadLetter{
+				ID:         4,
+				JobID:      42,
+				Payload:    `"the data"`,
+				LastError:  "smtp is down",
+				RetryCount: 10,
+				Priority:   gobble.PriorityCritical,
+			}
+
+			Expect(deadLetter.Job()).To(Equal(&gobble.Job{
+				Payload:  `"the data"`,
+				Priority: gobble.PriorityCritical,
+			}))
+		})
+	})
+})
@@ -5,14 +5,22 @@ import (
 	"time"
 )
 
+const (
+	PriorityNormal   = 0
+	PriorityCritical = 1
+)
+
 type Job struct {
-	ID          int       `db:"id"`
-	WorkerID    string    `db:"worker_id"`
-	Payload     string    `db:"payload"`
-	Version     int64     `db:"version"`
-	RetryCount  int       `db:"retry_count"`
-	ActiveAt    time.Time `db:"active_at"`
-	ShouldRetry bool      `db:"-"`
+	ID               int       `db:"id"`
+	WorkerID         string    `db:"worker_id"`
+	Payload          string    `db:"payload"`
+	Version          int64     `db:"version"`
+	RetryCount       int       `db:"retry_count"`
+	ActiveAt         time.Time `db:"active_at"`
+	Priority         int       `db:"priority"`
+	ShouldRetry      bool      `db:"-"`
+	ShouldDeadLetter bool      `db:"-"`
+	LastError        string    `db:"-"`
 }
 
 func NewJob(data interface{}) *Job {
@@ -37,6 +45,12 @@ func (job *Job) Retry(duration time.Duration) {
 	job.ShouldRetry = true
 }
 
+func (job *Job) DeadLetter(reason string) {
+	job.ShouldRetry = false
+	job.ShouldDeadLetter = true
+	job.LastError = reason
+}
+
 func (job *Job) State() (int, time.Time) {
 	return job.RetryCount, job.ActiveAt
 }
@@ -57,6 +57,19 @@ var _ = Describe("Job", func() {
 		})
 	})
 
+	Describe("DeadLetter", func() {
+		It("sets up the job to be moved to the dead letters", func() {
+			job := gobble.NewJob("the data")
+			job.ShouldRetry = true
+
+			job.DeadLetter("smtp is down")
+
+			Expect(job.ShouldRetry).To(BeFalse())
+			Expect(job.ShouldDeadLetter).To(BeTrue())
+			Expect(job.LastError).To(Equal("smtp is down"))
+		})
+	})
+
 	Describe("State", func() {
 		It("returns the current retry count and active at values", func() {
 			expectedActiveAt := time.Now().Add(-5 * time.Minute)
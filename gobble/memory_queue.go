@@ -0,0 +1,322 @@
+package gobble
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// afterCommitter is implemented by transactions that can run a callback
+// once they have been committed.
+type afterCommitter interface {
+	AfterCommit(func())
+}
+
+// MemoryQueue is an in-process implementation of QueueInterface. It shares
+// the scheduling semantics of Queue, but jobs only live for as long as the
+// process does and are only visible to workers within the same process.
+// A job enqueued on a transaction is only stored once that transaction
+// commits. Dead letters are inserted through the given connection, like
+// those of Queue, so that they can be listed and replayed. Without a
+// connection they are kept in memory and returned by DeadLetters.
+type MemoryQueue struct {
+	config     Config
+	connection ConnectionInterface
+	clock      clock
+
+	mutex       sync.Mutex
+	jobs        map[int]Job
+	deadLetters []DeadLetter
+	lastID      int
+	changed     chan struct{}
+	closed      chan struct{}
+	closing     *sync.Once
+}
+
+func NewMemoryQueue(connection ConnectionInterface, clock clock, config Config) *MemoryQueue {
+	if config.WaitMaxDuration == 0 {
+		config.WaitMaxDuration = WaitMaxDuration
+	}
+
+	return &MemoryQueue{
+		config:     config,
+		connection: connection,
+		clock:      clock,
+		jobs:       map[int]Job{},
+		changed:    make(chan struct{}),
+		closed:     make(chan struct{}),
+		closing:    &sync.Once{},
+	}
+}
+
+func (queue *MemoryQueue) Enqueue(job *Job, connection ConnectionInterface) (*Job, error) {
+	queue.mutex.Lock()
+	if (job.ActiveAt == time.Time{}) {
+		job.ActiveAt = queue.clock.Now()
+	}
+
+	queue.lastID++
+	job.ID = queue.lastID
+	job.Version = 1
+	queue.mutex.Unlock()
+
+	stored := *job
+	if transaction, ok := connection.(afterCommitter); ok {
+		transaction.AfterCommit(func() {
+			queue.store(stored)
+		})
+	} else {
+		queue.store(stored)
+	}
+
+	return job, nil
+}
+
+func (queue *MemoryQueue) store(job Job) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	queue.jobs[job.ID] = job
+	queue.notify()
+}
+
+func (queue *MemoryQueue) Requeue(job *Job) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	if queue.update(job) {
+		queue.notify()
+	}
+}
+
+func (queue *MemoryQueue) Release(job *Job) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	job.WorkerID = ""
+	job.ActiveAt = time.Now()
+	if queue.update(job) {
+		queue.notify()
+	}
+}
+
+func (queue *MemoryQueue) Dequeue(job *Job) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	if queue.owns(job) {
+		delete(queue.jobs, job.ID)
+	}
+}
+
+func (queue *MemoryQueue) DeadLetter(job *Job) {
+	queue.mutex.Lock()
+	owned := queue.owns(job)
+	if owned {
+		delete(queue.jobs, job.ID)
+	}
+	queue.mutex.Unlock()
+
+	if !owned {
+		return
+	}
+
+	deadLetter := NewDeadLetter(job, queue.clock.Now())
+	if queue.connection == nil {
+		queue.mutex.Lock()
+		deadLetter.ID = len(queue.deadLetters) + 1
+		queue.deadLetters = append(queue.deadLetters, deadLetter)
+		queue.mutex.Unlock()
+		return
+	}
+
+	err := queue.connection.Insert(&deadLetter)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// DeadLetters returns the dead letters kept in memory by a queue that was
+// built without a connection.
+func (queue *MemoryQueue) DeadLetters() []DeadLetter {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	return append([]DeadLetter{}, queue.deadLetters...)
+}
+
+func (queue *MemoryQueue) Len() (int, error) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	return len(queue.jobs), nil
+}
+
+func (queue *MemoryQueue) RetryQueueLengths() (map[int]int, error) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	lengths := map[int]int{}
+	for _, job := range queue.jobs {
+		lengths[job.RetryCount]++
+	}
+
+	return lengths, nil
+}
+
+func (queue *MemoryQueue) PriorityQueueLengths() (map[int]int, error) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	lengths := map[int]int{}
+	for _, job := range queue.jobs {
+		lengths[job.Priority]++
+	}
+
+	return lengths, nil
+}
+
+func (queue *MemoryQueue) Close() {
+	queue.closing.Do(func() {
+		close(queue.closed)
+	})
+}
+
+func (queue *MemoryQueue) Reserve(workerID string) <-chan *Job {
+	channel := make(chan *Job)
+	go func() {
+		jobs := queue.claimJobs(workerID, 1)
+		if jobs == nil {
+			return
+		}
+
+		select {
+		case channel <- jobs[0]:
+		case <-queue.closed:
+			queue.Release(jobs[0])
+		}
+	}()
+
+	return channel
+}
+
+// ReserveBatch claims up to size jobs for workerID at once. Like
+// Queue.ReserveBatch, it is not used by Worker.
+func (queue *MemoryQueue) ReserveBatch(workerID string, size int) <-chan []*Job {
+	channel := make(chan []*Job)
+	go func() {
+		jobs := queue.claimJobs(workerID, size)
+		if jobs == nil {
+			return
+		}
+
+		select {
+		case channel <- jobs:
+		case <-queue.closed:
+			for _, job := range jobs {
+				queue.Release(job)
+			}
+		}
+	}()
+
+	return channel
+}
+
+func (queue *MemoryQueue) claimJobs(workerID string, size int) []*Job {
+	for {
+		select {
+		case <-queue.closed:
+			return nil
+		default:
+		}
+
+		jobs, changed := queue.claim(workerID, size)
+		if len(jobs) > 0 {
+			return jobs
+		}
+
+		select {
+		case <-changed:
+		case <-time.After(queue.config.WaitMaxDuration):
+		case <-queue.closed:
+			return nil
+		}
+	}
+}
+
+func (queue *MemoryQueue) claim(workerID string, size int) ([]*Job, <-chan struct{}) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	now := time.Now()
+	expired := now.Add(-2 * time.Minute)
+
+	var available []Job
+	for _, job := range queue.jobs {
+		if (job.WorkerID == "" && !job.ActiveAt.After(now)) || !job.ActiveAt.After(expired) {
+			available = append(available, job)
+		}
+	}
+
+	sort.Sort(byPriority(available))
+	if len(available) > size {
+		available = available[:size]
+	}
+
+	var jobs []*Job
+	for _, job := range available {
+		job.WorkerID = workerID
+		job.ActiveAt = now
+		job.Version++
+
+		// The outcome of a previous attempt is not persisted by Queue either.
+		job.ShouldRetry = false
+		job.ShouldDeadLetter = false
+		job.LastError = ""
+		queue.jobs[job.ID] = job
+
+		claimed := job
+		jobs = append(jobs, &claimed)
+	}
+
+	return jobs, queue.changed
+}
+
+func (queue *MemoryQueue) owns(job *Job) bool {
+	stored, ok := queue.jobs[job.ID]
+	return ok && stored.Version == job.Version
+}
+
+func (queue *MemoryQueue) update(job *Job) bool {
+	if !queue.owns(job) {
+		return false
+	}
+
+	job.Version++
+	queue.jobs[job.ID] = *job
+
+	return true
+}
+
+func (queue *MemoryQueue) notify() {
+	close(queue.changed)
+	queue.changed = make(chan struct{})
+}
+
+type byPriority []Job
+
+func (jobs byPriority) Len() int {
+	return len(jobs)
+}
+
+func (jobs byPriority) Swap(i, j int) {
+	jobs[i], jobs[j] = jobs[j], jobs[i]
+}
+
+func (jobs byPriority) Less(i, j int) bool {
+	if jobs[i].Priority != jobs[j].Priority {
+		return jobs[i].Priority > jobs[j].Priority
+	}
+
+	return jobs[i].ID < jobs[j].ID
+}
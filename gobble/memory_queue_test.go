@@ -0,0 +1,400 @@
+package gobble_test
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MemoryQueue", func() {
+	var (
+		queue    *gobble.MemoryQueue
+		database *gobble.DB
+		clock    *mocks.Clock
+	)
+
+	BeforeEach(func() {
+		TruncateTables()
+		database = gobble.NewDatabase(sqlDB)
+		clock = &mocks.Clock{}
+		clock.NowCall.Returns.Time = time.Now().UTC().Truncate(time.Second)
+
+		queue = gobble.NewMemoryQueue(database.Connection, clock, gobble.Config{
+			WaitMaxDuration: 50 * time.Millisecond,
+		})
+	})
+
+	AfterEach(func() {
+		queue.Close()
+	})
+
+	Describe("Enqueue", func() {
+		It("assigns an id to the job and schedules it to be active now", func() {
+			job, err := queue.Enqueue(&gobble.Job{Payload: "the-payload"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(job.ID).To(Equal(1))
+			Expect(job.ActiveAt).To(Equal(clock.NowCall.Returns.Time))
+
+			job, err = queue.Enqueue(&gobble.Job{Payload: "the-payload"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(job.ID).To(Equal(2))
+
+			length, err := queue.Len()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(length).To(Equal(2))
+		})
+
+		It("only stores a job enqueued on a transaction once it commits", func() {
+			transaction := &committingTransaction{}
+
+			job, err := queue.Enqueue(&gobble.Job{Payload: "the-payload"}, transaction)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(job.ID).To(Equal(1))
+
+			length, err := queue.Len()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(length).To(Equal(0))
+
+			transaction.Commit()
+
+			length, err = queue.Len()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(length).To(Equal(1))
+		})
+
+		It("drops a job enqueued on a transaction that is rolled back", func() {
+			transaction := &committingTransaction{}
+
+			_, err := queue.Enqueue(&gobble.Job{Payload: "the-payload"}, transaction)
+			Expect(err).NotTo(HaveOccurred())
+
+			transaction.Rollback()
+
+			length, err := queue.Len()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(length).To(Equal(0))
+		})
+	})
+
+	Describe("Reserve", func() {
+		It("reserves a job for the worker", func() {
+			job, err := queue.Enqueue(&gobble.Job{Payload: "the-payload"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			var reservedJob *gobble.Job
+			Eventually(queue.Reserve("worker-1")).Should(Receive(&reservedJob))
+			Expect(reservedJob.ID).To(Equal(job.ID))
+			Expect(reservedJob.Payload).To(Equal("the-payload"))
+			Expect(reservedJob.WorkerID).To(Equal("worker-1"))
+			Expect(reservedJob.ActiveAt).To(BeTemporally("~", time.Now(), 1*time.Second))
+		})
+
+		It("does not reserve a job that has already been reserved", func() {
+			_, err := queue.Enqueue(&gobble.Job{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(queue.Reserve("worker-1")).Should(Receive())
+			Consistently(queue.Reserve("worker-2"), 200*time.Millisecond).ShouldNot(Receive())
+		})
+
+		It("does not reserve a job before it is active", func() {
+			_, err := queue.Enqueue(&gobble.Job{
+				ActiveAt: time.Now().Add(1 * time.Hour),
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Consistently(queue.Reserve("worker-1"), 200*time.Millisecond).ShouldNot(Receive())
+		})
+
+		It("reserves jobs whose reservation has expired", func() {
+			job, err := queue.Enqueue(&gobble.Job{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			var reservedJob *gobble.Job
+			Eventually(queue.Reserve("worker-1")).Should(Receive(&reservedJob))
+
+			reservedJob.ActiveAt = time.Now().Add(-5 * time.Minute)
+			queue.Requeue(reservedJob)
+
+			Eventually(queue.Reserve("worker-2")).Should(Receive(&reservedJob))
+			Expect(reservedJob.ID).To(Equal(job.ID))
+			Expect(reservedJob.WorkerID).To(Equal("worker-2"))
+		})
+
+		It("reserves critical jobs ahead of normal jobs", func() {
+			_, err := queue.Enqueue(&gobble.Job{Priority: gobble.PriorityNormal}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			criticalJob, err := queue.Enqueue(&gobble.Job{Priority: gobble.PriorityCritical}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			var reservedJob *gobble.Job
+			Eventually(queue.Reserve("worker-1")).Should(Receive(&reservedJob))
+			Expect(reservedJob.ID).To(Equal(criticalJob.ID))
+		})
+
+		It("wakes up waiting workers when a job is enqueued", func() {
+			queue = gobble.NewMemoryQueue(database.Connection, clock, gobble.Config{
+				WaitMaxDuration: 1 * time.Hour,
+			})
+
+			reservation := queue.Reserve("worker-1")
+
+			_, err := queue.Enqueue(&gobble.Job{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(reservation).Should(Receive())
+		})
+	})
+
+	Describe("ReserveBatch", func() {
+		It("reserves up to the given number of jobs", func() {
+			for i := 0; i < 3; i++ {
+				_, err := queue.Enqueue(&gobble.Job{}, nil)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			var jobs []*gobble.Job
+			Eventually(queue.ReserveBatch("worker-1", 2)).Should(Receive(&jobs))
+			Expect(jobs).To(HaveLen(2))
+
+			Eventually(queue.ReserveBatch("worker-2", 2)).Should(Receive(&jobs))
+			Expect(jobs).To(HaveLen(1))
+		})
+	})
+
+	Describe("Requeue", func() {
+		It("schedules the job to be retried", func() {
+			_, err := queue.Enqueue(&gobble.Job{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			var job *gobble.Job
+			Eventually(queue.Reserve("worker-1")).Should(Receive(&job))
+
+			job.Retry(1 * time.Minute)
+			queue.Requeue(job)
+
+			lengths, err := queue.RetryQueueLengths()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lengths).To(Equal(map[int]int{1: 1}))
+
+			Consistently(queue.Reserve("worker-2"), 200*time.Millisecond).ShouldNot(Receive())
+		})
+
+		It("ignores jobs that have since been reserved by another worker", func() {
+			_, err := queue.Enqueue(&gobble.Job{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			var job *gobble.Job
+			Eventually(queue.Reserve("worker-1")).Should(Receive(&job))
+
+			staleJob := *job
+			job.ActiveAt = time.Now().Add(-5 * time.Minute)
+			queue.Requeue(job)
+
+			Eventually(queue.Reserve("worker-2")).Should(Receive())
+
+			staleJob.RetryCount = 5
+			queue.Requeue(&staleJob)
+
+			lengths, err := queue.RetryQueueLengths()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lengths).To(Equal(map[int]int{0: 1}))
+		})
+	})
+
+	Describe("Dequeue", func() {
+		It("removes the job from the queue", func() {
+			_, err := queue.Enqueue(&gobble.Job{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			var job *gobble.Job
+			Eventually(queue.Reserve("worker-1")).Should(Receive(&job))
+
+			queue.Dequeue(job)
+			queue.Dequeue(job)
+
+			length, err := queue.Len()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(length).To(Equal(0))
+		})
+	})
+
+	Describe("DeadLetter", func() {
+		It("moves the job from the queue into the dead letters table", func() {
+			_, err := queue.Enqueue(&gobble.Job{Payload: "the-payload"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			var job *gobble.Job
+			Eventually(queue.Reserve("worker-1")).Should(Receive(&job))
+
+			job.DeadLetter("smtp is down")
+			queue.DeadLetter(job)
+
+			length, err := queue.Len()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(length).To(Equal(0))
+
+			deadLetter := gobble.DeadLetter{}
+			err = database.Connection.SelectOne(&deadLetter, "SELECT * FROM `dead_letters` WHERE `job_id` = ?", job.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(deadLetter.Payload).To(Equal("the-payload"))
+			Expect(deadLetter.LastError).To(Equal("smtp is down"))
+			Expect(deadLetter.CreatedAt).To(Equal(clock.NowCall.Returns.Time))
+		})
+
+		Context("when the queue has no connection", func() {
+			It("keeps the dead letters in memory", func() {
+				queue = gobble.NewMemoryQueue(nil, clock, gobble.Config{
+					WaitMaxDuration: 50 * time.Millisecond,
+				})
+
+				_, err := queue.Enqueue(&gobble.Job{Payload: "the-payload"}, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				var job *gobble.Job
+				Eventually(queue.Reserve("worker-1")).Should(Receive(&job))
+
+				job.DeadLetter("smtp is down")
+				queue.DeadLetter(job)
+
+				length, err := queue.Len()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(length).To(Equal(0))
+
+				deadLetters := queue.DeadLetters()
+				Expect(deadLetters).To(HaveLen(1))
+				Expect(deadLetters[0].JobID).To(Equal(job.ID))
+				Expect(deadLetters[0].Payload).To(Equal("the-payload"))
+				Expect(deadLetters[0].LastError).To(Equal("smtp is down"))
+				Expect(deadLetters[0].CreatedAt).To(Equal(clock.NowCall.Returns.Time))
+			})
+		})
+	})
+
+	Describe("Release", func() {
+		It("makes the job available to other workers", func() {
+			_, err := queue.Enqueue(&gobble.Job{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			var job *gobble.Job
+			Eventually(queue.Reserve("worker-1")).Should(Receive(&job))
+
+			queue.Release(job)
+
+			var reservedJob *gobble.Job
+			Eventually(queue.Reserve("worker-2")).Should(Receive(&reservedJob))
+			Expect(reservedJob.ID).To(Equal(job.ID))
+		})
+	})
+
+	Describe("PriorityQueueLengths", func() {
+		It("returns the number of jobs at each priority", func() {
+			_, err := queue.Enqueue(&gobble.Job{Priority: gobble.PriorityNormal}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = queue.Enqueue(&gobble.Job{Priority: gobble.PriorityCritical}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = queue.Enqueue(&gobble.Job{Priority: gobble.PriorityCritical}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			lengths, err := queue.PriorityQueueLengths()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lengths).To(Equal(map[int]int{
+				gobble.PriorityNormal:   1,
+				gobble.PriorityCritical: 2,
+			}))
+		})
+	})
+
+	Describe("Close", func() {
+		It("stops reserving jobs", func() {
+			queue.Close()
+
+			_, err := queue.Enqueue(&gobble.Job{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Consistently(queue.Reserve("worker-1"), 200*time.Millisecond).ShouldNot(Receive())
+		})
+	})
+
+	Context("when used by a worker", func() {
+		It("performs each job and heartbeats for ownership while it runs", func() {
+			ticker := gobble.NewTicker(time.NewTicker, 10*time.Millisecond)
+			heartbeater := gobble.NewHeartbeater(queue, ticker)
+
+			performed := make(chan *gobble.Job, 2)
+			worker := gobble.NewWorker(1, queue, func(job *gobble.Job) {
+				time.Sleep(100 * time.Millisecond)
+				performed <- job
+			}, heartbeater)
+
+			_, err := queue.Enqueue(&gobble.Job{Payload: "first"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = queue.Enqueue(&gobble.Job{Payload: "second"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			worker.Work()
+
+			Eventually(performed).Should(Receive())
+			Eventually(performed).Should(Receive())
+			Eventually(queue.Len).Should(Equal(0))
+
+			worker.Halt()
+			Expect(worker.Wait(5 * time.Second)).To(BeTrue())
+		})
+
+		It("removes a job that succeeds after being retried", func() {
+			ticker := gobble.NewTicker(time.NewTicker, 1*time.Minute)
+			heartbeater := gobble.NewHeartbeater(queue, ticker)
+
+			attempts := make(chan int, 3)
+			worker := gobble.NewWorker(1, queue, func(job *gobble.Job) {
+				attempts <- job.RetryCount
+				if job.RetryCount == 0 {
+					job.Retry(0)
+				}
+			}, heartbeater)
+
+			_, err := queue.Enqueue(&gobble.Job{Payload: "the-payload"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			worker.Work()
+
+			Eventually(attempts).Should(Receive(Equal(0)))
+			Eventually(attempts).Should(Receive(Equal(1)))
+			Eventually(queue.Len).Should(Equal(0))
+			Consistently(attempts, 200*time.Millisecond).ShouldNot(Receive())
+
+			worker.Halt()
+			Expect(worker.Wait(5 * time.Second)).To(BeTrue())
+		})
+	})
+})
+
+type committingTransaction struct {
+	callbacks []func()
+}
+
+func (t *committingTransaction) Insert(...interface{}) error {
+	return nil
+}
+
+func (t *committingTransaction) AfterCommit(callback func()) {
+	t.callbacks = append(t.callbacks, callback)
+}
+
+func (t *committingTransaction) Commit() {
+	for _, callback := range t.callbacks {
+		callback()
+	}
+	t.callbacks = nil
+}
+
+func (t *committingTransaction) Rollback() {
+	t.callbacks = nil
+}
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE `jobs` ADD `priority` INT(11) NOT NULL DEFAULT '0';
+ALTER TABLE `jobs` ADD KEY `priority` (`priority`);
+
+-- +migrate Down
+ALTER TABLE `jobs` DROP INDEX `priority`;
+ALTER TABLE `jobs` DROP COLUMN `priority`;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE `dead_letters` ADD `priority` INT(11) NOT NULL DEFAULT '0';
+
+-- +migrate Down
+ALTER TABLE `dead_letters` DROP COLUMN `priority`;
@@ -1,9 +1,9 @@
 package gobble
 
 import (
-	"database/sql"
 	"math/rand"
 	"strings"
+	"sync"
 	"time"
 
 	"gopkg.in/gorp.v1"
@@ -14,10 +14,15 @@ var WaitMaxDuration = 5 * time.Second
 type QueueInterface interface {
 	Enqueue(*Job, ConnectionInterface) (*Job, error)
 	Reserve(string) <-chan *Job
+	ReserveBatch(string, int) <-chan []*Job
 	Dequeue(*Job)
 	Requeue(*Job)
+	DeadLetter(*Job)
+	Release(*Job)
 	Len() (int, error)
 	RetryQueueLengths() (map[int]int, error)
+	PriorityQueueLengths() (map[int]int, error)
+	Close()
 }
 
 type clock interface {
@@ -28,7 +33,8 @@ type Queue struct {
 	config   Config
 	database *DB
 	clock    clock
-	closed   bool
+	closed   chan struct{}
+	closing  *sync.Once
 }
 
 func NewQueue(database DatabaseInterface, clock clock, config Config) *Queue {
@@ -40,6 +46,8 @@ func NewQueue(database DatabaseInterface, clock clock, config Config) *Queue {
 		database: database.(*DB),
 		clock:    clock,
 		config:   config,
+		closed:   make(chan struct{}),
+		closing:  &sync.Once{},
 	}
 }
 
@@ -89,8 +97,40 @@ func (queue *Queue) RetryQueueLengths() (map[int]int, error) {
 	return lengths, nil
 }
 
+func (queue *Queue) PriorityQueueLengths() (map[int]int, error) {
+	lengths := map[int]int{}
+
+	type QueueLength struct {
+		Priority int `db:"priority"`
+		Count    int `db:"count"`
+	}
+
+	records, err := queue.database.Connection.Select(QueueLength{}, "SELECT priority, COUNT(*) AS count FROM `jobs` GROUP BY priority")
+	if err != nil {
+		return lengths, err
+	}
+
+	for _, value := range records {
+		length := value.(*QueueLength)
+		lengths[length.Priority] = length.Count
+	}
+
+	return lengths, nil
+}
+
 func (queue *Queue) Close() {
-	queue.closed = true
+	queue.closing.Do(func() {
+		close(queue.closed)
+	})
+}
+
+func (queue *Queue) isClosed() bool {
+	select {
+	case <-queue.closed:
+		return true
+	default:
+		return false
+	}
 }
 
 func (queue *Queue) Reserve(workerID string) <-chan *Job {
@@ -100,33 +140,102 @@ func (queue *Queue) Reserve(workerID string) <-chan *Job {
 	return channel
 }
 
+// ReserveBatch claims up to size jobs for workerID in a single round trip.
+// It is only exposed as API: Worker still reserves one job at a time with
+// Reserve, because the heartbeater only keeps the job being performed
+// reserved, and the rest of a batch would expire while they wait.
+func (queue *Queue) ReserveBatch(workerID string, size int) <-chan []*Job {
+	channel := make(chan []*Job)
+	go queue.reserveBatch(channel, workerID, size)
+
+	return channel
+}
+
 func (queue *Queue) reserve(channel chan *Job, workerID string) {
-	var job *Job
-	for job == nil {
-		var err error
+	jobs := queue.claimJobs(workerID, 1)
+	if jobs == nil {
+		return
+	}
 
-		job = queue.findJob()
-		if queue.closed {
-			return
+	select {
+	case channel <- jobs[0]:
+	case <-queue.closed:
+		queue.Release(jobs[0])
+	}
+}
+
+func (queue *Queue) reserveBatch(channel chan []*Job, workerID string, size int) {
+	jobs := queue.claimJobs(workerID, size)
+	if jobs == nil {
+		return
+	}
+
+	select {
+	case channel <- jobs:
+	case <-queue.closed:
+		for _, job := range jobs {
+			queue.Release(job)
+		}
+	}
+}
+
+func (queue *Queue) claimJobs(workerID string, size int) []*Job {
+	for {
+		if queue.isClosed() {
+			return nil
 		}
 
-		job, err = queue.updateJob(job, workerID)
+		jobs, err := queue.claim(workerID, size)
 		if err != nil {
-			if _, ok := err.(gorp.OptimisticLockError); ok {
-				job = nil
-				continue
-			} else {
-				panic(err)
+			panic(err)
+		}
+
+		if len(jobs) == 0 {
+			queue.waitUpTo(queue.config.WaitMaxDuration)
+			continue
+		}
+
+		if queue.isClosed() {
+			for _, job := range jobs {
+				queue.Release(job)
 			}
+			return nil
 		}
+
+		return jobs
 	}
+}
 
-	if queue.closed {
-		queue.updateJob(job, "")
-		return
+func (queue *Queue) claim(workerID string, size int) ([]*Job, error) {
+	now := time.Now()
+	expired := now.Add(-2 * time.Minute)
+	version := now.UnixNano()
+
+	result, err := queue.database.Connection.Exec("UPDATE `jobs` SET `worker_id` = ?, `active_at` = ?, `version` = ? WHERE ( `worker_id` = \"\" AND `active_at` <= ? ) OR `active_at` <= ? ORDER BY `priority` DESC LIMIT ?", workerID, now, version, now, expired, size)
+	if err != nil {
+		return nil, err
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if claimed == 0 {
+		return nil, nil
 	}
 
-	channel <- job
+	var jobs []*Job
+	_, err = queue.database.Connection.Select(&jobs, "SELECT * FROM `jobs` WHERE `worker_id` = ? AND `version` = ? ORDER BY `priority` DESC", workerID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, job := range jobs {
+		job.ActiveAt = now
+	}
+
+	return jobs, nil
 }
 
 func (queue *Queue) Dequeue(job *Job) {
@@ -139,41 +248,51 @@ func (queue *Queue) Dequeue(job *Job) {
 	}
 }
 
-func (queue *Queue) findJob() *Job {
-	var job *Job
-	for job == nil {
-		job = &Job{}
-		now := time.Now()
-		expired := now.Add(-2 * time.Minute)
-		err := queue.database.Connection.SelectOne(job, "SELECT * FROM `jobs` WHERE ( `worker_id` = \"\" AND `active_at` <= ? ) OR `active_at` <= ? LIMIT 1", now, expired)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				job = nil
-				queue.waitUpTo(queue.config.WaitMaxDuration)
-				continue
-			}
-			panic(err)
+func (queue *Queue) DeadLetter(job *Job) {
+	transaction, err := queue.database.Connection.Begin()
+	if err != nil {
+		panic(err)
+	}
+
+	deadLetter := NewDeadLetter(job, queue.clock.Now())
+	err = transaction.Insert(&deadLetter)
+	if err != nil {
+		transaction.Rollback()
+		panic(err)
+	}
+
+	_, err = transaction.Delete(job)
+	if err != nil {
+		transaction.Rollback()
+		if _, ok := err.(gorp.OptimisticLockError); ok && strings.Contains(err.Error(), "no row found") {
+			return
 		}
+		panic(err)
 	}
-	return job
-}
 
-func (queue *Queue) updateJob(job *Job, workerID string) (*Job, error) {
-	if job == nil {
-		return job, nil
+	err = transaction.Commit()
+	if err != nil {
+		panic(err)
 	}
+}
 
-	job.WorkerID = workerID
+func (queue *Queue) Release(job *Job) {
+	job.WorkerID = ""
 	job.ActiveAt = time.Now()
 	_, err := queue.database.Connection.Update(job)
 	if err != nil {
-		return job, err
+		if _, ok := err.(gorp.OptimisticLockError); ok {
+			return
+		}
+		panic(err)
 	}
-	return job, nil
 }
 
 func (queue *Queue) waitUpTo(max time.Duration) {
 	rand.Seed(time.Now().UnixNano())
 	waitTime := rand.Int63n(int64(max))
-	<-time.After(time.Duration(waitTime))
+	select {
+	case <-time.After(time.Duration(waitTime)):
+	case <-queue.closed:
+	}
 }
@@ -0,0 +1,175 @@
+package gobble_test
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/application"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/util"
+	"gopkg.in/gorp.v1"
+)
+
+const benchmarkWorkerCount = 10
+
+func BenchmarkReserveWithOptimisticLocking(b *testing.B) {
+	database := setupBenchmarkDatabase(b)
+
+	runBenchmarkWorkers(b, database, func(workerID string) int {
+		job := reserveWithOptimisticLocking(database, workerID)
+		if job == nil {
+			return 0
+		}
+
+		dequeue(b, database, job)
+		return 1
+	})
+}
+
+func BenchmarkReserve(b *testing.B) {
+	database := setupBenchmarkDatabase(b)
+	queue := gobble.NewQueue(database, util.NewClock(), gobble.Config{
+		WaitMaxDuration: 10 * time.Millisecond,
+	})
+	defer queue.Close()
+
+	runBenchmarkWorkers(b, database, func(workerID string) int {
+		select {
+		case job := <-queue.Reserve(workerID):
+			queue.Dequeue(job)
+			return 1
+		case <-time.After(100 * time.Millisecond):
+			return 0
+		}
+	})
+}
+
+func BenchmarkReserveBatch(b *testing.B) {
+	database := setupBenchmarkDatabase(b)
+	queue := gobble.NewQueue(database, util.NewClock(), gobble.Config{
+		WaitMaxDuration: 10 * time.Millisecond,
+	})
+	defer queue.Close()
+
+	runBenchmarkWorkers(b, database, func(workerID string) int {
+		select {
+		case jobs := <-queue.ReserveBatch(workerID, 10):
+			for _, job := range jobs {
+				queue.Dequeue(job)
+			}
+			return len(jobs)
+		case <-time.After(100 * time.Millisecond):
+			return 0
+		}
+	})
+}
+
+func setupBenchmarkDatabase(b *testing.B) *gobble.DB {
+	connection, err := instantiateDBConnection()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	env, err := application.NewEnvironment()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	database := gobble.NewDatabase(connection)
+	database.Migrate(env.GobbleMigrationsPath)
+
+	err = database.Connection.TruncateTables()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return database
+}
+
+func runBenchmarkWorkers(b *testing.B, database *gobble.DB, work func(workerID string) int) {
+	for i := 0; i < b.N; i++ {
+		err := database.Connection.Insert(&gobble.Job{
+			Payload:  "{}",
+			ActiveAt: time.Now().Add(-1 * time.Second),
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ResetTimer()
+
+	var (
+		mutex     sync.Mutex
+		remaining = b.N
+		group     sync.WaitGroup
+	)
+
+	for i := 0; i < benchmarkWorkerCount; i++ {
+		group.Add(1)
+		go func(workerID string) {
+			defer group.Done()
+
+			for {
+				mutex.Lock()
+				done := remaining <= 0
+				mutex.Unlock()
+
+				if done {
+					return
+				}
+
+				count := work(workerID)
+
+				mutex.Lock()
+				remaining -= count
+				mutex.Unlock()
+			}
+		}(fmt.Sprintf("benchmark-worker-%d", i))
+	}
+
+	group.Wait()
+}
+
+// reserveWithOptimisticLocking mirrors the reservation strategy the queue
+// used before claims were made with a single UPDATE, so that the two
+// approaches can be compared against the same database.
+func reserveWithOptimisticLocking(database *gobble.DB, workerID string) *gobble.Job {
+	for {
+		job := &gobble.Job{}
+		now := time.Now()
+		expired := now.Add(-2 * time.Minute)
+		err := database.Connection.SelectOne(job, "SELECT * FROM `jobs` WHERE ( `worker_id` = \"\" AND `active_at` <= ? ) OR `active_at` <= ? LIMIT 1", now, expired)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil
+			}
+			panic(err)
+		}
+
+		job.WorkerID = workerID
+		job.ActiveAt = time.Now()
+		_, err = database.Connection.Update(job)
+		if err != nil {
+			if _, ok := err.(gorp.OptimisticLockError); ok {
+				continue
+			}
+			panic(err)
+		}
+
+		return job
+	}
+}
+
+func dequeue(b *testing.B, database *gobble.DB, job *gobble.Job) {
+	_, err := database.Connection.Delete(job)
+	if err != nil {
+		if _, ok := err.(gorp.OptimisticLockError); ok {
+			return
+		}
+		b.Fatal(err)
+	}
+}
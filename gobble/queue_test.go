@@ -98,6 +98,34 @@ var _ = Describe("Queue", func() {
 		})
 	})
 
+	Describe("DeadLetter", func() {
+		It("moves the job from the queue into the dead letters", func() {
+			job, err := queue.Enqueue(&gobble.Job{
+				Payload:    "the-payload",
+				RetryCount: 10,
+				ActiveAt:   time.Now().UTC().Truncate(time.Second),
+			}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			job.LastError = "smtp is down"
+			queue.DeadLetter(job)
+
+			length, err := queue.Len()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(length).To(Equal(0))
+
+			deadLetter := gobble.DeadLetter{}
+			err = database.Connection.SelectOne(&deadLetter, "SELECT * FROM `dead_letters` WHERE `job_id` = ?", job.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(deadLetter.Payload).To(Equal("the-payload"))
+			Expect(deadLetter.LastError).To(Equal("smtp is down"))
+			Expect(deadLetter.RetryCount).To(Equal(10))
+			Expect(deadLetter.ActiveAt).To(Equal(job.ActiveAt))
+			Expect(deadLetter.CreatedAt).To(Equal(clock.NowCall.Returns.Time))
+		})
+	})
+
 	Describe("Reserve", func() {
 		It("reserves a job in the database", func() {
 			job := gobble.Job{
@@ -170,6 +198,23 @@ var _ = Describe("Queue", func() {
 			Expect(job.ID).To(Equal(job2.ID))
 		})
 
+		It("picks critical jobs ahead of normal jobs", func() {
+			_, err := queue.Enqueue(&gobble.Job{
+				Priority: gobble.PriorityNormal,
+			}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			criticalJob, err := queue.Enqueue(&gobble.Job{
+				Priority: gobble.PriorityCritical,
+			}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			job := <-queue.Reserve("worker-id")
+
+			Expect(job.ID).To(Equal(criticalJob.ID))
+			Expect(job.Priority).To(Equal(gobble.PriorityCritical))
+		})
+
 		Context("when the worker id is set", func() {
 			Context("when active_at is in the future", func() {
 				It("should not grab the job", func() {
@@ -228,6 +273,95 @@ var _ = Describe("Queue", func() {
 		})
 	})
 
+	Describe("ReserveBatch", func() {
+		It("reserves up to the requested number of jobs in a single claim", func() {
+			for i := 0; i < 5; i++ {
+				_, err := queue.Enqueue(&gobble.Job{}, database.Connection)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			var jobs []*gobble.Job
+			Eventually(queue.ReserveBatch("worker-id", 3)).Should(Receive(&jobs))
+			Expect(jobs).To(HaveLen(3))
+
+			for _, job := range jobs {
+				Expect(job.WorkerID).To(Equal("worker-id"))
+			}
+
+			results, err := database.Connection.Select(gobble.Job{}, "SELECT * FROM `jobs` WHERE `worker_id` = 'worker-id'")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(3))
+		})
+
+		It("returns fewer jobs when fewer are available", func() {
+			_, err := queue.Enqueue(&gobble.Job{}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			var jobs []*gobble.Job
+			Eventually(queue.ReserveBatch("worker-id", 10)).Should(Receive(&jobs))
+			Expect(jobs).To(HaveLen(1))
+		})
+
+		It("claims critical jobs first", func() {
+			for i := 0; i < 3; i++ {
+				_, err := queue.Enqueue(&gobble.Job{Priority: gobble.PriorityNormal}, database.Connection)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			criticalJob, err := queue.Enqueue(&gobble.Job{Priority: gobble.PriorityCritical}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			var jobs []*gobble.Job
+			Eventually(queue.ReserveBatch("worker-id", 2)).Should(Receive(&jobs))
+			Expect(jobs).To(HaveLen(2))
+			Expect(jobs[0].ID).To(Equal(criticalJob.ID))
+		})
+
+		It("does not hand the same job to more than one worker", func() {
+			for i := 0; i < 100; i++ {
+				_, err := queue.Enqueue(&gobble.Job{}, database.Connection)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			reserved := make(chan []*gobble.Job, 20)
+			reserveJobs := func(id string) {
+				for i := 0; i < 10; i++ {
+					reserved <- <-queue.ReserveBatch(id, 5)
+				}
+			}
+
+			go reserveJobs("worker-1")
+			go reserveJobs("worker-2")
+
+			seen := map[int]bool{}
+			for i := 0; i < 20; i++ {
+				var jobs []*gobble.Job
+				Eventually(reserved, 30*time.Second).Should(Receive(&jobs))
+
+				for _, job := range jobs {
+					Expect(seen).NotTo(HaveKey(job.ID))
+					seen[job.ID] = true
+				}
+			}
+
+			Expect(seen).To(HaveLen(100))
+		})
+
+		It("can be dequeued after being reserved", func() {
+			_, err := queue.Enqueue(&gobble.Job{}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			var jobs []*gobble.Job
+			Eventually(queue.ReserveBatch("worker-id", 1)).Should(Receive(&jobs))
+
+			queue.Dequeue(jobs[0])
+
+			length, err := queue.Len()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(length).To(Equal(0))
+		})
+	})
+
 	Describe("Dequeue", func() {
 		It("deletes the job from the queue", func() {
 			job, err := queue.Enqueue(&gobble.Job{}, database.Connection)
@@ -262,6 +396,66 @@ var _ = Describe("Queue", func() {
 		})
 	})
 
+	Describe("Release", func() {
+		It("clears the worker so the job can be reserved again", func() {
+			_, err := queue.Enqueue(&gobble.Job{}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			var job *gobble.Job
+			Eventually(queue.Reserve("worker-1")).Should(Receive(&job))
+			Expect(job.WorkerID).To(Equal("worker-1"))
+
+			queue.Release(job)
+
+			reloadedJob := gobble.Job{}
+			err = database.Connection.SelectOne(&reloadedJob, "SELECT * FROM `jobs` where id = ?", job.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reloadedJob.WorkerID).To(Equal(""))
+
+			var reservedJob *gobble.Job
+			Eventually(queue.Reserve("worker-2")).Should(Receive(&reservedJob))
+			Expect(reservedJob.ID).To(Equal(job.ID))
+			Expect(reservedJob.WorkerID).To(Equal("worker-2"))
+		})
+	})
+
+	Describe("Close", func() {
+		It("stops reserving jobs", func() {
+			queue.Close()
+
+			_, err := queue.Enqueue(&gobble.Job{}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			Consistently(queue.Reserve("worker-1")).ShouldNot(Receive())
+
+			reloadedJob := gobble.Job{}
+			err = database.Connection.SelectOne(&reloadedJob, "SELECT * FROM `jobs`")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reloadedJob.WorkerID).To(Equal(""))
+		})
+
+		It("releases jobs that were reserved but never received", func() {
+			_, err := queue.Enqueue(&gobble.Job{}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			queue.Reserve("worker-1")
+
+			Eventually(func() (string, error) {
+				job := gobble.Job{}
+				err := database.Connection.SelectOne(&job, "SELECT * FROM `jobs`")
+				return job.WorkerID, err
+			}).Should(Equal("worker-1"))
+
+			queue.Close()
+
+			Eventually(func() (string, error) {
+				job := gobble.Job{}
+				err := database.Connection.SelectOne(&job, "SELECT * FROM `jobs`")
+				return job.WorkerID, err
+			}).Should(Equal(""))
+		})
+	})
+
 	Describe("Len", func() {
 		It("returns the length of the queue", func() {
 			job, err := queue.Enqueue(&gobble.Job{}, database.Connection)
@@ -307,4 +501,23 @@ var _ = Describe("Queue", func() {
 			}))
 		})
 	})
+
+	Describe("PriorityQueueLengths", func() {
+		It("returns information about the length of the queue grouped by priority", func() {
+			for i := 0; i < 3; i++ {
+				_, err := queue.Enqueue(&gobble.Job{Priority: gobble.PriorityNormal}, database.Connection)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			_, err := queue.Enqueue(&gobble.Job{Priority: gobble.PriorityCritical}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			lengths, err := queue.PriorityQueueLengths()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lengths).To(Equal(map[int]int{
+				gobble.PriorityNormal:   3,
+				gobble.PriorityCritical: 1,
+			}))
+		})
+	})
 })
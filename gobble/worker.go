@@ -3,6 +3,8 @@ package gobble
 import (
 	"fmt"
 	"os"
+	"sync"
+	"time"
 )
 
 type heartbeater interface {
@@ -16,6 +18,9 @@ type Worker struct {
 	callback func(*Job)
 	beater   heartbeater
 	halt     chan bool
+	halting  *sync.Once
+	done     chan bool
+	current  *currentJob
 }
 
 func NewWorker(id int, queue QueueInterface, callback func(*Job), beater heartbeater) Worker {
@@ -25,18 +30,29 @@ func NewWorker(id int, queue QueueInterface, callback func(*Job), beater heartbe
 		callback: callback,
 		beater:   beater,
 		halt:     make(chan bool),
+		halting:  &sync.Once{},
+		done:     make(chan bool),
+		current:  &currentJob{},
 	}
 }
 
 func (worker *Worker) Perform() int {
 	select {
 	case job := <-worker.queue.Reserve(worker.ID):
+		worker.current.start(job)
+
 		go worker.beater.Beat(job)
 		defer worker.beater.Halt()
 		worker.callback(job)
 
+		if !worker.current.finish() {
+			return 1
+		}
+
 		if job.ShouldRetry {
 			worker.queue.Requeue(job)
+		} else if job.ShouldDeadLetter {
+			worker.queue.DeadLetter(job)
 		} else {
 			worker.queue.Dequeue(job)
 		}
@@ -48,14 +64,71 @@ func (worker *Worker) Perform() int {
 
 func (worker *Worker) Work() {
 	go func() {
+		defer close(worker.done)
+
 		for {
 			if worker.Perform() != 0 {
 				return
 			}
+
+			select {
+			case <-worker.halt:
+				return
+			default:
+			}
 		}
 	}()
 }
 
 func (worker *Worker) Halt() {
-	worker.halt <- true
+	worker.halting.Do(func() {
+		close(worker.halt)
+	})
+}
+
+// Wait blocks until the work loop has stopped or the timeout has elapsed.
+// A job that is still being performed when the timeout elapses is released
+// back to the queue, with its worker_id cleared, so that another worker can
+// pick it up without waiting for its reservation to expire. Its outcome is
+// then discarded. Wait is meant to be called as the process shuts down.
+func (worker *Worker) Wait(timeout time.Duration) bool {
+	select {
+	case <-worker.done:
+		return true
+	case <-time.After(timeout):
+		worker.current.release(worker.queue)
+		return false
+	}
+}
+
+type currentJob struct {
+	sync.Mutex
+	job *Job
+}
+
+func (current *currentJob) start(job *Job) {
+	current.Lock()
+	defer current.Unlock()
+
+	current.job = job
+}
+
+func (current *currentJob) finish() bool {
+	current.Lock()
+	defer current.Unlock()
+
+	performing := current.job != nil
+	current.job = nil
+
+	return performing
+}
+
+func (current *currentJob) release(queue QueueInterface) {
+	current.Lock()
+	defer current.Unlock()
+
+	if current.job != nil {
+		queue.Release(current.job)
+		current.job = nil
+	}
 }
@@ -97,6 +97,33 @@ var _ = Describe("Worker", func() {
 			Expect(retriedJob.ActiveAt).To(BeTemporally("~", time.Now().Add(1*time.Minute), 1*time.Minute))
 		})
 
+		It("moves jobs that are marked for dead lettering into the dead letters", func() {
+			callback = func(job *gobble.Job) {
+				job.DeadLetter("something went wrong")
+			}
+			worker = gobble.NewWorker(1, queue, callback, heartbeater)
+
+			job, err := queue.Enqueue(&gobble.Job{
+				Payload: "the-payload",
+			}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			worker.Perform()
+
+			results, err := database.Connection.Select(gobble.Job{}, "SELECT * FROM `jobs`")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(0))
+
+			deadLetters, err := database.Connection.Select(gobble.DeadLetter{}, "SELECT * FROM `dead_letters`")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadLetters).To(HaveLen(1))
+
+			deadLetter := deadLetters[0].(*gobble.DeadLetter)
+			Expect(deadLetter.JobID).To(Equal(job.ID))
+			Expect(deadLetter.Payload).To(Equal("the-payload"))
+			Expect(deadLetter.LastError).To(Equal("something went wrong"))
+		})
+
 		It("heartbeats for job ownership while the job executes", func() {
 			job, err := queue.Enqueue(&gobble.Job{
 				Payload: "the-payload",
@@ -150,4 +177,97 @@ var _ = Describe("Worker", func() {
 			worker.Halt()
 		})
 	})
+
+	Describe("Halt", func() {
+		It("does not block while the worker is busy", func() {
+			hold := make(chan struct{})
+			callback = func(*gobble.Job) {
+				<-hold
+			}
+			worker = gobble.NewWorker(1, queue, callback, &MockHeartbeater{})
+
+			_, err := queue.Enqueue(&gobble.Job{}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			worker.Work()
+
+			Eventually(func() (string, error) {
+				job := gobble.Job{}
+				err := database.Connection.SelectOne(&job, "SELECT * FROM `jobs`")
+				return job.WorkerID, err
+			}).Should(Equal(worker.ID))
+
+			queue.Close()
+			worker.Halt()
+			worker.Halt()
+
+			close(hold)
+			Expect(worker.Wait(5 * time.Second)).To(BeTrue())
+		})
+	})
+
+	Describe("Wait", func() {
+		var (
+			mockQueue *mocks.Queue
+			reserved  chan *gobble.Job
+			hold      chan struct{}
+			performed chan struct{}
+		)
+
+		BeforeEach(func() {
+			reserved = make(chan *gobble.Job, 1)
+			hold = make(chan struct{})
+			performed = make(chan struct{}, 1)
+
+			mockQueue = mocks.NewQueue()
+			mockQueue.ReserveCall.Returns.Chan = reserved
+
+			callback = func(*gobble.Job) {
+				performed <- struct{}{}
+				<-hold
+			}
+			worker = gobble.NewWorker(1, mockQueue, callback, &MockHeartbeater{})
+		})
+
+		It("waits for the job in flight to complete", func() {
+			job := &gobble.Job{ID: 42}
+			reserved <- job
+
+			worker.Work()
+			Eventually(performed).Should(Receive())
+
+			worker.Halt()
+
+			finished := make(chan bool, 1)
+			go func() {
+				finished <- worker.Wait(5 * time.Second)
+			}()
+			Consistently(finished).ShouldNot(Receive())
+
+			close(hold)
+
+			Eventually(finished).Should(Receive(BeTrue()))
+			Expect(mockQueue.DequeueCall.Receives.Job).To(Equal(job))
+			Expect(mockQueue.ReleaseCall.Receives.Job).To(BeNil())
+		})
+
+		It("releases the job in flight when the timeout elapses", func() {
+			job := &gobble.Job{ID: 42}
+			reserved <- job
+
+			worker.Work()
+			Eventually(performed).Should(Receive())
+
+			worker.Halt()
+
+			Expect(worker.Wait(10 * time.Millisecond)).To(BeFalse())
+			Expect(mockQueue.ReleaseCall.Receives.Job).To(Equal(job))
+
+			close(hold)
+
+			Expect(worker.Wait(5 * time.Second)).To(BeTrue())
+			Expect(mockQueue.DequeueCall.Receives.Job).To(BeNil())
+			Expect(mockQueue.RequeueCall.Receives.Job).To(BeNil())
+		})
+	})
 })
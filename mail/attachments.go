@@ -0,0 +1,67 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"path/filepath"
+)
+
+// Attachments are base64 encoded on the wire, which grows them by a third,
+// so these limits keep a message well within the 25MB accepted by most
+// mail servers.
+const (
+	MaxAttachments          = 10
+	MaxAttachmentSize       = 5 * 1024 * 1024
+	MaxAttachmentsTotalSize = 10 * 1024 * 1024
+)
+
+// ValidateAttachments checks the attachments against the size limits and
+// makes sure each one can be rendered.
+func ValidateAttachments(attachments []Attachment) error {
+	if len(attachments) > MaxAttachments {
+		return fmt.Errorf("too many attachments, the limit is %d", MaxAttachments)
+	}
+
+	total := 0
+	for _, attachment := range attachments {
+		if attachment.Filename == "" {
+			return errors.New("attachment is missing a filename")
+		}
+
+		if attachment.ContentType != "" {
+			if _, _, err := mime.ParseMediaType(attachment.ContentType); err != nil {
+				return fmt.Errorf("attachment %q has an invalid content type %q", attachment.Filename, attachment.ContentType)
+			}
+		}
+
+		if len(attachment.Content) > MaxAttachmentSize {
+			return fmt.Errorf("attachment %q is larger than the %d byte limit", attachment.Filename, MaxAttachmentSize)
+		}
+
+		total += len(attachment.Content)
+	}
+
+	if total > MaxAttachmentsTotalSize {
+		return fmt.Errorf("attachments are larger than the %d byte limit in total", MaxAttachmentsTotalSize)
+	}
+
+	return nil
+}
+
+// mediaType returns the attachment content type with the filename added as
+// the name parameter, which older clients use instead of the disposition.
+func (a Attachment) mediaType() string {
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(a.Filename))
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = "application/octet-stream", map[string]string{}
+	}
+	params["name"] = a.Filename
+
+	return mime.FormatMediaType(mediaType, params)
+}
@@ -0,0 +1,58 @@
+package mail_test
+
+import (
+	"bytes"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ValidateAttachments", func() {
+	It("accepts attachments within the limits", func() {
+		err := mail.ValidateAttachments([]mail.Attachment{
+			{Filename: "report.csv", ContentType: "text/csv", Content: []byte("a,b,c")},
+			{Filename: "invoice.pdf", Content: bytes.Repeat([]byte("a"), mail.MaxAttachmentSize)},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("rejects too many attachments", func() {
+		attachments := make([]mail.Attachment, mail.MaxAttachments+1)
+		for i := range attachments {
+			attachments[i] = mail.Attachment{Filename: "file.txt"}
+		}
+
+		err := mail.ValidateAttachments(attachments)
+		Expect(err).To(MatchError("too many attachments, the limit is 10"))
+	})
+
+	It("rejects attachments without a filename", func() {
+		err := mail.ValidateAttachments([]mail.Attachment{{Content: []byte("a")}})
+		Expect(err).To(MatchError("attachment is missing a filename"))
+	})
+
+	It("rejects invalid content types", func() {
+		err := mail.ValidateAttachments([]mail.Attachment{{Filename: "file.txt", ContentType: "text/"}})
+		Expect(err).To(MatchError(`attachment "file.txt" has an invalid content type "text/"`))
+	})
+
+	It("rejects attachments that are too large", func() {
+		err := mail.ValidateAttachments([]mail.Attachment{
+			{Filename: "huge.bin", Content: bytes.Repeat([]byte("a"), mail.MaxAttachmentSize+1)},
+		})
+		Expect(err).To(MatchError(`attachment "huge.bin" is larger than the 5242880 byte limit`))
+	})
+
+	It("rejects attachments that are too large in total", func() {
+		content := bytes.Repeat([]byte("a"), mail.MaxAttachmentSize)
+
+		err := mail.ValidateAttachments([]mail.Attachment{
+			{Filename: "one.bin", Content: content},
+			{Filename: "two.bin", Content: content},
+			{Filename: "three.bin", Content: []byte("a")},
+		})
+		Expect(err).To(MatchError("attachments are larger than the 10485760 byte limit in total"))
+	})
+})
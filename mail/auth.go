@@ -0,0 +1,112 @@
+package mail
+
+import (
+	"errors"
+	"net/smtp"
+	"strings"
+)
+
+// LoginAuth returns an smtp.Auth that implements the LOGIN mechanism, which
+// is required by servers such as Office 365 that do not offer PLAIN. Like
+// smtp.PlainAuth, it only sends credentials over TLS or to localhost.
+func LoginAuth(username, password, host string) smtp.Auth {
+	return &loginAuth{
+		username: username,
+		password: password,
+		host:     host,
+	}
+}
+
+type loginAuth struct {
+	username string
+	password string
+	host     string
+	step     int
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if err := checkEncrypted(server, a.host); err != nil {
+		return "", nil, err
+	}
+
+	a.step = 0
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+
+	prompt := strings.ToLower(strings.TrimSpace(string(fromServer)))
+	a.step++
+
+	switch {
+	case strings.HasPrefix(prompt, "username"):
+		return []byte(a.username), nil
+	case strings.HasPrefix(prompt, "password"):
+		return []byte(a.password), nil
+	case a.step == 1:
+		return []byte(a.username), nil
+	case a.step == 2:
+		return []byte(a.password), nil
+	default:
+		return nil, errors.New("unexpected server challenge")
+	}
+}
+
+// XOAUTH2Auth returns an smtp.Auth that authenticates with an OAuth 2.0
+// bearer token, as used by Gmail and Office 365. The token is taken from
+// the source each time a connection authenticates.
+func XOAUTH2Auth(username string, tokens TokenSource, host string) smtp.Auth {
+	return &xoauth2Auth{
+		username: username,
+		tokens:   tokens,
+		host:     host,
+	}
+}
+
+type xoauth2Auth struct {
+	username string
+	tokens   TokenSource
+	host     string
+}
+
+func (a *xoauth2Auth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if err := checkEncrypted(server, a.host); err != nil {
+		return "", nil, err
+	}
+
+	token, err := a.tokens.Token()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return "XOAUTH2", []byte("user=" + a.username + "\x01auth=Bearer " + token + "\x01\x01"), nil
+}
+
+func (a *xoauth2Auth) Next(fromServer []byte, more bool) ([]byte, error) {
+	// On failure the server sends a JSON error as a challenge and expects
+	// an empty response before it replies with the final error status.
+	if more {
+		return []byte{}, nil
+	}
+
+	return nil, nil
+}
+
+func checkEncrypted(server *smtp.ServerInfo, host string) error {
+	if server.TLS || isLocalhost(server.Name) {
+		if server.Name != host {
+			return errors.New("wrong host name")
+		}
+
+		return nil
+	}
+
+	return errors.New("unencrypted connection")
+}
+
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
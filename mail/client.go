@@ -4,7 +4,6 @@ import (
 	"crypto/tls"
 	"encoding/base64"
 	"errors"
-	"fmt"
 	"net"
 	"net/smtp"
 	"strings"
@@ -17,13 +16,22 @@ const (
 	AuthNone AuthMechanism = iota
 	AuthPlain
 	AuthCRAMMD5
+	AuthLogin
+	AuthXOAUTH2
 )
 
 type AuthMechanism int
 
+// Client delivers messages over a single SMTP connection. The connection is
+// kept open between calls to Send so that the handshake and authentication
+// are only performed once for up to MaxMessagesPerConnection messages. A
+// reused connection is checked with NOOP and reset with RSET before each
+// message, and is replaced if the server has dropped it.
 type Client struct {
-	config Config
-	client *smtp.Client
+	config      Config
+	client      *smtp.Client
+	established bool
+	sent        int
 }
 
 type Config struct {
@@ -32,12 +40,18 @@ type Config struct {
 	User           string
 	Pass           string
 	Secret         string
+	Token          string
+	TokenSource    TokenSource
+	EnvelopeFrom   string
 	AuthMechanism  AuthMechanism
 	TestMode       bool
 	SkipVerifySSL  bool
 	DisableTLS     bool
+	ImplicitTLS    bool
 	ConnectTimeout time.Duration
 	LoggingEnabled bool
+
+	MaxMessagesPerConnection int
 }
 
 type connection struct {
@@ -52,6 +66,10 @@ func NewClient(config Config) *Client {
 		client.config.ConnectTimeout = 15 * time.Second
 	}
 
+	if client.config.MaxMessagesPerConnection == 0 {
+		client.config.MaxMessagesPerConnection = 1
+	}
+
 	return client
 }
 
@@ -97,7 +115,7 @@ func (c *Client) connect() chan connection {
 	channel := make(chan connection)
 
 	go func() {
-		client, err := smtp.Dial(net.JoinHostPort(c.config.Host, c.config.Port))
+		client, err := c.dial()
 		channel <- connection{
 			client: client,
 			err:    err,
@@ -107,6 +125,37 @@ func (c *Client) connect() chan connection {
 	return channel
 }
 
+// dial opens the connection to the server. With ImplicitTLS (SMTPS,
+// usually port 465) the TLS handshake happens before the SMTP greeting
+// instead of being negotiated with STARTTLS.
+func (c *Client) dial() (*smtp.Client, error) {
+	address := net.JoinHostPort(c.config.Host, c.config.Port)
+
+	if !c.config.ImplicitTLS {
+		return smtp.Dial(address)
+	}
+
+	conn, err := tls.Dial("tcp", address, c.tlsConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := smtp.NewClient(conn, c.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (c *Client) tlsConfig() *tls.Config {
+	return &tls.Config{
+		ServerName:         c.config.Host,
+		InsecureSkipVerify: c.config.SkipVerifySSL,
+	}
+}
+
 func (c *Client) Send(msg Message, logger lager.Logger) error {
 	logger = c.createLoggerSession(logger)
 
@@ -115,53 +164,60 @@ func (c *Client) Send(msg Message, logger lager.Logger) error {
 		return nil
 	}
 
-	err := c.Connect(logger)
-	if err != nil {
-		return c.Error(logger, err)
+	if c.established {
+		c.Reuse(logger)
 	}
 
-	c.PrintLog(logger, "hello-initiating")
-	err = c.Hello()
+	err := c.Connect(logger)
 	if err != nil {
 		return c.Error(logger, err)
 	}
-	c.PrintLog(logger, "hello-complete")
 
-	if !c.config.DisableTLS {
-		c.PrintLog(logger, "tls-starting")
-		err = c.StartTLS()
+	if !c.established {
+		err = c.Establish(logger)
 		if err != nil {
 			return c.Error(logger, err)
 		}
-		c.PrintLog(logger, "tls-connected")
+	}
 
-		c.PrintLog(logger, "authentication-starting")
-		err = c.Auth(logger)
-		if err != nil {
-			return c.Error(logger, err)
-		}
-		c.PrintLog(logger, "authenticated")
+	// The envelope sender stays the configured sender whatever the From
+	// header says, so that bounces reach the bounce mailbox.
+	from := msg.EnvelopeFrom()
+	if c.config.EnvelopeFrom != "" {
+		from = envelopeFrom(c.config.EnvelopeFrom)
 	}
 
-	c.PrintLog(logger, "setting-msg-from", lager.Data{"from": msg.From})
-	err = c.client.Mail(msg.From)
+	c.PrintLog(logger, "setting-msg-from", lager.Data{"from": from})
+	err = c.client.Mail(from)
 	if err != nil {
 		return c.Error(logger, err)
 	}
 
-	c.PrintLog(logger, "setting-msg-to", lager.Data{"to": msg.To})
-	err = c.client.Rcpt(msg.To)
-	if err != nil {
-		return c.Error(logger, err)
+	// Every recipient is added to the same transaction, so a rejected
+	// recipient fails the whole message rather than a partial delivery.
+	for _, recipient := range msg.Recipients() {
+		c.PrintLog(logger, "setting-msg-to", lager.Data{"to": recipient})
+		err = c.client.Rcpt(recipient)
+		if err != nil {
+			return c.Error(logger, err)
+		}
 	}
 
-	c.PrintLog(logger, "setting-msg-data", lager.Data{"message-data": base64.StdEncoding.EncodeToString([]byte(msg.Data()))})
+	if c.config.LoggingEnabled {
+		c.PrintLog(logger, "setting-msg-data", lager.Data{"message-data": base64.StdEncoding.EncodeToString([]byte(msg.Data()))})
+	}
 	err = c.Data(msg)
 	if err != nil {
 		return c.Error(logger, err)
 	}
 	c.PrintLog(logger, "msg-data-sent")
 
+	c.sent++
+	if c.sent < c.config.MaxMessagesPerConnection {
+		c.PrintLog(logger, "connection-kept-alive", lager.Data{"messages-sent": c.sent})
+		return nil
+	}
+
 	c.PrintLog(logger, "quiting")
 	err = c.Quit()
 	if err != nil {
@@ -172,6 +228,90 @@ func (c *Client) Send(msg Message, logger lager.Logger) error {
 	return nil
 }
 
+func (c *Client) Establish(logger lager.Logger) error {
+	c.PrintLog(logger, "hello-initiating")
+	err := c.Hello()
+	if err != nil {
+		return err
+	}
+	c.PrintLog(logger, "hello-complete")
+
+	if c.config.ImplicitTLS || !c.config.DisableTLS {
+		if !c.config.ImplicitTLS {
+			c.PrintLog(logger, "tls-starting")
+			err = c.StartTLS()
+			if err != nil {
+				return err
+			}
+			c.PrintLog(logger, "tls-connected")
+		}
+
+		c.PrintLog(logger, "authentication-starting")
+		err = c.Auth(logger)
+		if err != nil {
+			return err
+		}
+		c.PrintLog(logger, "authenticated")
+	}
+
+	c.established = true
+
+	return nil
+}
+
+// Reuse prepares an established connection for the next message. If the
+// server no longer responds, the connection is discarded so that the next
+// call to Connect opens a new one.
+func (c *Client) Reuse(logger lager.Logger) {
+	err := c.Noop()
+	if err == nil {
+		err = c.Reset()
+	}
+
+	if err != nil {
+		c.PrintLog(logger, "connection-stale", lager.Data{"error": err.Error()})
+		c.Discard()
+		return
+	}
+
+	c.PrintLog(logger, "connection-reused", lager.Data{"messages-sent": c.sent})
+}
+
+func (c *Client) Noop() error {
+	return c.command(250, "NOOP")
+}
+
+func (c *Client) Reset() error {
+	return c.command(250, "RSET")
+}
+
+func (c *Client) command(expectCode int, format string, args ...interface{}) error {
+	id, err := c.client.Text.Cmd(format, args...)
+	if err != nil {
+		return err
+	}
+
+	c.client.Text.StartResponse(id)
+	defer c.client.Text.EndResponse(id)
+
+	_, _, err = c.client.Text.ReadResponse(expectCode)
+	return err
+}
+
+func (c *Client) Discard() {
+	if c.client != nil {
+		c.client.Close()
+	}
+
+	c.forget()
+}
+
+func (c *Client) forget() {
+	c.client = nil
+	c.established = false
+	c.sent = 0
+}
+
 func (c *Client) Hello() error {
 	err := c.client.Hello("localhost")
 	if err != nil {
@@ -187,10 +327,7 @@ func (c *Client) Extension(name string) (bool, string) {
 
 func (c *Client) StartTLS() error {
 	if ok, _ := c.Extension("STARTTLS"); ok {
-		err := c.client.StartTLS(&tls.Config{
-			ServerName:         c.config.Host,
-			InsecureSkipVerify: c.config.SkipVerifySSL,
-		})
+		err := c.client.StartTLS(c.tlsConfig())
 		if err != nil {
 			return err
 		}
@@ -220,6 +357,16 @@ func (c *Client) AuthMechanism(logger lager.Logger) smtp.Auth {
 	case AuthPlain:
 		c.PrintLog(logger, "plain-authentication")
 		return smtp.PlainAuth("", c.config.User, c.config.Pass, c.config.Host)
+	case AuthLogin:
+		c.PrintLog(logger, "login-authentication")
+		return LoginAuth(c.config.User, c.config.Pass, c.config.Host)
+	case AuthXOAUTH2:
+		c.PrintLog(logger, "xoauth2-authentication")
+		tokens := c.config.TokenSource
+		if tokens == nil {
+			tokens = StaticToken(c.config.Token)
+		}
+		return XOAUTH2Auth(c.config.User, tokens, c.config.Host)
 	default:
 		c.PrintLog(logger, "no-authentication")
 		return nil
@@ -232,8 +379,7 @@ func (c *Client) Data(msg Message) error {
 		return err
 	}
 
-	data := strings.Replace(string(msg.Data()), "%", "%%", -1)
-	_, err = fmt.Fprintf(wc, data)
+	_, err = msg.WriteTo(wc)
 	if err != nil {
 		return err
 	}
@@ -248,11 +394,14 @@ func (c *Client) Data(msg Message) error {
 
 func (c *Client) Quit() error {
 	err := c.client.Quit()
-	c.client = nil
 	if err != nil {
+		c.client.Close()
+		c.forget()
 		return err
 	}
 
+	c.forget()
+
 	return nil
 }
 
@@ -260,13 +409,13 @@ func (c *Client) Error(logger lager.Logger, err error) error {
 	if c.client != nil {
 		failure := c.Quit()
 		if failure != nil {
-			return failure
+			return NewSMTPError(failure)
 		}
 	}
 
 	logger.Error("failed", err)
 
-	return err
+	return NewSMTPError(err)
 }
 
 func (c *Client) PrintLog(logger lager.Logger, action string, data ...lager.Data) {
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/testing/servers"
 	"github.com/pivotal-golang/lager"
 
 	. "github.com/onsi/ginkgo"
@@ -87,6 +88,14 @@ var _ = Describe("Mail", func() {
 
 			Expect(client.ConnectTimeout()).To(Equal(15 * time.Second))
 		})
+
+		It("defaults the MaxMessagesPerConnection to 1", func() {
+			config.MaxMessagesPerConnection = 0
+
+			client = mail.NewClient(config)
+
+			Expect(client.MaxMessagesPerConnection()).To(Equal(1))
+		})
 	})
 
 	Describe("Send", func() {
@@ -190,6 +199,7 @@ var _ = Describe("Mail", func() {
 					},
 				},
 			}
+			data := msg.Data()
 
 			err := client.Send(msg, logger)
 			if err != nil {
@@ -203,10 +213,60 @@ var _ = Describe("Mail", func() {
 
 			Expect(delivery.Sender).To(Equal("me@example.com"))
 			Expect(delivery.Recipient).To(Equal("you@example.com"))
-			Expect(delivery.Data).To(Equal(strings.Split(msg.Data(), "\n")))
+			Expect(delivery.Data).To(Equal(strings.Split(data, "\n")))
 			Expect(delivery.UsedTLS).To(BeTrue())
 		})
 
+		It("sends from the bare sender address when the sender has a display name", func() {
+			msg := mail.Message{
+				From:    "Notifications <me@example.com>",
+				To:      "you@example.com",
+				Subject: "Urgent! Read now!",
+				Body: []mail.Part{
+					{
+						ContentType: "text/plain",
+						Content:     "This email is the most important thing you will read all day!",
+					},
+				},
+			}
+
+			err := client.Send(msg, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int {
+				return len(mailServer.Deliveries)
+			}).Should(Equal(1))
+
+			Expect(mailServer.Deliveries[0].Sender).To(Equal("me@example.com"))
+		})
+
+		It("sends from the configured envelope sender whatever the From header says", func() {
+			config.EnvelopeFrom = "Notifications <bounces@example.com>"
+			client = mail.NewClient(config)
+
+			msg := mail.Message{
+				From:    "Some Team <team@example.com>",
+				To:      "you@example.com",
+				Subject: "Urgent! Read now!",
+				Body: []mail.Part{
+					{
+						ContentType: "text/plain",
+						Content:     "This email is the most important thing you will read all day!",
+					},
+				},
+			}
+
+			err := client.Send(msg, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int {
+				return len(mailServer.Deliveries)
+			}).Should(Equal(1))
+
+			Expect(mailServer.Deliveries[0].Sender).To(Equal("bounces@example.com"))
+			Expect(mailServer.Deliveries[0].Data).To(ContainElement(`From: "Some Team" <team@example.com>`))
+		})
+
 		It("can make multiple requests", func() {
 			firstMsg := mail.Message{
 				From:    "me@example.com",
@@ -219,6 +279,7 @@ var _ = Describe("Mail", func() {
 					},
 				},
 			}
+			firstData := firstMsg.Data()
 
 			err := client.Send(firstMsg, logger)
 			if err != nil {
@@ -232,7 +293,7 @@ var _ = Describe("Mail", func() {
 
 			Expect(delivery.Sender).To(Equal("me@example.com"))
 			Expect(delivery.Recipient).To(Equal("you@example.com"))
-			Expect(delivery.Data).To(Equal(strings.Split(firstMsg.Data(), "\n")))
+			Expect(delivery.Data).To(Equal(strings.Split(firstData, "\n")))
 
 			secondMsg := mail.Message{
 				From:    "first@example.com",
@@ -245,6 +306,7 @@ var _ = Describe("Mail", func() {
 					},
 				},
 			}
+			secondData := secondMsg.Data()
 
 			err = client.Send(secondMsg, logger)
 			if err != nil {
@@ -258,7 +320,40 @@ var _ = Describe("Mail", func() {
 
 			Expect(delivery.Sender).To(Equal("first@example.com"))
 			Expect(delivery.Recipient).To(Equal("second@example.com"))
-			Expect(delivery.Data).To(Equal(strings.Split(secondMsg.Data(), "\n")))
+			Expect(delivery.Data).To(Equal(strings.Split(secondData, "\n")))
+		})
+
+		It("adds every To, Cc and Bcc recipient to one transaction", func() {
+			msg := mail.Message{
+				From:    "me@example.com",
+				To:      "you@example.com, Team <team@example.com>",
+				Cc:      []string{"Shared Inbox <shared@example.com>", "TEAM@example.com"},
+				Bcc:     []string{"audit@example.com"},
+				Subject: "Urgent! Read now!",
+				Body: []mail.Part{
+					{
+						ContentType: "text/plain",
+						Content:     "This email is the most important thing you will read all day!",
+					},
+				},
+			}
+
+			err := client.Send(msg, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int {
+				return len(mailServer.Deliveries)
+			}).Should(Equal(1))
+			delivery := mailServer.Deliveries[0]
+
+			Expect(delivery.Recipients).To(Equal([]string{
+				"you@example.com",
+				"team@example.com",
+				"shared@example.com",
+				"audit@example.com",
+			}))
+			Expect(delivery.Data).To(ContainElement(`Cc: "Shared Inbox" <shared@example.com>, TEAM@example.com`))
+			Expect(strings.Join(delivery.Data, "\n")).NotTo(ContainSubstring("audit@example.com"))
 		})
 
 		Context("when configured to use TLS", func() {
@@ -326,6 +421,234 @@ var _ = Describe("Mail", func() {
 				Expect(delivery.UsedTLS).To(BeFalse())
 			})
 		})
+
+		Context("when configured to use implicit TLS", func() {
+			BeforeEach(func() {
+				mailServer.ImplicitTLS = true
+				config.ImplicitTLS = true
+				config.AuthMechanism = mail.AuthPlain
+				client = mail.NewClient(config)
+			})
+
+			It("negotiates TLS before the SMTP greeting and authenticates", func() {
+				msg := mail.Message{
+					From:    "me@example.com",
+					To:      "you@example.com",
+					Subject: "Urgent! Read now!",
+					Body: []mail.Part{
+						{
+							ContentType: "text/plain",
+							Content:     "This email is the most important thing you will read all day!",
+						},
+					},
+				}
+
+				err := client.Send(msg, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(func() int {
+					return len(mailServer.Deliveries)
+				}).Should(Equal(1))
+				delivery := mailServer.Deliveries[0]
+				Expect(delivery.UsedTLS).To(BeTrue())
+				Expect(delivery.AuthMechanism).To(Equal("PLAIN"))
+				Expect(delivery.Recipient).To(Equal("you@example.com"))
+			})
+
+			It("ignores the DisableTLS setting", func() {
+				config.DisableTLS = true
+				client = mail.NewClient(config)
+
+				err := client.Send(mail.Message{From: "me@example.com", To: "you@example.com"}, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(func() int {
+					return len(mailServer.Deliveries)
+				}).Should(Equal(1))
+				Expect(mailServer.Deliveries[0].UsedTLS).To(BeTrue())
+				Expect(mailServer.Deliveries[0].AuthMechanism).To(Equal("PLAIN"))
+			})
+		})
+
+		Context("when configured to use LOGIN auth", func() {
+			BeforeEach(func() {
+				config.AuthMechanism = mail.AuthLogin
+				client = mail.NewClient(config)
+			})
+
+			It("answers the username and password prompts", func() {
+				err := client.Send(mail.Message{From: "me@example.com", To: "you@example.com"}, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(func() int {
+					return len(mailServer.Deliveries)
+				}).Should(Equal(1))
+				delivery := mailServer.Deliveries[0]
+				Expect(delivery.UsedTLS).To(BeTrue())
+				Expect(delivery.AuthMechanism).To(Equal("LOGIN"))
+				Expect(delivery.AuthCredentials).To(Equal([]string{"user", "pass"}))
+			})
+		})
+
+		Context("when configured to use XOAUTH2 auth", func() {
+			BeforeEach(func() {
+				mailServer.ImplicitTLS = true
+				config.ImplicitTLS = true
+				config.AuthMechanism = mail.AuthXOAUTH2
+				config.Token = "some-access-token"
+				client = mail.NewClient(config)
+			})
+
+			It("sends the user and bearer token", func() {
+				err := client.Send(mail.Message{From: "me@example.com", To: "you@example.com"}, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(func() int {
+					return len(mailServer.Deliveries)
+				}).Should(Equal(1))
+				delivery := mailServer.Deliveries[0]
+				Expect(delivery.UsedTLS).To(BeTrue())
+				Expect(delivery.AuthMechanism).To(Equal("XOAUTH2"))
+				Expect(delivery.AuthCredentials).To(Equal([]string{"user=user\x01auth=Bearer some-access-token\x01\x01"}))
+			})
+
+			Context("when a token source is configured", func() {
+				It("sends the token from the source", func() {
+					config.TokenSource = mail.StaticToken("some-refreshed-token")
+					client = mail.NewClient(config)
+
+					err := client.Send(mail.Message{From: "me@example.com", To: "you@example.com"}, logger)
+					Expect(err).NotTo(HaveOccurred())
+
+					Eventually(func() int {
+						return len(mailServer.Deliveries)
+					}).Should(Equal(1))
+					Expect(mailServer.Deliveries[0].AuthCredentials).To(Equal([]string{"user=user\x01auth=Bearer some-refreshed-token\x01\x01"}))
+				})
+			})
+		})
+
+		Context("when the server rejects the recipient", func() {
+			BeforeEach(func() {
+				mailServer.RcptToReply = "550 5.1.1 mailbox does not exist"
+			})
+
+			It("returns an SMTPError describing the rejection", func() {
+				msg := mail.Message{
+					From:    "me@example.com",
+					To:      "nobody@example.com",
+					Subject: "Urgent! Read now!",
+					Body: []mail.Part{
+						{
+							ContentType: "text/plain",
+							Content:     "This email is the most important thing you will read all day!",
+						},
+					},
+				}
+
+				err := client.Send(msg, logger)
+				Expect(err).To(Equal(mail.SMTPError{
+					Code:           550,
+					EnhancedStatus: "5.1.1",
+					Message:        "5.1.1 mailbox does not exist",
+				}))
+				Expect(mail.IsPermanent(err)).To(BeTrue())
+			})
+		})
+	})
+
+	Context("when keeping connections alive", func() {
+		var (
+			smtpServer *servers.SMTP
+			msg        mail.Message
+		)
+
+		BeforeEach(func() {
+			var err error
+
+			smtpServer = servers.NewSMTP()
+			smtpServer.Boot()
+
+			config.Host, config.Port, err = net.SplitHostPort(smtpServer.Addr())
+			if err != nil {
+				panic(err)
+			}
+
+			config.MaxMessagesPerConnection = 3
+			client = mail.NewClient(config)
+
+			msg = mail.Message{
+				From:    "me@example.com",
+				To:      "you@example.com",
+				Subject: "Urgent! Read now!",
+				Body: []mail.Part{
+					{
+						ContentType: "text/plain",
+						Content:     "This email is the most important thing you will read all day!",
+					},
+				},
+			}
+		})
+
+		AfterEach(func() {
+			smtpServer.Close()
+		})
+
+		It("sends consecutive messages over the same connection", func() {
+			for i := 0; i < 3; i++ {
+				Expect(client.Send(msg, logger)).To(Succeed())
+			}
+
+			Expect(smtpServer.Deliveries).To(HaveLen(3))
+			Expect(smtpServer.ConnectionCount()).To(Equal(1))
+		})
+
+		It("opens a new connection once the per-connection message limit is reached", func() {
+			for i := 0; i < 4; i++ {
+				Expect(client.Send(msg, logger)).To(Succeed())
+			}
+
+			Expect(smtpServer.Deliveries).To(HaveLen(4))
+			Expect(smtpServer.ConnectionCount()).To(Equal(2))
+		})
+
+		It("reconnects when the server has dropped the connection", func() {
+			Expect(client.Send(msg, logger)).To(Succeed())
+
+			smtpServer.DropConnections()
+
+			Expect(client.Send(msg, logger)).To(Succeed())
+			Expect(smtpServer.Deliveries).To(HaveLen(2))
+			Expect(smtpServer.ConnectionCount()).To(Equal(2))
+		})
+
+		It("logs when a stale connection is replaced", func() {
+			config.LoggingEnabled = true
+			client = mail.NewClient(config)
+
+			Expect(client.Send(msg, logger)).To(Succeed())
+			smtpServer.DropConnections()
+			Expect(client.Send(msg, logger)).To(Succeed())
+
+			lines, err := parseLogLines(buffer.Bytes())
+			Expect(err).NotTo(HaveOccurred())
+
+			var messages []string
+			for _, line := range lines {
+				messages = append(messages, line.Message)
+			}
+			Expect(messages).To(ContainElement("notifications.smtp.connection-stale"))
+		})
+
+		It("opens a new connection after a failed delivery", func() {
+			smtpServer.HandlerCall.Returns.Error = errors.New("mailbox full")
+			Expect(client.Send(msg, logger)).NotTo(Succeed())
+
+			smtpServer.HandlerCall.Returns.Error = nil
+			Expect(client.Send(msg, logger)).To(Succeed())
+
+			Expect(smtpServer.ConnectionCount()).To(Equal(2))
+		})
 	})
 
 	Describe("Connect", func() {
@@ -445,6 +768,34 @@ var _ = Describe("Mail", func() {
 			})
 		})
 
+		Context("when configured to use LOGIN auth", func() {
+			BeforeEach(func() {
+				config.AuthMechanism = mail.AuthLogin
+				client = mail.NewClient(config)
+			})
+
+			It("creates a LoginAuth strategy", func() {
+				auth := mail.LoginAuth(config.User, config.Pass, config.Host)
+				mechanism := client.AuthMechanism(logger)
+
+				Expect(mechanism).To(BeAssignableToTypeOf(auth))
+			})
+		})
+
+		Context("when configured to use XOAUTH2 auth", func() {
+			BeforeEach(func() {
+				config.AuthMechanism = mail.AuthXOAUTH2
+				client = mail.NewClient(config)
+			})
+
+			It("creates an XOAUTH2Auth strategy", func() {
+				auth := mail.XOAUTH2Auth(config.User, mail.StaticToken(config.Token), config.Host)
+				mechanism := client.AuthMechanism(logger)
+
+				Expect(mechanism).To(BeAssignableToTypeOf(auth))
+			})
+		})
+
 		Context("when configured to use no auth", func() {
 			BeforeEach(func() {
 				config.AuthMechanism = mail.AuthNone
@@ -0,0 +1,263 @@
+package mail
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	netmail "net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pivotal-golang/lager"
+)
+
+var DKIMSignedHeaders = []string{
+	"From",
+	"Reply-To",
+	"To",
+	"Cc",
+	"Subject",
+	"Date",
+	"Message-ID",
+	"Mime-Version",
+	"Content-Type",
+	"Content-Transfer-Encoding",
+	"List-Unsubscribe",
+	"List-Unsubscribe-Post",
+}
+
+var whitespacePattern = regexp.MustCompile(`[ \t]+`)
+
+// DKIMSigner produces DKIM-Signature headers for a single signing domain
+// using relaxed/relaxed canonicalization. RSA keys sign with rsa-sha256 and
+// Ed25519 keys sign with ed25519-sha256 (RFC 8463).
+type DKIMSigner struct {
+	Domain   string
+	Selector string
+
+	key       crypto.Signer
+	algorithm string
+	now       func() time.Time
+}
+
+func NewDKIMSigner(domain, selector string, privateKeyPEM []byte) (*DKIMSigner, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+
+	var (
+		key interface{}
+		err error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported private key type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer := &DKIMSigner{
+		Domain:   strings.ToLower(domain),
+		Selector: selector,
+		now:      time.Now,
+	}
+
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		signer.key = key
+		signer.algorithm = "rsa-sha256"
+	case ed25519.PrivateKey:
+		signer.key = key
+		signer.algorithm = "ed25519-sha256"
+	default:
+		return nil, errors.New("private key must be an RSA or Ed25519 key")
+	}
+
+	return signer, nil
+}
+
+// Sign returns a DKIM-Signature header, including the trailing newline,
+// that can be prepended to the given message.
+func (s *DKIMSigner) Sign(data string) (string, error) {
+	headers, body := splitMessage(data)
+
+	bodyHash := sha256.Sum256([]byte(relaxedBody(body)))
+
+	var signedNames []string
+	hash := sha256.New()
+	used := map[int]bool{}
+	for _, name := range DKIMSignedHeaders {
+		index := lastHeader(headers, name, used)
+		if index < 0 {
+			continue
+		}
+		used[index] = true
+
+		signedNames = append(signedNames, strings.ToLower(name))
+		hash.Write([]byte(relaxedHeader(headers[index]) + "\r\n"))
+	}
+
+	tags := []string{
+		"v=1",
+		"a=" + s.algorithm,
+		"c=relaxed/relaxed",
+		"d=" + s.Domain,
+		"s=" + s.Selector,
+		fmt.Sprintf("t=%d", s.now().Unix()),
+		"h=" + strings.Join(signedNames, ":"),
+		"bh=" + base64.StdEncoding.EncodeToString(bodyHash[:]),
+		"b=",
+	}
+	header := "DKIM-Signature: " + strings.Join(tags, "; ")
+	hash.Write([]byte(relaxedHeader(header)))
+
+	var (
+		signature []byte
+		err       error
+	)
+	switch key := s.key.(type) {
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(key, hash.Sum(nil))
+	default:
+		signature, err = s.key.Sign(rand.Reader, hash.Sum(nil), crypto.SHA256)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return foldSignature(header, base64.StdEncoding.EncodeToString(signature)) + "\n", nil
+}
+
+// DKIMSigners holds a signer for each sending domain.
+type DKIMSigners map[string]*DKIMSigner
+
+func (signers DKIMSigners) For(from string) (*DKIMSigner, bool) {
+	address := from
+	if parsed, err := netmail.ParseAddress(from); err == nil {
+		address = parsed.Address
+	}
+
+	at := strings.LastIndex(address, "@")
+	if at < 0 {
+		return nil, false
+	}
+
+	signer, ok := signers[strings.ToLower(address[at+1:])]
+	return signer, ok
+}
+
+// SigningTransport signs every message sent from a domain that has a DKIM
+// signer before handing it to the underlying transport.
+type SigningTransport struct {
+	Transport
+	signers DKIMSigners
+}
+
+func NewSigningTransport(transport Transport, signers DKIMSigners) SigningTransport {
+	return SigningTransport{
+		Transport: transport,
+		signers:   signers,
+	}
+}
+
+func (t SigningTransport) Send(msg Message, logger lager.Logger) error {
+	if signer, ok := t.signers.For(msg.From); ok {
+		msg.DKIMSigner = signer
+	}
+
+	return t.Transport.Send(msg, logger)
+}
+
+func splitMessage(data string) ([]string, string) {
+	data = strings.Replace(data, "\r\n", "\n", -1)
+
+	head, body := data, ""
+	if index := strings.Index(data, "\n\n"); index >= 0 {
+		head, body = data[:index], data[index+2:]
+	}
+
+	var headers []string
+	for _, line := range strings.Split(head, "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(headers) > 0 {
+			headers[len(headers)-1] += "\n" + line
+			continue
+		}
+
+		headers = append(headers, line)
+	}
+
+	return headers, body
+}
+
+func lastHeader(headers []string, name string, used map[int]bool) int {
+	for i := len(headers) - 1; i >= 0; i-- {
+		if used[i] {
+			continue
+		}
+
+		colon := strings.Index(headers[i], ":")
+		if colon >= 0 && strings.EqualFold(strings.TrimSpace(headers[i][:colon]), name) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func relaxedHeader(header string) string {
+	colon := strings.Index(header, ":")
+	if colon < 0 {
+		return header
+	}
+
+	name := strings.ToLower(strings.TrimRight(header[:colon], " \t"))
+
+	value := strings.Replace(header[colon+1:], "\r\n", "", -1)
+	value = strings.Replace(value, "\n", "", -1)
+	value = whitespacePattern.ReplaceAllString(value, " ")
+	value = strings.TrimSpace(value)
+
+	return name + ":" + value
+}
+
+func relaxedBody(body string) string {
+	lines := strings.Split(strings.Replace(body, "\r\n", "\n", -1), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(whitespacePattern.ReplaceAllString(line, " "), " ")
+	}
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+func foldSignature(header, signature string) string {
+	folded := strings.Replace(header, "; ", ";\n\t", -1)
+
+	for len(signature) > 64 {
+		folded += signature[:64] + "\n\t"
+		signature = signature[64:]
+	}
+
+	return folded + signature
+}
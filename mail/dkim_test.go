@@ -0,0 +1,346 @@
+package mail_test
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func rsaKeyPEM() ([]byte, crypto.PublicKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		panic(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}), key.Public()
+}
+
+func ed25519KeyPEM() ([]byte, crypto.PublicKey) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		panic(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: der,
+	}), public
+}
+
+var signatureValuePattern = regexp.MustCompile(`(^|;)(\s*b=)[^;]*`)
+
+// canonicalizeHeader applies the "relaxed" header canonicalization from
+// RFC 6376 section 3.4.2. It is written independently of the signer so that
+// a canonicalization bug cannot show up on both sides of the check.
+func canonicalizeHeader(header string) string {
+	colon := strings.IndexByte(header, ':')
+	name := strings.ToLower(strings.TrimRight(header[:colon], " \t"))
+
+	var value []byte
+	space := false
+	for _, c := range []byte(header[colon+1:]) {
+		switch c {
+		case '\r', '\n':
+		case ' ', '\t':
+			space = true
+		default:
+			if space && len(value) > 0 {
+				value = append(value, ' ')
+			}
+			space = false
+			value = append(value, c)
+		}
+	}
+
+	return name + ":" + string(value)
+}
+
+// canonicalizeBody applies the "relaxed" body canonicalization from RFC 6376
+// section 3.4.4.
+func canonicalizeBody(body string) string {
+	var canonical string
+	empty := 0
+	for _, line := range strings.Split(strings.TrimSuffix(strings.Replace(body, "\r\n", "\n", -1), "\n"), "\n") {
+		var reduced []byte
+		space := false
+		for _, c := range []byte(line) {
+			if c == ' ' || c == '\t' {
+				space = true
+				continue
+			}
+			if space {
+				reduced = append(reduced, ' ')
+			}
+			space = false
+			reduced = append(reduced, c)
+		}
+
+		if len(reduced) == 0 {
+			empty++
+			continue
+		}
+
+		canonical += strings.Repeat("\r\n", empty) + string(reduced) + "\r\n"
+		empty = 0
+	}
+
+	return canonical
+}
+
+// verifyDKIM checks the first DKIM-Signature header of the message the way
+// a receiving server would, using the given public key in place of the DNS
+// lookup.
+func verifyDKIM(data string, publicKey crypto.PublicKey) error {
+	parts := strings.SplitN(data, "\n\n", 2)
+	if len(parts) != 2 {
+		return errors.New("message has no body")
+	}
+
+	var headers []string
+	for _, line := range strings.Split(parts[0], "\n") {
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			headers[len(headers)-1] += "\n" + line
+			continue
+		}
+		headers = append(headers, line)
+	}
+
+	if !strings.HasPrefix(headers[0], "DKIM-Signature:") {
+		return errors.New("message is not signed")
+	}
+	signatureHeader := headers[0]
+
+	tags := map[string]string{}
+	value := strings.TrimPrefix(canonicalizeHeader(signatureHeader), "dkim-signature:")
+	for _, tag := range strings.Split(value, ";") {
+		pair := strings.SplitN(strings.TrimSpace(tag), "=", 2)
+		tags[pair[0]] = strings.Replace(pair[1], " ", "", -1)
+	}
+
+	if tags["c"] != "relaxed/relaxed" {
+		return errors.New("unexpected canonicalization " + tags["c"])
+	}
+
+	bodyHash := sha256.Sum256([]byte(canonicalizeBody(parts[1])))
+	if base64.StdEncoding.EncodeToString(bodyHash[:]) != tags["bh"] {
+		return errors.New("body hash did not verify")
+	}
+
+	hash := sha256.New()
+	used := map[int]bool{}
+	for _, name := range strings.Split(tags["h"], ":") {
+		for i := len(headers) - 1; i > 0; i-- {
+			if used[i] || !strings.HasPrefix(canonicalizeHeader(headers[i]), name+":") {
+				continue
+			}
+
+			used[i] = true
+			hash.Write([]byte(canonicalizeHeader(headers[i]) + "\r\n"))
+			break
+		}
+	}
+	hash.Write([]byte(canonicalizeHeader(signatureValuePattern.ReplaceAllString(signatureHeader, "$1$2"))))
+	digest := hash.Sum(nil)
+
+	signature, err := base64.StdEncoding.DecodeString(tags["b"])
+	if err != nil {
+		return err
+	}
+
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		if tags["a"] != "rsa-sha256" {
+			return errors.New("unexpected algorithm " + tags["a"])
+		}
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, signature)
+	case ed25519.PublicKey:
+		if tags["a"] != "ed25519-sha256" {
+			return errors.New("unexpected algorithm " + tags["a"])
+		}
+		if !ed25519.Verify(key, digest, signature) {
+			return errors.New("signature did not verify")
+		}
+		return nil
+	}
+
+	return errors.New("unsupported key")
+}
+
+var _ = Describe("DKIM", func() {
+	var msg mail.Message
+
+	BeforeEach(func() {
+		msg = mail.Message{
+			From:    "no-reply@notifications.example.com",
+			ReplyTo: "support@example.com",
+			To:      "you@example.com",
+			Subject: "Urgent!   Read now!",
+			Headers: []string{"X-CF-Client-ID: some-client"},
+			Body: []mail.Part{
+				{
+					ContentType: "text/plain",
+					Content:     "This email is the most important thing you will read all day!  \n\n\n",
+				},
+				{
+					ContentType: "text/html",
+					Content:     "<p>This email is the most important thing you will read all day!</p>",
+				},
+			},
+		}
+	})
+
+	It("still verifies after whitespace changes that relaxed canonicalization ignores", func() {
+		privateKey, publicKey := rsaKeyPEM()
+
+		signer, err := mail.NewDKIMSigner("notifications.example.com", "rsa", privateKey)
+		Expect(err).NotTo(HaveOccurred())
+		msg.DKIMSigner = signer
+
+		data := msg.Data()
+		data = strings.Replace(data, "Subject: Urgent!   Read now!", "SUBJECT :\tUrgent! Read\n now!  ", 1)
+		data = strings.Replace(data, "read all day!", "read  all\tday! \t", 1)
+		data += "\n\n\n"
+
+		Expect(verifyDKIM(data, publicKey)).To(Succeed())
+	})
+
+	Describe("NewDKIMSigner", func() {
+		It("errors when the key is not PEM encoded", func() {
+			_, err := mail.NewDKIMSigner("example.com", "selector", []byte("banana"))
+			Expect(err).To(MatchError("private key is not PEM encoded"))
+		})
+
+		It("errors when the key is not a supported type", func() {
+			_, err := mail.NewDKIMSigner("example.com", "selector", pem.EncodeToMemory(&pem.Block{
+				Type:  "EC PRIVATE KEY",
+				Bytes: []byte("banana"),
+			}))
+			Expect(err).To(MatchError(`unsupported private key type "EC PRIVATE KEY"`))
+		})
+	})
+
+	Context("when signing with an RSA key", func() {
+		It("produces a signature that verifies against the message data", func() {
+			privateKey, publicKey := rsaKeyPEM()
+
+			signer, err := mail.NewDKIMSigner("Notifications.Example.com", "notifications", privateKey)
+			Expect(err).NotTo(HaveOccurred())
+			msg.DKIMSigner = signer
+
+			data := msg.Data()
+			Expect(data).To(HavePrefix("DKIM-Signature: v=1;\n\ta=rsa-sha256;\n\tc=relaxed/relaxed;\n\td=notifications.example.com;\n\ts=notifications;"))
+			Expect(data).To(ContainSubstring("h=from:reply-to:to:subject:date:message-id:mime-version:content-type;"))
+			Expect(verifyDKIM(data, publicKey)).To(Succeed())
+		})
+	})
+
+	Context("when signing with an Ed25519 key", func() {
+		It("produces a signature that verifies against the message data", func() {
+			privateKey, publicKey := ed25519KeyPEM()
+
+			signer, err := mail.NewDKIMSigner("notifications.example.com", "ed", privateKey)
+			Expect(err).NotTo(HaveOccurred())
+			msg.DKIMSigner = signer
+
+			data := msg.Data()
+			Expect(data).To(ContainSubstring("a=ed25519-sha256;"))
+			Expect(verifyDKIM(data, publicKey)).To(Succeed())
+		})
+	})
+
+	It("signs the List-Unsubscribe headers required for one-click unsubscribe", func() {
+		privateKey, publicKey := ed25519KeyPEM()
+
+		signer, err := mail.NewDKIMSigner("notifications.example.com", "ed", privateKey)
+		Expect(err).NotTo(HaveOccurred())
+		msg.DKIMSigner = signer
+		msg.Headers = append(msg.Headers,
+			"List-Unsubscribe: <https://notifications.example.com/unsubscribe/some-id>",
+			"List-Unsubscribe-Post: List-Unsubscribe=One-Click",
+		)
+
+		data := msg.Data()
+		Expect(data).To(ContainSubstring(":list-unsubscribe:list-unsubscribe-post;"))
+		Expect(verifyDKIM(data, publicKey)).To(Succeed())
+	})
+
+	It("does not verify once a signed header has been modified", func() {
+		privateKey, publicKey := ed25519KeyPEM()
+
+		signer, err := mail.NewDKIMSigner("notifications.example.com", "ed", privateKey)
+		Expect(err).NotTo(HaveOccurred())
+		msg.DKIMSigner = signer
+
+		data := strings.Replace(msg.Data(), "Subject: Urgent!", "Subject: Ignore!", 1)
+		Expect(verifyDKIM(data, publicKey)).To(MatchError("signature did not verify"))
+	})
+
+	It("does not verify once the body has been modified", func() {
+		privateKey, publicKey := rsaKeyPEM()
+
+		signer, err := mail.NewDKIMSigner("notifications.example.com", "rsa", privateKey)
+		Expect(err).NotTo(HaveOccurred())
+		msg.DKIMSigner = signer
+
+		data := strings.Replace(msg.Data(), "most important", "least important", 1)
+		Expect(verifyDKIM(data, publicKey)).To(MatchError("body hash did not verify"))
+	})
+
+	Describe("DKIMSigners", func() {
+		It("finds the signer for the domain of the sender", func() {
+			privateKey, _ := rsaKeyPEM()
+			signer, err := mail.NewDKIMSigner("example.com", "selector", privateKey)
+			Expect(err).NotTo(HaveOccurred())
+
+			signers := mail.DKIMSigners{"example.com": signer}
+
+			found, ok := signers.For("Notifications <no-reply@Example.com>")
+			Expect(ok).To(BeTrue())
+			Expect(found).To(Equal(signer))
+
+			_, ok = signers.For("no-reply@other.example.com")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("SigningTransport", func() {
+		It("attaches the signer for the sending domain before delegating", func() {
+			privateKey, _ := rsaKeyPEM()
+			signer, err := mail.NewDKIMSigner("notifications.example.com", "selector", privateKey)
+			Expect(err).NotTo(HaveOccurred())
+
+			client := mocks.NewMailClient()
+			transport := mail.NewSigningTransport(client, mail.DKIMSigners{"notifications.example.com": signer})
+
+			Expect(transport.Send(msg, lager.NewLogger("notifications"))).To(Succeed())
+			Expect(client.SendCall.Receives.Message.DKIMSigner).To(Equal(signer))
+
+			msg.From = "no-reply@other.example.com"
+			Expect(transport.Send(msg, lager.NewLogger("notifications"))).To(Succeed())
+			Expect(client.SendCall.Receives.Message.DKIMSigner).To(BeNil())
+		})
+	})
+})
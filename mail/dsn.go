@@ -0,0 +1,155 @@
+package mail
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	netmail "net/mail"
+	"net/textproto"
+	"strings"
+)
+
+var ErrNotDSN = errors.New("message is not a delivery status notification")
+
+// DeliveryStatusNotification is the machine-readable part of an RFC 3464
+// bounce report. NotificationID and To are read from the X-CF-Notification-ID
+// and To headers of the returned message, when the reporting MTA includes
+// them.
+type DeliveryStatusNotification struct {
+	NotificationID string
+	To             []string
+	Recipients     []RecipientStatus
+}
+
+type RecipientStatus struct {
+	Recipient      string
+	Action         string
+	Status         string
+	DiagnosticCode string
+}
+
+func (r RecipientStatus) Failed() bool {
+	return r.Action == "failed"
+}
+
+// Permanent reports whether delivery failed with a 5.X.X status, meaning
+// that retrying the address will not help.
+func (r RecipientStatus) Permanent() bool {
+	return r.Failed() && strings.HasPrefix(r.Status, "5")
+}
+
+func ParseDSN(r io.Reader) (DeliveryStatusNotification, error) {
+	var dsn DeliveryStatusNotification
+
+	message, err := netmail.ReadMessage(r)
+	if err != nil {
+		return dsn, err
+	}
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/report" || !strings.EqualFold(params["report-type"], "delivery-status") {
+		return dsn, ErrNotDSN
+	}
+
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return dsn, err
+		}
+
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		switch partType {
+		case "message/delivery-status":
+			dsn.Recipients, err = parseDeliveryStatus(part)
+		case "text/rfc822-headers", "message/rfc822":
+			dsn.NotificationID, dsn.To, err = parseOriginalHeaders(part)
+		}
+		if err != nil {
+			return dsn, err
+		}
+	}
+
+	if len(dsn.Recipients) == 0 {
+		return dsn, errors.New("delivery status notification has no recipients")
+	}
+
+	return dsn, nil
+}
+
+func parseDeliveryStatus(r io.Reader) ([]RecipientStatus, error) {
+	reader := textproto.NewReader(bufio.NewReader(r))
+
+	// The first group holds the per-message fields, which are not needed.
+	_, err := reader.ReadMIMEHeader()
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	var recipients []RecipientStatus
+	for err != io.EOF {
+		var fields textproto.MIMEHeader
+		fields, err = reader.ReadMIMEHeader()
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		if len(fields) == 0 {
+			continue
+		}
+
+		recipient := fields.Get("Final-Recipient")
+		if recipient == "" {
+			recipient = fields.Get("Original-Recipient")
+		}
+
+		recipients = append(recipients, RecipientStatus{
+			Recipient:      addressField(recipient),
+			Action:         strings.ToLower(strings.TrimSpace(fields.Get("Action"))),
+			Status:         firstField(fields.Get("Status")),
+			DiagnosticCode: strings.TrimSpace(fields.Get("Diagnostic-Code")),
+		})
+	}
+
+	return recipients, nil
+}
+
+func parseOriginalHeaders(r io.Reader) (string, []string, error) {
+	headers, err := textproto.NewReader(bufio.NewReader(r)).ReadMIMEHeader()
+	if err != nil && err != io.EOF {
+		return "", nil, err
+	}
+
+	var to []string
+	if addresses, err := netmail.ParseAddressList(headers.Get("To")); err == nil {
+		for _, address := range addresses {
+			to = append(to, address.Address)
+		}
+	}
+
+	return strings.TrimSpace(headers.Get("X-CF-Notification-ID")), to, nil
+}
+
+// addressField strips the address type from a field such as
+// "rfc822; user@example.com".
+func addressField(value string) string {
+	if index := strings.Index(value, ";"); index != -1 {
+		value = value[index+1:]
+	}
+
+	return strings.Trim(strings.TrimSpace(value), "<>")
+}
+
+func firstField(value string) string {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return fields[0]
+}
@@ -0,0 +1,130 @@
+package mail_test
+
+import (
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const bounceReport = "From: MAILER-DAEMON@example.com\r\n" +
+	"To: no-reply@notifications.example.com\r\n" +
+	"Subject: Undelivered Mail Returned to Sender\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/report; report-type=delivery-status; boundary=\"REPORT\"\r\n" +
+	"\r\n" +
+	"--REPORT\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"\r\n" +
+	"Your message could not be delivered.\r\n" +
+	"--REPORT\r\n" +
+	"Content-Type: message/delivery-status\r\n" +
+	"\r\n" +
+	"Reporting-MTA: dns; mx.example.com\r\n" +
+	"Arrival-Date: Mon, 1 Jun 2015 12:00:00 +0000\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; Missing@Example.com\r\n" +
+	"Action: failed\r\n" +
+	"Status: 5.1.1 (bad destination mailbox address)\r\n" +
+	"Diagnostic-Code: smtp; 550 5.1.1 User unknown\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; full@example.com\r\n" +
+	"Action: failed\r\n" +
+	"Status: 4.2.2\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; slow@example.com\r\n" +
+	"Action: Delayed\r\n" +
+	"Status: 4.4.1\r\n" +
+	"--REPORT\r\n" +
+	"Content-Type: text/rfc822-headers\r\n" +
+	"\r\n" +
+	"From: no-reply@notifications.example.com\r\n" +
+	"To: Missing <missing@example.com>, other@example.com\r\n" +
+	"Cc: full@example.com\r\n" +
+	"X-CF-Notification-ID: some-message-id\r\n" +
+	"\r\n" +
+	"--REPORT--\r\n"
+
+var _ = Describe("ParseDSN", func() {
+	It("parses the recipients and notification ID from the report", func() {
+		dsn, err := mail.ParseDSN(strings.NewReader(bounceReport))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(dsn.NotificationID).To(Equal("some-message-id"))
+		Expect(dsn.To).To(Equal([]string{"missing@example.com", "other@example.com"}))
+		Expect(dsn.Recipients).To(Equal([]mail.RecipientStatus{
+			{
+				Recipient:      "Missing@Example.com",
+				Action:         "failed",
+				Status:         "5.1.1",
+				DiagnosticCode: "smtp; 550 5.1.1 User unknown",
+			},
+			{
+				Recipient: "full@example.com",
+				Action:    "failed",
+				Status:    "4.2.2",
+			},
+			{
+				Recipient: "slow@example.com",
+				Action:    "delayed",
+				Status:    "4.4.1",
+			},
+		}))
+	})
+
+	It("distinguishes permanent failures from transient ones", func() {
+		dsn, err := mail.ParseDSN(strings.NewReader(bounceReport))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(dsn.Recipients[0].Failed()).To(BeTrue())
+		Expect(dsn.Recipients[0].Permanent()).To(BeTrue())
+
+		Expect(dsn.Recipients[1].Failed()).To(BeTrue())
+		Expect(dsn.Recipients[1].Permanent()).To(BeFalse())
+
+		Expect(dsn.Recipients[2].Failed()).To(BeFalse())
+		Expect(dsn.Recipients[2].Permanent()).To(BeFalse())
+	})
+
+	Context("when the returned message headers are missing", func() {
+		It("leaves the notification ID empty", func() {
+			report := strings.Replace(bounceReport, "X-CF-Notification-ID: some-message-id\r\n", "", 1)
+
+			dsn, err := mail.ParseDSN(strings.NewReader(report))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dsn.NotificationID).To(BeEmpty())
+			Expect(dsn.Recipients).To(HaveLen(3))
+		})
+	})
+
+	Context("when the message is not a delivery status report", func() {
+		It("returns an error", func() {
+			_, err := mail.ParseDSN(strings.NewReader("From: someone@example.com\r\nContent-Type: text/plain\r\n\r\nhello\r\n"))
+			Expect(err).To(Equal(mail.ErrNotDSN))
+		})
+	})
+
+	Context("when the report has no recipients", func() {
+		It("returns an error", func() {
+			report := "Content-Type: multipart/report; report-type=delivery-status; boundary=\"REPORT\"\r\n" +
+				"\r\n" +
+				"--REPORT\r\n" +
+				"Content-Type: message/delivery-status\r\n" +
+				"\r\n" +
+				"Reporting-MTA: dns; mx.example.com\r\n" +
+				"--REPORT--\r\n"
+
+			_, err := mail.ParseDSN(strings.NewReader(report))
+			Expect(err).To(MatchError("delivery status notification has no recipients"))
+		})
+	})
+
+	Context("when the message cannot be read", func() {
+		It("returns an error", func() {
+			_, err := mail.ParseDSN(strings.NewReader(""))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,57 @@
+package mail
+
+import (
+	"fmt"
+	"net/textproto"
+	"regexp"
+)
+
+var enhancedStatusPattern = regexp.MustCompile(`^([245]\.\d{1,3}\.\d{1,3})(\s|$)`)
+
+// SMTPError is a reply from the SMTP server that rejected a command. The
+// EnhancedStatus is the RFC 3463 status code (e.g. "5.1.1") when the server
+// includes one in its reply.
+type SMTPError struct {
+	Code           int
+	EnhancedStatus string
+	Message        string
+}
+
+func NewSMTPError(err error) error {
+	protocolError, ok := err.(*textproto.Error)
+	if !ok {
+		return err
+	}
+
+	smtpError := SMTPError{
+		Code:    protocolError.Code,
+		Message: protocolError.Msg,
+	}
+
+	if matches := enhancedStatusPattern.FindStringSubmatch(protocolError.Msg); matches != nil {
+		smtpError.EnhancedStatus = matches[1]
+	}
+
+	return smtpError
+}
+
+func (e SMTPError) Error() string {
+	return fmt.Sprintf("%03d %s", e.Code, e.Message)
+}
+
+func (e SMTPError) Permanent() bool {
+	return e.Code >= 500
+}
+
+func (e SMTPError) Temporary() bool {
+	return e.Code >= 400 && e.Code < 500
+}
+
+// IsPermanent reports whether the error is a rejection from a transport
+// that will not succeed if the message is sent again.
+func IsPermanent(err error) bool {
+	rejection, ok := err.(interface {
+		Permanent() bool
+	})
+	return ok && rejection.Permanent()
+}
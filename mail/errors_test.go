@@ -0,0 +1,69 @@
+package mail_test
+
+import (
+	"errors"
+	"net/textproto"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SMTPError", func() {
+	Describe("NewSMTPError", func() {
+		It("converts an SMTP reply into an SMTPError", func() {
+			err := mail.NewSMTPError(&textproto.Error{
+				Code: 550,
+				Msg:  "5.1.1 mailbox does not exist",
+			})
+
+			Expect(err).To(Equal(mail.SMTPError{
+				Code:           550,
+				EnhancedStatus: "5.1.1",
+				Message:        "5.1.1 mailbox does not exist",
+			}))
+			Expect(err.Error()).To(Equal("550 5.1.1 mailbox does not exist"))
+		})
+
+		It("leaves the enhanced status empty when the reply does not include one", func() {
+			err := mail.NewSMTPError(&textproto.Error{
+				Code: 421,
+				Msg:  "service not available",
+			})
+
+			Expect(err).To(Equal(mail.SMTPError{
+				Code:    421,
+				Message: "service not available",
+			}))
+		})
+
+		It("returns other errors unchanged", func() {
+			original := errors.New("connection reset by peer")
+
+			Expect(mail.NewSMTPError(original)).To(Equal(original))
+		})
+	})
+
+	Describe("Permanent", func() {
+		It("is true for 5xx replies", func() {
+			Expect(mail.SMTPError{Code: 554}.Permanent()).To(BeTrue())
+			Expect(mail.SMTPError{Code: 451}.Permanent()).To(BeFalse())
+		})
+	})
+
+	Describe("Temporary", func() {
+		It("is true for 4xx replies", func() {
+			Expect(mail.SMTPError{Code: 451}.Temporary()).To(BeTrue())
+			Expect(mail.SMTPError{Code: 554}.Temporary()).To(BeFalse())
+		})
+	})
+
+	Describe("IsPermanent", func() {
+		It("reports whether the error is a permanent SMTP rejection", func() {
+			Expect(mail.IsPermanent(mail.SMTPError{Code: 550})).To(BeTrue())
+			Expect(mail.IsPermanent(mail.SMTPError{Code: 450})).To(BeFalse())
+			Expect(mail.IsPermanent(errors.New("connection refused"))).To(BeFalse())
+		})
+	})
+})
@@ -5,3 +5,11 @@ import "time"
 func (c *Client) ConnectTimeout() time.Duration {
 	return c.config.ConnectTimeout
 }
+
+func (c *Client) MaxMessagesPerConnection() int {
+	return c.config.MaxMessagesPerConnection
+}
+
+func (t *RelayTransport) SetRandom(random func(int) int) {
+	t.random = random
+}
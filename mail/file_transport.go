@@ -0,0 +1,100 @@
+package mail
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/pivotal-golang/lager"
+)
+
+// FileTransport writes each message to its own file instead of delivering
+// it. In maildir mode the file is written into tmp/ and then moved into new/
+// so that mail readers never see a partially written message; otherwise it
+// is written into the directory with an .eml extension.
+type FileTransport struct {
+	directory string
+	maildir   bool
+	hostname  string
+	sequence  int64
+}
+
+func NewFileTransport(directory string) *FileTransport {
+	return newFileTransport(directory, false)
+}
+
+func NewMaildirTransport(directory string) *FileTransport {
+	return newFileTransport(directory, true)
+}
+
+func newFileTransport(directory string, maildir bool) *FileTransport {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "localhost"
+	}
+
+	return &FileTransport{
+		directory: directory,
+		maildir:   maildir,
+		hostname:  hostname,
+	}
+}
+
+func (t *FileTransport) Connect(logger lager.Logger) error {
+	if !t.maildir {
+		return os.MkdirAll(t.directory, 0755)
+	}
+
+	for _, dir := range []string{"tmp", "new", "cur"} {
+		err := os.MkdirAll(filepath.Join(t.directory, dir), 0755)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *FileTransport) Send(msg Message, logger lager.Logger) error {
+	err := t.Connect(logger)
+	if err != nil {
+		return err
+	}
+
+	name := t.uniqueName()
+
+	var path string
+	if t.maildir {
+		path = filepath.Join(t.directory, "new", name)
+		err = t.write(filepath.Join(t.directory, "tmp", name), path, msg)
+	} else {
+		path = filepath.Join(t.directory, name+".eml")
+		err = ioutil.WriteFile(path, []byte(msg.Data()), 0644)
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.Info("message-written", lager.Data{"path": path})
+
+	return nil
+}
+
+func (t *FileTransport) write(tmpPath, path string, msg Message) error {
+	err := ioutil.WriteFile(tmpPath, []byte(msg.Data()), 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+func (t *FileTransport) uniqueName() string {
+	sequence := atomic.AddInt64(&t.sequence, 1)
+	now := time.Now()
+
+	return fmt.Sprintf("%d.M%dP%dQ%d.%s", now.Unix(), now.Nanosecond()/1000, os.Getpid(), sequence, t.hostname)
+}
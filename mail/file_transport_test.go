@@ -0,0 +1,96 @@
+package mail_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FileTransport", func() {
+	var (
+		directory string
+		logger    lager.Logger
+		msg       mail.Message
+	)
+
+	BeforeEach(func() {
+		var err error
+		directory, err = ioutil.TempDir("", "mail")
+		Expect(err).NotTo(HaveOccurred())
+
+		logger = lager.NewLogger("notifications")
+
+		msg = mail.Message{
+			From:    "me@example.com",
+			To:      "you@example.com",
+			Subject: "Urgent! Read now!",
+			Body: []mail.Part{
+				{
+					ContentType: "text/plain",
+					Content:     "This email is the most important thing you will read all day!",
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(directory)
+	})
+
+	Context("when writing eml files", func() {
+		It("writes each message to its own file", func() {
+			transport := mail.NewFileTransport(filepath.Join(directory, "outbox"))
+
+			Expect(transport.Send(msg, logger)).To(Succeed())
+			Expect(transport.Send(msg, logger)).To(Succeed())
+
+			files, err := filepath.Glob(filepath.Join(directory, "outbox", "*.eml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(HaveLen(2))
+
+			contents, err := ioutil.ReadFile(files[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(ContainSubstring("To: you@example.com\n"))
+			Expect(string(contents)).To(ContainSubstring("Subject: Urgent! Read now!\n"))
+			Expect(string(contents)).To(ContainSubstring("This email is the most important thing you will read all day!"))
+		})
+	})
+
+	Context("when writing a maildir", func() {
+		It("creates the maildir structure on connect", func() {
+			transport := mail.NewMaildirTransport(directory)
+
+			Expect(transport.Connect(logger)).To(Succeed())
+
+			for _, dir := range []string{"tmp", "new", "cur"} {
+				info, err := os.Stat(filepath.Join(directory, dir))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.IsDir()).To(BeTrue())
+			}
+		})
+
+		It("delivers messages into new", func() {
+			transport := mail.NewMaildirTransport(directory)
+
+			Expect(transport.Send(msg, logger)).To(Succeed())
+
+			files, err := ioutil.ReadDir(filepath.Join(directory, "new"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(HaveLen(1))
+
+			contents, err := ioutil.ReadFile(filepath.Join(directory, "new", files[0].Name()))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(ContainSubstring("From: me@example.com\n"))
+
+			files, err = ioutil.ReadDir(filepath.Join(directory, "tmp"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,110 @@
+package mail
+
+import (
+	"fmt"
+	"mime"
+	"sort"
+	"strings"
+)
+
+const MaxCustomHeaders = 20
+
+// reservedHeaders are set by the notifications service itself, either when
+// the message is packed or when it is rendered, and cannot be overridden by
+// the sender.
+var reservedHeaders = []string{
+	"Bcc",
+	"Cc",
+	"Content-Disposition",
+	"Content-Transfer-Encoding",
+	"Content-Type",
+	"Date",
+	"DKIM-Signature",
+	"From",
+	"List-Unsubscribe",
+	"List-Unsubscribe-Post",
+	"Message-ID",
+	"Mime-Version",
+	"Received",
+	"Reply-To",
+	"Return-Path",
+	"Sender",
+	"Subject",
+	"To",
+}
+
+// ValidateHeaders checks that custom headers have well formed names, that
+// their values cannot inject further headers, and that none of them replace
+// a header the service sets itself.
+func ValidateHeaders(headers map[string]string) error {
+	if len(headers) > MaxCustomHeaders {
+		return fmt.Errorf("too many headers, the limit is %d", MaxCustomHeaders)
+	}
+
+	for _, name := range sortedNames(headers) {
+		if !validHeaderName(name) {
+			return fmt.Errorf("header name %q is invalid", name)
+		}
+
+		if isReservedHeader(name) {
+			return fmt.Errorf("header %q cannot be set", name)
+		}
+
+		if strings.ContainsAny(headers[name], "\r\n") {
+			return fmt.Errorf("header %q has an invalid value", name)
+		}
+	}
+
+	return nil
+}
+
+// FormatHeaders renders custom headers in name order, encoding values that
+// are not plain ASCII.
+func FormatHeaders(headers map[string]string) []string {
+	var formatted []string
+	for _, name := range sortedNames(headers) {
+		formatted = append(formatted, name+": "+mime.QEncoding.Encode("UTF-8", headers[name]))
+	}
+
+	return formatted
+}
+
+func sortedNames(headers map[string]string) []string {
+	var names []string
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// validHeaderName follows RFC 5322, which allows any printable ASCII
+// character other than a colon.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for _, r := range name {
+		if r < 33 || r > 126 || r == ':' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isReservedHeader(name string) bool {
+	if strings.HasPrefix(strings.ToLower(name), "x-cf-") {
+		return true
+	}
+
+	for _, reserved := range reservedHeaders {
+		if strings.EqualFold(name, reserved) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,61 @@
+package mail_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/mail"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Headers", func() {
+	Describe("ValidateHeaders", func() {
+		It("accepts custom headers", func() {
+			err := mail.ValidateHeaders(map[string]string{
+				"X-Ticket-ID": "TICKET-1234",
+				"References":  "<some-message-id@example.com>",
+				"In-Reply-To": "<some-message-id@example.com>",
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("rejects headers that the service sets itself", func() {
+			Expect(mail.ValidateHeaders(map[string]string{"from": "evil@example.com"})).To(MatchError(`header "from" cannot be set`))
+			Expect(mail.ValidateHeaders(map[string]string{"Content-Type": "text/html"})).To(MatchError(`header "Content-Type" cannot be set`))
+			Expect(mail.ValidateHeaders(map[string]string{"X-CF-Client-ID": "other"})).To(MatchError(`header "X-CF-Client-ID" cannot be set`))
+		})
+
+		It("rejects malformed header names", func() {
+			Expect(mail.ValidateHeaders(map[string]string{"X Ticket": "1"})).To(MatchError(`header name "X Ticket" is invalid`))
+			Expect(mail.ValidateHeaders(map[string]string{"X-Ticket:": "1"})).To(MatchError(`header name "X-Ticket:" is invalid`))
+			Expect(mail.ValidateHeaders(map[string]string{"": "1"})).To(MatchError(`header name "" is invalid`))
+		})
+
+		It("rejects values that would inject other headers", func() {
+			err := mail.ValidateHeaders(map[string]string{"X-Ticket-ID": "1\r\nBcc: everyone@example.com"})
+			Expect(err).To(MatchError(`header "X-Ticket-ID" has an invalid value`))
+		})
+
+		It("rejects too many headers", func() {
+			headers := map[string]string{}
+			for i := 0; i <= mail.MaxCustomHeaders; i++ {
+				headers[string(rune('A'+i))] = "value"
+			}
+
+			Expect(mail.ValidateHeaders(headers)).To(MatchError("too many headers, the limit is 20"))
+		})
+	})
+
+	Describe("FormatHeaders", func() {
+		It("formats the headers in name order, encoding non-ASCII values", func() {
+			Expect(mail.FormatHeaders(map[string]string{
+				"X-Ticket-ID": "TICKET-1234",
+				"References":  "<some-message-id@example.com>",
+				"X-Team":      "Über",
+			})).To(Equal([]string{
+				"References: <some-message-id@example.com>",
+				"X-Team: =?UTF-8?q?=C3=9Cber?=",
+				"X-Ticket-ID: TICKET-1234",
+			}))
+		})
+	})
+})
@@ -0,0 +1,159 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pivotal-golang/lager"
+)
+
+type HTTPTransportConfig struct {
+	URL           string
+	Token         string
+	SkipVerifySSL bool
+	Timeout       time.Duration
+}
+
+// HTTPTransport delivers messages by posting them as JSON to an HTTP email
+// gateway. The request carries the individual message fields as well as the
+// complete rendered message for gateways that accept raw MIME.
+type HTTPTransport struct {
+	config HTTPTransportConfig
+	client *http.Client
+}
+
+type relayRequest struct {
+	From        string            `json:"from"`
+	ReplyTo     string            `json:"reply_to,omitempty"`
+	To          string            `json:"to"`
+	Cc          []string          `json:"cc,omitempty"`
+	Bcc         []string          `json:"bcc,omitempty"`
+	Subject     string            `json:"subject"`
+	Headers     []string          `json:"headers,omitempty"`
+	Parts       []relayPart       `json:"parts"`
+	Attachments []relayAttachment `json:"attachments,omitempty"`
+	Raw         string            `json:"raw"`
+}
+
+type relayAttachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Content     []byte `json:"content"`
+}
+
+type relayPart struct {
+	ContentType string `json:"content_type"`
+	Content     string `json:"content"`
+}
+
+// HTTPRelayError is returned when the gateway responds with a non-2xx
+// status. Client errors other than timeouts and rate limiting are
+// permanent, since the gateway will reject the same request again.
+type HTTPRelayError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e HTTPRelayError) Error() string {
+	return fmt.Sprintf("HTTP relay responded with %d: %s", e.StatusCode, e.Body)
+}
+
+func (e HTTPRelayError) Permanent() bool {
+	switch e.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	}
+
+	return e.StatusCode >= 400 && e.StatusCode < 500
+}
+
+func NewHTTPTransport(config HTTPTransportConfig) *HTTPTransport {
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	return &HTTPTransport{
+		config: config,
+		client: &http.Client{
+			Timeout: config.Timeout,
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: config.SkipVerifySSL,
+				},
+			},
+		},
+	}
+}
+
+func (t *HTTPTransport) Connect(logger lager.Logger) error {
+	return nil
+}
+
+func (t *HTTPTransport) Send(msg Message, logger lager.Logger) error {
+	payload := relayRequest{
+		From:    msg.From,
+		ReplyTo: msg.ReplyTo,
+		To:      msg.To,
+		Cc:      msg.Cc,
+		Bcc:     msg.Bcc,
+		Subject: msg.Subject,
+		Headers: msg.Headers,
+		Raw:     msg.Data(),
+	}
+	for _, part := range msg.Body {
+		payload.Parts = append(payload.Parts, relayPart{
+			ContentType: part.ContentType,
+			Content:     part.Content,
+		})
+	}
+	for _, attachment := range msg.Attachments {
+		payload.Attachments = append(payload.Attachments, relayAttachment{
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			Content:     attachment.Content,
+		})
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest("POST", t.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	if t.config.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+t.config.Token)
+	}
+
+	response, err := t.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return HTTPRelayError{
+			StatusCode: response.StatusCode,
+			Body:       string(responseBody),
+		}
+	}
+
+	logger.Info("message-relayed", lager.Data{"status": response.StatusCode})
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package mail_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HTTPTransport", func() {
+	var (
+		server    *httptest.Server
+		transport *mail.HTTPTransport
+		logger    lager.Logger
+		msg       mail.Message
+		status    int
+		request   *http.Request
+		body      []byte
+	)
+
+	BeforeEach(func() {
+		status = http.StatusAccepted
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			var err error
+			body, err = ioutil.ReadAll(req.Body)
+			if err != nil {
+				panic(err)
+			}
+			request = req
+
+			w.WriteHeader(status)
+			w.Write([]byte(`{"errors": ["something happened"]}`))
+		}))
+
+		transport = mail.NewHTTPTransport(mail.HTTPTransportConfig{
+			URL:   server.URL + "/messages",
+			Token: "some-token",
+		})
+
+		logger = lager.NewLogger("notifications")
+
+		msg = mail.Message{
+			From:    "me@example.com",
+			ReplyTo: "reply@example.com",
+			To:      "you@example.com",
+			Subject: "Urgent! Read now!",
+			Headers: []string{"X-CF-Client-ID: some-client"},
+			Body: []mail.Part{
+				{
+					ContentType: "text/plain",
+					Content:     "This email is the most important thing you will read all day!",
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("posts the message to the relay as JSON", func() {
+		Expect(transport.Send(msg, logger)).To(Succeed())
+
+		Expect(request.Method).To(Equal("POST"))
+		Expect(request.URL.Path).To(Equal("/messages"))
+		Expect(request.Header.Get("Content-Type")).To(Equal("application/json"))
+		Expect(request.Header.Get("Authorization")).To(Equal("Bearer some-token"))
+
+		var payload map[string]interface{}
+		Expect(json.Unmarshal(body, &payload)).To(Succeed())
+		Expect(payload).To(HaveKeyWithValue("from", "me@example.com"))
+		Expect(payload).To(HaveKeyWithValue("reply_to", "reply@example.com"))
+		Expect(payload).To(HaveKeyWithValue("to", "you@example.com"))
+		Expect(payload).To(HaveKeyWithValue("subject", "Urgent! Read now!"))
+		Expect(payload).To(HaveKeyWithValue("headers", []interface{}{"X-CF-Client-ID: some-client"}))
+		Expect(payload).To(HaveKeyWithValue("parts", []interface{}{
+			map[string]interface{}{
+				"content_type": "text/plain",
+				"content":      "This email is the most important thing you will read all day!",
+			},
+		}))
+		Expect(payload["raw"]).To(ContainSubstring("Subject: Urgent! Read now!\n"))
+	})
+
+	It("includes the Cc and Bcc recipients", func() {
+		msg.Cc = []string{"shared@example.com"}
+		msg.Bcc = []string{"audit@example.com"}
+
+		Expect(transport.Send(msg, logger)).To(Succeed())
+
+		var payload map[string]interface{}
+		Expect(json.Unmarshal(body, &payload)).To(Succeed())
+		Expect(payload).To(HaveKeyWithValue("cc", []interface{}{"shared@example.com"}))
+		Expect(payload).To(HaveKeyWithValue("bcc", []interface{}{"audit@example.com"}))
+		Expect(payload["raw"]).NotTo(ContainSubstring("audit@example.com"))
+	})
+
+	It("includes the attachments base64 encoded", func() {
+		msg.Attachments = []mail.Attachment{
+			{
+				Filename:    "report.csv",
+				ContentType: "text/csv",
+				Content:     []byte("a,b,c\n"),
+			},
+		}
+
+		Expect(transport.Send(msg, logger)).To(Succeed())
+
+		var payload map[string]interface{}
+		Expect(json.Unmarshal(body, &payload)).To(Succeed())
+		Expect(payload).To(HaveKeyWithValue("attachments", []interface{}{
+			map[string]interface{}{
+				"filename":     "report.csv",
+				"content_type": "text/csv",
+				"content":      "YSxiLGMK",
+			},
+		}))
+	})
+
+	It("omits the authorization header when no token is configured", func() {
+		transport = mail.NewHTTPTransport(mail.HTTPTransportConfig{
+			URL: server.URL,
+		})
+
+		Expect(transport.Send(msg, logger)).To(Succeed())
+		Expect(request.Header).NotTo(HaveKey("Authorization"))
+	})
+
+	Context("when the relay responds with an error", func() {
+		It("returns an HTTPRelayError", func() {
+			status = http.StatusUnprocessableEntity
+
+			err := transport.Send(msg, logger)
+			Expect(err).To(Equal(mail.HTTPRelayError{
+				StatusCode: http.StatusUnprocessableEntity,
+				Body:       `{"errors": ["something happened"]}`,
+			}))
+			Expect(mail.IsPermanent(err)).To(BeTrue())
+		})
+
+		It("treats server errors and rate limiting as temporary", func() {
+			status = http.StatusBadGateway
+			Expect(mail.IsPermanent(transport.Send(msg, logger))).To(BeFalse())
+
+			status = http.StatusTooManyRequests
+			Expect(mail.IsPermanent(transport.Send(msg, logger))).To(BeFalse())
+		})
+	})
+
+	Context("when the relay cannot be reached", func() {
+		It("returns the error", func() {
+			server.Close()
+
+			Expect(transport.Send(msg, logger)).NotTo(Succeed())
+		})
+	})
+})
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"crypto/rand"
 	"crypto/tls"
+	"encoding/base64"
 	"log"
 	"net"
 	"net/url"
@@ -82,17 +83,22 @@ type SMTPServer struct {
 	Deliveries      []Delivery
 	Listener        *net.TCPListener
 	SupportsTLS     bool
+	ImplicitTLS     bool
 	ConnectWait     time.Duration
 	halt            chan bool
 	ConnectionState string
 	FailsHello      bool
+	RcptToReply     string
 }
 
 type Delivery struct {
-	Recipient string
-	Sender    string
-	Data      []string
-	UsedTLS   bool
+	Recipient       string
+	Recipients      []string
+	Sender          string
+	Data            []string
+	UsedTLS         bool
+	AuthMechanism   string
+	AuthCredentials []string
 }
 
 func NewSMTPServer(user, pass string) *SMTPServer {
@@ -155,6 +161,11 @@ func (server *SMTPServer) Respond(conn net.Conn) {
 	<-time.After(server.ConnectWait)
 	server.ConnectionState = StateConnected
 
+	if server.ImplicitTLS {
+		conn = tls.Server(conn, server.TLSConfig())
+		server.CurrentDelivery.UsedTLS = true
+	}
+
 	input := bufio.NewReader(conn)
 	output := bufio.NewWriter(conn)
 	server.Broadcast(output)
@@ -169,6 +180,10 @@ Loop:
 			conn, input, output = server.RespondToStartTLS(conn, input, output)
 		case strings.Contains(msg, "AUTH PLAIN"):
 			server.RespondToAuthPlain(output)
+		case strings.Contains(msg, "AUTH LOGIN"):
+			server.RespondToAuthLogin(output, input)
+		case strings.Contains(msg, "AUTH XOAUTH2"):
+			server.RespondToAuthXOAUTH2(output, msg)
 		case strings.Contains(msg, "MAIL FROM"):
 			server.RespondToMailFrom(output, msg)
 		case strings.Contains(msg, "RCPT TO"):
@@ -199,7 +214,9 @@ func (server *SMTPServer) RespondToEHLO(output *bufio.Writer) {
 	}
 
 	output.WriteString("250-localhost Hello\n")
-	if server.SupportsTLS {
+	if server.ImplicitTLS {
+		output.WriteString("250 AUTH PLAIN LOGIN XOAUTH2\r\n")
+	} else if server.SupportsTLS {
 		output.WriteString("250-STARTTLS\n")
 		output.WriteString("250 AUTH PLAIN LOGIN\r\n")
 	} else {
@@ -214,18 +231,62 @@ func (server *SMTPServer) RespondToStartTLS(conn net.Conn, input *bufio.Reader,
 
 	server.CurrentDelivery.UsedTLS = true
 
+	tlsConn := tls.Server(conn, server.TLSConfig())
+
+	return tlsConn, bufio.NewReader(tlsConn), bufio.NewWriter(tlsConn)
+}
+
+func (server *SMTPServer) TLSConfig() *tls.Config {
 	cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
 	if err != nil {
 		log.Fatalf("server: loadkeys: %s", err)
 	}
 	config := tls.Config{Certificates: []tls.Certificate{cert}}
 	config.Rand = rand.Reader
-	tlsConn := tls.Server(conn, &config)
 
-	return tlsConn, bufio.NewReader(tlsConn), bufio.NewWriter(tlsConn)
+	return &config
 }
 
 func (server *SMTPServer) RespondToAuthPlain(output *bufio.Writer) {
+	server.CurrentDelivery.AuthMechanism = "PLAIN"
+
+	output.WriteString("235 OK, Go ahead\r\n")
+	output.Flush()
+}
+
+func (server *SMTPServer) RespondToAuthLogin(output *bufio.Writer, input *bufio.Reader) {
+	server.CurrentDelivery.AuthMechanism = "LOGIN"
+
+	for _, prompt := range []string{"Username:", "Password:"} {
+		output.WriteString("334 " + base64.StdEncoding.EncodeToString([]byte(prompt)) + "\r\n")
+		output.Flush()
+
+		msg, _ := input.ReadString('\n')
+		credential, err := base64.StdEncoding.DecodeString(strings.TrimSpace(msg))
+		if err != nil {
+			output.WriteString("501 Invalid encoding\r\n")
+			output.Flush()
+			return
+		}
+		server.CurrentDelivery.AuthCredentials = append(server.CurrentDelivery.AuthCredentials, string(credential))
+	}
+
+	output.WriteString("235 OK, Go ahead\r\n")
+	output.Flush()
+}
+
+func (server *SMTPServer) RespondToAuthXOAUTH2(output *bufio.Writer, msg string) {
+	server.CurrentDelivery.AuthMechanism = "XOAUTH2"
+
+	encoded := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(msg), "AUTH XOAUTH2"))
+	credential, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		output.WriteString("501 Invalid encoding\r\n")
+		output.Flush()
+		return
+	}
+	server.CurrentDelivery.AuthCredentials = append(server.CurrentDelivery.AuthCredentials, string(credential))
+
 	output.WriteString("235 OK, Go ahead\r\n")
 	output.Flush()
 }
@@ -245,6 +306,13 @@ func (server *SMTPServer) RespondToRcptTo(output *bufio.Writer, msg string) {
 	recipient = strings.TrimPrefix(recipient, "RCPT TO:")
 	recipient = strings.Trim(recipient, "<>")
 	server.CurrentDelivery.Recipient = recipient
+	server.CurrentDelivery.Recipients = append(server.CurrentDelivery.Recipients, recipient)
+
+	if server.RcptToReply != "" {
+		output.WriteString(server.RcptToReply + "\r\n")
+		output.Flush()
+		return
+	}
 
 	output.WriteString("250 OK\r\n")
 	output.Flush()
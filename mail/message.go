@@ -2,38 +2,44 @@ package mail
 
 import (
 	"bytes"
-	"io/ioutil"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
 	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"net/textproto"
 	"strings"
-	"text/template"
-
-	"gopkg.in/gomail.v1"
+	"time"
 )
 
-const emailTemplate = `{{range .Headers}}{{.}}
-{{end}}Date: {{.Date}}
-Mime-Version: {{.MimeVersion}}
-Content-Type: {{.ContentType}}
-{{if .ContentTransferEncoding}}Content-Transfer-Encoding: {{.ContentTransferEncoding}}
-{{end}}From: {{.From}}{{if .ReplyTo}}
-Reply-To: {{.ReplyTo}}{{end}}
-To: {{.To}}
-Subject: {{.Subject}}
-
-{{.CompiledBody}}`
+const (
+	maxHeaderLineLength = 78
+	maxBase64LineLength = 76
+)
 
+// Message is rendered as RFC 5322 text with LF line endings; the SMTP client
+// converts them to CRLF on the wire. Date, MessageID and ContentType are
+// generated the first time the message is rendered so that rendering it
+// again produces the same message. To may hold a comma separated address
+// list. Bcc recipients are added to the envelope but never to the headers.
 type Message struct {
-	Date                    string
-	MimeVersion             string
-	ContentType             string
-	ContentTransferEncoding string
-	From                    string
-	ReplyTo                 string
-	To                      string
-	Subject                 string
-	Body                    []Part
-	Headers                 []string
-	CompiledBody            string
+	Date        string
+	MessageID   string
+	ContentType string
+	From        string
+	ReplyTo     string
+	To          string
+	Cc          []string
+	Bcc         []string
+	Subject     string
+	Body        []Part
+	Attachments []Attachment
+	Headers     []string
+	DKIMSigner  *DKIMSigner
 }
 
 type Part struct {
@@ -41,53 +47,400 @@ type Part struct {
 	Content     string
 }
 
+// Attachment is a file sent alongside the message body. When ContentType is
+// empty it is guessed from the file extension.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
 func (msg *Message) Data() string {
 	buf := bytes.NewBuffer([]byte{})
 
-	err := msg.CompileBody()
+	_, err := msg.WriteTo(buf)
 	if err != nil {
 		panic(err)
 	}
 
-	tmpl, err := template.New("test").Parse(emailTemplate)
+	return buf.String()
+}
+
+// WriteTo renders the message to w. Unsigned messages are streamed as they
+// are rendered; signed messages are rendered in full first, since the
+// signature covers the whole message and is written ahead of it.
+func (msg *Message) WriteTo(w io.Writer) (int64, error) {
+	msg.stamp()
+
+	if msg.DKIMSigner != nil {
+		buf := bytes.NewBuffer([]byte{})
+		err := msg.write(buf)
+		if err != nil {
+			return 0, err
+		}
+
+		signature, err := msg.DKIMSigner.Sign(buf.String())
+		if err != nil {
+			return 0, err
+		}
+
+		n, err := io.WriteString(w, signature+buf.String())
+		return int64(n), err
+	}
+
+	counter := &countingWriter{writer: w}
+	err := msg.write(counter)
+
+	return counter.count, err
+}
+
+func (msg Message) Boundary() string {
+	_, params, err := mime.ParseMediaType(msg.ContentType)
 	if err != nil {
 		panic(err)
 	}
 
-	err = tmpl.Execute(buf, msg)
+	return params["boundary"]
+}
+
+// EnvelopeFrom returns the address of the sender without its display name,
+// as the SMTP envelope requires.
+func (msg Message) EnvelopeFrom() string {
+	return envelopeFrom(msg.From)
+}
+
+func envelopeFrom(from string) string {
+	addresses := envelopeAddresses(from)
+	if len(addresses) == 0 {
+		return from
+	}
+
+	return addresses[0]
+}
+
+// Recipients returns the envelope address of every To, Cc and Bcc
+// recipient, without duplicates.
+func (msg Message) Recipients() []string {
+	lists := []string{msg.To}
+	lists = append(lists, msg.Cc...)
+	lists = append(lists, msg.Bcc...)
+
+	var recipients []string
+	seen := map[string]bool{}
+	for _, list := range lists {
+		for _, address := range envelopeAddresses(list) {
+			key := strings.ToLower(address)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			recipients = append(recipients, address)
+		}
+	}
+
+	return recipients
+}
+
+func (msg *Message) stamp() {
+	if msg.Date == "" {
+		msg.Date = time.Now().Format(time.RFC1123Z)
+	}
+
+	if msg.MessageID == "" {
+		msg.MessageID = NewMessageID(msg.From)
+	}
+
+	if msg.ContentType == "" {
+		if len(msg.Attachments) > 0 {
+			boundary := multipart.NewWriter(nil).Boundary()
+			msg.ContentType = mime.FormatMediaType("multipart/mixed", map[string]string{"boundary": boundary})
+		} else if len(msg.Body) == 1 {
+			msg.ContentType = msg.Body[0].ContentType + "; charset=UTF-8"
+		} else {
+			boundary := multipart.NewWriter(nil).Boundary()
+			msg.ContentType = mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": boundary})
+		}
+	}
+}
+
+func (msg *Message) write(w io.Writer) error {
+	headers := &headerWriter{writer: w}
+
+	for _, header := range msg.Headers {
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) != 2 {
+			headers.writeLine(header)
+			continue
+		}
+		headers.write(parts[0], strings.TrimSpace(parts[1]))
+	}
+
+	headers.write("Date", msg.Date)
+	headers.write("Message-ID", msg.MessageID)
+	headers.write("Mime-Version", "1.0")
+	headers.write("Content-Type", msg.ContentType)
+	if len(msg.Attachments) == 0 && len(msg.Body) == 1 {
+		headers.write("Content-Transfer-Encoding", "quoted-printable")
+	}
+	headers.write("From", encodeAddressList(msg.From))
+	if msg.ReplyTo != "" {
+		headers.write("Reply-To", encodeAddressList(msg.ReplyTo))
+	}
+	headers.write("To", encodeAddressList(msg.To))
+	if len(msg.Cc) > 0 {
+		headers.write("Cc", encodeAddressList(strings.Join(msg.Cc, ", ")))
+	}
+	headers.write("Subject", mime.QEncoding.Encode("UTF-8", msg.Subject))
+	headers.writeLine("")
+
+	if headers.err != nil {
+		return headers.err
+	}
+
+	body := lfWriter{writer: w}
+	if len(msg.Attachments) > 0 {
+		return msg.writeMixed(body)
+	}
+
+	if len(msg.Body) == 1 {
+		return writeQuotedPrintable(body, msg.Body[0].Content)
+	}
+
+	return msg.writeAlternative(body, msg.Boundary())
+}
+
+// writeMixed writes the body followed by the attachments. A body with more
+// than one part is nested as multipart/alternative, using a boundary
+// derived from the outer one so that rendering stays repeatable.
+func (msg *Message) writeMixed(w io.Writer) error {
+	writer := multipart.NewWriter(w)
+	err := writer.SetBoundary(msg.Boundary())
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	return buf.String()
+	switch {
+	case len(msg.Body) == 1:
+		partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {msg.Body[0].ContentType + "; charset=UTF-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return err
+		}
+
+		err = writeQuotedPrintable(partWriter, msg.Body[0].Content)
+		if err != nil {
+			return err
+		}
+	case len(msg.Body) > 1:
+		boundary := "alt-" + msg.Boundary()
+		partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type": {mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": boundary})},
+		})
+		if err != nil {
+			return err
+		}
+
+		err = msg.writeAlternative(partWriter, boundary)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, attachment := range msg.Attachments {
+		partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {attachment.mediaType()},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return err
+		}
+
+		err = writeBase64(partWriter, attachment.Content)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
 }
 
-func (msg *Message) CompileBody() error {
-	message := gomail.NewMessage()
+func (msg *Message) writeAlternative(w io.Writer, boundary string) error {
+	writer := multipart.NewWriter(w)
+	err := writer.SetBoundary(boundary)
+	if err != nil {
+		return err
+	}
+
 	for _, part := range msg.Body {
-		message.AddAlternative(part.ContentType, part.Content)
+		partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.ContentType + "; charset=UTF-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return err
+		}
+
+		err = writeQuotedPrintable(partWriter, part.Content)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+func writeBase64(w io.Writer, content []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(content)
+	for len(encoded) > 0 {
+		length := maxBase64LineLength
+		if len(encoded) < length {
+			length = len(encoded)
+		}
+
+		_, err := io.WriteString(w, encoded[:length]+"\r\n")
+		if err != nil {
+			return err
+		}
+		encoded = encoded[length:]
 	}
 
-	m := message.Export()
-	body, err := ioutil.ReadAll(m.Body)
+	return nil
+}
+
+func writeQuotedPrintable(w io.Writer, content string) error {
+	writer := quotedprintable.NewWriter(w)
+
+	_, err := io.WriteString(writer, content)
+	if err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+// NewMessageID returns a unique Message-ID in the domain of the sender.
+func NewMessageID(from string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domain = strings.TrimRight(from[at+1:], "> ")
+	}
+
+	random := make([]byte, 16)
+	_, err := rand.Read(random)
 	if err != nil {
 		panic(err)
 	}
 
-	msg.CompiledBody = strings.Replace(string(body), "\r\n", "\n", -1)
-	msg.Date = m.Header.Get("Date")
-	msg.MimeVersion = m.Header.Get("Mime-Version")
-	msg.ContentType = m.Header.Get("Content-Type")
-	msg.ContentTransferEncoding = m.Header.Get("Content-Transfer-Encoding")
+	return fmt.Sprintf("<%s.%d@%s>", hex.EncodeToString(random), time.Now().UnixNano(), domain)
+}
 
-	return nil
+// encodeAddressList RFC 2047 encodes the display names in a list of
+// addresses. Values that do not parse as addresses are left untouched.
+func encodeAddressList(value string) string {
+	addresses, err := netmail.ParseAddressList(value)
+	if err != nil {
+		return value
+	}
+
+	var encoded []string
+	for _, address := range addresses {
+		if address.Name == "" {
+			encoded = append(encoded, address.Address)
+			continue
+		}
+
+		encoded = append(encoded, address.String())
+	}
+
+	return strings.Join(encoded, ", ")
 }
 
-func (msg Message) Boundary() string {
-	_, params, err := mime.ParseMediaType(msg.ContentType)
+// envelopeAddresses returns the bare addresses in an address list. A value
+// that cannot be parsed is passed through untouched for the server to judge.
+func envelopeAddresses(value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	addresses, err := netmail.ParseAddressList(value)
 	if err != nil {
-		panic(err)
+		return []string{value}
 	}
 
-	return params["boundary"]
+	var envelope []string
+	for _, address := range addresses {
+		envelope = append(envelope, address.Address)
+	}
+
+	return envelope
+}
+
+type headerWriter struct {
+	writer io.Writer
+	err    error
+}
+
+// write folds the header before whitespace so that no line is longer than
+// 78 characters where possible. Folding only inserts line breaks, so the
+// header unfolds to its original value.
+func (h *headerWriter) write(name, value string) {
+	line := name + ": " + value
+	start := len(name) + 1
+	for len(line) > maxHeaderLineLength {
+		index := strings.LastIndexAny(line[:maxHeaderLineLength], " \t")
+		if index < start {
+			index = strings.IndexAny(line[maxHeaderLineLength:], " \t")
+			if index < 0 {
+				break
+			}
+			index += maxHeaderLineLength
+		}
+
+		h.writeLine(line[:index])
+		line = line[index:]
+		start = 1
+	}
+
+	h.writeLine(line)
+}
+
+func (h *headerWriter) writeLine(line string) {
+	if h.err != nil {
+		return
+	}
+
+	_, h.err = io.WriteString(h.writer, line+"\n")
+}
+
+// lfWriter drops the carriage returns that the multipart and
+// quoted-printable writers emit, keeping the message in LF form. Both only
+// emit carriage returns as part of a CRLF line ending.
+type lfWriter struct {
+	writer io.Writer
+}
+
+func (w lfWriter) Write(p []byte) (int, error) {
+	_, err := w.writer.Write(bytes.Replace(p, []byte("\r"), nil, -1))
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+type countingWriter struct {
+	writer io.Writer
+	count  int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.count += int64(n)
+
+	return n, err
 }
@@ -1,6 +1,15 @@
 package mail_test
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	netmail "net/mail"
+	"net/textproto"
 	"strings"
 	"time"
 
@@ -40,8 +49,10 @@ var _ = Describe("Message", func() {
 				"From: me@example.com",
 				"To: you@example.com",
 				"Subject: Super Urgent! Read Now!",
-				"Content-Type: multipart/alternative; boundary=" + boundary,
-				"Date: " + time.Now().Format(time.RFC822Z),
+				"Content-Type: multipart/alternative;",
+				" boundary=" + boundary,
+				"Date: " + msg.Date,
+				"Message-ID: " + msg.MessageID,
 				"Mime-Version: 1.0",
 				"",
 				"--" + boundary,
@@ -70,8 +81,10 @@ var _ = Describe("Message", func() {
 					"Reply-To: banana@chiquita.com",
 					"To: you@example.com",
 					"Subject: Super Urgent! Read Now!",
-					"Content-Type: multipart/alternative; boundary=" + boundary,
-					"Date: " + time.Now().Format(time.RFC822Z),
+					"Content-Type: multipart/alternative;",
+					" boundary=" + boundary,
+					"Date: " + msg.Date,
+					"Message-ID: " + msg.MessageID,
 					"Mime-Version: 1.0",
 					"",
 					"--" + boundary,
@@ -89,6 +102,16 @@ var _ = Describe("Message", func() {
 				}))
 			})
 
+			It("includes Cc but never Bcc in the headers", func() {
+				msg.Cc = []string{"Shared Inbox <shared@example.com>", "team@example.com"}
+				msg.Bcc = []string{"audit@example.com"}
+				data := msg.Data()
+
+				Expect(strings.Split(data, "\n")).To(ContainElement(`Cc: "Shared Inbox" <shared@example.com>, team@example.com`))
+				Expect(data).NotTo(ContainSubstring("Bcc"))
+				Expect(data).NotTo(ContainSubstring("audit@example.com"))
+			})
+
 			It("includes headers in the response if there are any", func() {
 				msg.Headers = append(msg.Headers, "X-ClientID: banana")
 				parts := strings.Split(msg.Data(), "\n")
@@ -99,8 +122,10 @@ var _ = Describe("Message", func() {
 					"To: you@example.com",
 					"Subject: Super Urgent! Read Now!",
 					"X-ClientID: banana",
-					"Content-Type: multipart/alternative; boundary=" + boundary,
-					"Date: " + time.Now().Format(time.RFC822Z),
+					"Content-Type: multipart/alternative;",
+					" boundary=" + boundary,
+					"Date: " + msg.Date,
+					"Message-ID: " + msg.MessageID,
 					"Mime-Version: 1.0",
 					"",
 					"--" + boundary,
@@ -129,7 +154,8 @@ var _ = Describe("Message", func() {
 				parts := strings.Split(msg.Data(), "\n")
 
 				Expect(parts).To(Equal([]string{
-					"Date: " + time.Now().Format(time.RFC822Z),
+					"Date: " + msg.Date,
+					"Message-ID: " + msg.MessageID,
 					"Mime-Version: 1.0",
 					"Content-Type: text/html; charset=UTF-8",
 					"Content-Transfer-Encoding: quoted-printable",
@@ -141,5 +167,291 @@ var _ = Describe("Message", func() {
 				}))
 			})
 		})
+
+		It("generates a date and message id once and reuses them", func() {
+			data := msg.Data()
+
+			date, err := time.Parse(time.RFC1123Z, msg.Date)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(date).To(BeTemporally("~", time.Now(), 2*time.Second))
+			Expect(msg.MessageID).To(MatchRegexp(`^<[0-9a-f]{32}\.\d+@example\.com>$`))
+
+			Expect(msg.Data()).To(Equal(data))
+		})
+
+		It("generates a different message id for each message", func() {
+			other := msg
+
+			msg.Data()
+			other.Data()
+
+			Expect(other.MessageID).NotTo(Equal(msg.MessageID))
+		})
+
+		It("encodes non-ASCII subjects", func() {
+			msg.Subject = "Wichtige Änderung: Über Ihr Konto"
+			headers := parseHeaders(msg.Data())
+
+			Expect(headers.Get("Subject")).To(HavePrefix("=?UTF-8?q?"))
+			Expect(decodeHeader(headers.Get("Subject"))).To(Equal("Wichtige Änderung: Über Ihr Konto"))
+		})
+
+		It("folds long encoded subjects", func() {
+			msg.Subject = "アカウントに関する重要なお知らせ：パスワードの有効期限が近づいています。今すぐ更新してください。"
+			data := msg.Data()
+
+			for _, line := range strings.Split(data, "\n") {
+				Expect(len(line)).To(BeNumerically("<=", 78))
+			}
+
+			headers := parseHeaders(data)
+			Expect(decodeHeader(headers.Get("Subject"))).To(Equal(msg.Subject))
+		})
+
+		It("folds long headers at whitespace without changing their value", func() {
+			msg.Headers = []string{"X-Long: " + strings.Repeat("banana ", 20) + "split"}
+			data := msg.Data()
+
+			Expect(data).To(ContainSubstring("X-Long: banana"))
+			for _, line := range strings.Split(data, "\n") {
+				Expect(len(line)).To(BeNumerically("<=", 78))
+			}
+
+			headers := parseHeaders(data)
+			Expect(headers.Get("X-Long")).To(Equal(strings.Repeat("banana ", 20) + "split"))
+		})
+
+		It("encodes non-ASCII display names", func() {
+			msg.From = "Jürgen Müller <juergen@example.com>"
+			msg.To = "山田太郎 <taro@example.com>"
+			headers := parseHeaders(msg.Data())
+
+			Expect(headers.Get("From")).To(Equal("=?utf-8?q?J=C3=BCrgen_M=C3=BCller?= <juergen@example.com>"))
+			Expect(decodeHeader(headers.Get("To"))).To(Equal("山田太郎 <taro@example.com>"))
+		})
+
+		It("leaves percent signs in the content alone", func() {
+			msg.Body = []mail.Part{
+				{
+					ContentType: "text/plain",
+					Content:     "100% done, 50%% off, %s %d",
+				},
+			}
+
+			Expect(msg.Data()).To(HaveSuffix("\n100% done, 50%% off, %s %d"))
+		})
+	})
+
+	Describe("EnvelopeFrom", func() {
+		It("returns the sender address without its display name", func() {
+			msg := mail.Message{From: "Notifications <no-reply@example.com>"}
+
+			Expect(msg.EnvelopeFrom()).To(Equal("no-reply@example.com"))
+		})
+
+		It("passes through a sender that cannot be parsed", func() {
+			msg := mail.Message{From: "not an address"}
+
+			Expect(msg.EnvelopeFrom()).To(Equal("not an address"))
+		})
+	})
+
+	Describe("Recipients", func() {
+		It("returns the envelope address of every recipient", func() {
+			msg := mail.Message{
+				To:  "you@example.com, Team <team@example.com>",
+				Cc:  []string{"Shared Inbox <shared@example.com>"},
+				Bcc: []string{"audit@example.com"},
+			}
+
+			Expect(msg.Recipients()).To(Equal([]string{
+				"you@example.com",
+				"team@example.com",
+				"shared@example.com",
+				"audit@example.com",
+			}))
+		})
+
+		It("skips duplicate recipients regardless of case", func() {
+			msg := mail.Message{
+				To:  "you@example.com",
+				Cc:  []string{"YOU@example.com"},
+				Bcc: []string{"you@example.com"},
+			}
+
+			Expect(msg.Recipients()).To(Equal([]string{"you@example.com"}))
+		})
+
+		It("passes through a recipient that cannot be parsed", func() {
+			msg := mail.Message{To: "not an address"}
+
+			Expect(msg.Recipients()).To(Equal([]string{"not an address"}))
+		})
+
+		It("returns nothing when there are no recipients", func() {
+			Expect(mail.Message{}.Recipients()).To(BeEmpty())
+		})
+	})
+
+	Describe("Attachments", func() {
+		var msg mail.Message
+
+		BeforeEach(func() {
+			msg = mail.Message{
+				From:    "me@example.com",
+				To:      "you@example.com",
+				Subject: "Your invoice",
+				Body: []mail.Part{
+					{
+						ContentType: "text/plain",
+						Content:     "Banana",
+					},
+					{
+						ContentType: "text/html",
+						Content:     "<p>banana</p>",
+					},
+				},
+				Attachments: []mail.Attachment{
+					{
+						Filename: "invoice.pdf",
+						Content:  bytes.Repeat([]byte("%PDF-1.4 banana "), 20),
+					},
+					{
+						Filename:    "maintenance.ics",
+						ContentType: "text/calendar; method=REQUEST",
+						Content:     []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"),
+					},
+				},
+			}
+		})
+
+		readParts := func(reader *multipart.Reader) []*multipart.Part {
+			var parts []*multipart.Part
+			for {
+				part, err := reader.NextPart()
+				if err == io.EOF {
+					return parts
+				}
+				Expect(err).NotTo(HaveOccurred())
+
+				content, err := ioutil.ReadAll(part)
+				Expect(err).NotTo(HaveOccurred())
+				part.Header.Set("X-Test-Content", string(content))
+				parts = append(parts, part)
+			}
+		}
+
+		It("sends the body and the attachments as multipart/mixed", func() {
+			message, err := netmail.ReadMessage(strings.NewReader(msg.Data()))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(message.Header.Get("Content-Transfer-Encoding")).To(BeEmpty())
+
+			mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mediaType).To(Equal("multipart/mixed"))
+
+			parts := readParts(multipart.NewReader(message.Body, params["boundary"]))
+			Expect(parts).To(HaveLen(3))
+
+			mediaType, params, err = mime.ParseMediaType(parts[0].Header.Get("Content-Type"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mediaType).To(Equal("multipart/alternative"))
+
+			alternatives := readParts(multipart.NewReader(strings.NewReader(parts[0].Header.Get("X-Test-Content")), params["boundary"]))
+			Expect(alternatives).To(HaveLen(2))
+			Expect(alternatives[0].Header.Get("Content-Type")).To(Equal("text/plain; charset=UTF-8"))
+			Expect(alternatives[0].Header.Get("X-Test-Content")).To(Equal("Banana"))
+			Expect(alternatives[1].Header.Get("Content-Type")).To(Equal("text/html; charset=UTF-8"))
+			Expect(alternatives[1].Header.Get("X-Test-Content")).To(Equal("<p>banana</p>"))
+
+			Expect(parts[1].Header.Get("Content-Type")).To(Equal(`application/pdf; name=invoice.pdf`))
+			Expect(parts[1].Header.Get("Content-Disposition")).To(Equal(`attachment; filename=invoice.pdf`))
+			Expect(parts[1].Header.Get("Content-Transfer-Encoding")).To(Equal("base64"))
+
+			encoded := parts[1].Header.Get("X-Test-Content")
+			for _, line := range strings.Split(strings.TrimSpace(encoded), "\n") {
+				Expect(len(line)).To(BeNumerically("<=", 76))
+			}
+
+			content, err := base64.StdEncoding.DecodeString(strings.Replace(encoded, "\n", "", -1))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content).To(Equal(msg.Attachments[0].Content))
+
+			Expect(parts[2].Header.Get("Content-Type")).To(Equal(`text/calendar; method=REQUEST; name=maintenance.ics`))
+		})
+
+		It("does not nest a single body part", func() {
+			msg.Body = msg.Body[:1]
+
+			message, err := netmail.ReadMessage(strings.NewReader(msg.Data()))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+			Expect(err).NotTo(HaveOccurred())
+
+			parts := readParts(multipart.NewReader(message.Body, params["boundary"]))
+			Expect(parts).To(HaveLen(3))
+			Expect(parts[0].Header.Get("Content-Type")).To(Equal("text/plain; charset=UTF-8"))
+			Expect(parts[0].Header.Get("X-Test-Content")).To(Equal("Banana"))
+		})
+
+		It("renders the same message every time", func() {
+			Expect(msg.Data()).To(Equal(msg.Data()))
+		})
+
+		It("falls back to application/octet-stream for unknown types", func() {
+			msg.Attachments = []mail.Attachment{
+				{
+					Filename: "data.banana",
+					Content:  []byte("banana"),
+				},
+			}
+
+			Expect(msg.Data()).To(ContainSubstring("Content-Type: application/octet-stream; name=data.banana\n"))
+		})
+	})
+
+	Describe("WriteTo", func() {
+		It("writes the same message as Data", func() {
+			msg := mail.Message{
+				From:    "me@example.com",
+				To:      "you@example.com",
+				Subject: "Streaming",
+				Body: []mail.Part{
+					{
+						ContentType: "text/plain",
+						Content:     "Banana",
+					},
+				},
+			}
+
+			buffer := bytes.NewBuffer([]byte{})
+			n, err := msg.WriteTo(buffer)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(int64(buffer.Len())))
+
+			Expect(buffer.String()).To(Equal(msg.Data()))
+		})
 	})
 })
+
+func parseHeaders(data string) textproto.MIMEHeader {
+	head := strings.SplitN(data, "\n\n", 2)[0]
+	reader := textproto.NewReader(bufio.NewReader(strings.NewReader(head + "\n\n")))
+
+	headers, err := reader.ReadMIMEHeader()
+	if err != nil {
+		panic(err)
+	}
+
+	return headers
+}
+
+func decodeHeader(value string) string {
+	decoded, err := new(mime.WordDecoder).DecodeHeader(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return decoded
+}
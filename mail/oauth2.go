@@ -0,0 +1,154 @@
+package mail
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tokens are refreshed this long before they expire, so that a token is not
+// handed out just as the server stops accepting it.
+const tokenExpiryMargin = time.Minute
+
+// TokenSource supplies the OAuth 2.0 access token for XOAUTH2 auth.
+type TokenSource interface {
+	Token() (string, error)
+}
+
+// StaticToken is an access token that is used as is. Providers usually
+// expire access tokens within an hour, so a static token stops working
+// unless it is replaced; use an OAuth2TokenSource for long-running
+// deployments.
+type StaticToken string
+
+func (t StaticToken) Token() (string, error) {
+	return string(t), nil
+}
+
+type OAuth2Config struct {
+	TokenURL      string
+	ClientID      string
+	ClientSecret  string
+	RefreshToken  string
+	Scope         string
+	SkipVerifySSL bool
+	Timeout       time.Duration
+}
+
+// OAuth2TokenError is returned when the token endpoint responds with a
+// non-200 status.
+type OAuth2TokenError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e OAuth2TokenError) Error() string {
+	return fmt.Sprintf("OAuth2 token endpoint responded with %d: %s", e.StatusCode, e.Body)
+}
+
+// OAuth2TokenSource fetches access tokens from an OAuth 2.0 token endpoint
+// and caches each one until shortly before it expires. With a refresh token
+// it uses the refresh_token grant, keeping any replacement refresh token the
+// server issues; without one it uses the client_credentials grant.
+type OAuth2TokenSource struct {
+	config OAuth2Config
+	client *http.Client
+	clock  clock
+
+	mutex  sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+func NewOAuth2TokenSource(config OAuth2Config, clock clock) *OAuth2TokenSource {
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	return &OAuth2TokenSource{
+		config: config,
+		clock:  clock,
+		client: &http.Client{
+			Timeout: config.Timeout,
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: config.SkipVerifySSL,
+				},
+			},
+		},
+	}
+}
+
+func (s *OAuth2TokenSource) Token() (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.clock.Now()
+	if s.token != "" && now.Add(tokenExpiryMargin).Before(s.expiry) {
+		return s.token, nil
+	}
+
+	form := url.Values{
+		"client_id":     {s.config.ClientID},
+		"client_secret": {s.config.ClientSecret},
+	}
+	if s.config.RefreshToken != "" {
+		form.Set("grant_type", "refresh_token")
+		form.Set("refresh_token", s.config.RefreshToken)
+	} else {
+		form.Set("grant_type", "client_credentials")
+	}
+	if s.config.Scope != "" {
+		form.Set("scope", s.config.Scope)
+	}
+
+	response, err := s.client.Post(s.config.TokenURL, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return "", OAuth2TokenError{
+			StatusCode: response.StatusCode,
+			Body:       string(body),
+		}
+	}
+
+	var token tokenResponse
+	err = json.Unmarshal(body, &token)
+	if err != nil {
+		return "", err
+	}
+
+	if token.AccessToken == "" {
+		return "", errors.New("OAuth2 token endpoint did not return an access token")
+	}
+
+	s.token = token.AccessToken
+	s.expiry = now.Add(time.Duration(token.ExpiresIn) * time.Second)
+	if token.RefreshToken != "" {
+		s.config.RefreshToken = token.RefreshToken
+	}
+
+	return s.token, nil
+}
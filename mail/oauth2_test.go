@@ -0,0 +1,136 @@
+package mail_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OAuth2TokenSource", func() {
+	var (
+		server    *httptest.Server
+		clock     *mocks.Clock
+		requests  []url.Values
+		responses []string
+		status    int
+		config    mail.OAuth2Config
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		responses = []string{
+			`{"access_token": "first-token", "expires_in": 3600, "refresh_token": "rotated-refresh-token"}`,
+			`{"access_token": "second-token", "expires_in": 3600}`,
+		}
+		status = http.StatusOK
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			req.ParseForm()
+			requests = append(requests, req.PostForm)
+
+			w.WriteHeader(status)
+			w.Write([]byte(responses[0]))
+			responses = responses[1:]
+		}))
+
+		clock = mocks.NewClock()
+		clock.NowCall.Returns.Time = time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+
+		config = mail.OAuth2Config{
+			TokenURL:     server.URL,
+			ClientID:     "some-client-id",
+			ClientSecret: "some-client-secret",
+			RefreshToken: "some-refresh-token",
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("exchanges the refresh token for an access token and caches it", func() {
+		source := mail.NewOAuth2TokenSource(config, clock)
+
+		token, err := source.Token()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token).To(Equal("first-token"))
+
+		token, err = source.Token()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token).To(Equal("first-token"))
+
+		Expect(requests).To(Equal([]url.Values{
+			{
+				"grant_type":    {"refresh_token"},
+				"refresh_token": {"some-refresh-token"},
+				"client_id":     {"some-client-id"},
+				"client_secret": {"some-client-secret"},
+			},
+		}))
+	})
+
+	It("refreshes the token shortly before it expires, using the rotated refresh token", func() {
+		source := mail.NewOAuth2TokenSource(config, clock)
+
+		_, err := source.Token()
+		Expect(err).NotTo(HaveOccurred())
+
+		clock.NowCall.Returns.Time = clock.NowCall.Returns.Time.Add(59 * time.Minute)
+
+		token, err := source.Token()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token).To(Equal("second-token"))
+
+		Expect(requests).To(HaveLen(2))
+		Expect(requests[1].Get("refresh_token")).To(Equal("rotated-refresh-token"))
+	})
+
+	It("uses the client credentials grant when there is no refresh token", func() {
+		config.RefreshToken = ""
+		config.Scope = "https://outlook.office365.com/.default"
+		source := mail.NewOAuth2TokenSource(config, clock)
+
+		_, err := source.Token()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(requests).To(Equal([]url.Values{
+			{
+				"grant_type":    {"client_credentials"},
+				"scope":         {"https://outlook.office365.com/.default"},
+				"client_id":     {"some-client-id"},
+				"client_secret": {"some-client-secret"},
+			},
+		}))
+	})
+
+	Context("when the token endpoint rejects the request", func() {
+		It("returns an error", func() {
+			status = http.StatusBadRequest
+			responses = []string{`{"error": "invalid_grant"}`}
+			source := mail.NewOAuth2TokenSource(config, clock)
+
+			_, err := source.Token()
+			Expect(err).To(Equal(mail.OAuth2TokenError{
+				StatusCode: http.StatusBadRequest,
+				Body:       `{"error": "invalid_grant"}`,
+			}))
+		})
+	})
+
+	Context("when the response has no access token", func() {
+		It("returns an error", func() {
+			responses = []string{`{}`}
+			source := mail.NewOAuth2TokenSource(config, clock)
+
+			_, err := source.Token()
+			Expect(err).To(MatchError("OAuth2 token endpoint did not return an access token"))
+		})
+	})
+})
@@ -0,0 +1,220 @@
+package mail
+
+import (
+	"errors"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/metrics"
+	"github.com/pivotal-golang/lager"
+)
+
+type clock interface {
+	Now() time.Time
+}
+
+// Relay is one SMTP server that mail can be delivered through. Name
+// identifies the relay in logs and metrics, usually as host:port.
+type Relay struct {
+	Name      string
+	Weight    int
+	Transport Transport
+}
+
+// RelayHealth tracks consecutive delivery failures for each relay. A relay
+// that fails FailureThreshold times in a row is cooled down and skipped
+// until the cooldown has passed. It is safe to share between workers so
+// that every worker stops using a relay once it is known to be down.
+type RelayHealth struct {
+	mutex            sync.Mutex
+	clock            clock
+	failureThreshold int
+	cooldown         time.Duration
+	relays           map[string]*relayState
+}
+
+type relayState struct {
+	failures  int
+	coolUntil time.Time
+}
+
+func NewRelayHealth(clock clock, failureThreshold int, cooldown time.Duration) *RelayHealth {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+
+	return &RelayHealth{
+		clock:            clock,
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		relays:           map[string]*relayState{},
+	}
+}
+
+func (h *RelayHealth) Available(name string) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return !h.state(name).coolUntil.After(h.clock.Now())
+}
+
+func (h *RelayHealth) RecordSuccess(name string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	state := h.state(name)
+	state.failures = 0
+	state.coolUntil = time.Time{}
+}
+
+// RecordFailure counts a failed delivery and reports whether the relay has
+// just been cooled down.
+func (h *RelayHealth) RecordFailure(name string) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	state := h.state(name)
+	state.failures++
+	if state.failures < h.failureThreshold {
+		return false
+	}
+
+	state.failures = 0
+	state.coolUntil = h.clock.Now().Add(h.cooldown)
+	return true
+}
+
+func (h *RelayHealth) state(name string) *relayState {
+	state, ok := h.relays[name]
+	if !ok {
+		state = &relayState{}
+		h.relays[name] = state
+	}
+
+	return state
+}
+
+// RelayTransport delivers each message through the first relay that
+// accepts it. Relays are tried in the configured order or, when any relay
+// has a weight, in a weighted random order. Relays that are cooling down
+// are only tried once every other relay has failed. A permanent rejection
+// is returned straight away because another relay would reject the
+// message too.
+type RelayTransport struct {
+	relays        []Relay
+	health        *RelayHealth
+	metricsLogger *log.Logger
+	random        func(int) int
+}
+
+func NewRelayTransport(relays []Relay, health *RelayHealth, metricsLogger *log.Logger) *RelayTransport {
+	return &RelayTransport{
+		relays:        relays,
+		health:        health,
+		metricsLogger: metricsLogger,
+		random:        rand.Intn,
+	}
+}
+
+// Connect is a no-op. Each relay connects when a message is sent through it
+// so that a relay that cannot be reached fails over to the next one.
+func (t *RelayTransport) Connect(logger lager.Logger) error {
+	return nil
+}
+
+func (t *RelayTransport) Send(msg Message, logger lager.Logger) error {
+	err := errors.New("no SMTP relays are configured")
+
+	for _, relay := range t.candidates() {
+		relayLogger := logger.Session("relay", lager.Data{"relay": relay.Name})
+
+		err = relay.Transport.Send(msg, relayLogger)
+		if err == nil {
+			t.health.RecordSuccess(relay.Name)
+			t.increment("notifications.smtp.relay.delivered", relay.Name)
+			return nil
+		}
+
+		if IsPermanent(err) {
+			t.health.RecordSuccess(relay.Name)
+			t.increment("notifications.smtp.relay.rejected", relay.Name)
+			return err
+		}
+
+		relayLogger.Info("relay-failed", lager.Data{"error": err.Error()})
+		t.increment("notifications.smtp.relay.failed", relay.Name)
+
+		if t.health.RecordFailure(relay.Name) {
+			relayLogger.Info("relay-cooling-down")
+			t.increment("notifications.smtp.relay.cooldown", relay.Name)
+		}
+	}
+
+	return err
+}
+
+func (t *RelayTransport) candidates() []Relay {
+	var available, cooling []Relay
+	for _, relay := range t.relays {
+		if t.health.Available(relay.Name) {
+			available = append(available, relay)
+		} else {
+			cooling = append(cooling, relay)
+		}
+	}
+
+	return append(t.order(available), cooling...)
+}
+
+func (t *RelayTransport) order(relays []Relay) []Relay {
+	weighted := false
+	for _, relay := range relays {
+		if relay.Weight > 0 {
+			weighted = true
+		}
+	}
+
+	if !weighted {
+		return relays
+	}
+
+	remaining := append([]Relay{}, relays...)
+	ordered := make([]Relay, 0, len(relays))
+	for len(remaining) > 0 {
+		total := 0
+		for _, relay := range remaining {
+			total += relayWeight(relay)
+		}
+
+		pick := t.random(total)
+		for i, relay := range remaining {
+			pick -= relayWeight(relay)
+			if pick < 0 {
+				ordered = append(ordered, relay)
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				break
+			}
+		}
+	}
+
+	return ordered
+}
+
+func relayWeight(relay Relay) int {
+	if relay.Weight < 1 {
+		return 1
+	}
+
+	return relay.Weight
+}
+
+func (t *RelayTransport) increment(name, relay string) {
+	metrics.NewMetric("counter", map[string]interface{}{
+		"name": name,
+		"tags": map[string]string{
+			"relay": relay,
+		},
+	}).LogWith(t.metricsLogger)
+}
@@ -0,0 +1,213 @@
+package mail_test
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/metrics"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RelayTransport", func() {
+	var (
+		transport     *mail.RelayTransport
+		health        *mail.RelayHealth
+		clock         *mocks.Clock
+		primary       *mocks.MailClient
+		secondary     *mocks.MailClient
+		metricsBuffer *bytes.Buffer
+		logger        lager.Logger
+		msg           mail.Message
+	)
+
+	BeforeEach(func() {
+		clock = mocks.NewClock()
+		clock.NowCall.Returns.Time = time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+		health = mail.NewRelayHealth(clock, 2, time.Minute)
+
+		primary = mocks.NewMailClient()
+		secondary = mocks.NewMailClient()
+		metricsBuffer = bytes.NewBuffer([]byte{})
+		logger = lager.NewLogger("notifications")
+
+		transport = mail.NewRelayTransport([]mail.Relay{
+			{Name: "primary.example.com:587", Transport: primary},
+			{Name: "secondary.example.com:587", Transport: secondary},
+		}, health, metrics.NewLogger(metricsBuffer))
+
+		msg = mail.Message{
+			From: "me@example.com",
+			To:   "you@example.com",
+		}
+	})
+
+	Describe("Connect", func() {
+		It("leaves connecting to each relay", func() {
+			Expect(transport.Connect(logger)).To(Succeed())
+			Expect(primary.ConnectCall.Receives.Logger).To(BeNil())
+		})
+	})
+
+	Describe("Send", func() {
+		It("delivers through the first relay", func() {
+			Expect(transport.Send(msg, logger)).To(Succeed())
+
+			Expect(primary.SendCall.CallCount).To(Equal(1))
+			Expect(primary.SendCall.Receives.Message).To(Equal(msg))
+			Expect(secondary.SendCall.CallCount).To(Equal(0))
+			Expect(metricsBuffer.String()).To(ContainSubstring(`{"name":"notifications.smtp.relay.delivered","tags":{"relay":"primary.example.com:587"}}`))
+		})
+
+		It("fails over to the next relay when a relay fails", func() {
+			primary.SendCall.Returns.Error = errors.New("connection refused")
+
+			Expect(transport.Send(msg, logger)).To(Succeed())
+
+			Expect(primary.SendCall.CallCount).To(Equal(1))
+			Expect(secondary.SendCall.CallCount).To(Equal(1))
+			Expect(metricsBuffer.String()).To(ContainSubstring(`{"name":"notifications.smtp.relay.failed","tags":{"relay":"primary.example.com:587"}}`))
+			Expect(metricsBuffer.String()).To(ContainSubstring(`{"name":"notifications.smtp.relay.delivered","tags":{"relay":"secondary.example.com:587"}}`))
+		})
+
+		It("does not fail over when the message is rejected permanently", func() {
+			primary.SendCall.Returns.Error = mail.SMTPError{Code: 550, Message: "mailbox does not exist"}
+
+			err := transport.Send(msg, logger)
+			Expect(err).To(Equal(mail.SMTPError{Code: 550, Message: "mailbox does not exist"}))
+
+			Expect(secondary.SendCall.CallCount).To(Equal(0))
+			Expect(metricsBuffer.String()).To(ContainSubstring(`{"name":"notifications.smtp.relay.rejected","tags":{"relay":"primary.example.com:587"}}`))
+		})
+
+		It("returns the last error when every relay fails", func() {
+			primary.SendCall.Returns.Error = errors.New("connection refused")
+			secondary.SendCall.Returns.Error = errors.New("server timeout")
+
+			err := transport.Send(msg, logger)
+			Expect(err).To(MatchError("server timeout"))
+		})
+
+		It("returns an error when no relays are configured", func() {
+			transport = mail.NewRelayTransport(nil, health, metrics.NewLogger(metricsBuffer))
+
+			err := transport.Send(msg, logger)
+			Expect(err).To(MatchError("no SMTP relays are configured"))
+		})
+
+		Context("when a relay keeps failing", func() {
+			BeforeEach(func() {
+				primary.SendCall.Returns.Error = errors.New("connection refused")
+
+				Expect(transport.Send(msg, logger)).To(Succeed())
+				Expect(transport.Send(msg, logger)).To(Succeed())
+				primary.SendCall.Returns.Error = nil
+			})
+
+			It("skips the relay until the cooldown has passed", func() {
+				Expect(metricsBuffer.String()).To(ContainSubstring(`{"name":"notifications.smtp.relay.cooldown","tags":{"relay":"primary.example.com:587"}}`))
+
+				Expect(transport.Send(msg, logger)).To(Succeed())
+				Expect(primary.SendCall.CallCount).To(Equal(2))
+				Expect(secondary.SendCall.CallCount).To(Equal(3))
+
+				clock.NowCall.Returns.Time = clock.NowCall.Returns.Time.Add(time.Minute)
+
+				Expect(transport.Send(msg, logger)).To(Succeed())
+				Expect(primary.SendCall.CallCount).To(Equal(3))
+				Expect(secondary.SendCall.CallCount).To(Equal(3))
+			})
+
+			It("still tries the relay when every other relay has failed", func() {
+				secondary.SendCall.Returns.Error = errors.New("server timeout")
+
+				Expect(transport.Send(msg, logger)).To(Succeed())
+				Expect(secondary.SendCall.CallCount).To(Equal(3))
+				Expect(primary.SendCall.CallCount).To(Equal(3))
+			})
+
+			It("shares the relay health with other transports", func() {
+				other := mocks.NewMailClient()
+				otherTransport := mail.NewRelayTransport([]mail.Relay{
+					{Name: "primary.example.com:587", Transport: other},
+					{Name: "secondary.example.com:587", Transport: secondary},
+				}, health, metrics.NewLogger(metricsBuffer))
+
+				Expect(otherTransport.Send(msg, logger)).To(Succeed())
+				Expect(other.SendCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when the relays are weighted", func() {
+			BeforeEach(func() {
+				transport = mail.NewRelayTransport([]mail.Relay{
+					{Name: "primary.example.com:587", Weight: 1, Transport: primary},
+					{Name: "secondary.example.com:587", Weight: 3, Transport: secondary},
+				}, health, metrics.NewLogger(metricsBuffer))
+			})
+
+			It("picks the first relay in proportion to its weight", func() {
+				var totals []int
+				transport.SetRandom(func(total int) int {
+					totals = append(totals, total)
+					return total - 1
+				})
+
+				Expect(transport.Send(msg, logger)).To(Succeed())
+				Expect(totals).To(Equal([]int{4, 1}))
+				Expect(secondary.SendCall.CallCount).To(Equal(1))
+				Expect(primary.SendCall.CallCount).To(Equal(0))
+			})
+
+			It("fails over to the remaining relays", func() {
+				transport.SetRandom(func(total int) int {
+					return 0
+				})
+				primary.SendCall.Returns.Error = errors.New("connection refused")
+
+				Expect(transport.Send(msg, logger)).To(Succeed())
+				Expect(primary.SendCall.CallCount).To(Equal(1))
+				Expect(secondary.SendCall.CallCount).To(Equal(1))
+			})
+		})
+	})
+})
+
+var _ = Describe("RelayHealth", func() {
+	var (
+		health *mail.RelayHealth
+		clock  *mocks.Clock
+	)
+
+	BeforeEach(func() {
+		clock = mocks.NewClock()
+		clock.NowCall.Returns.Time = time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+		health = mail.NewRelayHealth(clock, 3, 30*time.Second)
+	})
+
+	It("cools a relay down after consecutive failures", func() {
+		Expect(health.RecordFailure("relay")).To(BeFalse())
+		Expect(health.RecordFailure("relay")).To(BeFalse())
+		Expect(health.Available("relay")).To(BeTrue())
+
+		Expect(health.RecordFailure("relay")).To(BeTrue())
+		Expect(health.Available("relay")).To(BeFalse())
+
+		clock.NowCall.Returns.Time = clock.NowCall.Returns.Time.Add(30 * time.Second)
+		Expect(health.Available("relay")).To(BeTrue())
+	})
+
+	It("resets the failure count after a success", func() {
+		health.RecordFailure("relay")
+		health.RecordFailure("relay")
+		health.RecordSuccess("relay")
+
+		Expect(health.RecordFailure("relay")).To(BeFalse())
+		Expect(health.Available("relay")).To(BeTrue())
+	})
+})
@@ -0,0 +1,11 @@
+package mail
+
+import "github.com/pivotal-golang/lager"
+
+// Transport delivers rendered messages. Client is the SMTP transport,
+// FileTransport writes messages to disk for local inspection, and
+// HTTPTransport posts them to an HTTP email gateway.
+type Transport interface {
+	Connect(lager.Logger) error
+	Send(Message, lager.Logger) error
+}
@@ -4,6 +4,8 @@ import (
 	"log"
 	"strconv"
 	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
 )
 
 type QueueGauge struct {
@@ -15,6 +17,12 @@ type QueueGauge struct {
 type queue interface {
 	Len() (int, error)
 	RetryQueueLengths() (map[int]int, error)
+	PriorityQueueLengths() (map[int]int, error)
+}
+
+var priorityNames = map[int]string{
+	gobble.PriorityNormal:   "normal",
+	gobble.PriorityCritical: "critical",
 }
 
 func NewQueueGauge(queue queue, logger *log.Logger, timer <-chan time.Time) QueueGauge {
@@ -29,6 +37,7 @@ func (g QueueGauge) Run() {
 	for _ = range g.timer {
 		length, _ := g.queue.Len()
 		retryCounts, _ := g.queue.RetryQueueLengths()
+		priorityCounts, _ := g.queue.PriorityQueueLengths()
 
 		NewMetric("gauge", map[string]interface{}{
 			"name":  "notifications.queue.length",
@@ -44,5 +53,15 @@ func (g QueueGauge) Run() {
 				"value": retryCounts[index],
 			}).LogWith(g.logger)
 		}
+
+		for _, priority := range []int{gobble.PriorityNormal, gobble.PriorityCritical} {
+			NewMetric("gauge", map[string]interface{}{
+				"name": "notifications.queue.priority",
+				"tags": map[string]interface{}{
+					"priority": priorityNames[priority],
+				},
+				"value": priorityCounts[priority],
+			}).LogWith(g.logger)
+		}
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/metrics"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 
@@ -49,6 +50,8 @@ var _ = Describe("QueueGauge", func() {
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"8"},"value":0}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"9"},"value":0}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"10"},"value":0}}`,
+			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.priority","tags":{"priority":"normal"},"value":0}}`,
+			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.priority","tags":{"priority":"critical"},"value":0}}`,
 			"",
 		}))
 
@@ -73,6 +76,8 @@ var _ = Describe("QueueGauge", func() {
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"8"},"value":0}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"9"},"value":0}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"10"},"value":0}}`,
+			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.priority","tags":{"priority":"normal"},"value":0}}`,
+			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.priority","tags":{"priority":"critical"},"value":0}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.length","value":1}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"0"},"value":1}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"1"},"value":0}}`,
@@ -85,6 +90,8 @@ var _ = Describe("QueueGauge", func() {
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"8"},"value":0}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"9"},"value":0}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"10"},"value":0}}`,
+			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.priority","tags":{"priority":"normal"},"value":0}}`,
+			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.priority","tags":{"priority":"critical"},"value":0}}`,
 			"",
 		}))
 
@@ -107,6 +114,8 @@ var _ = Describe("QueueGauge", func() {
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"8"},"value":0}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"9"},"value":0}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"10"},"value":0}}`,
+			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.priority","tags":{"priority":"normal"},"value":0}}`,
+			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.priority","tags":{"priority":"critical"},"value":0}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.length","value":1}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"0"},"value":1}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"1"},"value":0}}`,
@@ -119,6 +128,8 @@ var _ = Describe("QueueGauge", func() {
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"8"},"value":0}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"9"},"value":0}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"10"},"value":0}}`,
+			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.priority","tags":{"priority":"normal"},"value":0}}`,
+			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.priority","tags":{"priority":"critical"},"value":0}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.length","value":0}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"0"},"value":0}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"1"},"value":0}}`,
@@ -131,6 +142,8 @@ var _ = Describe("QueueGauge", func() {
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"8"},"value":0}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"9"},"value":0}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"10"},"value":0}}`,
+			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.priority","tags":{"priority":"normal"},"value":0}}`,
+			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.priority","tags":{"priority":"critical"},"value":0}}`,
 			"",
 		}))
 	})
@@ -162,7 +175,30 @@ var _ = Describe("QueueGauge", func() {
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"8"},"value":0}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"9"},"value":0}}`,
 			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.retry","tags":{"count":"10"},"value":0}}`,
+			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.priority","tags":{"priority":"normal"},"value":0}}`,
+			`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.priority","tags":{"priority":"critical"},"value":0}}`,
 			"",
 		}))
 	})
+
+	It("reports the number of jobs grouped by priority", func() {
+		go gauge.Run()
+
+		Expect(buffer.String()).To(BeEmpty())
+
+		queue.PriorityQueueLengthsCall.Returns.Lengths = map[int]int{
+			gobble.PriorityNormal:   5,
+			gobble.PriorityCritical: 2,
+		}
+		queue.LenCall.Returns.Length = 7
+		timer <- time.Now()
+
+		Eventually(func() []string {
+			return strings.Split(buffer.String(), "\n")
+		}).Should(ContainElement(`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.priority","tags":{"priority":"normal"},"value":5}}`))
+
+		Eventually(func() []string {
+			return strings.Split(buffer.String(), "\n")
+		}).Should(ContainElement(`[METRIC] {"kind":"gauge","payload":{"name":"notifications.queue.priority","tags":{"priority":"critical"},"value":2}}`))
+	})
 })
@@ -4,7 +4,8 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"os"
-	"time"
+	"strconv"
+	"strings"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
 	"github.com/cloudfoundry-incubator/notifications/db"
@@ -31,7 +32,8 @@ import (
 type mother interface {
 	SQLDatabase() *sql.DB
 	Database() db.DatabaseInterface
-	MailClient() *mail.Client
+	MailTransport() mail.Transport
+	Queue() gobble.QueueInterface
 }
 
 type uaaTokenValidator interface {
@@ -39,22 +41,23 @@ type uaaTokenValidator interface {
 }
 
 type Config struct {
-	UAAClientID          string
-	UAAClientSecret      string
-	UAATokenValidator    *uaa.TokenValidator
-	UAAHost              string
-	VerifySSL            bool
-	InstanceIndex        int
-	WorkerCount          int
-	EncryptionKey        []byte
-	DBLoggingEnabled     bool
-	Sender               string
-	Domain               string
-	QueueWaitMaxDuration int
-	CCHost               string
+	UAAClientID       string
+	UAAClientSecret   string
+	UAATokenValidator *uaa.TokenValidator
+	UAAHost           string
+	VerifySSL         bool
+	InstanceIndex     int
+	WorkerCount       int
+	EncryptionKey     []byte
+	DBLoggingEnabled  bool
+	Sender            string
+	Domain            string
+	PublicURL         string
+	CCHost            string
+	RetryPolicies     common.RetryPolicies
 }
 
-func Boot(mom mother, config Config) {
+func Boot(mom mother, config Config) WorkerPool {
 	uaaClient := uaa.NewZonedUAAClient(config.UAAClientID, config.UAAClientSecret, config.VerifySSL, config.UAATokenValidator)
 
 	logger := lager.NewLogger("notifications")
@@ -65,10 +68,7 @@ func Boot(mom mother, config Config) {
 	sqlDatabase := mom.SQLDatabase()
 	database := mom.Database()
 
-	gobbleDatabase := gobble.NewDatabase(sqlDatabase)
-	gobbleQueue := gobble.NewQueue(gobbleDatabase, clock, gobble.Config{
-		WaitMaxDuration: time.Duration(config.QueueWaitMaxDuration) * time.Millisecond,
-	})
+	gobbleQueue := mom.Queue()
 
 	cloak, err := conceal.NewCloak(config.EncryptionKey)
 	if err != nil {
@@ -85,13 +85,12 @@ func Boot(mom mother, config Config) {
 	clientsRepo := v1models.NewClientsRepo()
 	kindsRepo := v1models.NewKindsRepo()
 	templatesRepo := v1models.NewTemplatesRepo()
-	v1TemplateLoader := v1.NewTemplatesLoader(database, clientsRepo, kindsRepo, templatesRepo)
-	deliveryFailureHandler := common.NewDeliveryFailureHandler()
+	partialsRepo := v1models.NewPartialsRepo()
+	v1TemplateLoader := v1.NewTemplatesLoader(database, clientsRepo, kindsRepo, templatesRepo, partialsRepo)
+	deliveryFailureHandler := common.NewDeliveryFailureHandler(config.RetryPolicies.For(common.JobTypeV1))
 	messageStatusUpdater := v1.NewMessageStatusUpdater(messagesRepo)
 	userLoader := common.NewUserLoader(uaaClient)
 	tokenLoader := uaa.NewTokenLoader(uaaClient)
-	packager := common.NewPackager(v1TemplateLoader, cloak)
-
 	// V2
 	metricsEmitter := metrics.NewEmitter(metrics.DefaultLogger)
 
@@ -113,20 +112,29 @@ func Boot(mom mother, config Config) {
 	v2database := v2models.NewDatabase(sqlDatabase, v2models.Config{})
 	v2messageStatusUpdater := v2.NewV2MessageStatusUpdater(messagesRepository)
 	unsubscribersRepository := v2models.NewUnsubscribersRepository(guidGenerator.Generate)
+	suppressionsRepository := v2models.NewSuppressionsRepository(guidGenerator.Generate, clock)
 	campaignsRepository := v2models.NewCampaignsRepository(guidGenerator.Generate, clock)
+	sendersRepository := v2models.NewSendersRepository(guidGenerator.Generate)
 	v2templatesRepo := v2models.NewTemplatesRepository(guidGenerator.Generate)
-	templatesCollection := collections.NewTemplatesCollection(v2templatesRepo)
-	v2TemplateLoader := v2.NewTemplatesLoader(v2database, templatesCollection)
-	v2deliveryFailureHandler := common.NewDeliveryFailureHandler()
+	v2templateVersionsRepo := v2models.NewTemplateVersionsRepository(guidGenerator.Generate, clock)
+	v2partialsRepo := v2models.NewPartialsRepository(guidGenerator.Generate)
+	templatesCollection := collections.NewTemplatesCollection(v2templatesRepo, v2templateVersionsRepo, v2partialsRepo)
+	partialsCollection := collections.NewPartialsCollection(v2partialsRepo, v2templatesRepo)
+	v2TemplateLoader := v2.NewTemplatesLoader(v2database, templatesCollection, partialsCollection)
+	attachmentsRepository := v2models.NewAttachmentsRepository(guidGenerator.Generate, clock)
+	attachmentsLoader := v2.NewAttachmentsLoader(v2database, attachmentsRepository)
+	packager := common.NewPackager(v1TemplateLoader, attachmentsLoader, cloak, unsubscribeURL(config.PublicURL, 1))
+	v2deliveryFailureHandler := common.NewDeliveryFailureHandler(config.RetryPolicies.For(common.JobTypeV2))
+	campaignFailureHandler := common.NewDeliveryFailureHandler(config.RetryPolicies.For(common.JobTypeCampaign))
 	campaignJobProcessor := v2.NewCampaignJobProcessor(notify.EmailFormatter{}, notify.HTMLExtractor{},
 		emailsAudienceGenerator, spacesAudienceGenerator, orgsAudienceGenerator, usersAudienceGenerator, v2enqueuer)
 
-	WorkerGenerator{
+	workers := WorkerGenerator{
 		InstanceIndex: config.InstanceIndex,
 		Count:         config.WorkerCount,
 	}.Work(func(index int) Worker {
 
-		mailClient := mom.MailClient()
+		mailClient := mom.MailTransport()
 
 		v1DeliveryJobProcessor := v1.NewDeliveryJobProcessor(v1.DeliveryJobProcessorConfig{
 			DBTrace: config.DBLoggingEnabled,
@@ -144,15 +152,16 @@ func Boot(mom mother, config Config) {
 			ReceiptsRepo:           receiptsRepo,
 			UnsubscribesRepo:       unsubscribesRepo,
 			GlobalUnsubscribesRepo: globalUnsubscribesRepo,
+			SuppressionsRepo:       suppressionsRepository,
 			MessageStatusUpdater:   messageStatusUpdater,
 			DeliveryFailureHandler: deliveryFailureHandler,
 		})
 
-		v2mailClient := mom.MailClient()
+		v2mailClient := mom.MailTransport()
 
-		v2DeliveryJobProcessor := v2.NewDeliveryJobProcessor(v2mailClient, common.NewPackager(v2TemplateLoader, cloak),
+		v2DeliveryJobProcessor := v2.NewDeliveryJobProcessor(v2mailClient, common.NewPackager(v2TemplateLoader, attachmentsLoader, cloak, unsubscribeURL(config.PublicURL, 2)),
 			common.NewUserLoader(uaaClient), uaa.NewTokenLoader(uaaClient), v2messageStatusUpdater, v2database,
-			unsubscribersRepository, campaignsRepository, config.Sender, config.Domain, config.UAAHost, metricsEmitter)
+			unsubscribersRepository, suppressionsRepository, campaignsRepository, sendersRepository, config.Sender, config.Domain, config.UAAHost, metricsEmitter)
 
 		worker := NewDeliveryWorker(v1DeliveryJobProcessor, v2DeliveryJobProcessor, DeliveryWorkerConfig{
 			ID:      index,
@@ -165,9 +174,28 @@ func Boot(mom mother, config Config) {
 			Database:               v2database,
 			CampaignJobProcessor:   campaignJobProcessor,
 			DeliveryFailureHandler: v2deliveryFailureHandler,
+			CampaignFailureHandler: campaignFailureHandler,
 			MessageStatusUpdater:   v2messageStatusUpdater,
 		})
 
 		return &worker
 	})
+
+	return NewWorkerPool(gobbleQueue, workers)
+}
+
+// unsubscribeURL returns the one-click unsubscribe endpoint for the given
+// API version. Mail clients cannot set the X-NOTIFICATIONS-VERSION header,
+// so versions other than the default are selected with a query parameter.
+func unsubscribeURL(publicURL string, version int) string {
+	if publicURL == "" {
+		return ""
+	}
+
+	unsubscribeURL := strings.TrimSuffix(publicURL, "/") + "/unsubscribe"
+	if version != 1 {
+		unsubscribeURL += "?version=" + strconv.Itoa(version)
+	}
+
+	return unsubscribeURL
 }
@@ -0,0 +1,110 @@
+package postal
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/pivotal-golang/lager"
+)
+
+type bounceProcessor interface {
+	Process(conn db.ConnectionInterface, dsn mail.DeliveryStatusNotification, logger lager.Logger) error
+}
+
+// BouncePoller reads bounce reports delivered to a maildir. Each report in
+// new/ is processed and then moved to cur/ and flagged as seen. Reports
+// that fail to process because of a database error stay in new/ and are
+// retried on the next poll.
+type BouncePoller struct {
+	maildir         string
+	processor       bounceProcessor
+	db              db.DatabaseInterface
+	logger          lager.Logger
+	timer           <-chan time.Time
+	pollingInterval time.Duration
+	halt            chan struct{}
+	done            chan struct{}
+}
+
+func NewBouncePoller(maildir string, processor bounceProcessor, db db.DatabaseInterface, pollingInterval time.Duration, logger lager.Logger) BouncePoller {
+	return BouncePoller{
+		maildir:         maildir,
+		processor:       processor,
+		db:              db,
+		logger:          logger.Session("bounce-poller"),
+		pollingInterval: pollingInterval,
+		timer:           time.After(0),
+		halt:            make(chan struct{}),
+		done:            make(chan struct{}),
+	}
+}
+
+func (p BouncePoller) Poll() {
+	files, err := ioutil.ReadDir(filepath.Join(p.maildir, "new"))
+	if err != nil {
+		p.logger.Error("read-maildir-failed", err)
+		return
+	}
+
+	for _, file := range files {
+		if !file.Mode().IsRegular() {
+			continue
+		}
+
+		err := p.process(file.Name())
+		if err != nil {
+			p.logger.Error("process-failed", err, lager.Data{"file": file.Name()})
+			continue
+		}
+
+		err = os.Rename(filepath.Join(p.maildir, "new", file.Name()), filepath.Join(p.maildir, "cur", file.Name()+":2,S"))
+		if err != nil {
+			p.logger.Error("move-failed", err, lager.Data{"file": file.Name()})
+		}
+	}
+}
+
+func (p BouncePoller) process(name string) error {
+	file, err := os.Open(filepath.Join(p.maildir, "new", name))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	dsn, err := mail.ParseDSN(file)
+	if err != nil {
+		// Mail that is not a bounce report will never parse, so it is
+		// moved out of new/ rather than retried.
+		p.logger.Info("not-a-bounce", lager.Data{"file": name, "error": err.Error()})
+		return nil
+	}
+
+	return p.processor.Process(p.db.Connection(), dsn, p.logger)
+}
+
+func (p BouncePoller) Run() {
+	go func() {
+		defer close(p.done)
+
+		for {
+			select {
+			case <-p.timer:
+				p.Poll()
+				p.timer = time.After(p.pollingInterval)
+			case <-p.halt:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts polling and waits for a poll that is in progress to finish.
+// It must only be called after Run.
+func (p BouncePoller) Stop() {
+	close(p.halt)
+	<-p.done
+}
@@ -0,0 +1,130 @@
+package postal_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/postal"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const maildirBounce = "Content-Type: multipart/report; report-type=delivery-status; boundary=\"REPORT\"\r\n" +
+	"\r\n" +
+	"--REPORT\r\n" +
+	"Content-Type: message/delivery-status\r\n" +
+	"\r\n" +
+	"Reporting-MTA: dns; mx.example.com\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; missing@example.com\r\n" +
+	"Action: failed\r\n" +
+	"Status: 5.1.1\r\n" +
+	"--REPORT\r\n" +
+	"Content-Type: text/rfc822-headers\r\n" +
+	"\r\n" +
+	"X-CF-Notification-ID: some-message-id\r\n" +
+	"\r\n" +
+	"--REPORT--\r\n"
+
+var _ = Describe("BouncePoller", func() {
+	var (
+		poller     postal.BouncePoller
+		processor  *mocks.BounceProcessor
+		database   *mocks.Database
+		connection *mocks.Connection
+		maildir    string
+	)
+
+	BeforeEach(func() {
+		var err error
+		maildir, err = ioutil.TempDir("", "bounces")
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, dir := range []string{"new", "cur", "tmp"} {
+			Expect(os.Mkdir(filepath.Join(maildir, dir), 0755)).To(Succeed())
+		}
+
+		connection = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+		processor = mocks.NewBounceProcessor()
+
+		poller = postal.NewBouncePoller(maildir, processor, database, 50*time.Millisecond, lager.NewLogger("notifications"))
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(maildir)
+	})
+
+	writeMessage := func(name, contents string) {
+		Expect(ioutil.WriteFile(filepath.Join(maildir, "new", name), []byte(contents), 0644)).To(Succeed())
+	}
+
+	Describe("Poll", func() {
+		It("processes new bounce reports and moves them to cur", func() {
+			writeMessage("1434.M1.example.com", maildirBounce)
+
+			poller.Poll()
+
+			Expect(processor.ProcessCall.CallCount).To(Equal(1))
+			Expect(processor.ProcessCall.Receives.Connection).To(Equal(connection))
+			Expect(processor.ProcessCall.Receives.DSN.NotificationID).To(Equal("some-message-id"))
+			Expect(processor.ProcessCall.Receives.DSN.Recipients[0].Recipient).To(Equal("missing@example.com"))
+
+			Expect(filepath.Join(maildir, "new", "1434.M1.example.com")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(maildir, "cur", "1434.M1.example.com:2,S")).To(BeAnExistingFile())
+		})
+
+		It("moves mail that is not a bounce report without processing it", func() {
+			writeMessage("1434.M2.example.com", "Content-Type: text/plain\r\n\r\nout of office\r\n")
+
+			poller.Poll()
+
+			Expect(processor.ProcessCall.CallCount).To(Equal(0))
+			Expect(filepath.Join(maildir, "cur", "1434.M2.example.com:2,S")).To(BeAnExistingFile())
+		})
+
+		It("leaves the report in new when processing fails", func() {
+			writeMessage("1434.M3.example.com", maildirBounce)
+			processor.ProcessCall.Returns.Error = errors.New("database failure")
+
+			poller.Poll()
+
+			Expect(processor.ProcessCall.CallCount).To(Equal(1))
+			Expect(filepath.Join(maildir, "new", "1434.M3.example.com")).To(BeAnExistingFile())
+		})
+	})
+
+	Describe("Run", func() {
+		It("polls the maildir on an interval", func() {
+			writeMessage("1434.M4.example.com", maildirBounce)
+
+			poller.Run()
+
+			Eventually(func() int {
+				return processor.ProcessCall.CallCount
+			}).Should(Equal(1))
+
+			poller.Stop()
+		})
+	})
+
+	Describe("Stop", func() {
+		It("stops polling the maildir", func() {
+			poller.Run()
+			poller.Stop()
+
+			writeMessage("1434.M5.example.com", maildirBounce)
+			Consistently(func() int {
+				return processor.ProcessCall.CallCount
+			}, 200*time.Millisecond).Should(Equal(0))
+			Expect(filepath.Join(maildir, "new", "1434.M5.example.com")).To(BeAnExistingFile())
+		})
+	})
+})
@@ -0,0 +1,116 @@
+package postal
+
+import (
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/metrics"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	v1models "github.com/cloudfoundry-incubator/notifications/v1/models"
+	v2models "github.com/cloudfoundry-incubator/notifications/v2/models"
+	"github.com/pivotal-golang/lager"
+)
+
+const SuppressionReasonBounce = "bounce"
+
+type messageFinder interface {
+	FindByID(conn v1models.ConnectionInterface, messageID string) (v1models.Message, error)
+}
+
+type suppressionsInserter interface {
+	Insert(conn v2models.ConnectionInterface, suppression v2models.Suppression) (v2models.Suppression, error)
+}
+
+// BounceProcessor records the outcome of delivery status notifications
+// returned by the mail server. Permanently failing recipients are added to
+// the suppression list. The message is only marked undeliverable once every
+// address it was sent to has failed; a bounce from a single Cc or Bcc
+// recipient is recorded against that address alone. V1 and V2 messages
+// share the messages table, so a single status updater handles both; V1
+// messages simply have no campaign ID.
+type BounceProcessor struct {
+	messages      messageFinder
+	suppressions  suppressionsInserter
+	statusUpdater messageStatusUpdater
+}
+
+func NewBounceProcessor(messages messageFinder, suppressions suppressionsInserter, statusUpdater messageStatusUpdater) BounceProcessor {
+	return BounceProcessor{
+		messages:      messages,
+		suppressions:  suppressions,
+		statusUpdater: statusUpdater,
+	}
+}
+
+func (p BounceProcessor) Process(conn db.ConnectionInterface, dsn mail.DeliveryStatusNotification, logger lager.Logger) error {
+	logger = logger.Session("bounce", lager.Data{
+		"message_id": dsn.NotificationID,
+	})
+
+	failed := map[string]bool{}
+	for _, recipient := range dsn.Recipients {
+		if !recipient.Failed() {
+			continue
+		}
+		failed[strings.ToLower(recipient.Recipient)] = true
+
+		logger.Info("recipient-bounced", lager.Data{
+			"recipient": recipient.Recipient,
+			"status":    recipient.Status,
+		})
+
+		if !recipient.Permanent() {
+			continue
+		}
+
+		_, err := p.suppressions.Insert(conn, v2models.Suppression{
+			Email:  recipient.Recipient,
+			Reason: SuppressionReasonBounce,
+		})
+		if err != nil {
+			if _, ok := err.(v2models.DuplicateRecordError); !ok {
+				return err
+			}
+		}
+
+		logger.Info("recipient-suppressed", lager.Data{
+			"recipient": recipient.Recipient,
+			"status":    recipient.Status,
+		})
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	metrics.NewMetric("counter", map[string]interface{}{
+		"name": "notifications.worker.bounced",
+	}).Log()
+
+	for _, address := range dsn.To {
+		if !failed[strings.ToLower(address)] {
+			logger.Info("bounce-recorded-for-recipients-only")
+			return nil
+		}
+	}
+
+	if dsn.NotificationID == "" {
+		logger.Info("bounce-not-correlated")
+		return nil
+	}
+
+	message, err := p.messages.FindByID(conn, dsn.NotificationID)
+	if err != nil {
+		if _, ok := err.(v1models.NotFoundError); ok {
+			logger.Info("bounce-not-correlated")
+			return nil
+		}
+
+		return err
+	}
+
+	p.statusUpdater.Update(conn, message.ID, common.StatusUndeliverable, message.CampaignID, logger)
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package postal_test
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	v1models "github.com/cloudfoundry-incubator/notifications/v1/models"
+	v2models "github.com/cloudfoundry-incubator/notifications/v2/models"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BounceProcessor", func() {
+	var (
+		processor     postal.BounceProcessor
+		messagesRepo  *mocks.MessagesRepo
+		suppressions  *mocks.SuppressionsRepository
+		statusUpdater *mocks.MessageStatusUpdater
+		connection    *mocks.Connection
+		logger        lager.Logger
+		buffer        *bytes.Buffer
+		dsn           mail.DeliveryStatusNotification
+	)
+
+	BeforeEach(func() {
+		buffer = bytes.NewBuffer([]byte{})
+		logger = lager.NewLogger("notifications")
+		logger.RegisterSink(lager.NewWriterSink(buffer, lager.DEBUG))
+
+		connection = mocks.NewConnection()
+		messagesRepo = mocks.NewMessagesRepo()
+		messagesRepo.FindByIDCall.Returns.Message = v1models.Message{
+			ID: "some-message-id",
+		}
+		suppressions = mocks.NewSuppressionsRepository()
+		statusUpdater = mocks.NewMessageStatusUpdater()
+
+		dsn = mail.DeliveryStatusNotification{
+			NotificationID: "some-message-id",
+			Recipients: []mail.RecipientStatus{
+				{Recipient: "missing@example.com", Action: "failed", Status: "5.1.1"},
+				{Recipient: "full@example.com", Action: "failed", Status: "4.2.2"},
+			},
+		}
+
+		processor = postal.NewBounceProcessor(messagesRepo, suppressions, statusUpdater)
+	})
+
+	It("marks the message as undeliverable", func() {
+		err := processor.Process(connection, dsn, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(messagesRepo.FindByIDCall.Receives.Connection).To(Equal(connection))
+		Expect(messagesRepo.FindByIDCall.Receives.MessageID).To(Equal("some-message-id"))
+
+		Expect(statusUpdater.UpdateCall.Receives.Connection).To(Equal(connection))
+		Expect(statusUpdater.UpdateCall.Receives.MessageID).To(Equal("some-message-id"))
+		Expect(statusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusUndeliverable))
+		Expect(statusUpdater.UpdateCall.Receives.CampaignID).To(BeEmpty())
+	})
+
+	Context("when the report lists the addresses the message was sent to", func() {
+		It("marks the message as undeliverable once every address has failed", func() {
+			dsn.To = []string{"Missing@Example.com", "full@example.com"}
+
+			err := processor.Process(connection, dsn, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statusUpdater.UpdateCall.Receives.MessageID).To(Equal("some-message-id"))
+			Expect(statusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusUndeliverable))
+		})
+
+		It("only records the failure against the recipients that bounced", func() {
+			dsn.To = []string{"someone@example.com"}
+
+			err := processor.Process(connection, dsn, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(suppressions.InsertCall.Receives.Suppressions).To(Equal([]v2models.Suppression{
+				{
+					Email:  "missing@example.com",
+					Reason: "bounce",
+				},
+			}))
+			Expect(messagesRepo.FindByIDCall.Receives.MessageID).To(BeEmpty())
+			Expect(statusUpdater.UpdateCall.Receives.MessageID).To(BeEmpty())
+			Expect(buffer.String()).To(ContainSubstring(`"recipient":"missing@example.com"`))
+			Expect(buffer.String()).To(ContainSubstring("bounce-recorded-for-recipients-only"))
+		})
+	})
+
+	Context("when the message belongs to a campaign", func() {
+		It("keeps the campaign ID on the message", func() {
+			messagesRepo.FindByIDCall.Returns.Message.CampaignID = "some-campaign-id"
+
+			err := processor.Process(connection, dsn, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statusUpdater.UpdateCall.Receives.MessageID).To(Equal("some-message-id"))
+			Expect(statusUpdater.UpdateCall.Receives.CampaignID).To(Equal("some-campaign-id"))
+		})
+	})
+
+	It("suppresses recipients that bounced permanently", func() {
+		err := processor.Process(connection, dsn, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(suppressions.InsertCall.Receives.Connection).To(Equal(connection))
+		Expect(suppressions.InsertCall.Receives.Suppressions).To(Equal([]v2models.Suppression{
+			{
+				Email:  "missing@example.com",
+				Reason: "bounce",
+			},
+		}))
+	})
+
+	Context("when the recipient is already suppressed", func() {
+		It("carries on", func() {
+			suppressions.InsertCall.Returns.Error = v2models.DuplicateRecordError{errors.New("duplicate")}
+
+			err := processor.Process(connection, dsn, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusUpdater.UpdateCall.Receives.MessageID).To(Equal("some-message-id"))
+		})
+	})
+
+	Context("when no recipient failed", func() {
+		It("does nothing", func() {
+			dsn.Recipients = []mail.RecipientStatus{
+				{Recipient: "slow@example.com", Action: "delayed", Status: "4.4.1"},
+			}
+
+			err := processor.Process(connection, dsn, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(suppressions.InsertCall.CallCount).To(Equal(0))
+			Expect(messagesRepo.FindByIDCall.Receives.MessageID).To(BeEmpty())
+			Expect(statusUpdater.UpdateCall.Receives.MessageID).To(BeEmpty())
+		})
+	})
+
+	Context("when the report does not identify the message", func() {
+		It("still suppresses the recipients", func() {
+			dsn.NotificationID = ""
+
+			err := processor.Process(connection, dsn, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(suppressions.InsertCall.CallCount).To(Equal(1))
+			Expect(messagesRepo.FindByIDCall.Receives.MessageID).To(BeEmpty())
+			Expect(buffer.String()).To(ContainSubstring("bounce-not-correlated"))
+		})
+	})
+
+	Context("when the message cannot be found", func() {
+		It("logs that the bounce could not be correlated", func() {
+			messagesRepo.FindByIDCall.Returns.Error = v1models.NotFoundError{errors.New("not found")}
+
+			err := processor.Process(connection, dsn, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(suppressions.InsertCall.CallCount).To(Equal(1))
+			Expect(statusUpdater.UpdateCall.Receives.MessageID).To(BeEmpty())
+			Expect(buffer.String()).To(ContainSubstring("bounce-not-correlated"))
+		})
+	})
+
+	Context("when an error occurs", func() {
+		It("returns the error from finding the message", func() {
+			messagesRepo.FindByIDCall.Returns.Error = errors.New("database failure")
+
+			err := processor.Process(connection, dsn, logger)
+			Expect(err).To(MatchError(errors.New("database failure")))
+		})
+
+		It("returns the error from suppressing a recipient", func() {
+			suppressions.InsertCall.Returns.Error = errors.New("database failure")
+
+			err := processor.Process(connection, dsn, logger)
+			Expect(err).To(MatchError(errors.New("database failure")))
+			Expect(statusUpdater.UpdateCall.Receives.MessageID).To(BeEmpty())
+		})
+	})
+})
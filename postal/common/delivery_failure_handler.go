@@ -1,7 +1,7 @@
 package common
 
 import (
-	"math"
+	"math/rand"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/metrics"
@@ -10,28 +10,54 @@ import (
 
 type Retryable interface {
 	Retry(duration time.Duration)
+	DeadLetter(reason string)
 	State() (retryCount int, activeAt time.Time)
 }
 
-type DeliveryFailureHandler struct{}
+type DeliveryFailureHandler struct {
+	policy RetryPolicy
+	random func() float64
+}
 
-func NewDeliveryFailureHandler() DeliveryFailureHandler {
-	return DeliveryFailureHandler{}
+func NewDeliveryFailureHandler(policy RetryPolicy) DeliveryFailureHandler {
+	return DeliveryFailureHandler{
+		policy: policy,
+		random: rand.Float64,
+	}
 }
 
-func (h DeliveryFailureHandler) Handle(job Retryable, logger lager.Logger) {
+func (h DeliveryFailureHandler) Handle(job Retryable, err error, logger lager.Logger) {
+	policy := h.policy.For(err)
+
 	retryCount, _ := job.State()
-	if retryCount > 9 {
+	if !policy.ShouldRetry(retryCount) {
+		var reason string
+		if err != nil {
+			reason = err.Error()
+		}
+
+		job.DeadLetter(reason)
+
+		logger.Info("delivery-failed-dead-lettered", lager.Data{
+			"retry_count": retryCount,
+			"error":       reason,
+			"error_class": ErrorClass(err),
+		})
+
+		metrics.NewMetric("counter", map[string]interface{}{
+			"name": "notifications.worker.dead-letter",
+		}).Log()
+
 		return
 	}
 
-	duration := time.Duration(int64(math.Pow(2, float64(retryCount)))) * time.Minute
-	job.Retry(duration)
+	job.Retry(policy.Delay(retryCount, h.random))
 
 	retryCount, activeAt := job.State()
 	logger.Info("delivery-failed-retrying", lager.Data{
 		"retry_count": retryCount,
 		"active_at":   activeAt.Format(time.RFC3339),
+		"error_class": ErrorClass(err),
 	})
 
 	// TODO: (rm) find way to test this without having to mock out globals
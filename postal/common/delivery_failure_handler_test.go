@@ -2,8 +2,10 @@ package common_test
 
 import (
 	"bytes"
+	"errors"
 	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/mail"
 	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/pivotal-golang/lager"
@@ -26,7 +28,7 @@ var _ = Describe("DeliveryFailureHandler", func() {
 		logger = lager.NewLogger("notifications")
 		logger.RegisterSink(lager.NewWriterSink(buffer, lager.INFO))
 
-		handler = common.NewDeliveryFailureHandler()
+		handler = common.NewDeliveryFailureHandler(common.DefaultRetryPolicy())
 	})
 
 	It("retries the job using an exponential backoff algorithm", func() {
@@ -46,7 +48,7 @@ var _ = Describe("DeliveryFailureHandler", func() {
 		for retryCount, duration := range backoffDurations {
 			job.StateCall.Returns.Count = retryCount
 
-			handler.Handle(job, logger)
+			handler.Handle(job, errors.New("some error"), logger)
 
 			Expect(job.RetryCall.Receives.Duration).To(Equal(duration))
 		}
@@ -55,17 +57,43 @@ var _ = Describe("DeliveryFailureHandler", func() {
 	It("gives up after 9 retries", func() {
 		job.StateCall.Returns.Count = 10
 
-		handler.Handle(job, logger)
+		handler.Handle(job, errors.New("some error"), logger)
 
 		Expect(job.RetryCall.WasCalled).To(BeFalse())
 	})
 
+	It("moves the job to the dead letters once it has given up", func() {
+		job.StateCall.Returns.Count = 10
+
+		handler.Handle(job, errors.New("smtp is down"), logger)
+
+		Expect(job.DeadLetterCall.WasCalled).To(BeTrue())
+		Expect(job.DeadLetterCall.Receives.Reason).To(Equal("smtp is down"))
+
+		lines, err := parseLogLines(buffer.Bytes())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lines).To(HaveLen(1))
+
+		line := lines[0]
+		Expect(line.Message).To(Equal("notifications.delivery-failed-dead-lettered"))
+		Expect(line.Data).To(HaveKeyWithValue("retry_count", float64(10)))
+		Expect(line.Data).To(HaveKeyWithValue("error", "smtp is down"))
+	})
+
+	It("does not dead letter jobs that are still being retried", func() {
+		job.StateCall.Returns.Count = 9
+
+		handler.Handle(job, errors.New("some error"), logger)
+
+		Expect(job.DeadLetterCall.WasCalled).To(BeFalse())
+	})
+
 	It("logs the retry attempt", func() {
 		expectedActiveAt := time.Now().Truncate(time.Second)
 		job.StateCall.Returns.Time = expectedActiveAt
 		job.StateCall.Returns.Count = 4
 
-		handler.Handle(job, logger)
+		handler.Handle(job, errors.New("some error"), logger)
 
 		lines, err := parseLogLines(buffer.Bytes())
 		Expect(err).NotTo(HaveOccurred())
@@ -82,4 +110,83 @@ var _ = Describe("DeliveryFailureHandler", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(activeAt.UTC()).To(Equal(expectedActiveAt.UTC()))
 	})
+
+	Context("when the retry policy is configured", func() {
+		var policy common.RetryPolicy
+
+		BeforeEach(func() {
+			policy = common.RetryPolicy{
+				MaxAttempts: 4,
+				BaseDelay:   30 * time.Second,
+				MaxDelay:    1 * time.Minute,
+			}
+		})
+
+		It("caps the backoff at the maximum delay", func() {
+			handler = common.NewDeliveryFailureHandler(policy)
+
+			backoffDurations := map[int]time.Duration{
+				0: 30 * time.Second,
+				1: 1 * time.Minute,
+				2: 1 * time.Minute,
+			}
+
+			for retryCount, duration := range backoffDurations {
+				job.StateCall.Returns.Count = retryCount
+
+				handler.Handle(job, errors.New("some error"), logger)
+
+				Expect(job.RetryCall.Receives.Duration).To(Equal(duration))
+			}
+		})
+
+		It("dead letters the job once it has run out of attempts", func() {
+			handler = common.NewDeliveryFailureHandler(policy)
+			job.StateCall.Returns.Count = 3
+
+			handler.Handle(job, errors.New("some error"), logger)
+
+			Expect(job.RetryCall.WasCalled).To(BeFalse())
+			Expect(job.DeadLetterCall.WasCalled).To(BeTrue())
+		})
+
+		It("spreads the delay by the jitter", func() {
+			policy.Jitter = 0.5
+			handler = common.NewDeliveryFailureHandler(policy)
+			job.StateCall.Returns.Count = 0
+
+			for i := 0; i < 20; i++ {
+				handler.Handle(job, errors.New("some error"), logger)
+
+				Expect(job.RetryCall.Receives.Duration).To(BeNumerically(">=", 15*time.Second))
+				Expect(job.RetryCall.Receives.Duration).To(BeNumerically("<=", 45*time.Second))
+			}
+		})
+
+		It("uses the override for the class of error", func() {
+			policy.Overrides = map[string]common.RetryPolicy{
+				common.ErrorClassSMTP4xx: {
+					MaxAttempts: 20,
+					BaseDelay:   5 * time.Minute,
+				},
+				common.ErrorClassSMTP5xx: {
+					MaxAttempts: 1,
+				},
+			}
+			handler = common.NewDeliveryFailureHandler(policy)
+			job.StateCall.Returns.Count = 5
+
+			handler.Handle(job, mail.SMTPError{Code: 451, Message: "greylisted"}, logger)
+
+			Expect(job.RetryCall.Receives.Duration).To(Equal(160 * time.Minute))
+
+			job = mocks.NewGobbleJob()
+			job.StateCall.Returns.Count = 0
+
+			handler.Handle(job, mail.SMTPError{Code: 550, Message: "no such user"}, logger)
+
+			Expect(job.RetryCall.WasCalled).To(BeFalse())
+			Expect(job.DeadLetterCall.WasCalled).To(BeTrue())
+		})
+	})
 })
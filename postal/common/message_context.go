@@ -1,10 +1,11 @@
 package common
 
 import (
-	"html"
+	"strings"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/mail"
 	"github.com/pivotal-golang/conceal"
 )
 
@@ -17,9 +18,14 @@ type Options struct {
 	HTML              HTML
 	KindID            string
 	To                string
+	Cc                []string
+	Bcc               []string
 	Role              string
 	Endorsement       string
 	TemplateID        string
+	Critical          bool
+	Headers           map[string]string
+	AttachmentIDs     []string
 }
 
 type Delivery struct {
@@ -37,11 +43,28 @@ type Delivery struct {
 	CampaignID      string
 }
 
+// Recipients returns the To addresses of the delivery. A delivery to a
+// shared inbox lists several comma separated addresses in Email.
+func (d Delivery) Recipients() []string {
+	var recipients []string
+	for _, address := range strings.Split(d.Email, ",") {
+		address = strings.TrimSpace(address)
+		if address != "" {
+			recipients = append(recipients, address)
+		}
+	}
+
+	return recipients
+}
+
+// Templates are the parts a message is compiled from. Partials maps the
+// name of each partial the parts may include with {{template}} to its body.
 type Templates struct {
-	Name    string
-	Subject string
-	Text    string
-	HTML    string
+	Name     string
+	Subject  string
+	Text     string
+	HTML     string
+	Partials map[string]string
 }
 
 type HTML struct {
@@ -55,6 +78,8 @@ type MessageContext struct {
 	From              string
 	ReplyTo           string
 	To                string
+	Cc                []string
+	Bcc               []string
 	Subject           string
 	Text              string
 	HTML              string
@@ -62,6 +87,7 @@ type MessageContext struct {
 	TextTemplate      string
 	HTMLTemplate      string
 	SubjectTemplate   string
+	Partials          map[string]string
 	KindDescription   string
 	SourceDescription string
 	UserGUID          string
@@ -77,6 +103,9 @@ type MessageContext struct {
 	OrganizationRole  string
 	RequestReceived   time.Time
 	Domain            string
+	Critical          bool
+	Headers           map[string]string
+	Attachments       []mail.Attachment
 }
 
 func NewMessageContext(delivery Delivery, sender, domain string, cloak conceal.CloakInterface, templates Templates) MessageContext {
@@ -100,6 +129,8 @@ func NewMessageContext(delivery Delivery, sender, domain string, cloak conceal.C
 		From:              sender,
 		ReplyTo:           options.ReplyTo,
 		To:                delivery.Email,
+		Cc:                options.Cc,
+		Bcc:               options.Bcc,
 		Subject:           options.Subject,
 		Text:              options.Text,
 		HTML:              options.HTML.BodyContent,
@@ -107,6 +138,7 @@ func NewMessageContext(delivery Delivery, sender, domain string, cloak conceal.C
 		TextTemplate:      templates.Text,
 		HTMLTemplate:      templates.HTML,
 		SubjectTemplate:   templates.Subject,
+		Partials:          templates.Partials,
 		KindDescription:   kindDescription,
 		SourceDescription: sourceDescription,
 		UserGUID:          delivery.UserGUID,
@@ -121,6 +153,8 @@ func NewMessageContext(delivery Delivery, sender, domain string, cloak conceal.C
 		OrganizationRole:  options.Role,
 		RequestReceived:   delivery.RequestReceived,
 		Domain:            domain,
+		Critical:          options.Critical,
+		Headers:           options.Headers,
 	}
 
 	if messageContext.Subject == "" {
@@ -135,18 +169,3 @@ func NewMessageContext(delivery Delivery, sender, domain string, cloak conceal.C
 	messageContext.UnsubscribeID = string(unsubscribeID)
 	return messageContext
 }
-
-func (context *MessageContext) Escape() {
-	context.From = html.EscapeString(context.From)
-	context.To = html.EscapeString(context.To)
-	context.ReplyTo = html.EscapeString(context.ReplyTo)
-	context.Subject = html.EscapeString(context.Subject)
-	context.Text = html.EscapeString(context.Text)
-	context.KindDescription = html.EscapeString(context.KindDescription)
-	context.SourceDescription = html.EscapeString(context.SourceDescription)
-	context.ClientID = html.EscapeString(context.ClientID)
-	context.MessageID = html.EscapeString(context.MessageID)
-	context.Space = html.EscapeString(context.Space)
-	context.Organization = html.EscapeString(context.Organization)
-	context.Endorsement = html.EscapeString(context.Endorsement)
-}
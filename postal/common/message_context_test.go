@@ -104,6 +104,13 @@ var _ = Describe("MessageContext", func() {
 			Expect(context.Domain).To(Equal(domain))
 		})
 
+		It("marks the context as critical when the delivery is critical", func() {
+			delivery.Options.Critical = true
+			context := common.NewMessageContext(delivery, sender, domain, cloak, templates)
+
+			Expect(context.Critical).To(BeTrue())
+		})
+
 		It("falls back to Kind if KindDescription is missing", func() {
 			delivery.Options.KindDescription = ""
 			context := common.NewMessageContext(delivery, sender, domain, cloak, templates)
@@ -125,51 +132,15 @@ var _ = Describe("MessageContext", func() {
 		})
 	})
 
-	Describe("Escape", func() {
-		BeforeEach(func() {
-			options = common.Options{
-				ReplyTo:           "awesomeness",
-				Subject:           "the & subject",
-				KindDescription:   "the & kind description",
-				SourceDescription: "the & source description",
-				Text:              "user & supplied email text",
-				HTML:              common.HTML{BodyContent: "user & supplied html"},
-				KindID:            "the & kind",
-				Endorsement:       "this & is the endorsement",
-				Role:              "OrgRole",
-			}
-
-			delivery.Options = options
-			delivery.ClientID = "the\"client id"
-			delivery.MessageID = "some>id"
-			delivery.Space.Name = "the<space"
-			delivery.Organization.Name = "the>org"
-			delivery.Scope = ""
-		})
+	Describe("Delivery.Recipients", func() {
+		It("splits a shared inbox delivery into its To addresses", func() {
+			delivery := common.Delivery{Email: "first@example.com, second@example.com"}
+			Expect(delivery.Recipients()).To(Equal([]string{"first@example.com", "second@example.com"}))
 
-		It("html escapes various fields on the message context", func() {
-			context := common.NewMessageContext(delivery, sender, domain, cloak, templates)
-			context.Escape()
-
-			Expect(context.From).To(Equal("no-reply@notifications.example.com"))
-			Expect(context.ReplyTo).To(Equal("awesomeness"))
-			Expect(context.To).To(Equal("bounce@example.com"))
-			Expect(context.Subject).To(Equal("the &amp; subject"))
-			Expect(context.Text).To(Equal("user &amp; supplied email text"))
-			Expect(context.HTML).To(Equal("user & supplied html"))
-			Expect(context.TextTemplate).To(Equal("the plainText email < template"))
-			Expect(context.HTMLTemplate).To(Equal("the html <h1> email < template</h1>"))
-			Expect(context.SubjectTemplate).To(Equal("the subject < template"))
-			Expect(context.KindDescription).To(Equal("the &amp; kind description"))
-			Expect(context.SourceDescription).To(Equal("the &amp; source description"))
-			Expect(context.UserGUID).To(Equal("the-user"))
-			Expect(context.ClientID).To(Equal("the&#34;client id"))
-			Expect(context.MessageID).To(Equal("some&gt;id"))
-			Expect(context.Space).To(Equal("the&lt;space"))
-			Expect(context.Organization).To(Equal("the&gt;org"))
-			Expect(context.Scope).To(Equal(""))
-			Expect(context.Endorsement).To(Equal("this &amp; is the endorsement"))
-			Expect(context.OrganizationRole).To(Equal("OrgRole"))
+			delivery = common.Delivery{Email: "only@example.com"}
+			Expect(delivery.Recipients()).To(Equal([]string{"only@example.com"}))
+
+			Expect(common.Delivery{}.Recipients()).To(BeEmpty())
 		})
 	})
 })
@@ -3,6 +3,9 @@ package common
 import (
 	"bytes"
 	"fmt"
+	htmltemplate "html/template"
+	"net/url"
+	"sort"
 	"strings"
 	"text/template"
 	"time"
@@ -19,19 +22,62 @@ const HTMLWrapperTemplate = `{{.HTMLComponents.Doctype}}
 	</body>
 </html>`
 
+// trustedHTML holds the caller-supplied parts of an HTML message. They are
+// inserted into HTML templates as they are, without escaping.
+type trustedHTML struct {
+	BodyContent    htmltemplate.HTML
+	BodyAttributes htmltemplate.HTMLAttr
+	Head           htmltemplate.HTML
+	Doctype        htmltemplate.HTML
+}
+
+// htmlMessageContext is the MessageContext that HTML parts are rendered
+// with. Only HTML and HTMLComponents are trusted; every other field is
+// escaped by html/template according to where it appears in the template.
+type htmlMessageContext struct {
+	MessageContext
+	HTML           htmltemplate.HTML
+	HTMLComponents trustedHTML
+}
+
+func newHTMLMessageContext(context MessageContext) htmlMessageContext {
+	return htmlMessageContext{
+		MessageContext: context,
+		HTML:           htmltemplate.HTML(context.HTML),
+		HTMLComponents: trustedHTML{
+			BodyContent:    htmltemplate.HTML(context.HTMLComponents.BodyContent),
+			BodyAttributes: htmltemplate.HTMLAttr(context.HTMLComponents.BodyAttributes),
+			Head:           htmltemplate.HTML(context.HTMLComponents.Head),
+			Doctype:        htmltemplate.HTML(context.HTMLComponents.Doctype),
+		},
+	}
+}
+
 type templatesLoader interface {
 	LoadTemplates(clientID, kindID, templateID string) (Templates, error)
 }
 
+type attachmentsLoader interface {
+	LoadAttachments(attachmentIDs []string) ([]mail.Attachment, error)
+}
+
 type Packager struct {
-	templates templatesLoader
-	cloak     conceal.CloakInterface
+	templates      templatesLoader
+	attachments    attachmentsLoader
+	cloak          conceal.CloakInterface
+	unsubscribeURL string
 }
 
-func NewPackager(templates templatesLoader, cloak conceal.CloakInterface) Packager {
+// NewPackager returns a Packager that adds List-Unsubscribe headers pointing
+// at unsubscribeURL, followed by the unsubscribe ID, to non-critical
+// messages. No List-Unsubscribe headers are added when unsubscribeURL is
+// empty.
+func NewPackager(templates templatesLoader, attachments attachmentsLoader, cloak conceal.CloakInterface, unsubscribeURL string) Packager {
 	return Packager{
-		templates: templates,
-		cloak:     cloak,
+		templates:      templates,
+		attachments:    attachments,
+		cloak:          cloak,
+		unsubscribeURL: unsubscribeURL,
 	}
 }
 
@@ -41,7 +87,16 @@ func (packager Packager) PrepareContext(delivery Delivery, sender, domain string
 		return MessageContext{}, err
 	}
 
-	return NewMessageContext(delivery, sender, domain, packager.cloak, templates), nil
+	context := NewMessageContext(delivery, sender, domain, packager.cloak, templates)
+
+	if len(delivery.Options.AttachmentIDs) > 0 {
+		context.Attachments, err = packager.attachments.LoadAttachments(delivery.Options.AttachmentIDs)
+		if err != nil {
+			return MessageContext{}, err
+		}
+	}
+
+	return context, nil
 }
 
 func (packager Packager) Pack(context MessageContext) (mail.Message, error) {
@@ -50,37 +105,69 @@ func (packager Packager) Pack(context MessageContext) (mail.Message, error) {
 		return mail.Message{}, err
 	}
 
-	compiledSubject, err := packager.compileTemplate(context, context.SubjectTemplate, false)
+	compiledSubject, err := packager.compileTemplate(context, context.SubjectTemplate)
 	if err != nil {
 		return mail.Message{}, err
 	}
 
+	headers := []string{
+		fmt.Sprintf("X-CF-Client-ID: %s", context.ClientID),
+		fmt.Sprintf("X-CF-Notification-ID: %s", context.MessageID),
+		fmt.Sprintf("X-CF-Notification-Timestamp: %s", time.Now().Format(time.RFC3339Nano)),
+		fmt.Sprintf("X-CF-Notification-Request-Received: %s", context.RequestReceived.Format(time.RFC3339Nano)),
+	}
+
+	// Messages sent straight to an email address have no user to
+	// unsubscribe, so they carry no List-Unsubscribe headers.
+	if !context.Critical && context.UserGUID != "" && packager.unsubscribeURL != "" {
+		link, err := packager.unsubscribeLink(context.UnsubscribeID)
+		if err != nil {
+			return mail.Message{}, err
+		}
+
+		headers = append(headers,
+			fmt.Sprintf("List-Unsubscribe: <%s>", link),
+			"List-Unsubscribe-Post: List-Unsubscribe=One-Click",
+		)
+	}
+
+	headers = append(headers, mail.FormatHeaders(context.Headers)...)
+
 	return mail.Message{
-		From:    context.From,
-		ReplyTo: context.ReplyTo,
-		To:      context.To,
-		Subject: compiledSubject,
-		Body:    parts,
-		Headers: []string{
-			fmt.Sprintf("X-CF-Client-ID: %s", context.ClientID),
-			fmt.Sprintf("X-CF-Notification-ID: %s", context.MessageID),
-			fmt.Sprintf("X-CF-Notification-Timestamp: %s", time.Now().Format(time.RFC3339Nano)),
-			fmt.Sprintf("X-CF-Notification-Request-Received: %s", context.RequestReceived.Format(time.RFC3339Nano)),
-		},
+		From:        context.From,
+		ReplyTo:     context.ReplyTo,
+		To:          context.To,
+		Cc:          context.Cc,
+		Bcc:         context.Bcc,
+		Subject:     compiledSubject,
+		Body:        parts,
+		Attachments: context.Attachments,
+		Headers:     headers,
 	}, nil
 }
 
+func (packager Packager) unsubscribeLink(unsubscribeID string) (string, error) {
+	link, err := url.Parse(packager.unsubscribeURL)
+	if err != nil {
+		return "", err
+	}
+
+	link.Path = strings.TrimSuffix(link.Path, "/") + "/" + unsubscribeID
+
+	return link.String(), nil
+}
+
 func (packager Packager) CompileParts(context MessageContext) ([]mail.Part, error) {
 	var parts []mail.Part
 	var err error
 
-	context.Endorsement, err = packager.compileTemplate(context, context.Endorsement, false)
+	context.Endorsement, err = packager.compileTemplate(context, context.Endorsement)
 	if err != nil {
 		return parts, err
 	}
 
 	if context.Text != "" {
-		plainText, err := packager.compileTemplate(context, context.TextTemplate, false)
+		plainText, err := packager.compileTemplate(context, context.TextTemplate)
 		if err != nil {
 			return parts, err
 		}
@@ -93,14 +180,18 @@ func (packager Packager) CompileParts(context MessageContext) ([]mail.Part, erro
 	}
 
 	if context.HTML != "" {
-		var err error
+		htmlContext := newHTMLMessageContext(context)
 
-		context.HTMLComponents.BodyContent, err = packager.compileTemplate(context, context.HTMLTemplate, true)
+		bodyContent, err := packager.compileHTMLTemplate(htmlContext, context.HTMLTemplate)
 		if err != nil {
 			return parts, err
 		}
 
-		htmlPart, err := packager.compileTemplate(context, HTMLWrapperTemplate, true)
+		// The body has already been escaped while rendering the HTML
+		// template, so it can be trusted inside the wrapper.
+		htmlContext.HTMLComponents.BodyContent = htmltemplate.HTML(bodyContent)
+
+		htmlPart, err := packager.compileHTMLTemplate(htmlContext, HTMLWrapperTemplate)
 		if err != nil {
 			return parts, err
 		}
@@ -114,20 +205,66 @@ func (packager Packager) CompileParts(context MessageContext) ([]mail.Part, erro
 	return parts, nil
 }
 
-func (packager Packager) compileTemplate(context MessageContext, theTemplate string, escapeContext bool) (string, error) {
+func (packager Packager) compileTemplate(context MessageContext, theTemplate string) (string, error) {
+	buffer := bytes.NewBuffer([]byte{})
+
+	source := template.New("compileTemplate")
+	for _, name := range partialNames(context.Partials) {
+		_, err := source.New(name).Parse(context.Partials[name])
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// The template is parsed after its partials so that the blocks it
+	// defines replace the defaults of a layout it includes.
+	_, err := source.Parse(theTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	err = source.Execute(buffer, context)
+	if err != nil {
+		return "", err
+	}
+
+	compiledTemplate := strings.TrimSuffix(buffer.String(), "\n")
+
+	return compiledTemplate, nil
+}
+
+func (packager Packager) compileHTMLTemplate(context htmlMessageContext, theTemplate string) (string, error) {
 	buffer := bytes.NewBuffer([]byte{})
 
-	source, err := template.New("compileTemplate").Parse(theTemplate)
+	source := htmltemplate.New("compileTemplate")
+	for _, name := range partialNames(context.Partials) {
+		_, err := source.New(name).Parse(context.Partials[name])
+		if err != nil {
+			return "", err
+		}
+	}
+
+	_, err := source.Parse(theTemplate)
 	if err != nil {
 		return "", err
 	}
 
-	if escapeContext {
-		context.Escape()
+	err = source.Execute(buffer, context)
+	if err != nil {
+		return "", err
 	}
 
-	source.Execute(buffer, context)
 	compiledTemplate := strings.TrimSuffix(buffer.String(), "\n")
 
 	return compiledTemplate, nil
 }
+
+func partialNames(partials map[string]string) []string {
+	var names []string
+	for name := range partials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -15,17 +15,19 @@ import (
 
 var _ = Describe("Packager", func() {
 	var (
-		packager        common.Packager
-		context         common.MessageContext
-		client          mail.Client
-		templatesLoader *mocks.TemplatesLoader
-		delivery        common.Delivery
-		cloak           *mocks.Cloak
+		packager          common.Packager
+		context           common.MessageContext
+		client            mail.Client
+		templatesLoader   *mocks.TemplatesLoader
+		attachmentsLoader *mocks.AttachmentsLoader
+		delivery          common.Delivery
+		cloak             *mocks.Cloak
 	)
 
 	BeforeEach(func() {
 		client = mail.Client{}
 		templatesLoader = mocks.NewTemplatesLoader()
+		attachmentsLoader = mocks.NewAttachmentsLoader()
 		cloak = mocks.NewCloak()
 
 		delivery = common.Delivery{
@@ -45,7 +47,7 @@ var _ = Describe("Packager", func() {
 			},
 		}
 
-		packager = common.NewPackager(templatesLoader, cloak)
+		packager = common.NewPackager(templatesLoader, attachmentsLoader, cloak, "")
 
 		requestReceivedTime, _ := time.Parse(time.RFC3339Nano, "2015-06-08T14:38:03.180764129-07:00")
 
@@ -78,10 +80,11 @@ var _ = Describe("Packager", func() {
 	Describe("PrepareContext", func() {
 		BeforeEach(func() {
 			templatesLoader.LoadTemplatesCall.Returns.Templates = common.Templates{
-				Name:    "some-name",
-				Subject: "subject template: {{.Subject}}",
-				Text:    "Some {{.Text}} text",
-				HTML:    "<h1>{{.HTML}}</h1>",
+				Name:     "some-name",
+				Subject:  "subject template: {{.Subject}}",
+				Text:     "Some {{.Text}} text",
+				HTML:     "<h1>{{.HTML}}</h1>",
+				Partials: map[string]string{"footer": "<footer>{{.Organization}}</footer>"},
 			}
 		})
 
@@ -116,11 +119,46 @@ var _ = Describe("Packager", func() {
 				TextTemplate:      "Some {{.Text}} text",
 				HTMLTemplate:      "<h1>{{.HTML}}</h1>",
 				SubjectTemplate:   "subject template: {{.Subject}}",
+				Partials:          map[string]string{"footer": "<footer>{{.Organization}}</footer>"},
 				KindDescription:   "some-kind-id",
 				SourceDescription: "some-client-id",
 			}))
 		})
 
+		It("does not load attachments when there are none", func() {
+			_, err := packager.PrepareContext(delivery, "some-sender", "some-domain")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attachmentsLoader.LoadAttachmentsCall.CallCount).To(Equal(0))
+		})
+
+		Context("when the delivery has attachments", func() {
+			BeforeEach(func() {
+				delivery.Options.AttachmentIDs = []string{"attachment-1", "attachment-2"}
+				attachmentsLoader.LoadAttachmentsCall.Returns.Attachments = []mail.Attachment{
+					{Filename: "report.pdf", ContentType: "application/pdf", Content: []byte("%PDF")},
+					{Filename: "notes.txt", ContentType: "text/plain", Content: []byte("notes")},
+				}
+			})
+
+			It("loads the attachments into the context", func() {
+				context, err := packager.PrepareContext(delivery, "some-sender", "some-domain")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(attachmentsLoader.LoadAttachmentsCall.Receives.AttachmentIDs).To(Equal([]string{"attachment-1", "attachment-2"}))
+				Expect(context.Attachments).To(Equal([]mail.Attachment{
+					{Filename: "report.pdf", ContentType: "application/pdf", Content: []byte("%PDF")},
+					{Filename: "notes.txt", ContentType: "text/plain", Content: []byte("notes")},
+				}))
+			})
+
+			It("returns an error when the attachments cannot be loaded", func() {
+				attachmentsLoader.LoadAttachmentsCall.Returns.Error = errors.New("some error")
+
+				_, err := packager.PrepareContext(delivery, "some-sender", "some-domain")
+				Expect(err).To(MatchError(errors.New("some error")))
+			})
+		})
+
 		Context("when the template cannot be loaded", func() {
 			It("returns an error", func() {
 				templatesLoader.LoadTemplatesCall.Returns.Error = errors.New("some error")
@@ -167,6 +205,106 @@ var _ = Describe("Packager", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(timestamp).To(BeTemporally("~", time.Now(), 2*time.Second))
 		})
+
+		It("adds the custom headers after the notification headers", func() {
+			context.Headers = map[string]string{
+				"X-Ticket-ID": "TICKET-1234",
+				"In-Reply-To": "<some-message-id@example.com>",
+			}
+
+			msg, err := packager.Pack(context)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Headers[len(msg.Headers)-2:]).To(Equal([]string{
+				"In-Reply-To: <some-message-id@example.com>",
+				"X-Ticket-ID: TICKET-1234",
+			}))
+		})
+
+		It("includes the Cc and Bcc recipients in the message", func() {
+			context.Cc = []string{"shared@example.com"}
+			context.Bcc = []string{"audit@example.com"}
+
+			msg, err := packager.Pack(context)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Cc).To(Equal([]string{"shared@example.com"}))
+			Expect(msg.Bcc).To(Equal([]string{"audit@example.com"}))
+		})
+
+		It("includes the attachments in the message", func() {
+			context.Attachments = []mail.Attachment{
+				{Filename: "report.pdf", ContentType: "application/pdf", Content: []byte("%PDF")},
+			}
+
+			msg, err := packager.Pack(context)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Attachments).To(Equal([]mail.Attachment{
+				{Filename: "report.pdf", ContentType: "application/pdf", Content: []byte("%PDF")},
+			}))
+		})
+
+		It("does not add List-Unsubscribe headers without an unsubscribe URL", func() {
+			msg, err := packager.Pack(context)
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, header := range msg.Headers {
+				Expect(header).NotTo(HavePrefix("List-Unsubscribe"))
+			}
+		})
+
+		Context("when an unsubscribe URL is configured", func() {
+			BeforeEach(func() {
+				packager = common.NewPackager(templatesLoader, attachmentsLoader, cloak, "https://notifications.example.com/unsubscribe?version=2")
+				context.UnsubscribeID = "some-unsubscribe-id"
+			})
+
+			It("adds one-click List-Unsubscribe headers", func() {
+				msg, err := packager.Pack(context)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(msg.Headers).To(ContainElement("List-Unsubscribe: <https://notifications.example.com/unsubscribe/some-unsubscribe-id?version=2>"))
+				Expect(msg.Headers).To(ContainElement("List-Unsubscribe-Post: List-Unsubscribe=One-Click"))
+			})
+
+			It("does not add List-Unsubscribe headers to critical messages", func() {
+				context.Critical = true
+
+				msg, err := packager.Pack(context)
+				Expect(err).NotTo(HaveOccurred())
+
+				for _, header := range msg.Headers {
+					Expect(header).NotTo(HavePrefix("List-Unsubscribe"))
+				}
+			})
+
+			It("does not add List-Unsubscribe headers to messages that are not sent to a user", func() {
+				context.UserGUID = ""
+
+				msg, err := packager.Pack(context)
+				Expect(err).NotTo(HaveOccurred())
+
+				for _, header := range msg.Headers {
+					Expect(header).NotTo(HavePrefix("List-Unsubscribe"))
+				}
+			})
+
+			Context("when the unsubscribe URL is invalid", func() {
+				It("returns an error", func() {
+					packager = common.NewPackager(templatesLoader, attachmentsLoader, cloak, "%%%")
+
+					_, err := packager.Pack(context)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+
+			Context("when a template references a field that does not exist", func() {
+				It("returns the error rather than sending a broken message", func() {
+					context.SubjectTemplate = "{{.Subjet}}"
+
+					_, err := packager.Pack(context)
+					Expect(err).To(MatchError(ContainSubstring("can't evaluate field Subjet")))
+				})
+			})
+		})
 	})
 
 	Describe("CompileParts", func() {
@@ -242,5 +380,135 @@ Banana preamble <p>user supplied banana html</p>  3&amp;3 4&#39;4 user-123
 				}))
 			})
 		})
+
+		Context("when names contain markup", func() {
+			var htmlPart = func(parts []mail.Part) string {
+				for _, part := range parts {
+					if part.ContentType == "text/html" {
+						return part.Content
+					}
+				}
+
+				return ""
+			}
+
+			BeforeEach(func() {
+				context.Text = ""
+				context.Endorsement = ""
+				context.HTMLComponents = common.HTML{}
+			})
+
+			It("escapes a space name in text and attribute values", func() {
+				context.Space = `"><script>alert('space')</script>`
+				context.HTMLTemplate = `<p title="{{.Space}}">{{.Space}}</p>`
+
+				parts, err := packager.CompileParts(context)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(htmlPart(parts)).To(ContainSubstring(`<p title="&#34;&gt;&lt;script&gt;alert(&#39;space&#39;)&lt;/script&gt;">&#34;&gt;&lt;script&gt;alert(&#39;space&#39;)&lt;/script&gt;</p>`))
+			})
+
+			It("escapes an organization name inside a script", func() {
+				context.Organization = `</script><script>alert("org")`
+				context.HTMLTemplate = `<script>var org = {{.Organization}};</script>`
+
+				parts, err := packager.CompileParts(context)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(htmlPart(parts)).To(ContainSubstring(`<script>var org = "\u003c/script\u003e\u003cscript\u003ealert(`))
+				Expect(htmlPart(parts)).NotTo(ContainSubstring("</script><script>"))
+			})
+
+			It("filters a kind name that would be used as a link", func() {
+				context.KindDescription = `javascript:alert('kind')`
+				context.HTMLTemplate = `<a href="{{.KindDescription}}">{{.KindDescription}}</a>`
+
+				parts, err := packager.CompileParts(context)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(htmlPart(parts)).To(ContainSubstring(`<a href="#ZgotmplZ">javascript:alert(&#39;kind&#39;)</a>`))
+			})
+
+			It("escapes names that reach the html through the endorsement", func() {
+				context.Endorsement = "You belong to the {{.Space}} space in the {{.Organization}} org."
+				context.Space = "<b>dev</b>"
+				context.Organization = "<img src=x onerror=alert(1)>"
+				context.HTMLTemplate = "<header>{{.Endorsement}}</header>"
+
+				parts, err := packager.CompileParts(context)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(htmlPart(parts)).To(ContainSubstring("<header>You belong to the &lt;b&gt;dev&lt;/b&gt; space in the &lt;img src=x onerror=alert(1)&gt; org.</header>"))
+			})
+
+			It("inserts the caller-supplied html without escaping it", func() {
+				context.HTML = `<p class="trusted">hello</p>`
+				context.HTMLTemplate = "<div>{{.HTML}}</div>"
+				context.HTMLComponents = common.HTML{
+					BodyAttributes: `class="bananaBody"`,
+					Head:           "<title>The title</title>",
+					Doctype:        "<!DOCTYPE html>",
+				}
+
+				parts, err := packager.CompileParts(context)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(htmlPart(parts)).To(Equal(`<!DOCTYPE html>
+<head><title>The title</title></head>
+<html>
+	<body class="bananaBody">
+		<div><p class="trusted">hello</p></div>
+	</body>
+</html>`))
+			})
+		})
+
+		Context("when the templates include partials", func() {
+			BeforeEach(func() {
+				context.Endorsement = ""
+				context.HTMLComponents = common.HTML{}
+				context.Organization = "<b>banana</b>"
+				context.Partials = map[string]string{
+					"footer": "<footer>{{.Organization}}</footer>",
+					"layout": `<main>{{block "content" .}}nothing to see{{end}}</main>`,
+				}
+			})
+
+			It("compiles each part with the partials it includes", func() {
+				context.TextTemplate = `{{.Text}} {{template "footer" .}}`
+				context.HTMLTemplate = `{{define "content"}}{{.HTML}}{{end}}{{template "layout" .}}{{template "footer" .}}`
+
+				parts, err := packager.CompileParts(context)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(parts).To(ConsistOf([]mail.Part{
+					{
+						ContentType: "text/plain",
+						Content:     `User <supplied> "banana" text <footer><b>banana</b></footer>`,
+					},
+					{
+						ContentType: "text/html",
+						Content:     "\n<head></head>\n<html>\n\t<body >\n\t\t<main><p>user supplied banana html</p></main><footer>&lt;b&gt;banana&lt;/b&gt;</footer>\n\t</body>\n</html>",
+					},
+				}))
+			})
+
+			It("uses the defaults of a layout for blocks the template does not define", func() {
+				context.Text = ""
+				context.HTMLTemplate = `{{template "layout" .}}`
+
+				parts, err := packager.CompileParts(context)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(parts[0].Content).To(ContainSubstring("<main>nothing to see</main>"))
+			})
+
+			It("returns an error when a partial is missing", func() {
+				context.HTMLTemplate = `{{template "header" .}}`
+
+				_, err := packager.CompileParts(context)
+				Expect(err).To(MatchError(ContainSubstring(`no such template "header"`)))
+			})
+		})
 	})
 })
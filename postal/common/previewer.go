@@ -0,0 +1,103 @@
+package common
+
+import "time"
+
+type htmlExtractor interface {
+	Extract(html string) (doctype, head, bodyContent, bodyAttributes string, err error)
+}
+
+// PreviewSample holds the values that stand in for a notification when a
+// template is previewed.
+type PreviewSample struct {
+	ClientID          string
+	Subject           string
+	Text              string
+	HTML              string
+	To                string
+	ReplyTo           string
+	UserGUID          string
+	KindDescription   string
+	SourceDescription string
+}
+
+// Preview is what a template produces for a sample. Source is the full MIME
+// message as it would be handed to the mail server.
+type Preview struct {
+	Subject string
+	Text    string
+	HTML    string
+	Source  string
+}
+
+// Previewer renders templates the same way they are rendered for delivery,
+// so that template authors can see the result without sending anything.
+type Previewer struct {
+	packager      Packager
+	htmlExtractor htmlExtractor
+	sender        string
+	domain        string
+}
+
+func NewPreviewer(packager Packager, htmlExtractor htmlExtractor, sender, domain string) Previewer {
+	return Previewer{
+		packager:      packager,
+		htmlExtractor: htmlExtractor,
+		sender:        sender,
+		domain:        domain,
+	}
+}
+
+func (p Previewer) Preview(templates Templates, sample PreviewSample) (Preview, error) {
+	var html HTML
+	if sample.HTML != "" {
+		doctype, head, bodyContent, bodyAttributes, err := p.htmlExtractor.Extract(sample.HTML)
+		if err != nil {
+			return Preview{}, err
+		}
+
+		html = HTML{
+			BodyContent:    bodyContent,
+			BodyAttributes: bodyAttributes,
+			Head:           head,
+			Doctype:        doctype,
+		}
+	}
+
+	delivery := Delivery{
+		ClientID:        sample.ClientID,
+		UserGUID:        sample.UserGUID,
+		Email:           sample.To,
+		RequestReceived: time.Now(),
+		Options: Options{
+			Subject:           sample.Subject,
+			Text:              sample.Text,
+			HTML:              html,
+			ReplyTo:           sample.ReplyTo,
+			KindDescription:   sample.KindDescription,
+			SourceDescription: sample.SourceDescription,
+		},
+	}
+
+	context := NewMessageContext(delivery, p.sender, p.domain, p.packager.cloak, templates)
+
+	message, err := p.packager.Pack(context)
+	if err != nil {
+		return Preview{}, err
+	}
+
+	preview := Preview{
+		Subject: message.Subject,
+		Source:  message.Data(),
+	}
+
+	for _, part := range message.Body {
+		switch part.ContentType {
+		case "text/plain":
+			preview.Text = part.Content
+		case "text/html":
+			preview.HTML = part.Content
+		}
+	}
+
+	return preview, nil
+}
@@ -0,0 +1,93 @@
+package common_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Previewer", func() {
+	var (
+		previewer     common.Previewer
+		htmlExtractor *mocks.HTMLExtractor
+		templates     common.Templates
+		sample        common.PreviewSample
+	)
+
+	BeforeEach(func() {
+		htmlExtractor = mocks.NewHTMLExtractor()
+		htmlExtractor.ExtractCall.Returns.BodyContent = "<p>sample html</p>"
+		htmlExtractor.ExtractCall.Returns.BodyAttributes = `class="sample"`
+
+		packager := common.NewPackager(mocks.NewTemplatesLoader(), mocks.NewAttachmentsLoader(), mocks.NewCloak(), "")
+		previewer = common.NewPreviewer(packager, htmlExtractor, "no-reply@example.com", "example.com")
+
+		templates = common.Templates{
+			Subject: "Preview: {{.Subject}}",
+			Text:    "{{.Text}} from {{.Domain}}",
+			HTML:    "<h1>{{.KindDescription}}</h1>{{.HTML}}",
+		}
+
+		sample = common.PreviewSample{
+			ClientID:        "some-client-id",
+			Subject:         "the subject",
+			Text:            "the text",
+			HTML:            "<html><body class=\"sample\"><p>sample html</p></body></html>",
+			To:              "user@example.com",
+			KindDescription: "Outages",
+		}
+	})
+
+	It("renders the subject, text and html of the template", func() {
+		preview, err := previewer.Preview(templates, sample)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(htmlExtractor.ExtractCall.Receives.HTML).To(Equal(sample.HTML))
+
+		Expect(preview.Subject).To(Equal("Preview: the subject"))
+		Expect(preview.Text).To(Equal("the text from example.com"))
+		Expect(preview.HTML).To(ContainSubstring(`<body class="sample">`))
+		Expect(preview.HTML).To(ContainSubstring("<h1>Outages</h1><p>sample html</p>"))
+	})
+
+	It("includes the full MIME source of the message", func() {
+		preview, err := previewer.Preview(templates, sample)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(preview.Source).To(ContainSubstring("From: no-reply@example.com\n"))
+		Expect(preview.Source).To(ContainSubstring("To: user@example.com\n"))
+		Expect(preview.Source).To(ContainSubstring("Subject: Preview: the subject\n"))
+		Expect(preview.Source).To(ContainSubstring("X-CF-Client-ID: some-client-id\n"))
+		Expect(preview.Source).To(ContainSubstring("multipart/alternative"))
+	})
+
+	It("leaves out the html part when the sample has no html", func() {
+		sample.HTML = ""
+
+		preview, err := previewer.Preview(templates, sample)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(preview.HTML).To(BeEmpty())
+		Expect(preview.Text).To(Equal("the text from example.com"))
+	})
+
+	Context("when an error occurs", func() {
+		It("returns the error from extracting the html", func() {
+			htmlExtractor.ExtractCall.Returns.Error = errors.New("bad html")
+
+			_, err := previewer.Preview(templates, sample)
+			Expect(err).To(MatchError(errors.New("bad html")))
+		})
+
+		It("returns the error from parsing the template", func() {
+			templates.Text = "{{.Text"
+
+			_, err := previewer.Preview(templates, sample)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,122 @@
+package common
+
+import (
+	"math"
+	"net"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/uaa"
+)
+
+const (
+	JobTypeV1       = "v1"
+	JobTypeV2       = "v2"
+	JobTypeCampaign = "campaign"
+)
+
+var JobTypes = []string{JobTypeV1, JobTypeV2, JobTypeCampaign}
+
+const (
+	ErrorClassNetwork = "network"
+	ErrorClassSMTP4xx = "smtp-4xx"
+	ErrorClassSMTP5xx = "smtp-5xx"
+	ErrorClassCC4xx   = "cc-4xx"
+	ErrorClassCC5xx   = "cc-5xx"
+	ErrorClassUAA4xx  = "uaa-4xx"
+	ErrorClassUAA5xx  = "uaa-5xx"
+)
+
+var ErrorClasses = []string{
+	ErrorClassNetwork,
+	ErrorClassSMTP4xx,
+	ErrorClassSMTP5xx,
+	ErrorClassCC4xx,
+	ErrorClassCC5xx,
+	ErrorClassUAA4xx,
+	ErrorClassUAA5xx,
+}
+
+// RetryPolicy describes how a failed job is rescheduled. MaxAttempts counts
+// the first delivery attempt, so a job is retried MaxAttempts-1 times before
+// it is dead lettered. The delay doubles from BaseDelay with each retry, is
+// capped at MaxDelay when it is set, and is then spread by up to Jitter (a
+// fraction of the delay) in either direction.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Jitter      float64
+	Overrides   map[string]RetryPolicy
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 11,
+		BaseDelay:   1 * time.Minute,
+	}
+}
+
+func (p RetryPolicy) For(err error) RetryPolicy {
+	if override, ok := p.Overrides[ErrorClass(err)]; ok {
+		return override
+	}
+
+	return p
+}
+
+func (p RetryPolicy) ShouldRetry(retryCount int) bool {
+	return retryCount+1 < p.MaxAttempts
+}
+
+func (p RetryPolicy) Delay(retryCount int, random func() float64) time.Duration {
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(retryCount))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*random() - 1)
+	}
+
+	return time.Duration(delay)
+}
+
+type RetryPolicies map[string]RetryPolicy
+
+func (policies RetryPolicies) For(jobType string) RetryPolicy {
+	if policy, ok := policies[jobType]; ok {
+		return policy
+	}
+
+	return DefaultRetryPolicy()
+}
+
+func ErrorClass(err error) string {
+	switch e := err.(type) {
+	case mail.SMTPError:
+		return statusClass(e.Code, ErrorClassSMTP4xx, ErrorClassSMTP5xx)
+	case cf.Failure:
+		return statusClass(e.Code, ErrorClassCC4xx, ErrorClassCC5xx)
+	case uaa.Failure:
+		return statusClass(e.Code(), ErrorClassUAA4xx, ErrorClassUAA5xx)
+	case UAADownError:
+		return ErrorClassUAA5xx
+	case net.Error:
+		return ErrorClassNetwork
+	}
+
+	return ""
+}
+
+func statusClass(code int, clientError, serverError string) string {
+	switch {
+	case code >= 500:
+		return serverError
+	case code >= 400:
+		return clientError
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,110 @@
+package common_test
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/uaa"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RetryPolicy", func() {
+	Describe("Delay", func() {
+		It("doubles the base delay with each retry", func() {
+			policy := common.RetryPolicy{BaseDelay: 10 * time.Second}
+
+			Expect(policy.Delay(0, nil)).To(Equal(10 * time.Second))
+			Expect(policy.Delay(1, nil)).To(Equal(20 * time.Second))
+			Expect(policy.Delay(4, nil)).To(Equal(160 * time.Second))
+		})
+
+		It("applies the jitter using the random source", func() {
+			policy := common.RetryPolicy{
+				BaseDelay: 10 * time.Second,
+				Jitter:    0.2,
+			}
+
+			Expect(policy.Delay(0, func() float64 { return 0 })).To(Equal(8 * time.Second))
+			Expect(policy.Delay(0, func() float64 { return 0.5 })).To(Equal(10 * time.Second))
+			Expect(policy.Delay(0, func() float64 { return 1 })).To(Equal(12 * time.Second))
+		})
+	})
+
+	Describe("ShouldRetry", func() {
+		It("counts the first attempt against the maximum", func() {
+			policy := common.RetryPolicy{MaxAttempts: 3}
+
+			Expect(policy.ShouldRetry(0)).To(BeTrue())
+			Expect(policy.ShouldRetry(1)).To(BeTrue())
+			Expect(policy.ShouldRetry(2)).To(BeFalse())
+		})
+	})
+
+	Describe("For", func() {
+		It("returns the override for the class of the error", func() {
+			override := common.RetryPolicy{MaxAttempts: 1}
+			policy := common.RetryPolicy{
+				MaxAttempts: 5,
+				Overrides: map[string]common.RetryPolicy{
+					common.ErrorClassCC5xx: override,
+				},
+			}
+
+			Expect(policy.For(cf.NewFailure(502, "bad gateway"))).To(Equal(override))
+			Expect(policy.For(cf.NewFailure(404, "not found"))).To(Equal(policy))
+			Expect(policy.For(errors.New("some error"))).To(Equal(policy))
+		})
+	})
+})
+
+var _ = Describe("RetryPolicies", func() {
+	Describe("For", func() {
+		It("returns the policy for the job type", func() {
+			policy := common.RetryPolicy{MaxAttempts: 2}
+			policies := common.RetryPolicies{
+				common.JobTypeCampaign: policy,
+			}
+
+			Expect(policies.For(common.JobTypeCampaign)).To(Equal(policy))
+		})
+
+		It("falls back to the default policy", func() {
+			policies := common.RetryPolicies{}
+
+			Expect(policies.For(common.JobTypeV1)).To(Equal(common.DefaultRetryPolicy()))
+		})
+	})
+})
+
+var _ = Describe("ErrorClass", func() {
+	It("classifies SMTP replies", func() {
+		Expect(common.ErrorClass(mail.SMTPError{Code: 421})).To(Equal(common.ErrorClassSMTP4xx))
+		Expect(common.ErrorClass(mail.SMTPError{Code: 554})).To(Equal(common.ErrorClassSMTP5xx))
+	})
+
+	It("classifies cloud controller failures", func() {
+		Expect(common.ErrorClass(cf.NewFailure(403, "forbidden"))).To(Equal(common.ErrorClassCC4xx))
+		Expect(common.ErrorClass(cf.NewFailure(503, "unavailable"))).To(Equal(common.ErrorClassCC5xx))
+	})
+
+	It("classifies UAA failures", func() {
+		Expect(common.ErrorClass(uaa.NewFailure(401, []byte("unauthorized")))).To(Equal(common.ErrorClassUAA4xx))
+		Expect(common.ErrorClass(uaa.NewFailure(500, []byte("oops")))).To(Equal(common.ErrorClassUAA5xx))
+		Expect(common.ErrorClass(common.UAADownError{errors.New("UAA is unavailable")})).To(Equal(common.ErrorClassUAA5xx))
+	})
+
+	It("classifies network errors", func() {
+		Expect(common.ErrorClass(&net.OpError{Op: "dial", Err: errors.New("connection refused")})).To(Equal(common.ErrorClassNetwork))
+	})
+
+	It("does not classify other errors", func() {
+		Expect(common.ErrorClass(errors.New("some error"))).To(Equal(""))
+		Expect(common.ErrorClass(nil)).To(Equal(""))
+	})
+})
@@ -0,0 +1,537 @@
+package common
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+var (
+	messageContextType = reflect.TypeOf(MessageContext{})
+	parseErrorPattern  = regexp.MustCompile(`^template: [^:]*:(\d+):(?:(\d+):)? (.*)$`)
+	locationPattern    = regexp.MustCompile(`^(.*):(\d+):(\d+)$`)
+)
+
+// TemplateError describes a problem with one part of a template. Line and
+// Column locate the problem; Column is 0 when the parser does not report
+// one.
+type TemplateError struct {
+	Part    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e TemplateError) Error() string {
+	if e.Column == 0 {
+		return fmt.Sprintf("%s template is invalid at line %d: %s", e.Part, e.Line, e.Message)
+	}
+
+	return fmt.Sprintf("%s template is invalid at line %d, column %d: %s", e.Part, e.Line, e.Column, e.Message)
+}
+
+// ValidateTemplates checks the subject, text and html of a template the way
+// the Packager will compile them with the given partials, so that a broken
+// template is rejected when it is saved rather than when it is sent.
+func ValidateTemplates(subject, text, html string, partials map[string]string) error {
+	parts := []struct {
+		name   string
+		source string
+	}{
+		{"subject", subject},
+		{"text", text},
+		{"html", html},
+	}
+
+	for _, part := range parts {
+		err := ValidateTemplate(part.name, part.source)
+		if err != nil {
+			return err
+		}
+	}
+
+	set, err := parsePartials(partials)
+	if err != nil {
+		return err
+	}
+
+	for _, part := range parts {
+		tmpl, err := set.Clone()
+		if err != nil {
+			return err
+		}
+
+		_, err = tmpl.New(part.name).Parse(part.source)
+		if err != nil {
+			return newParseError(part.name, err)
+		}
+
+		checker := referenceChecker{
+			set:     tmpl,
+			sources: withSource(partials, part.name, part.source),
+			part:    part.name,
+		}
+
+		err = checker.check(part.name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return validateHTMLEscaping("html", html, partials)
+}
+
+// ValidatePartials checks the partials of a client. Every partial must
+// parse, and partials may not include each other in a cycle. A partial may
+// include a template that is not one of the partials, such as a block that
+// a layout expects the template using it to define, so those references are
+// only checked when a template is saved.
+func ValidatePartials(partials map[string]string) error {
+	set, err := parsePartials(partials)
+	if err != nil {
+		return err
+	}
+
+	checker := referenceChecker{
+		set:     set,
+		sources: partials,
+	}
+
+	for _, name := range partialNames(partials) {
+		err = checker.check(name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func parsePartials(partials map[string]string) (*template.Template, error) {
+	set := template.New("")
+
+	for _, name := range partialNames(partials) {
+		_, err := set.New(name).Parse(partials[name])
+		if err != nil {
+			return nil, newParseError(partialPart(name), err)
+		}
+	}
+
+	return set, nil
+}
+
+func partialPart(name string) string {
+	return fmt.Sprintf("partial %q", name)
+}
+
+func withSource(partials map[string]string, name, source string) map[string]string {
+	sources := map[string]string{name: source}
+	for partial, body := range partials {
+		if partial != name {
+			sources[partial] = body
+		}
+	}
+
+	return sources
+}
+
+// referenceChecker follows the {{template}} actions of a template through
+// the partials it includes and reports partials that include themselves.
+// When checking a template part, rather than partials on their own, it also
+// reports references to templates that are not defined anywhere.
+type referenceChecker struct {
+	set     *template.Template
+	sources map[string]string
+	part    string
+}
+
+func (c referenceChecker) check(name string) error {
+	return c.visit(name, []string{name}, map[string]bool{})
+}
+
+func (c referenceChecker) visit(name string, path []string, checked map[string]bool) error {
+	if checked[name] {
+		return nil
+	}
+
+	tmpl := c.set.Lookup(name)
+	if tmpl == nil || tmpl.Tree == nil {
+		return nil
+	}
+
+	for _, node := range templateNodes(tmpl.Tree.Root) {
+		for i, included := range path {
+			if included == node.Name {
+				cycle := append(append([]string{}, path[i:]...), node.Name)
+				return c.errorAt(tmpl.Tree, node, fmt.Sprintf("partials include each other in a cycle: %s", strings.Join(cycle, " -> ")))
+			}
+		}
+
+		if c.set.Lookup(node.Name) == nil {
+			if c.part != "" {
+				return c.errorAt(tmpl.Tree, node, fmt.Sprintf("unknown partial %q", node.Name))
+			}
+
+			continue
+		}
+
+		err := c.visit(node.Name, append(path[:len(path):len(path)], node.Name), checked)
+		if err != nil {
+			return err
+		}
+	}
+
+	checked[name] = true
+
+	return nil
+}
+
+// errorAt reports a problem with a {{template}} action, locating it in the
+// source of the template or partial it was parsed from.
+func (c referenceChecker) errorAt(tree *parse.Tree, node *parse.TemplateNode, message string) error {
+	source := c.sources[tree.ParseName]
+
+	part := c.part
+	if tree.ParseName != c.part {
+		part = partialPart(tree.ParseName)
+	}
+
+	offset := int(node.Position())
+	if offset > len(source) {
+		offset = len(source)
+	}
+
+	return TemplateError{
+		Part:    part,
+		Line:    1 + strings.Count(source[:offset], "\n"),
+		Column:  offset - strings.LastIndex(source[:offset], "\n"),
+		Message: message,
+	}
+}
+
+func templateNodes(node parse.Node) []*parse.TemplateNode {
+	var nodes []*parse.TemplateNode
+
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+
+		for _, child := range n.Nodes {
+			nodes = append(nodes, templateNodes(child)...)
+		}
+	case *parse.IfNode:
+		nodes = append(nodes, templateNodes(n.List)...)
+		nodes = append(nodes, templateNodes(n.ElseList)...)
+	case *parse.RangeNode:
+		nodes = append(nodes, templateNodes(n.List)...)
+		nodes = append(nodes, templateNodes(n.ElseList)...)
+	case *parse.WithNode:
+		nodes = append(nodes, templateNodes(n.List)...)
+		nodes = append(nodes, templateNodes(n.ElseList)...)
+	case *parse.TemplateNode:
+		nodes = append(nodes, n)
+	}
+
+	return nodes
+}
+
+// validateHTMLEscaping runs html/template's contextual escaper over an html
+// template and the partials it includes. The escaper rejects actions it
+// cannot escape safely, such as an {{if}} whose branches leave the template
+// in different HTML contexts.
+func validateHTMLEscaping(part, source string, partials map[string]string) error {
+	tmpl := htmltemplate.New(part)
+	for _, name := range partialNames(partials) {
+		_, err := tmpl.New(name).Parse(partials[name])
+		if err != nil {
+			return newParseError(partialPart(name), err)
+		}
+	}
+
+	_, err := tmpl.Parse(source)
+	if err != nil {
+		return newParseError(part, err)
+	}
+
+	err = tmpl.Execute(ioutil.Discard, htmlMessageContext{})
+	if escapeErr, ok := err.(*htmltemplate.Error); ok {
+		templateError := TemplateError{
+			Part: part,
+			Line: escapeErr.Line,
+			// Descriptions end with a dump of html/template's internal
+			// escaping state, which means nothing to a template author.
+			Message: strings.SplitN(escapeErr.Description, ": {", 2)[0],
+		}
+
+		// The node, when there is one, knows which template or partial it
+		// was parsed from, even when the error is reported against the
+		// template that included it.
+		if escapeErr.Node != nil {
+			location, _ := tmpl.Tree.ErrorContext(escapeErr.Node)
+			if matches := locationPattern.FindStringSubmatch(location); matches != nil {
+				if matches[1] != part {
+					templateError.Part = partialPart(matches[1])
+				}
+
+				templateError.Line, _ = strconv.Atoi(matches[2])
+				templateError.Column, _ = strconv.Atoi(matches[3])
+				templateError.Column++
+			}
+		}
+
+		if templateError.Line == 0 {
+			templateError.Line = 1
+		}
+
+		return templateError
+	}
+
+	return nil
+}
+
+// ValidateTemplate parses a single template and checks that every field it
+// references exists on MessageContext.
+func ValidateTemplate(part, source string) error {
+	tmpl, err := template.New(part).Parse(source)
+	if err != nil {
+		return newParseError(part, err)
+	}
+
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil || t.Tree.Root == nil {
+			continue
+		}
+
+		// Templates created with {{define}} may be invoked with any dot, so
+		// only the main template can be checked against MessageContext.
+		var root reflect.Type
+		if t.Name() == part {
+			root = messageContextType
+		}
+
+		checker := fieldChecker{part: part, source: source, root: root}
+		err = checker.checkNode(t.Tree.Root, root)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newParseError(part string, err error) error {
+	matches := parseErrorPattern.FindStringSubmatch(err.Error())
+	if matches == nil {
+		return TemplateError{Part: part, Line: 1, Message: err.Error()}
+	}
+
+	line, _ := strconv.Atoi(matches[1])
+	column, _ := strconv.Atoi(matches[2])
+
+	return TemplateError{
+		Part:    part,
+		Line:    line,
+		Column:  column,
+		Message: matches[3],
+	}
+}
+
+// fieldChecker walks a parsed template, tracking the type of dot, and
+// reports the first field that cannot be resolved. A nil dot means that its
+// type is not known and fields on it are not checked.
+type fieldChecker struct {
+	part   string
+	source string
+	root   reflect.Type
+}
+
+func (c fieldChecker) checkNode(node parse.Node, dot reflect.Type) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+
+		for _, child := range n.Nodes {
+			err := c.checkNode(child, dot)
+			if err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		_, err := c.checkPipe(n.Pipe, dot)
+		return err
+	case *parse.IfNode:
+		return c.checkBranch(&n.BranchNode, dot, dot)
+	case *parse.WithNode:
+		pipeType, err := c.checkPipe(n.Pipe, dot)
+		if err != nil {
+			return err
+		}
+
+		return c.checkBranch(&n.BranchNode, dot, pipeType)
+	case *parse.RangeNode:
+		pipeType, err := c.checkPipe(n.Pipe, dot)
+		if err != nil {
+			return err
+		}
+
+		return c.checkBranch(&n.BranchNode, dot, elementType(pipeType))
+	case *parse.TemplateNode:
+		_, err := c.checkPipe(n.Pipe, dot)
+		return err
+	}
+
+	return nil
+}
+
+func (c fieldChecker) checkBranch(branch *parse.BranchNode, dot, listDot reflect.Type) error {
+	_, err := c.checkPipe(branch.Pipe, dot)
+	if err != nil {
+		return err
+	}
+
+	err = c.checkNode(branch.List, listDot)
+	if err != nil {
+		return err
+	}
+
+	return c.checkNode(branch.ElseList, dot)
+}
+
+// checkPipe checks every field in a pipeline and returns the type the
+// pipeline evaluates to, when that can be worked out.
+func (c fieldChecker) checkPipe(pipe *parse.PipeNode, dot reflect.Type) (reflect.Type, error) {
+	if pipe == nil {
+		return nil, nil
+	}
+
+	var result reflect.Type
+	for _, cmd := range pipe.Cmds {
+		result = nil
+
+		for i, arg := range cmd.Args {
+			var (
+				argType reflect.Type
+				err     error
+			)
+
+			switch a := arg.(type) {
+			case *parse.FieldNode:
+				argType, err = c.resolve(a, dot, a.Ident)
+			case *parse.VariableNode:
+				if len(a.Ident) > 1 && a.Ident[0] == "$" {
+					argType, err = c.resolve(a, c.root, a.Ident[1:])
+				}
+			case *parse.DotNode:
+				argType = dot
+			case *parse.PipeNode:
+				_, err = c.checkPipe(a, dot)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if i == 0 && len(cmd.Args) == 1 {
+				result = argType
+			}
+		}
+	}
+
+	if len(pipe.Decl) > 0 {
+		return nil, nil
+	}
+
+	return result, nil
+}
+
+func (c fieldChecker) resolve(node parse.Node, typ reflect.Type, idents []string) (reflect.Type, error) {
+	for i, ident := range idents {
+		if typ == nil {
+			return nil, nil
+		}
+
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+
+		if method, ok := reflect.PtrTo(typ).MethodByName(ident); ok {
+			if method.Type.NumOut() == 0 {
+				return nil, nil
+			}
+
+			typ = method.Type.Out(0)
+			continue
+		}
+
+		switch typ.Kind() {
+		case reflect.Map:
+			typ = typ.Elem()
+		case reflect.Struct:
+			field, ok := typ.FieldByName(ident)
+			if !ok || field.PkgPath != "" {
+				return nil, c.unknownField(node, idents, i)
+			}
+
+			typ = field.Type
+		case reflect.Interface:
+			return nil, nil
+		default:
+			return nil, c.unknownField(node, idents, i)
+		}
+	}
+
+	return typ, nil
+}
+
+// unknownField reports idents[index] as unknown, locating it in the source
+// by finding the text of the node that references it.
+func (c fieldChecker) unknownField(node parse.Node, idents []string, index int) error {
+	text := node.String()
+
+	end := int(node.Position()) + len(text)
+	if end > len(c.source) {
+		end = len(c.source)
+	}
+
+	offset := strings.LastIndex(c.source[:end], text)
+	if offset < 0 {
+		offset = int(node.Position())
+	}
+
+	offset += strings.Index(text, ".")
+	for _, ident := range idents[:index] {
+		offset += len(ident) + 1
+	}
+
+	line := 1 + strings.Count(c.source[:offset], "\n")
+	column := offset - strings.LastIndex(c.source[:offset], "\n")
+
+	return TemplateError{
+		Part:    c.part,
+		Line:    line,
+		Column:  column,
+		Message: fmt.Sprintf("unknown field %q", idents[index]),
+	}
+}
+
+func elementType(typ reflect.Type) reflect.Type {
+	if typ == nil {
+		return nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Array, reflect.Slice, reflect.Map, reflect.Chan:
+		return typ.Elem()
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package common_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ValidateTemplate", func() {
+	It("accepts templates that only reference message context fields", func() {
+		Expect(common.ValidateTemplate("html", `
+			<p>{{.Text}} from {{.SourceDescription}}</p>
+			{{with .HTMLComponents}}{{.BodyContent}}{{end}}
+			{{range .Cc}}{{.}}{{end}}
+			{{if .Critical}}{{$.Subject}}{{end}}
+			{{.RequestReceived.Format "2006-01-02"}}
+			{{.Headers.Anything}}
+			{{.Subject | printf "%s"}}
+		`)).To(Succeed())
+	})
+
+	It("rejects a reference to an unknown field with its line and column", func() {
+		err := common.ValidateTemplate("text", "Hello\n  {{.Subjet}}")
+		Expect(err).To(Equal(common.TemplateError{
+			Part:    "text",
+			Line:    2,
+			Column:  5,
+			Message: `unknown field "Subjet"`,
+		}))
+		Expect(err).To(MatchError(`text template is invalid at line 2, column 5: unknown field "Subjet"`))
+	})
+
+	It("locates an unknown field in the middle of a chain", func() {
+		err := common.ValidateTemplate("html", "{{.HTMLComponents.Bodyy.Content}}")
+		Expect(err).To(MatchError(`html template is invalid at line 1, column 18: unknown field "Bodyy"`))
+	})
+
+	It("checks fields inside with blocks against the new dot", func() {
+		err := common.ValidateTemplate("html", "{{with .HTMLComponents}}{{.Subject}}{{end}}")
+		Expect(err).To(MatchError(`html template is invalid at line 1, column 27: unknown field "Subject"`))
+	})
+
+	It("checks fields referenced through $", func() {
+		err := common.ValidateTemplate("html", "{{range .Cc}}{{$.Recipient}}{{end}}")
+		Expect(err).To(MatchError(`html template is invalid at line 1, column 17: unknown field "Recipient"`))
+	})
+
+	It("checks fields in branches that might not be taken", func() {
+		err := common.ValidateTemplate("html", "{{if .Critical}}{{.Urgent}}{{end}}")
+		Expect(err).To(MatchError(`html template is invalid at line 1, column 19: unknown field "Urgent"`))
+	})
+
+	It("rejects templates that cannot be parsed", func() {
+		err := common.ValidateTemplate("subject", "Hi\n{{.Subject")
+		Expect(err).To(Equal(common.TemplateError{
+			Part:    "subject",
+			Line:    2,
+			Message: "unclosed action",
+		}))
+		Expect(err).To(MatchError("subject template is invalid at line 2: unclosed action"))
+	})
+})
+
+var _ = Describe("ValidateTemplates", func() {
+	It("validates the subject, text and html in turn", func() {
+		Expect(common.ValidateTemplates("{{.Subject}}", "{{.Text}}", "{{.HTML}}", nil)).To(Succeed())
+
+		err := common.ValidateTemplates("{{.Subject}}", "{{.Text}}", "{{.HTMl}}", nil)
+		Expect(err).To(MatchError(`html template is invalid at line 1, column 3: unknown field "HTMl"`))
+	})
+
+	It("rejects html that cannot be escaped safely", func() {
+		err := common.ValidateTemplates("{{.Subject}}", "{{.Text}}", `<p>{{if .Critical}}<a href="{{end}}{{.HTML}}</p>`, nil)
+		Expect(err).To(Equal(common.TemplateError{
+			Part:    "html",
+			Line:    1,
+			Column:  9,
+			Message: "{{if}} branches end in different contexts",
+		}))
+	})
+
+	It("does not apply html escaping rules to the subject and text", func() {
+		Expect(common.ValidateTemplates(`{{if .Critical}}<a href="{{end}}`, `{{if .Critical}}<a href="{{end}}`, "{{.HTML}}", nil)).To(Succeed())
+	})
+
+	Context("with partials", func() {
+		var partials map[string]string
+
+		BeforeEach(func() {
+			partials = map[string]string{
+				"header": "<header>{{.Subject}}</header>",
+				"footer": `<footer>{{template "legal" .}}</footer>`,
+				"legal":  "Sent to {{.To}}",
+				"layout": `<main>{{template "content" .}}</main>`,
+			}
+		})
+
+		It("accepts templates that include partials", func() {
+			err := common.ValidateTemplates(`{{template "legal" .}}`, `{{.Text}} {{template "legal" .}}`, `{{template "header" .}}{{.HTML}}{{template "footer" .}}`, partials)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("accepts templates that define the blocks of a layout", func() {
+			err := common.ValidateTemplates("{{.Subject}}", "{{.Text}}", `{{define "content"}}{{.HTML}}{{end}}{{template "layout" .}}`, partials)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("rejects a reference to a partial that does not exist", func() {
+			err := common.ValidateTemplates("{{.Subject}}", "Hi\n{{template \"footr\" .}}", "{{.HTML}}", partials)
+			Expect(err).To(Equal(common.TemplateError{
+				Part:    "text",
+				Line:    2,
+				Column:  12,
+				Message: `unknown partial "footr"`,
+			}))
+		})
+
+		It("rejects a layout whose blocks the template does not define", func() {
+			err := common.ValidateTemplates("{{.Subject}}", "{{.Text}}", `{{template "layout" .}}`, partials)
+			Expect(err).To(Equal(common.TemplateError{
+				Part:    `partial "layout"`,
+				Line:    1,
+				Column:  18,
+				Message: `unknown partial "content"`,
+			}))
+			Expect(err).To(MatchError(`partial "layout" template is invalid at line 1, column 18: unknown partial "content"`))
+		})
+
+		It("rejects partials that include each other", func() {
+			partials["legal"] = `{{template "footer" .}}`
+
+			err := common.ValidateTemplates("{{.Subject}}", "{{.Text}}", `{{template "footer" .}}`, partials)
+			Expect(err).To(MatchError(`partial "legal" template is invalid at line 1, column 12: partials include each other in a cycle: footer -> legal -> footer`))
+		})
+
+		It("rejects html in a partial that cannot be escaped safely", func() {
+			partials["footer"] = `{{if .Critical}}<a href="{{end}}`
+
+			err := common.ValidateTemplates("{{.Subject}}", "{{.Text}}", `{{template "footer" .}}`, partials)
+			Expect(err).To(BeAssignableToTypeOf(common.TemplateError{}))
+			Expect(err.(common.TemplateError).Part).To(Equal(`partial "footer"`))
+			Expect(err.(common.TemplateError).Message).To(Equal("{{if}} branches end in different contexts"))
+		})
+	})
+})
+
+var _ = Describe("ValidatePartials", func() {
+	It("accepts partials that include templates they do not define", func() {
+		err := common.ValidatePartials(map[string]string{
+			"layout": `<main>{{template "content" .}}</main>{{template "footer" .}}`,
+			"footer": "<footer>{{.Organization}}</footer>",
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("rejects partials that cannot be parsed", func() {
+		err := common.ValidatePartials(map[string]string{
+			"footer": "<footer>\n{{.Organization</footer>",
+		})
+		Expect(err).To(BeAssignableToTypeOf(common.TemplateError{}))
+		Expect(err.(common.TemplateError).Part).To(Equal(`partial "footer"`))
+		Expect(err.(common.TemplateError).Line).To(Equal(2))
+	})
+
+	It("rejects a partial that includes itself", func() {
+		err := common.ValidatePartials(map[string]string{
+			"footer": `<footer>{{template "footer" .}}</footer>`,
+		})
+		Expect(err).To(MatchError(`partial "footer" template is invalid at line 1, column 20: partials include each other in a cycle: footer -> footer`))
+	})
+
+	It("rejects partials that include each other", func() {
+		err := common.ValidatePartials(map[string]string{
+			"a": `{{template "b" .}}`,
+			"b": `{{if .Critical}}{{template "c" .}}{{end}}`,
+			"c": `{{template "a" .}}`,
+		})
+		Expect(err).To(MatchError(`partial "c" template is invalid at line 1, column 12: partials include each other in a cycle: a -> b -> c -> a`))
+	})
+})
@@ -28,7 +28,7 @@ type messageStatusUpdater interface {
 }
 
 type deliveryFailureHandler interface {
-	Handle(job common.Retryable, logger lager.Logger)
+	Handle(job common.Retryable, err error, logger lager.Logger)
 }
 
 type DeliveryWorkerConfig struct {
@@ -40,6 +40,7 @@ type DeliveryWorkerConfig struct {
 	Database               db.DatabaseInterface
 	CampaignJobProcessor   campaignJobProcessor
 	DeliveryFailureHandler deliveryFailureHandler
+	CampaignFailureHandler deliveryFailureHandler
 	MessageStatusUpdater   messageStatusUpdater
 }
 
@@ -53,6 +54,7 @@ type DeliveryWorker struct {
 	database               db.DatabaseInterface
 	campaignJobProcessor   campaignJobProcessor
 	deliveryFailureHandler deliveryFailureHandler
+	campaignFailureHandler deliveryFailureHandler
 	messageStatusUpdater   messageStatusUpdater
 }
 
@@ -65,6 +67,7 @@ func NewDeliveryWorker(v1DeliveryJobProcessor v1DeliveryJobProcessor, v2Delivery
 		database:               config.Database,
 		campaignJobProcessor:   config.CampaignJobProcessor,
 		deliveryFailureHandler: config.DeliveryFailureHandler,
+		campaignFailureHandler: config.CampaignFailureHandler,
 		messageStatusUpdater:   config.MessageStatusUpdater,
 	}
 	ticker := gobble.NewTicker(time.NewTicker, 30*time.Second)
@@ -85,23 +88,23 @@ func (worker DeliveryWorker) Deliver(job *gobble.Job) {
 			"name": "notifications.worker.panic.json",
 		}).Log()
 
-		worker.deliveryFailureHandler.Handle(job, worker.logger)
+		worker.deliveryFailureHandler.Handle(job, err, worker.logger)
 		return
 	}
 
 	switch typedJob.JobType {
-	case "campaign":
+	case common.JobTypeCampaign:
 		err := worker.campaignJobProcessor.Process(worker.database.Connection(), worker.uaaHost, *job, worker.logger)
 		if err != nil {
-			worker.deliveryFailureHandler.Handle(job, worker.logger)
+			worker.campaignFailureHandler.Handle(job, err, worker.logger)
 		}
-	case "v2":
+	case common.JobTypeV2:
 		var delivery common.Delivery
 		job.Unmarshal(&delivery)
 
 		err = worker.V2DeliveryJobProcessor.Process(delivery, worker.logger)
 		if err != nil {
-			worker.deliveryFailureHandler.Handle(job, worker.logger)
+			worker.deliveryFailureHandler.Handle(job, err, worker.logger)
 			status := common.StatusFailed
 			if job.ShouldRetry {
 				status = common.StatusRetry
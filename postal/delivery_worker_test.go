@@ -23,6 +23,7 @@ var _ = Describe("DeliveryWorker", func() {
 		delivery               common.Delivery
 		queue                  *mocks.Queue
 		deliveryFailureHandler *mocks.DeliveryFailureHandler
+		campaignFailureHandler *mocks.DeliveryFailureHandler
 		v1DeliveryJobProcessor *mocks.V1DeliveryJobProcessor
 		v2DeliveryJobProcessor *mocks.V2DeliveryJobProcessor
 		campaignJobProcessor   *mocks.CampaignJobProcessor
@@ -36,6 +37,7 @@ var _ = Describe("DeliveryWorker", func() {
 		logger.RegisterSink(lager.NewWriterSink(buffer, lager.DEBUG))
 		queue = mocks.NewQueue()
 		deliveryFailureHandler = mocks.NewDeliveryFailureHandler()
+		campaignFailureHandler = mocks.NewDeliveryFailureHandler()
 		campaignJobProcessor = mocks.NewCampaignJobProcessor()
 		connection = mocks.NewConnection()
 		database := mocks.NewDatabase()
@@ -47,6 +49,7 @@ var _ = Describe("DeliveryWorker", func() {
 			Logger: logger,
 			Queue:  queue,
 			DeliveryFailureHandler: deliveryFailureHandler,
+			CampaignFailureHandler: campaignFailureHandler,
 			CampaignJobProcessor:   campaignJobProcessor,
 			Database:               database,
 			UAAHost:                "my-uaa-host",
@@ -121,14 +124,16 @@ var _ = Describe("DeliveryWorker", func() {
 			})
 
 			Context("when the strategy fails to determine", func() {
-				It("uses the deliveryFailureHandler", func() {
+				It("uses the campaignFailureHandler", func() {
 					campaignJobProcessor.ProcessCall.Returns.Error = errors.New("some error")
 
 					worker.Deliver(job)
 
-					Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeTrue())
-					Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
-					Expect(deliveryFailureHandler.HandleCall.Receives.Logger).ToNot(BeNil())
+					Expect(campaignFailureHandler.HandleCall.WasCalled).To(BeTrue())
+					Expect(campaignFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+					Expect(campaignFailureHandler.HandleCall.Receives.Error).To(MatchError(errors.New("some error")))
+					Expect(campaignFailureHandler.HandleCall.Receives.Logger).ToNot(BeNil())
+					Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
 				})
 			})
 		})
@@ -165,6 +170,7 @@ var _ = Describe("DeliveryWorker", func() {
 					worker.Deliver(job)
 
 					Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+					Expect(deliveryFailureHandler.HandleCall.Receives.Error).To(MatchError(errors.New("delivery failure")))
 					Expect(deliveryFailureHandler.HandleCall.Receives.Logger).NotTo(BeNil())
 					Expect(messageStatusUpdater.UpdateCall.Receives.Connection).To(Equal(connection))
 					Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal("some-message-id"))
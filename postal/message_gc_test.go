@@ -18,6 +18,7 @@ var _ = Describe("MessageGC", func() {
 	var (
 		messageGC       postal.MessageGC
 		repo            *mocks.MessagesRepo
+		attachmentsRepo *mocks.AttachmentsRepo
 		oldMessageID    string
 		newMessageID    string
 		database        *mocks.Database
@@ -36,13 +37,14 @@ var _ = Describe("MessageGC", func() {
 		database.ConnectionCall.Returns.Connection = conn
 
 		repo = mocks.NewMessagesRepo()
+		attachmentsRepo = mocks.NewAttachmentsRepo()
 
 		lifetime = 2 * time.Minute
 		pollingInterval = 500 * time.Millisecond
 		oldMessageID = "that-message"
 		newMessageID = "this-message"
 
-		messageGC = postal.NewMessageGC(lifetime, database, repo, pollingInterval, logger)
+		messageGC = postal.NewMessageGC(lifetime, database, repo, attachmentsRepo, pollingInterval, logger)
 	})
 
 	Describe("Run", func() {
@@ -68,6 +70,23 @@ var _ = Describe("MessageGC", func() {
 			Expect(repo.DeleteBeforeCall.Receives.ThresholdTime).To(BeTemporally("~", time.Now().Add(-2*time.Minute), 10*time.Second))
 		})
 
+		It("deletes attachments older than the specified time", func() {
+			messageGC.Collect()
+
+			Expect(attachmentsRepo.DeleteBeforeCall.Receives.Connection).To(Equal(conn))
+			Expect(attachmentsRepo.DeleteBeforeCall.Receives.ThresholdTime).To(BeTemporally("~", time.Now().Add(-2*time.Minute), 10*time.Second))
+		})
+
+		Context("when the attachments cannot be deleted", func() {
+			It("logs the error", func() {
+				attachmentsRepo.DeleteBeforeCall.Returns.Error = errors.New("attachments table is missing")
+
+				messageGC.Collect()
+
+				Expect(loggerBuffer.String()).To(ContainSubstring("attachments table is missing"))
+			})
+		})
+
 		Context("When the repo errors unexpectantly", func() {
 			It("logs the error", func() {
 				repo.DeleteBeforeCall.Returns.Error = errors.New("messages table is totally corrupt")
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/cloudfoundry-incubator/notifications/db"
@@ -10,6 +11,7 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/uaa"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	v2models "github.com/cloudfoundry-incubator/notifications/v2/models"
 	"github.com/pivotal-golang/lager"
 )
 
@@ -31,7 +33,7 @@ type messageStatusUpdater interface {
 }
 
 type deliveryFailureHandler interface {
-	Handle(job common.Retryable, logger lager.Logger)
+	Handle(job common.Retryable, err error, logger lager.Logger)
 }
 
 type kindsFinder interface {
@@ -50,6 +52,10 @@ type globalUnsubscribesGetter interface {
 	Get(connection models.ConnectionInterface, userGUID string) (bool, error)
 }
 
+type suppressionsGetter interface {
+	GetByEmail(connection v2models.ConnectionInterface, email string) (v2models.Suppression, error)
+}
+
 type DeliveryJobProcessorConfig struct {
 	DBTrace bool
 	UAAHost string
@@ -66,6 +72,7 @@ type DeliveryJobProcessorConfig struct {
 	ReceiptsRepo           receiptsCreator
 	UnsubscribesRepo       unsubscribesGetter
 	GlobalUnsubscribesRepo globalUnsubscribesGetter
+	SuppressionsRepo       suppressionsGetter
 	MessageStatusUpdater   messageStatusUpdater
 	DeliveryFailureHandler deliveryFailureHandler
 }
@@ -86,6 +93,7 @@ type DeliveryJobProcessor struct {
 	receiptsRepo           receiptsCreator
 	unsubscribesRepo       unsubscribesGetter
 	globalUnsubscribesRepo globalUnsubscribesGetter
+	suppressionsRepo       suppressionsGetter
 	messageStatusUpdater   messageStatusUpdater
 	deliveryFailureHandler deliveryFailureHandler
 }
@@ -107,6 +115,7 @@ func NewDeliveryJobProcessor(config DeliveryJobProcessorConfig) DeliveryJobProce
 		receiptsRepo:           config.ReceiptsRepo,
 		unsubscribesRepo:       config.UnsubscribesRepo,
 		globalUnsubscribesRepo: config.GlobalUnsubscribesRepo,
+		suppressionsRepo:       config.SuppressionsRepo,
 		messageStatusUpdater:   config.MessageStatusUpdater,
 		deliveryFailureHandler: config.DeliveryFailureHandler,
 	}
@@ -120,7 +129,7 @@ func (p DeliveryJobProcessor) Process(job *gobble.Job, logger lager.Logger) erro
 			"name": "notifications.worker.panic.json",
 		}).Log()
 
-		p.deliveryFailureHandler.Handle(job, logger)
+		p.deliveryFailureHandler.Handle(job, err, logger)
 		return nil
 	}
 
@@ -135,7 +144,7 @@ func (p DeliveryJobProcessor) Process(job *gobble.Job, logger lager.Logger) erro
 
 	err = p.receiptsRepo.CreateReceipts(p.database.Connection(), []string{delivery.UserGUID}, delivery.ClientID, delivery.Options.KindID)
 	if err != nil {
-		p.deliveryFailureHandler.Handle(job, logger)
+		p.deliveryFailureHandler.Handle(job, err, logger)
 		return nil
 	}
 
@@ -144,13 +153,17 @@ func (p DeliveryJobProcessor) Process(job *gobble.Job, logger lager.Logger) erro
 
 		token, err = p.tokenLoader.Load(p.uaaHost)
 		if err != nil {
-			p.deliveryFailureHandler.Handle(job, logger)
+			p.deliveryFailureHandler.Handle(job, err, logger)
 			return nil
 		}
 
 		users, err := p.userLoader.Load([]string{delivery.UserGUID}, token)
-		if err != nil || len(users) < 1 {
-			p.deliveryFailureHandler.Handle(job, logger)
+		if err == nil && len(users) < 1 {
+			err = fmt.Errorf("user %q could not be loaded", delivery.UserGUID)
+		}
+
+		if err != nil {
+			p.deliveryFailureHandler.Handle(job, err, logger)
 			return nil
 		}
 
@@ -164,11 +177,35 @@ func (p DeliveryJobProcessor) Process(job *gobble.Job, logger lager.Logger) erro
 		"recipient": delivery.Email,
 	})
 
+	// Suppressed addresses are never mailed, even for critical kinds. A
+	// shared inbox delivery is still sent to its other To addresses.
+	if delivery.Email != "" {
+		recipients, err := p.withoutSuppressed(p.database.Connection(), delivery.Recipients(), logger)
+		if err != nil {
+			p.deliveryFailureHandler.Handle(job, err, logger)
+			return nil
+		}
+
+		if len(recipients) == 0 {
+			p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, common.StatusUndeliverable, "", logger)
+			metrics.NewMetric("counter", map[string]interface{}{
+				"name": "notifications.worker.suppressed",
+			}).Log()
+			return nil
+		}
+
+		delivery.Email = strings.Join(recipients, ", ")
+	}
+
 	if p.shouldDeliver(delivery, logger) {
-		status := p.process(delivery, logger)
+		status, err := p.process(delivery, logger)
 
-		if status != common.StatusDelivered {
-			p.deliveryFailureHandler.Handle(job, logger)
+		if status == common.StatusUndeliverable {
+			metrics.NewMetric("counter", map[string]interface{}{
+				"name": "notifications.worker.undeliverable",
+			}).Log()
+		} else if status != common.StatusDelivered {
+			p.deliveryFailureHandler.Handle(job, err, logger)
 			return nil
 		} else {
 			metrics.NewMetric("counter", map[string]interface{}{
@@ -184,27 +221,43 @@ func (p DeliveryJobProcessor) Process(job *gobble.Job, logger lager.Logger) erro
 	return nil
 }
 
-func (p DeliveryJobProcessor) process(delivery common.Delivery, logger lager.Logger) string {
+func (p DeliveryJobProcessor) process(delivery common.Delivery, logger lager.Logger) (string, error) {
+	conn := p.database.Connection()
+
+	var err error
+	delivery.Options.Cc, err = p.withoutSuppressed(conn, delivery.Options.Cc, logger)
+	if err != nil {
+		return common.StatusFailed, err
+	}
+
+	delivery.Options.Bcc, err = p.withoutSuppressed(conn, delivery.Options.Bcc, logger)
+	if err != nil {
+		return common.StatusFailed, err
+	}
+
 	context, err := p.packager.PrepareContext(delivery, p.sender, p.domain)
 	if err != nil {
-		panic(err)
+		logger.Error("prepare-context-failed", err)
+		p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, common.StatusFailed, "", logger)
+		return common.StatusFailed, err
 	}
 
 	message, err := p.packager.Pack(context)
 	if err != nil {
 		logger.Info("template-pack-failed")
 		p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, common.StatusFailed, "", logger)
-		return common.StatusFailed
+		return common.StatusFailed, err
 	}
 
-	status := p.sendMail(delivery.MessageID, message, logger)
+	status, err := p.sendMail(delivery.MessageID, message, logger)
 	p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, status, "", logger)
 
-	return status
+	return status, err
 }
 
 func (p DeliveryJobProcessor) shouldDeliver(delivery common.Delivery, logger lager.Logger) bool {
 	conn := p.database.Connection()
+
 	if p.isCritical(conn, delivery.Options.KindID, delivery.ClientID) {
 		return true
 	}
@@ -238,24 +291,29 @@ func (p DeliveryJobProcessor) shouldDeliver(delivery common.Delivery, logger lag
 	return true
 }
 
-func (p DeliveryJobProcessor) sendMail(messageID string, message mail.Message, logger lager.Logger) string {
+func (p DeliveryJobProcessor) sendMail(messageID string, message mail.Message, logger lager.Logger) (string, error) {
 	err := p.mailClient.Connect(logger)
 	if err != nil {
 		logger.Error("smtp-connection-error", err)
-		return common.StatusFailed
+		return common.StatusFailed, err
 	}
 
 	logger.Info("delivery-start")
 
 	err = p.mailClient.Send(message, logger)
 	if err != nil {
+		if mail.IsPermanent(err) {
+			logger.Error("delivery-failed-smtp-rejected", err)
+			return common.StatusUndeliverable, err
+		}
+
 		logger.Error("delivery-failed-smtp-error", err)
-		return common.StatusFailed
+		return common.StatusFailed, err
 	}
 
 	logger.Info("message-sent")
 
-	return common.StatusDelivered
+	return common.StatusDelivered, nil
 }
 
 func (p DeliveryJobProcessor) isCritical(conn db.ConnectionInterface, kindID, clientID string) bool {
@@ -266,3 +324,37 @@ func (p DeliveryJobProcessor) isCritical(conn db.ConnectionInterface, kindID, cl
 
 	return kind.Critical
 }
+
+// withoutSuppressed drops suppressed addresses so that they do not stop the
+// message from reaching its other recipients.
+func (p DeliveryJobProcessor) withoutSuppressed(conn db.ConnectionInterface, emails []string, logger lager.Logger) ([]string, error) {
+	var allowed []string
+	for _, email := range emails {
+		suppressed, err := p.isSuppressed(conn, email)
+		if err != nil {
+			return nil, err
+		}
+
+		if suppressed {
+			logger.Info("recipient-suppressed", lager.Data{"recipient": email})
+			continue
+		}
+
+		allowed = append(allowed, email)
+	}
+
+	return allowed, nil
+}
+
+func (p DeliveryJobProcessor) isSuppressed(conn db.ConnectionInterface, email string) (bool, error) {
+	_, err := p.suppressionsRepo.GetByEmail(conn, email)
+	if err != nil {
+		if _, ok := err.(v2models.RecordNotFoundError); ok {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
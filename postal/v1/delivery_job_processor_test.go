@@ -14,6 +14,7 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/uaa"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	v2models "github.com/cloudfoundry-incubator/notifications/v2/models"
 	"github.com/pivotal-golang/conceal"
 	"github.com/pivotal-golang/lager"
 
@@ -31,6 +32,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		queue                  *mocks.Queue
 		unsubscribesRepo       *mocks.UnsubscribesRepo
 		globalUnsubscribesRepo *mocks.GlobalUnsubscribesRepo
+		suppressionsRepo       *mocks.SuppressionsRepository
 		kindsRepo              *mocks.KindsRepo
 		database               *mocks.Database
 		campaignJobProcessor   *mocks.CampaignJobProcessor
@@ -39,6 +41,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		userGUID               string
 		fakeUserEmail          string
 		templateLoader         *mocks.TemplatesLoader
+		attachmentsLoader      *mocks.AttachmentsLoader
 		receiptsRepo           *mocks.ReceiptsRepo
 		tokenLoader            *mocks.TokenLoader
 		messageID              string
@@ -57,6 +60,8 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		queue = mocks.NewQueue()
 		unsubscribesRepo = mocks.NewUnsubscribesRepo()
 		globalUnsubscribesRepo = mocks.NewGlobalUnsubscribesRepo()
+		suppressionsRepo = mocks.NewSuppressionsRepository()
+		suppressionsRepo.GetByEmailCall.Returns.Error = v2models.RecordNotFoundError{errors.New("not suppressed")}
 
 		kindsRepo = mocks.NewKindsRepo()
 		kindsRepo.FindCall.Returns.Kinds = []models.Kind{
@@ -88,6 +93,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			HTML:    "<p>{{.HTML}}</p>",
 			Subject: "{{.Subject}}",
 		}
+		attachmentsLoader = mocks.NewAttachmentsLoader()
 		receiptsRepo = mocks.NewReceiptsRepo()
 		messageStatusUpdater = mocks.NewMessageStatusUpdater()
 		deliveryFailureHandler = mocks.NewDeliveryFailureHandler()
@@ -101,7 +107,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			Sender:  "from@example.com",
 			Domain:  "example.com",
 
-			Packager:    common.NewPackager(templateLoader, cloak),
+			Packager:    common.NewPackager(templateLoader, attachmentsLoader, cloak, ""),
 			MailClient:  mailClient,
 			Database:    database,
 			TokenLoader: tokenLoader,
@@ -111,6 +117,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			ReceiptsRepo:           receiptsRepo,
 			UnsubscribesRepo:       unsubscribesRepo,
 			GlobalUnsubscribesRepo: globalUnsubscribesRepo,
+			SuppressionsRepo:       suppressionsRepo,
 			MessageStatusUpdater:   messageStatusUpdater,
 			DeliveryFailureHandler: deliveryFailureHandler,
 		})
@@ -197,7 +204,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				Sender:  "from@example.com",
 				Domain:  "example.com",
 
-				Packager:    common.NewPackager(templateLoader, cloak),
+				Packager:    common.NewPackager(templateLoader, mocks.NewAttachmentsLoader(), cloak, ""),
 				MailClient:  mailClient,
 				Database:    database,
 				TokenLoader: tokenLoader,
@@ -207,6 +214,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				ReceiptsRepo:           receiptsRepo,
 				UnsubscribesRepo:       unsubscribesRepo,
 				GlobalUnsubscribesRepo: globalUnsubscribesRepo,
+				SuppressionsRepo:       suppressionsRepo,
 				MessageStatusUpdater:   messageStatusUpdater,
 				DeliveryFailureHandler: deliveryFailureHandler,
 			})
@@ -246,6 +254,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				processor.Process(job, logger)
 
 				Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+				Expect(deliveryFailureHandler.HandleCall.Receives.Error).To(MatchError(errors.New("something happened")))
 				Expect(deliveryFailureHandler.HandleCall.Receives.Logger.SessionName()).To(Equal("notifications.worker"))
 			})
 		})
@@ -311,6 +320,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 					processor.Process(job, logger)
 
 					Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+					Expect(deliveryFailureHandler.HandleCall.Receives.Error).To(MatchError(errors.New("Error sending message!!!")))
 					Expect(deliveryFailureHandler.HandleCall.Receives.Logger.SessionName()).To(Equal("notifications.worker"))
 				})
 
@@ -345,6 +355,65 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				})
 			})
 
+			Context("because the server rejected the message permanently", func() {
+				BeforeEach(func() {
+					mailClient.SendCall.Returns.Error = mail.SMTPError{
+						Code:           550,
+						EnhancedStatus: "5.1.1",
+						Message:        "5.1.1 mailbox does not exist",
+					}
+				})
+
+				It("does not retry the job", func() {
+					err := processor.Process(job, logger)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
+				})
+
+				It("updates the message status as undeliverable", func() {
+					processor.Process(job, logger)
+
+					Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal(messageID))
+					Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusUndeliverable))
+				})
+
+				It("logs the rejection", func() {
+					processor.Process(job, logger)
+
+					lines, err := parseLogLines(buffer.Bytes())
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(lines).To(ContainElement(logLine{
+						Source:   "notifications",
+						Message:  "notifications.worker.delivery-failed-smtp-rejected",
+						LogLevel: int(lager.ERROR),
+						Data: map[string]interface{}{
+							"session":         "1",
+							"error":           "550 5.1.1 mailbox does not exist",
+							"recipient":       "user-123@example.com",
+							"worker_id":       float64(1234),
+							"message_id":      "randomly-generated-guid",
+							"vcap_request_id": "some-request-id",
+						},
+					}))
+				})
+			})
+
+			Context("because the server rejected the message temporarily", func() {
+				It("marks the job for retry", func() {
+					mailClient.SendCall.Returns.Error = mail.SMTPError{
+						Code:    451,
+						Message: "4.7.1 greylisted",
+					}
+
+					processor.Process(job, logger)
+
+					Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeTrue())
+					Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusFailed))
+				})
+			})
+
 			Context("and the error is a connect error", func() {
 				It("logs an SMTP connection error", func() {
 					mailClient.ConnectCall.Returns.Error = errors.New("server timeout")
@@ -424,6 +493,90 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			})
 		})
 
+		Context("when the message has Cc and Bcc recipients", func() {
+			It("sends to them, leaving out the suppressed recipients", func() {
+				suppressionsRepo.GetByEmailCall.Returns.SuppressedEmails = []string{"bounced@example.com"}
+				delivery.Options.Cc = []string{"shared@example.com", "bounced@example.com"}
+				delivery.Options.Bcc = []string{"audit@example.com"}
+
+				processor.Process(gobble.NewJob(delivery), logger)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(1))
+				Expect(mailClient.SendCall.Receives.Message.To).To(Equal("user-123@example.com"))
+				Expect(mailClient.SendCall.Receives.Message.Cc).To(Equal([]string{"shared@example.com"}))
+				Expect(mailClient.SendCall.Receives.Message.Bcc).To(Equal([]string{"audit@example.com"}))
+			})
+		})
+
+		Context("when the recipient is suppressed", func() {
+			BeforeEach(func() {
+				suppressionsRepo.GetByEmailCall.Returns.Error = nil
+				suppressionsRepo.GetByEmailCall.Returns.Suppression = v2models.Suppression{
+					ID:    "some-suppression-id",
+					Email: "user-123@example.com",
+				}
+			})
+
+			It("does not send the notification", func() {
+				processor.Process(job, logger)
+
+				Expect(suppressionsRepo.GetByEmailCall.Receives.Connection).To(Equal(conn))
+				Expect(suppressionsRepo.GetByEmailCall.Receives.Email).To(Equal("user-123@example.com"))
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+			})
+
+			It("updates the message status as undeliverable", func() {
+				processor.Process(job, logger)
+
+				Expect(messageStatusUpdater.UpdateCall.Receives.Connection).To(Equal(conn))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal(messageID))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusUndeliverable))
+			})
+
+			It("logs that the recipient is suppressed", func() {
+				processor.Process(job, logger)
+
+				Expect(buffer.String()).To(ContainSubstring("notifications.worker.recipient-suppressed"))
+			})
+
+			Context("when the kind is critical", func() {
+				It("still does not send the notification", func() {
+					kindsRepo.FindCall.Returns.Kinds[0].Critical = true
+
+					processor.Process(job, logger)
+
+					Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				})
+			})
+
+			Context("when the delivery is for a raw email address", func() {
+				It("does not send the notification", func() {
+					delivery.UserGUID = ""
+					delivery.Email = "someone@example.com"
+
+					processor.Process(gobble.NewJob(delivery), logger)
+
+					Expect(suppressionsRepo.GetByEmailCall.Receives.Email).To(Equal("someone@example.com"))
+					Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when the suppression list cannot be read", func() {
+			BeforeEach(func() {
+				suppressionsRepo.GetByEmailCall.Returns.Error = errors.New("database failure")
+			})
+
+			It("hands the job to the failure handler so that it is retried", func() {
+				processor.Process(job, logger)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(BeEmpty())
+				Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+				Expect(deliveryFailureHandler.HandleCall.Receives.Error).To(MatchError(errors.New("database failure")))
+			})
+		})
+
 		Context("when the recipient hasn't unsubscribed, but doesn't have a valid email address", func() {
 			Context("when the recipient has no emails", func() {
 				BeforeEach(func() {
@@ -629,6 +782,31 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			})
 		})
 
+		Context("when the attachments cannot be loaded", func() {
+			BeforeEach(func() {
+				delivery.Options.AttachmentIDs = []string{"attachment-1"}
+				attachmentsLoader.LoadAttachmentsCall.Returns.Error = errors.New("database failure")
+				job = gobble.NewJob(delivery)
+			})
+
+			It("hands the job to the failure handler so that it is retried", func() {
+				Expect(func() {
+					processor.Process(job, logger)
+				}).NotTo(Panic())
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+				Expect(deliveryFailureHandler.HandleCall.Receives.Error).To(MatchError(errors.New("database failure")))
+			})
+
+			It("updates the message status as failed", func() {
+				processor.Process(job, logger)
+
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal(messageID))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusFailed))
+			})
+		})
+
 		Context("when the job contains malformed JSON", func() {
 			BeforeEach(func() {
 				job.Payload = `{"Space":"my-space","Options":{"HTML":"<p>some text that just abruptly ends`
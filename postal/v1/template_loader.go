@@ -18,20 +18,26 @@ type templateFinder interface {
 	FindByID(connection models.ConnectionInterface, templateID string) (models.Template, error)
 }
 
+type partialsFinder interface {
+	FindAllByClientID(connection models.ConnectionInterface, clientID string) (map[string]string, error)
+}
+
 type TemplatesLoader struct {
 	database db.DatabaseInterface
 
 	clientsRepo   clientFinder
 	kindsRepo     kindFinder
 	templatesRepo templateFinder
+	partialsRepo  partialsFinder
 }
 
-func NewTemplatesLoader(database db.DatabaseInterface, clientsRepo clientFinder, kindsRepo kindFinder, templatesRepo templateFinder) TemplatesLoader {
+func NewTemplatesLoader(database db.DatabaseInterface, clientsRepo clientFinder, kindsRepo kindFinder, templatesRepo templateFinder, partialsRepo partialsFinder) TemplatesLoader {
 	return TemplatesLoader{
 		database:      database,
 		clientsRepo:   clientsRepo,
 		kindsRepo:     kindsRepo,
 		templatesRepo: templatesRepo,
+		partialsRepo:  partialsRepo,
 	}
 }
 
@@ -57,15 +63,25 @@ func (loader TemplatesLoader) LoadTemplates(clientID, kindID, templateID string)
 	return loader.loadTemplate(conn, client.TemplateID)
 }
 
+// loadTemplate loads a template along with the partials of the client that
+// saved it. Templates are shared between clients, so resolving the partials
+// of the sending client would compile a template other than the one that
+// was validated when it was saved.
 func (loader TemplatesLoader) loadTemplate(conn db.ConnectionInterface, templateID string) (common.Templates, error) {
 	template, err := loader.templatesRepo.FindByID(conn, templateID)
 	if err != nil {
 		return common.Templates{}, err
 	}
 
+	partials, err := loader.partialsRepo.FindAllByClientID(conn, template.PartialsClientID)
+	if err != nil {
+		return common.Templates{}, err
+	}
+
 	return common.Templates{
-		Subject: template.Subject,
-		Text:    template.Text,
-		HTML:    template.HTML,
+		Subject:  template.Subject,
+		Text:     template.Text,
+		HTML:     template.HTML,
+		Partials: partials,
 	}, nil
 }
@@ -19,6 +19,7 @@ var _ = Describe("TemplateLoader", func() {
 		clientsRepo   *mocks.ClientsRepository
 		kindsRepo     *mocks.KindsRepo
 		templatesRepo *mocks.TemplatesRepo
+		partialsRepo  *mocks.PartialsRepo
 		conn          db.ConnectionInterface
 		database      *mocks.Database
 	)
@@ -27,12 +28,13 @@ var _ = Describe("TemplateLoader", func() {
 		clientsRepo = mocks.NewClientsRepository()
 		kindsRepo = mocks.NewKindsRepo()
 		templatesRepo = mocks.NewTemplatesRepo()
+		partialsRepo = mocks.NewPartialsRepo()
 
 		conn = mocks.NewConnection()
 		database = mocks.NewDatabase()
 		database.ConnectionCall.Returns.Connection = conn
 
-		loader = v1.NewTemplatesLoader(database, clientsRepo, kindsRepo, templatesRepo)
+		loader = v1.NewTemplatesLoader(database, clientsRepo, kindsRepo, templatesRepo, partialsRepo)
 	})
 
 	Describe("LoadTemplates", func() {
@@ -146,6 +148,38 @@ var _ = Describe("TemplateLoader", func() {
 			})
 		})
 
+		Context("when the template includes partials", func() {
+			It("returns the partials of the client that saved the template rather than the sender", func() {
+				templatesRepo.FindByIDCall.Returns.Template.PartialsClientID = "some-admin-client-id"
+				partialsRepo.FindAllByClientIDCall.Returns.Partials = map[string]string{
+					"footer": "<footer>{{.Organization}}</footer>",
+				}
+
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(templates).To(Equal(common.Templates{
+					HTML:    "<p>The default template</p>",
+					Text:    "The default template",
+					Subject: "default subject",
+					Partials: map[string]string{
+						"footer": "<footer>{{.Organization}}</footer>",
+					},
+				}))
+
+				Expect(partialsRepo.FindAllByClientIDCall.Receives.Connection).To(Equal(conn))
+				Expect(partialsRepo.FindAllByClientIDCall.Receives.ClientID).To(Equal("some-admin-client-id"))
+			})
+		})
+
+		Context("when the partials repo has an error", func() {
+			It("bubbles up the error", func() {
+				partialsRepo.FindAllByClientIDCall.Returns.Error = errors.New("BOOM!")
+
+				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				Expect(err).To(MatchError("BOOM!"))
+			})
+		})
+
 		Context("when the kinds repo has an error", func() {
 			It("bubbles up the error", func() {
 				kindsRepo.FindCall.Returns.Error = errors.New("BOOM!")
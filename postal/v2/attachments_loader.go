@@ -0,0 +1,45 @@
+package v2
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+)
+
+type attachmentGetter interface {
+	Get(connection models.ConnectionInterface, id string) (models.Attachment, error)
+}
+
+// AttachmentsLoader reads stored attachments for delivery. V1 notifications
+// and V2 campaigns share the attachments table, so it serves both.
+type AttachmentsLoader struct {
+	database    db.DatabaseInterface
+	attachments attachmentGetter
+}
+
+func NewAttachmentsLoader(database db.DatabaseInterface, attachments attachmentGetter) AttachmentsLoader {
+	return AttachmentsLoader{
+		database:    database,
+		attachments: attachments,
+	}
+}
+
+func (loader AttachmentsLoader) LoadAttachments(attachmentIDs []string) ([]mail.Attachment, error) {
+	conn := loader.database.Connection()
+
+	var attachments []mail.Attachment
+	for _, id := range attachmentIDs {
+		attachment, err := loader.attachments.Get(conn, id)
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, mail.Attachment{
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			Content:     attachment.Content,
+		})
+	}
+
+	return attachments, nil
+}
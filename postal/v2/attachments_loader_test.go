@@ -0,0 +1,72 @@
+package v2_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/postal/v2"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AttachmentsLoader", func() {
+	var (
+		conn            db.ConnectionInterface
+		database        *mocks.Database
+		attachmentsRepo *mocks.AttachmentsRepository
+		loader          v2.AttachmentsLoader
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		attachmentsRepo = mocks.NewAttachmentsRepository()
+		attachmentsRepo.GetCall.Returns.Attachments = map[string]models.Attachment{
+			"attachment-1": {
+				ID:          "attachment-1",
+				CampaignID:  "some-campaign-id",
+				Filename:    "report.pdf",
+				ContentType: "application/pdf",
+				Content:     []byte("%PDF"),
+			},
+			"attachment-2": {
+				ID:          "attachment-2",
+				CampaignID:  "some-campaign-id",
+				Filename:    "notes.txt",
+				ContentType: "text/plain",
+				Content:     []byte("notes"),
+			},
+		}
+
+		loader = v2.NewAttachmentsLoader(database, attachmentsRepo)
+	})
+
+	Describe("LoadAttachments", func() {
+		It("returns the attachments in order", func() {
+			attachments, err := loader.LoadAttachments([]string{"attachment-2", "attachment-1"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(attachments).To(Equal([]mail.Attachment{
+				{Filename: "notes.txt", ContentType: "text/plain", Content: []byte("notes")},
+				{Filename: "report.pdf", ContentType: "application/pdf", Content: []byte("%PDF")},
+			}))
+			Expect(attachmentsRepo.GetCall.Receives.Connection).To(Equal(conn))
+			Expect(attachmentsRepo.GetCall.Receives.IDs).To(Equal([]string{"attachment-2", "attachment-1"}))
+		})
+
+		Context("when an attachment cannot be loaded", func() {
+			It("returns the error", func() {
+				attachmentsRepo.GetCall.Returns.Error = errors.New("some error")
+
+				_, err := loader.LoadAttachments([]string{"attachment-1"})
+				Expect(err).To(MatchError(errors.New("some error")))
+			})
+		})
+	})
+})
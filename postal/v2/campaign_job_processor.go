@@ -2,6 +2,7 @@ package v2
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
@@ -96,6 +97,14 @@ func (p CampaignJobProcessor) Process(conn services.ConnectionInterface, uaaHost
 			return err
 		}
 
+		// Cc and Bcc recipients share the message with the emails
+		// audience, so its addresses get one delivery between them
+		// rather than one each, which would copy the Cc and Bcc
+		// recipients once per address.
+		if audienceName == "emails" && (len(campaignJob.Campaign.Cc) > 0 || len(campaignJob.Campaign.Bcc) > 0) {
+			aud = sharedInbox(aud)
+		}
+
 		audiences = append(audiences, aud...)
 	}
 
@@ -117,6 +126,8 @@ func (p CampaignJobProcessor) Process(conn services.ConnectionInterface, uaaHost
 
 	options := queue.Options{
 		ReplyTo: campaignJob.Campaign.ReplyTo,
+		Cc:      campaignJob.Campaign.Cc,
+		Bcc:     campaignJob.Campaign.Bcc,
 		Subject: campaignJob.Campaign.Subject,
 		Text:    campaignJob.Campaign.Text,
 		HTML: queue.HTML{
@@ -125,7 +136,10 @@ func (p CampaignJobProcessor) Process(conn services.ConnectionInterface, uaaHost
 			BodyContent:    bodyContent,
 			BodyAttributes: bodyAttributes,
 		},
-		TemplateID: campaignJob.Campaign.TemplateID,
+		TemplateID:    campaignJob.Campaign.TemplateID,
+		Critical:      campaignJob.Campaign.Critical,
+		Headers:       campaignJob.Campaign.Headers,
+		AttachmentIDs: campaignJob.Campaign.AttachmentIDs,
 	}
 
 	p.enqueuer.Enqueue(conn, usersSlice, options, cf.CloudControllerSpace{},
@@ -134,6 +148,25 @@ func (p CampaignJobProcessor) Process(conn services.ConnectionInterface, uaaHost
 	return nil
 }
 
+// sharedInbox replaces the users of each audience with a single user that
+// is sent to all of their addresses.
+func sharedInbox(audiences []horde.Audience) []horde.Audience {
+	var shared []horde.Audience
+	for _, audience := range audiences {
+		var emails []string
+		for _, user := range audience.Users {
+			emails = append(emails, user.Email)
+		}
+
+		shared = append(shared, horde.Audience{
+			Users:       []horde.User{{Email: strings.Join(emails, ", ")}},
+			Endorsement: audience.Endorsement,
+		})
+	}
+
+	return shared
+}
+
 func (p CampaignJobProcessor) findAudienceGenerator(audience string) (audienceGenerator, error) {
 	switch audience {
 	case "users":
@@ -115,6 +115,60 @@ var _ = Describe("CampaignJobProcessor", func() {
 		})
 	})
 
+	Context("when the campaign is critical", func() {
+		It("enqueues the deliveries as critical", func() {
+			users.GenerateAudiencesCall.Returns.Audiences = []horde.Audience{
+				{
+					Users: []horde.User{
+						{GUID: "some-user-guid"},
+					},
+				},
+			}
+
+			err := processor.Process(database.Connection(), "some-uaa-host", *gobble.NewJob(queue.CampaignJob{
+				Campaign: collections.Campaign{
+					ID: "some-id",
+					SendTo: map[string][]string{
+						"users": {"some-user-guid"},
+					},
+					CampaignTypeID: "some-campaign-type-id",
+					Text:           "some-text",
+					ClientID:       "some-client-id",
+					Critical:       true,
+				},
+			}), logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(enqueuer.EnqueueCall.Receives.Options.Critical).To(BeTrue())
+		})
+
+		It("enqueues the deliveries with the custom headers", func() {
+			users.GenerateAudiencesCall.Returns.Audiences = []horde.Audience{
+				{
+					Users: []horde.User{
+						{GUID: "some-user-guid"},
+					},
+				},
+			}
+
+			err := processor.Process(database.Connection(), "some-uaa-host", *gobble.NewJob(queue.CampaignJob{
+				Campaign: collections.Campaign{
+					ID: "some-id",
+					SendTo: map[string][]string{
+						"users": {"some-user-guid"},
+					},
+					CampaignTypeID: "some-campaign-type-id",
+					Text:           "some-text",
+					ClientID:       "some-client-id",
+					Headers:        map[string]string{"X-Ticket-ID": "TICKET-1234"},
+				},
+			}), logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(enqueuer.EnqueueCall.Receives.Options.Headers).To(Equal(map[string]string{"X-Ticket-ID": "TICKET-1234"}))
+		})
+	})
+
 	Context("when the audience is emails", func() {
 		It("enqueues a job based on the emails audience", func() {
 			emails.GenerateAudiencesCall.Returns.Audiences = []horde.Audience{
@@ -187,6 +241,39 @@ var _ = Describe("CampaignJobProcessor", func() {
 			Expect(enqueuer.EnqueueCall.Receives.RequestReceived).To(Equal(time.Time{}))
 			Expect(enqueuer.EnqueueCall.Receives.CampaignID).To(Equal("some-id"))
 		})
+
+		It("sends one delivery to every address along with the Cc and Bcc recipients", func() {
+			emails.GenerateAudiencesCall.Returns.Audiences = []horde.Audience{
+				{
+					Users: []horde.User{
+						{Email: "some-user@example.com"},
+						{Email: "some-other-user@example.com"},
+					},
+					Endorsement: "some emails endorsement",
+				},
+			}
+
+			err := processor.Process(database.Connection(), "some-uaa-host", *gobble.NewJob(queue.CampaignJob{
+				Campaign: collections.Campaign{
+					ID: "some-id",
+					SendTo: map[string][]string{
+						"emails": {"some-user@example.com", "some-other-user@example.com"},
+					},
+					CampaignTypeID: "some-campaign-type-id",
+					Text:           "some-text",
+					Cc:             []string{"shared@example.com"},
+					Bcc:            []string{"audit@example.com"},
+					ClientID:       "some-client-id",
+				},
+			}), logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(enqueuer.EnqueueCall.Receives.Users).To(Equal([]queue.User{
+				{Email: "some-user@example.com, some-other-user@example.com", Endorsement: "some emails endorsement"},
+			}))
+			Expect(enqueuer.EnqueueCall.Receives.Options.Cc).To(Equal([]string{"shared@example.com"}))
+			Expect(enqueuer.EnqueueCall.Receives.Options.Bcc).To(Equal([]string{"audit@example.com"}))
+		})
 	})
 
 	Context("when the audience is spaces", func() {
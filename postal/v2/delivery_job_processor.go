@@ -1,6 +1,7 @@
 package v2
 
 import (
+	netmail "net/mail"
 	"strings"
 
 	"github.com/cloudfoundry-incubator/notifications/db"
@@ -37,10 +38,18 @@ type unsubscribersRepositoryInterface interface {
 	Get(connection models.ConnectionInterface, userGUID, campaignTypeID string) (models.Unsubscriber, error)
 }
 
+type suppressionsRepositoryInterface interface {
+	GetByEmail(connection models.ConnectionInterface, email string) (models.Suppression, error)
+}
+
 type campaignsRepositoryInterface interface {
 	Get(connection models.ConnectionInterface, campaignID string) (models.Campaign, error)
 }
 
+type sendersRepositoryInterface interface {
+	Get(connection models.ConnectionInterface, senderID string) (models.Sender, error)
+}
+
 type metricsEmitter interface {
 	Increment(counter string)
 }
@@ -52,7 +61,9 @@ type DeliveryJobProcessor struct {
 	tokenLoader             tokenLoader
 	messageStatusUpdater    messageStatusUpdater
 	unsubscribersRepository unsubscribersRepositoryInterface
+	suppressionsRepository  suppressionsRepositoryInterface
 	campaignsRepository     campaignsRepositoryInterface
+	sendersRepository       sendersRepositoryInterface
 	database                db.DatabaseInterface
 	sender                  string
 	domain                  string
@@ -62,7 +73,8 @@ type DeliveryJobProcessor struct {
 
 func NewDeliveryJobProcessor(mailClient mailSender, packager messagePackager, userLoader userLoader, tokenLoader tokenLoader,
 	messageStatusUpdater messageStatusUpdater, database db.DatabaseInterface, unsubscribersRepository unsubscribersRepositoryInterface,
-	campaignsRepository campaignsRepositoryInterface, sender, domain, uaaHost string, metricsEmitter metricsEmitter) DeliveryJobProcessor {
+	suppressionsRepository suppressionsRepositoryInterface, campaignsRepository campaignsRepositoryInterface,
+	sendersRepository sendersRepositoryInterface, sender, domain, uaaHost string, metricsEmitter metricsEmitter) DeliveryJobProcessor {
 
 	return DeliveryJobProcessor{
 		mailClient:              mailClient,
@@ -71,7 +83,9 @@ func NewDeliveryJobProcessor(mailClient mailSender, packager messagePackager, us
 		tokenLoader:             tokenLoader,
 		messageStatusUpdater:    messageStatusUpdater,
 		campaignsRepository:     campaignsRepository,
+		sendersRepository:       sendersRepository,
 		unsubscribersRepository: unsubscribersRepository,
+		suppressionsRepository:  suppressionsRepository,
 		database:                database,
 		sender:                  sender,
 		domain:                  domain,
@@ -128,7 +142,41 @@ func (p DeliveryJobProcessor) Process(delivery common.Delivery, logger lager.Log
 		return nil
 	}
 
-	context, err := p.packager.PrepareContext(delivery, p.sender, p.domain)
+	// A shared inbox delivery is still sent to its To addresses that are
+	// not suppressed.
+	recipients, err := p.withoutSuppressed(conn, delivery.Recipients(), logger)
+	if err != nil {
+		return err
+	}
+
+	if len(recipients) == 0 {
+		p.messageStatusUpdater.Update(conn, delivery.MessageID, common.StatusUndeliverable, delivery.CampaignID, logger)
+		p.metricsEmitter.Increment("notifications.worker.suppressed")
+		return nil
+	}
+
+	delivery.Email = strings.Join(recipients, ", ")
+
+	delivery.Options.Cc, err = p.withoutSuppressed(conn, delivery.Options.Cc, logger)
+	if err != nil {
+		return err
+	}
+
+	delivery.Options.Bcc, err = p.withoutSuppressed(conn, delivery.Options.Bcc, logger)
+	if err != nil {
+		return err
+	}
+
+	// The campaign type stands in for the v1 kind, so that the unsubscribe
+	// ID in the message identifies what the user is unsubscribing from.
+	delivery.Options.KindID = campaign.CampaignTypeID
+
+	from, err := p.resolveSender(conn, campaign.SenderID, &delivery)
+	if err != nil {
+		return err
+	}
+
+	context, err := p.packager.PrepareContext(delivery, from, p.domain)
 	if err != nil {
 		return err
 	}
@@ -140,6 +188,13 @@ func (p DeliveryJobProcessor) Process(delivery common.Delivery, logger lager.Log
 
 	err = p.mailClient.Send(message, logger)
 	if err != nil {
+		if mail.IsPermanent(err) {
+			logger.Error("delivery-failed-smtp-rejected", err)
+			p.messageStatusUpdater.Update(conn, delivery.MessageID, common.StatusUndeliverable, delivery.CampaignID, logger)
+			p.metricsEmitter.Increment("notifications.worker.undeliverable")
+			return nil
+		}
+
 		return err
 	}
 
@@ -149,3 +204,57 @@ func (p DeliveryJobProcessor) Process(delivery common.Delivery, logger lager.Log
 
 	return nil
 }
+
+// resolveSender returns the From address of the campaign's sender, falling
+// back to the globally configured sender when the sender has none. The
+// sender's reply to address is used when the campaign did not set one.
+func (p DeliveryJobProcessor) resolveSender(conn models.ConnectionInterface, senderID string, delivery *common.Delivery) (string, error) {
+	sender, err := p.sendersRepository.Get(conn, senderID)
+	if err != nil {
+		if _, ok := err.(models.RecordNotFoundError); ok {
+			return p.sender, nil
+		}
+
+		return "", err
+	}
+
+	if delivery.Options.ReplyTo == "" {
+		delivery.Options.ReplyTo = sender.ReplyTo
+	}
+
+	if sender.FromAddress == "" {
+		return p.sender, nil
+	}
+
+	if sender.FromName == "" {
+		return sender.FromAddress, nil
+	}
+
+	from := netmail.Address{
+		Name:    sender.FromName,
+		Address: sender.FromAddress,
+	}
+
+	return from.String(), nil
+}
+
+// withoutSuppressed drops suppressed addresses so that they do not stop the
+// message from reaching its other recipients.
+func (p DeliveryJobProcessor) withoutSuppressed(conn models.ConnectionInterface, emails []string, logger lager.Logger) ([]string, error) {
+	var allowed []string
+	for _, email := range emails {
+		_, err := p.suppressionsRepository.GetByEmail(conn, email)
+		if err == nil {
+			logger.Info("recipient-suppressed", lager.Data{"recipient": email})
+			continue
+		}
+
+		if _, ok := err.(models.RecordNotFoundError); !ok {
+			return nil, err
+		}
+
+		allowed = append(allowed, email)
+	}
+
+	return allowed, nil
+}
@@ -32,6 +32,8 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		delivery                common.Delivery
 		campaignsRepository     *mocks.CampaignsRepository
 		unsubscribersRepository *mocks.UnsubscribersRepository
+		suppressionsRepository  *mocks.SuppressionsRepository
+		sendersRepository       *mocks.SendersRepository
 		metricsEmitter          *mocks.MetricsEmitter
 	)
 
@@ -58,8 +60,15 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		}
 
 		campaignsRepository = mocks.NewCampaignsRepository()
+		campaignsRepository.GetCall.Returns.Campaign = models.Campaign{
+			CampaignTypeID: "some-campaign-type-id",
+			SenderID:       "some-sender-id",
+		}
+		sendersRepository = mocks.NewSendersRepository()
 		unsubscribersRepository = mocks.NewUnsubscribersRepository()
 		unsubscribersRepository.GetCall.Returns.Error = models.RecordNotFoundError{errors.New("not unsubscribed == will be delivered!")}
+		suppressionsRepository = mocks.NewSuppressionsRepository()
+		suppressionsRepository.GetByEmailCall.Returns.Error = models.RecordNotFoundError{errors.New("not suppressed")}
 
 		packager = mocks.NewPackager()
 		packager.PrepareContextCall.Returns.MessageContext = common.MessageContext{
@@ -96,9 +105,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		}
 		packager.PackCall.Returns.Message = mail.Message{
 			Date:                    "",
-			MimeVersion:             "",
 			ContentType:             "",
-			ContentTransferEncoding: "",
 			From:    "from@example.com",
 			ReplyTo: "thesender@example.com",
 			To:      "user-123@example.com",
@@ -115,7 +122,6 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				"X-CF-Notification-Timestamp: 2015-09-10T12:27:11.675885866-07:00",
 				"X-CF-Notification-Request-Received: 0001-01-01T00:00:00Z",
 			},
-			CompiledBody: "",
 		}
 
 		mailClient = mocks.NewMailClient()
@@ -127,7 +133,6 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				Subject:    "the subject",
 				Text:       "body content",
 				ReplyTo:    "thesender@example.com",
-				KindID:     "some-kind",
 				TemplateID: "some-template-id",
 			},
 			MessageID:     "randomly-generated-guid",
@@ -138,8 +143,8 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		metricsEmitter = mocks.NewMetricsEmitter()
 
 		processor = v2.NewDeliveryJobProcessor(mailClient, packager, userLoader, tokenLoader,
-			messageStatusUpdater, database, unsubscribersRepository, campaignsRepository,
-			"from@example.com", "example.com", "uaa-host", metricsEmitter)
+			messageStatusUpdater, database, unsubscribersRepository, suppressionsRepository, campaignsRepository,
+			sendersRepository, "from@example.com", "example.com", "uaa-host", metricsEmitter)
 	})
 
 	It("ensures message delivery", func() {
@@ -152,6 +157,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		Expect(userLoader.LoadCall.Receives.Token).To(Equal("some-token"))
 
 		delivery.Email = "user-123@example.com"
+		delivery.Options.KindID = "some-campaign-type-id"
 		Expect(packager.PrepareContextCall.Receives.Delivery).To(Equal(delivery))
 		Expect(packager.PrepareContextCall.Receives.Sender).To(Equal("from@example.com"))
 		Expect(packager.PrepareContextCall.Receives.Domain).To(Equal("example.com"))
@@ -216,6 +222,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			err := processor.Process(delivery, logger)
 			Expect(err).NotTo(HaveOccurred())
 
+			delivery.Options.KindID = "some-campaign-type-id"
 			Expect(packager.PrepareContextCall.Receives.Delivery).To(Equal(delivery))
 			Expect(packager.PrepareContextCall.Receives.Sender).To(Equal("from@example.com"))
 			Expect(packager.PrepareContextCall.Receives.Domain).To(Equal("example.com"))
@@ -303,6 +310,146 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		})
 	})
 
+	Context("when the message has Cc and Bcc recipients", func() {
+		BeforeEach(func() {
+			delivery.Options.Cc = []string{"shared@example.com", "bounced@example.com"}
+			delivery.Options.Bcc = []string{"audit@example.com"}
+		})
+
+		It("leaves out the suppressed recipients", func() {
+			suppressionsRepository.GetByEmailCall.Returns.SuppressedEmails = []string{"bounced@example.com"}
+
+			err := processor.Process(delivery, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(packager.PrepareContextCall.Receives.Delivery.Options.Cc).To(Equal([]string{"shared@example.com"}))
+			Expect(packager.PrepareContextCall.Receives.Delivery.Options.Bcc).To(Equal([]string{"audit@example.com"}))
+			Expect(mailClient.SendCall.CallCount).To(Equal(1))
+		})
+	})
+
+	Context("when the delivery is for a shared inbox", func() {
+		BeforeEach(func() {
+			delivery.UserGUID = ""
+			delivery.Email = "some-user@example.com, bounced@example.com"
+		})
+
+		It("sends the message to the To addresses that are not suppressed", func() {
+			suppressionsRepository.GetByEmailCall.Returns.SuppressedEmails = []string{"bounced@example.com"}
+
+			err := processor.Process(delivery, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(packager.PrepareContextCall.Receives.Delivery.Email).To(Equal("some-user@example.com"))
+			Expect(mailClient.SendCall.CallCount).To(Equal(1))
+		})
+
+		It("marks the message as undeliverable when every To address is suppressed", func() {
+			suppressionsRepository.GetByEmailCall.Returns.SuppressedEmails = []string{"some-user@example.com", "bounced@example.com"}
+
+			err := processor.Process(delivery, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(mailClient.SendCall.CallCount).To(Equal(0))
+			Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusUndeliverable))
+		})
+	})
+
+	Context("when the campaign's sender has a from address", func() {
+		BeforeEach(func() {
+			sendersRepository.GetCall.Returns.Sender = models.Sender{
+				ID:          "some-sender-id",
+				FromAddress: "no-reply@example.com",
+				FromName:    "Some Sender",
+				ReplyTo:     "support@example.com",
+			}
+		})
+
+		It("sends from the sender's address", func() {
+			err := processor.Process(delivery, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sendersRepository.GetCall.Receives.Connection).To(Equal(conn))
+			Expect(sendersRepository.GetCall.Receives.SenderID).To(Equal("some-sender-id"))
+			Expect(packager.PrepareContextCall.Receives.Sender).To(Equal(`"Some Sender" <no-reply@example.com>`))
+		})
+
+		It("sends from the bare address when the sender has no from name", func() {
+			sendersRepository.GetCall.Returns.Sender.FromName = ""
+
+			err := processor.Process(delivery, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(packager.PrepareContextCall.Receives.Sender).To(Equal("no-reply@example.com"))
+		})
+
+		It("keeps the reply to address of the campaign", func() {
+			err := processor.Process(delivery, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(packager.PrepareContextCall.Receives.Delivery.Options.ReplyTo).To(Equal("thesender@example.com"))
+		})
+
+		It("uses the sender's reply to address when the campaign has none", func() {
+			delivery.Options.ReplyTo = ""
+
+			err := processor.Process(delivery, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(packager.PrepareContextCall.Receives.Delivery.Options.ReplyTo).To(Equal("support@example.com"))
+		})
+	})
+
+	Context("when the campaign's sender no longer exists", func() {
+		It("sends from the configured sender", func() {
+			sendersRepository.GetCall.Returns.Error = models.RecordNotFoundError{errors.New("not found")}
+
+			err := processor.Process(delivery, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(packager.PrepareContextCall.Receives.Sender).To(Equal("from@example.com"))
+			Expect(mailClient.SendCall.CallCount).To(Equal(1))
+		})
+	})
+
+	Context("when the recipient is suppressed", func() {
+		BeforeEach(func() {
+			suppressionsRepository.GetByEmailCall.Returns.Error = nil
+			suppressionsRepository.GetByEmailCall.Returns.Suppression = models.Suppression{
+				ID:    "some-suppression-id",
+				Email: "user-123@example.com",
+			}
+		})
+
+		It("marks the message as undeliverable without sending it", func() {
+			err := processor.Process(delivery, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(suppressionsRepository.GetByEmailCall.Receives.Connection).To(Equal(conn))
+			Expect(suppressionsRepository.GetByEmailCall.Receives.Email).To(Equal("user-123@example.com"))
+
+			Expect(mailClient.SendCall.CallCount).To(Equal(0))
+			Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal(delivery.MessageID))
+			Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusUndeliverable))
+			Expect(messageStatusUpdater.UpdateCall.Receives.CampaignID).To(Equal(delivery.CampaignID))
+			Expect(metricsEmitter.IncrementCall.Receives.Counter).To(Equal("notifications.worker.suppressed"))
+		})
+
+		Context("when the delivery is for a raw email address", func() {
+			It("checks the email address", func() {
+				delivery.UserGUID = ""
+				delivery.Email = "User-123@Example.com"
+
+				err := processor.Process(delivery, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(suppressionsRepository.GetByEmailCall.Receives.Email).To(Equal("User-123@Example.com"))
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusUndeliverable))
+			})
+		})
+	})
+
 	Context("when the user is unsubscribed from the campaign type", func() {
 		BeforeEach(func() {
 			unsubscribersRepository.GetCall.Returns.Unsubscriber = models.Unsubscriber{
@@ -353,6 +500,16 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			})
 		})
 
+		Context("when the senders repository has an error", func() {
+			It("returns the error", func() {
+				sendersRepository.GetCall.Returns.Error = errors.New("some-senders-repository-error")
+
+				err := processor.Process(delivery, logger)
+				Expect(err).To(MatchError(errors.New("some-senders-repository-error")))
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+			})
+		})
+
 		Context("when the unsubscriber has an unknown error", func() {
 			It("returns the error", func() {
 				unsubscribersRepository.GetCall.Returns.Error = errors.New("some-unsubscriber-error")
@@ -363,6 +520,16 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			})
 		})
 
+		Context("when the suppression lookup has an unknown error", func() {
+			It("returns the error", func() {
+				suppressionsRepository.GetByEmailCall.Returns.Error = errors.New("some-suppression-error")
+
+				err := processor.Process(delivery, logger)
+				Expect(err).To(MatchError(errors.New("some-suppression-error")))
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+			})
+		})
+
 		Context("when the token cannot be loaded", func() {
 			It("returns the error", func() {
 				tokenLoader.LoadCall.Returns.Error = errors.New("some-token-error")
@@ -411,6 +578,42 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				Expect(err).To(HaveOccurred())
 				Expect(err).To(MatchError(errors.New("smtp error")))
 			})
+
+			Context("when the server rejects the message permanently", func() {
+				BeforeEach(func() {
+					mailClient.SendCall.Returns.Error = mail.SMTPError{
+						Code:           550,
+						EnhancedStatus: "5.1.1",
+						Message:        "5.1.1 mailbox does not exist",
+					}
+				})
+
+				It("marks the message status as undeliverable without retrying", func() {
+					err := processor.Process(delivery, logger)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal("randomly-generated-guid"))
+					Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusUndeliverable))
+					Expect(messageStatusUpdater.UpdateCall.Receives.CampaignID).To(Equal("some-campaign-id"))
+					Expect(metricsEmitter.IncrementCall.Receives.Counter).To(Equal("notifications.worker.undeliverable"))
+				})
+			})
+
+			Context("when the server rejects the message temporarily", func() {
+				It("returns the error so that the delivery is retried", func() {
+					mailClient.SendCall.Returns.Error = mail.SMTPError{
+						Code:    421,
+						Message: "4.7.0 try again later",
+					}
+
+					err := processor.Process(delivery, logger)
+					Expect(err).To(MatchError(mail.SMTPError{
+						Code:    421,
+						Message: "4.7.0 try again later",
+					}))
+					Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).NotTo(Equal(common.StatusUndeliverable))
+				})
+			})
 		})
 	})
 })
@@ -10,18 +10,28 @@ type templateGetter interface {
 	Get(connection collections.ConnectionInterface, templateID, clientID string) (collections.Template, error)
 }
 
+type partialsLister interface {
+	List(connection collections.ConnectionInterface, clientID string) ([]collections.Partial, error)
+}
+
 type TemplatesLoader struct {
 	database            db.DatabaseInterface
 	templatesCollection templateGetter
+	partialsCollection  partialsLister
 }
 
-func NewTemplatesLoader(database db.DatabaseInterface, templatesCollection templateGetter) TemplatesLoader {
+func NewTemplatesLoader(database db.DatabaseInterface, templatesCollection templateGetter, partialsCollection partialsLister) TemplatesLoader {
 	return TemplatesLoader{
 		database:            database,
 		templatesCollection: templatesCollection,
+		partialsCollection:  partialsCollection,
 	}
 }
 
+// LoadTemplates loads a template along with the partials of the client that
+// saved it. That is the client sending the message unless the template is
+// the default template, which resolves the partials it was validated
+// against rather than those of the sender.
 func (loader TemplatesLoader) LoadTemplates(clientID, kindID, templateID string) (common.Templates, error) {
 	conn := loader.database.Connection()
 	template, err := loader.templatesCollection.Get(conn, templateID, clientID)
@@ -29,9 +39,20 @@ func (loader TemplatesLoader) LoadTemplates(clientID, kindID, templateID string)
 		return common.Templates{}, err
 	}
 
+	partials, err := loader.partialsCollection.List(conn, template.PartialsClientID)
+	if err != nil {
+		return common.Templates{}, err
+	}
+
+	bodies := map[string]string{}
+	for _, partial := range partials {
+		bodies[partial.Name] = partial.Body
+	}
+
 	return common.Templates{
-		Subject: template.Subject,
-		Text:    template.Text,
-		HTML:    template.HTML,
+		Subject:  template.Subject,
+		Text:     template.Text,
+		HTML:     template.HTML,
+		Partials: bodies,
 	}, nil
 }
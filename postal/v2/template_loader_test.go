@@ -18,6 +18,7 @@ var _ = Describe("V2TemplateLoader", func() {
 		conn                db.ConnectionInterface
 		database            *mocks.Database
 		templatesCollection *mocks.TemplatesCollection
+		partialsCollection  *mocks.PartialsCollection
 		loader              v2.TemplatesLoader
 	)
 
@@ -27,7 +28,8 @@ var _ = Describe("V2TemplateLoader", func() {
 		database.ConnectionCall.Returns.Connection = conn
 
 		templatesCollection = mocks.NewTemplatesCollection()
-		loader = v2.NewTemplatesLoader(database, templatesCollection)
+		partialsCollection = mocks.NewPartialsCollection()
+		loader = v2.NewTemplatesLoader(database, templatesCollection, partialsCollection)
 	})
 
 	Describe("LoadTemplates", func() {
@@ -46,9 +48,10 @@ var _ = Describe("V2TemplateLoader", func() {
 				Expect(err).ToNot(HaveOccurred())
 
 				Expect(templates).To(Equal(common.Templates{
-					HTML:    "<p>v2 awesome</p>",
-					Text:    "some testing text",
-					Subject: "some subject",
+					HTML:     "<p>v2 awesome</p>",
+					Text:     "some testing text",
+					Subject:  "some subject",
+					Partials: map[string]string{},
 				}))
 				Expect(templatesCollection.GetCall.Receives.TemplateID).To(Equal("some-v2-template-id"))
 				Expect(templatesCollection.GetCall.Receives.Connection).To(Equal(conn))
@@ -56,6 +59,38 @@ var _ = Describe("V2TemplateLoader", func() {
 			})
 		})
 
+		Context("when the template includes partials", func() {
+			It("returns the partials of the client that saved the template rather than the sender", func() {
+				templatesCollection.GetCall.Returns.Template = collections.Template{
+					ID:               "default",
+					PartialsClientID: "some-admin-client-id",
+				}
+				partialsCollection.ListCall.Returns.Partials = []collections.Partial{
+					{Name: "footer", Body: "<footer>{{.Organization}}</footer>"},
+					{Name: "layout", Body: `<main>{{template "content" .}}</main>`},
+				}
+
+				templates, err := loader.LoadTemplates("my-client-id", "", "default")
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(templates.Partials).To(Equal(map[string]string{
+					"footer": "<footer>{{.Organization}}</footer>",
+					"layout": `<main>{{template "content" .}}</main>`,
+				}))
+				Expect(partialsCollection.ListCall.Receives.Connection).To(Equal(conn))
+				Expect(partialsCollection.ListCall.Receives.ClientID).To(Equal("some-admin-client-id"))
+			})
+		})
+
+		Context("when the partials collection has an error", func() {
+			It("returns the error", func() {
+				partialsCollection.ListCall.Returns.Error = errors.New("some error listing partials")
+
+				_, err := loader.LoadTemplates("my-client-id", "", "some-v2-template-id")
+				Expect(err).To(MatchError("some error listing partials"))
+			})
+		})
+
 		Context("when the templates collection has an error", func() {
 			It("returns the error", func() {
 				templatesCollection.GetCall.Returns.Error = errors.New("some error on the collection")
@@ -1,5 +1,7 @@
 package postal
 
+import "time"
+
 type WorkerGenerator struct {
 	InstanceIndex int
 	Count         int
@@ -7,11 +9,19 @@ type WorkerGenerator struct {
 
 type Worker interface {
 	Work()
+	Halt()
+	Wait(time.Duration) bool
 }
 
-func (w WorkerGenerator) Work(workerFunc func(id int) Worker) {
+func (w WorkerGenerator) Work(workerFunc func(id int) Worker) []Worker {
+	var workers []Worker
+
 	firstID := w.InstanceIndex*w.Count + 1
 	for i := 0; i < w.Count; i++ {
-		workerFunc(firstID + i).Work()
+		worker := workerFunc(firstID + i)
+		worker.Work()
+		workers = append(workers, worker)
 	}
+
+	return workers
 }
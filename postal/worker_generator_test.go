@@ -1,6 +1,8 @@
 package postal_test
 
 import (
+	"time"
+
 	"github.com/cloudfoundry-incubator/notifications/postal"
 
 	. "github.com/onsi/ginkgo"
@@ -13,11 +15,18 @@ func (m *mockWorker) Work() {
 	*m++
 }
 
+func (m *mockWorker) Halt() {}
+
+func (m *mockWorker) Wait(time.Duration) bool {
+	return true
+}
+
 var _ = Describe("WorkerGenerator", func() {
 	Describe("#Work", func() {
 		var (
 			workerIDs []int
 			worker    mockWorker
+			workers   []postal.Worker
 		)
 
 		BeforeEach(func() {
@@ -28,7 +37,7 @@ var _ = Describe("WorkerGenerator", func() {
 				InstanceIndex: 2,
 			}
 
-			generator.Work(func(id int) postal.Worker {
+			workers = generator.Work(func(id int) postal.Worker {
 				workerIDs = append(workerIDs, id)
 				return &worker
 			})
@@ -41,5 +50,9 @@ var _ = Describe("WorkerGenerator", func() {
 		It("should do work on each worker", func() {
 			Expect(worker).To(BeEquivalentTo(5))
 		})
+
+		It("returns the workers it generated", func() {
+			Expect(workers).To(HaveLen(5))
+		})
 	})
 })
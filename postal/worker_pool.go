@@ -0,0 +1,41 @@
+package postal
+
+import "time"
+
+type queueCloser interface {
+	Close()
+}
+
+type WorkerPool struct {
+	queue   queueCloser
+	workers []Worker
+}
+
+func NewWorkerPool(queue queueCloser, workers []Worker) WorkerPool {
+	return WorkerPool{
+		queue:   queue,
+		workers: workers,
+	}
+}
+
+// Shutdown stops the pool from reserving any new jobs and waits for the jobs
+// that are in flight to finish. Any job that has not finished by the time the
+// timeout elapses is released back to the queue. It returns false if any job
+// had to be released.
+func (pool WorkerPool) Shutdown(timeout time.Duration) bool {
+	pool.queue.Close()
+
+	for _, worker := range pool.workers {
+		worker.Halt()
+	}
+
+	deadline := time.Now().Add(timeout)
+	finished := true
+	for _, worker := range pool.workers {
+		if !worker.Wait(deadline.Sub(time.Now())) {
+			finished = false
+		}
+	}
+
+	return finished
+}
@@ -0,0 +1,90 @@
+package postal_test
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/postal"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type closableQueue struct {
+	CloseCall struct {
+		WasCalled bool
+	}
+}
+
+func (q *closableQueue) Close() {
+	q.CloseCall.WasCalled = true
+}
+
+type haltableWorker struct {
+	HaltCall struct {
+		WasCalled bool
+	}
+
+	WaitCall struct {
+		Receives struct {
+			Timeout time.Duration
+		}
+		Returns struct {
+			Finished bool
+		}
+	}
+}
+
+func (w *haltableWorker) Work() {}
+
+func (w *haltableWorker) Halt() {
+	w.HaltCall.WasCalled = true
+}
+
+func (w *haltableWorker) Wait(timeout time.Duration) bool {
+	w.WaitCall.Receives.Timeout = timeout
+
+	return w.WaitCall.Returns.Finished
+}
+
+var _ = Describe("WorkerPool", func() {
+	var (
+		queue   *closableQueue
+		workers []*haltableWorker
+		pool    postal.WorkerPool
+	)
+
+	BeforeEach(func() {
+		queue = &closableQueue{}
+		workers = []*haltableWorker{{}, {}}
+		workers[0].WaitCall.Returns.Finished = true
+		workers[1].WaitCall.Returns.Finished = true
+
+		pool = postal.NewWorkerPool(queue, []postal.Worker{workers[0], workers[1]})
+	})
+
+	Describe("Shutdown", func() {
+		It("closes the queue and halts every worker", func() {
+			Expect(pool.Shutdown(10 * time.Second)).To(BeTrue())
+
+			Expect(queue.CloseCall.WasCalled).To(BeTrue())
+			Expect(workers[0].HaltCall.WasCalled).To(BeTrue())
+			Expect(workers[1].HaltCall.WasCalled).To(BeTrue())
+		})
+
+		It("waits on every worker within the timeout", func() {
+			pool.Shutdown(10 * time.Second)
+
+			Expect(workers[0].WaitCall.Receives.Timeout).To(BeNumerically("~", 10*time.Second, 1*time.Second))
+			Expect(workers[1].WaitCall.Receives.Timeout).To(BeNumerically("~", 10*time.Second, 1*time.Second))
+		})
+
+		Context("when a worker does not finish before the timeout", func() {
+			It("returns false", func() {
+				workers[0].WaitCall.Returns.Finished = false
+
+				Expect(pool.Shutdown(10 * time.Second)).To(BeFalse())
+				Expect(workers[1].HaltCall.WasCalled).To(BeTrue())
+			})
+		})
+	})
+})
@@ -0,0 +1,27 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/mail"
+
+type AttachmentsLoader struct {
+	LoadAttachmentsCall struct {
+		CallCount int
+		Receives  struct {
+			AttachmentIDs []string
+		}
+		Returns struct {
+			Attachments []mail.Attachment
+			Error       error
+		}
+	}
+}
+
+func NewAttachmentsLoader() *AttachmentsLoader {
+	return &AttachmentsLoader{}
+}
+
+func (l *AttachmentsLoader) LoadAttachments(attachmentIDs []string) ([]mail.Attachment, error) {
+	l.LoadAttachmentsCall.CallCount++
+	l.LoadAttachmentsCall.Receives.AttachmentIDs = attachmentIDs
+
+	return l.LoadAttachmentsCall.Returns.Attachments, l.LoadAttachmentsCall.Returns.Error
+}
@@ -0,0 +1,75 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type AttachmentsRepo struct {
+	CreateCall struct {
+		CallCount int
+		Receives  struct {
+			Connection  models.ConnectionInterface
+			Attachments []models.Attachment
+		}
+		Returns struct {
+			Attachments []models.Attachment
+			Error       error
+		}
+	}
+
+	DeleteCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			IDs        []string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	DeleteBeforeCall struct {
+		CallCount int
+		Receives  struct {
+			Connection    models.ConnectionInterface
+			ThresholdTime time.Time
+		}
+		Returns struct {
+			Count int
+			Error error
+		}
+	}
+}
+
+func NewAttachmentsRepo() *AttachmentsRepo {
+	return &AttachmentsRepo{}
+}
+
+func (r *AttachmentsRepo) Create(conn models.ConnectionInterface, attachment models.Attachment) (models.Attachment, error) {
+	r.CreateCall.Receives.Connection = conn
+	r.CreateCall.Receives.Attachments = append(r.CreateCall.Receives.Attachments, attachment)
+
+	var created models.Attachment
+	if r.CreateCall.CallCount < len(r.CreateCall.Returns.Attachments) {
+		created = r.CreateCall.Returns.Attachments[r.CreateCall.CallCount]
+	}
+	r.CreateCall.CallCount++
+
+	return created, r.CreateCall.Returns.Error
+}
+
+func (r *AttachmentsRepo) Delete(conn models.ConnectionInterface, id string) error {
+	r.DeleteCall.Receives.Connection = conn
+	r.DeleteCall.Receives.IDs = append(r.DeleteCall.Receives.IDs, id)
+
+	return r.DeleteCall.Returns.Error
+}
+
+func (r *AttachmentsRepo) DeleteBefore(conn models.ConnectionInterface, threshold time.Time) (int, error) {
+	r.DeleteBeforeCall.CallCount++
+	r.DeleteBeforeCall.Receives.Connection = conn
+	r.DeleteBeforeCall.Receives.ThresholdTime = threshold
+
+	return r.DeleteBeforeCall.Returns.Count, r.DeleteBeforeCall.Returns.Error
+}
@@ -0,0 +1,55 @@
+package mocks
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+)
+
+type AttachmentsRepository struct {
+	InsertCall struct {
+		CallCount int
+		Receives  struct {
+			Connection  db.ConnectionInterface
+			Attachments []models.Attachment
+		}
+		Returns struct {
+			Attachments []models.Attachment
+			Error       error
+		}
+	}
+
+	GetCall struct {
+		Receives struct {
+			Connection db.ConnectionInterface
+			IDs        []string
+		}
+		Returns struct {
+			Attachments map[string]models.Attachment
+			Error       error
+		}
+	}
+}
+
+func NewAttachmentsRepository() *AttachmentsRepository {
+	return &AttachmentsRepository{}
+}
+
+func (r *AttachmentsRepository) Insert(conn models.ConnectionInterface, attachment models.Attachment) (models.Attachment, error) {
+	r.InsertCall.Receives.Connection = conn
+	r.InsertCall.Receives.Attachments = append(r.InsertCall.Receives.Attachments, attachment)
+
+	var inserted models.Attachment
+	if r.InsertCall.CallCount < len(r.InsertCall.Returns.Attachments) {
+		inserted = r.InsertCall.Returns.Attachments[r.InsertCall.CallCount]
+	}
+	r.InsertCall.CallCount++
+
+	return inserted, r.InsertCall.Returns.Error
+}
+
+func (r *AttachmentsRepository) Get(conn models.ConnectionInterface, id string) (models.Attachment, error) {
+	r.GetCall.Receives.Connection = conn
+	r.GetCall.Receives.IDs = append(r.GetCall.Receives.IDs, id)
+
+	return r.GetCall.Returns.Attachments[id], r.GetCall.Returns.Error
+}
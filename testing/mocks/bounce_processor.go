@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/pivotal-golang/lager"
+)
+
+type BounceProcessor struct {
+	ProcessCall struct {
+		CallCount int
+		Receives  struct {
+			Connection db.ConnectionInterface
+			DSN        mail.DeliveryStatusNotification
+			Logger     lager.Logger
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewBounceProcessor() *BounceProcessor {
+	return &BounceProcessor{}
+}
+
+func (p *BounceProcessor) Process(conn db.ConnectionInterface, dsn mail.DeliveryStatusNotification, logger lager.Logger) error {
+	p.ProcessCall.CallCount++
+	p.ProcessCall.Receives.Connection = conn
+	p.ProcessCall.Receives.DSN = dsn
+	p.ProcessCall.Receives.Logger = logger
+
+	return p.ProcessCall.Returns.Error
+}
@@ -0,0 +1,94 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v2/collections"
+
+type DeadLettersCollection struct {
+	ListCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+		}
+		Returns struct {
+			DeadLetters []collections.DeadLetter
+			Error       error
+		}
+	}
+
+	GetCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			ID         string
+		}
+		Returns struct {
+			DeadLetter collections.DeadLetter
+			Error      error
+		}
+	}
+
+	ReplayCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			ID         string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	DeleteCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			ID         string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	PurgeCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection collections.ConnectionInterface
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewDeadLettersCollection() *DeadLettersCollection {
+	return &DeadLettersCollection{}
+}
+
+func (c *DeadLettersCollection) List(connection collections.ConnectionInterface) ([]collections.DeadLetter, error) {
+	c.ListCall.Receives.Connection = connection
+
+	return c.ListCall.Returns.DeadLetters, c.ListCall.Returns.Error
+}
+
+func (c *DeadLettersCollection) Get(connection collections.ConnectionInterface, id string) (collections.DeadLetter, error) {
+	c.GetCall.Receives.Connection = connection
+	c.GetCall.Receives.ID = id
+
+	return c.GetCall.Returns.DeadLetter, c.GetCall.Returns.Error
+}
+
+func (c *DeadLettersCollection) Replay(connection collections.ConnectionInterface, id string) error {
+	c.ReplayCall.Receives.Connection = connection
+	c.ReplayCall.Receives.ID = id
+
+	return c.ReplayCall.Returns.Error
+}
+
+func (c *DeadLettersCollection) Delete(connection collections.ConnectionInterface, id string) error {
+	c.DeleteCall.Receives.Connection = connection
+	c.DeleteCall.Receives.ID = id
+
+	return c.DeleteCall.Returns.Error
+}
+
+func (c *DeadLettersCollection) Purge(connection collections.ConnectionInterface) error {
+	c.PurgeCall.WasCalled = true
+	c.PurgeCall.Receives.Connection = connection
+
+	return c.PurgeCall.Returns.Error
+}
@@ -0,0 +1,77 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v2/models"
+
+type DeadLettersRepository struct {
+	ListCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+		}
+		Returns struct {
+			DeadLetters []models.DeadLetter
+			Error       error
+		}
+	}
+
+	GetCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			ID         int
+		}
+		Returns struct {
+			DeadLetter models.DeadLetter
+			Error      error
+		}
+	}
+
+	DeleteCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection models.ConnectionInterface
+			ID         int
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	DeleteAllCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewDeadLettersRepository() *DeadLettersRepository {
+	return &DeadLettersRepository{}
+}
+
+func (r *DeadLettersRepository) List(connection models.ConnectionInterface) ([]models.DeadLetter, error) {
+	r.ListCall.Receives.Connection = connection
+
+	return r.ListCall.Returns.DeadLetters, r.ListCall.Returns.Error
+}
+
+func (r *DeadLettersRepository) Get(connection models.ConnectionInterface, id int) (models.DeadLetter, error) {
+	r.GetCall.Receives.Connection = connection
+	r.GetCall.Receives.ID = id
+
+	return r.GetCall.Returns.DeadLetter, r.GetCall.Returns.Error
+}
+
+func (r *DeadLettersRepository) Delete(connection models.ConnectionInterface, id int) error {
+	r.DeleteCall.WasCalled = true
+	r.DeleteCall.Receives.Connection = connection
+	r.DeleteCall.Receives.ID = id
+
+	return r.DeleteCall.Returns.Error
+}
+
+func (r *DeadLettersRepository) DeleteAll(connection models.ConnectionInterface) error {
+	r.DeleteAllCall.Receives.Connection = connection
+
+	return r.DeleteAllCall.Returns.Error
+}
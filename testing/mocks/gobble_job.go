@@ -10,6 +10,13 @@ type GobbleJob struct {
 		}
 	}
 
+	DeadLetterCall struct {
+		WasCalled bool
+		Receives  struct {
+			Reason string
+		}
+	}
+
 	StateCall struct {
 		Returns struct {
 			Count int
@@ -27,6 +34,11 @@ func (j *GobbleJob) Retry(duration time.Duration) {
 	j.RetryCall.Receives.Duration = duration
 }
 
+func (j *GobbleJob) DeadLetter(reason string) {
+	j.DeadLetterCall.WasCalled = true
+	j.DeadLetterCall.Receives.Reason = reason
+}
+
 func (j *GobbleJob) State() (int, time.Time) {
 	return j.StateCall.Returns.Count, j.StateCall.Returns.Time
 }
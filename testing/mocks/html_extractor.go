@@ -2,8 +2,15 @@ package mocks
 
 type HTMLExtractor struct {
 	ExtractCall struct {
+		Receives struct {
+			HTML string
+		}
 		Returns struct {
-			Error error
+			Doctype        string
+			Head           string
+			BodyContent    string
+			BodyAttributes string
+			Error          error
 		}
 	}
 }
@@ -12,6 +19,8 @@ func NewHTMLExtractor() *HTMLExtractor {
 	return &HTMLExtractor{}
 }
 
-func (e HTMLExtractor) Extract(html string) (doctype, head, bodyContent, bodyAttributes string, err error) {
-	return "", "", "", "", e.ExtractCall.Returns.Error
+func (e *HTMLExtractor) Extract(html string) (doctype, head, bodyContent, bodyAttributes string, err error) {
+	e.ExtractCall.Receives.HTML = html
+
+	return e.ExtractCall.Returns.Doctype, e.ExtractCall.Returns.Head, e.ExtractCall.Returns.BodyContent, e.ExtractCall.Returns.BodyAttributes, e.ExtractCall.Returns.Error
 }
@@ -0,0 +1,27 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
+type PartialsRepo struct {
+	FindAllByClientIDCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			ClientID   string
+		}
+		Returns struct {
+			Partials map[string]string
+			Error    error
+		}
+	}
+}
+
+func NewPartialsRepo() *PartialsRepo {
+	return &PartialsRepo{}
+}
+
+func (r *PartialsRepo) FindAllByClientID(conn models.ConnectionInterface, clientID string) (map[string]string, error) {
+	r.FindAllByClientIDCall.Receives.Connection = conn
+	r.FindAllByClientIDCall.Receives.ClientID = clientID
+
+	return r.FindAllByClientIDCall.Returns.Partials, r.FindAllByClientIDCall.Returns.Error
+}
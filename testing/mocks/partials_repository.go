@@ -0,0 +1,98 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v2/models"
+
+type PartialsRepository struct {
+	InsertCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Partial    models.Partial
+		}
+		Returns struct {
+			Partial models.Partial
+			Error   error
+		}
+	}
+
+	UpdateCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Partial    models.Partial
+		}
+		Returns struct {
+			Partial models.Partial
+			Error   error
+		}
+	}
+
+	GetCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			PartialID  string
+		}
+		Returns struct {
+			Partial models.Partial
+			Error   error
+		}
+	}
+
+	ListCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			ClientID   string
+		}
+		Returns struct {
+			Partials []models.Partial
+			Error    error
+		}
+	}
+
+	DeleteCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Partial    models.Partial
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewPartialsRepository() *PartialsRepository {
+	return &PartialsRepository{}
+}
+
+func (r *PartialsRepository) Insert(conn models.ConnectionInterface, partial models.Partial) (models.Partial, error) {
+	r.InsertCall.Receives.Connection = conn
+	r.InsertCall.Receives.Partial = partial
+
+	return r.InsertCall.Returns.Partial, r.InsertCall.Returns.Error
+}
+
+func (r *PartialsRepository) Update(conn models.ConnectionInterface, partial models.Partial) (models.Partial, error) {
+	r.UpdateCall.Receives.Connection = conn
+	r.UpdateCall.Receives.Partial = partial
+
+	return r.UpdateCall.Returns.Partial, r.UpdateCall.Returns.Error
+}
+
+func (r *PartialsRepository) Get(conn models.ConnectionInterface, partialID string) (models.Partial, error) {
+	r.GetCall.Receives.Connection = conn
+	r.GetCall.Receives.PartialID = partialID
+
+	return r.GetCall.Returns.Partial, r.GetCall.Returns.Error
+}
+
+func (r *PartialsRepository) List(conn models.ConnectionInterface, clientID string) ([]models.Partial, error) {
+	r.ListCall.Receives.Connection = conn
+	r.ListCall.Receives.ClientID = clientID
+
+	return r.ListCall.Returns.Partials, r.ListCall.Returns.Error
+}
+
+func (r *PartialsRepository) Delete(conn models.ConnectionInterface, partial models.Partial) error {
+	r.DeleteCall.Receives.Connection = conn
+	r.DeleteCall.Receives.Partial = partial
+
+	return r.DeleteCall.Returns.Error
+}
@@ -17,6 +17,18 @@ type PreferenceUpdater struct {
 			Error error
 		}
 	}
+
+	UnsubscribeCall struct {
+		Receives struct {
+			Connection services.ConnectionInterface
+			UserID     string
+			ClientID   string
+			KindID     string
+		}
+		Returns struct {
+			Error error
+		}
+	}
 }
 
 func NewPreferenceUpdater() *PreferenceUpdater {
@@ -31,3 +43,12 @@ func (pu *PreferenceUpdater) Update(conn services.ConnectionInterface, preferenc
 
 	return pu.UpdateCall.Returns.Error
 }
+
+func (pu *PreferenceUpdater) Unsubscribe(conn services.ConnectionInterface, userID, clientID, kindID string) error {
+	pu.UnsubscribeCall.Receives.Connection = conn
+	pu.UnsubscribeCall.Receives.UserID = userID
+	pu.UnsubscribeCall.Receives.ClientID = clientID
+	pu.UnsubscribeCall.Receives.KindID = kindID
+
+	return pu.UnsubscribeCall.Returns.Error
+}
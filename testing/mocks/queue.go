@@ -28,6 +28,18 @@ type Queue struct {
 		}
 	}
 
+	DeadLetterCall struct {
+		Receives struct {
+			Job *gobble.Job
+		}
+	}
+
+	ReleaseCall struct {
+		Receives struct {
+			Job *gobble.Job
+		}
+	}
+
 	LenCall struct {
 		Returns struct {
 			Length int
@@ -44,12 +56,33 @@ type Queue struct {
 		}
 	}
 
+	ReserveBatchCall struct {
+		Receives struct {
+			ID   string
+			Size int
+		}
+		Returns struct {
+			Chan <-chan []*gobble.Job
+		}
+	}
+
 	RetryQueueLengthsCall struct {
 		Returns struct {
 			Lengths map[int]int
 			Error   error
 		}
 	}
+
+	PriorityQueueLengthsCall struct {
+		Returns struct {
+			Lengths map[int]int
+			Error   error
+		}
+	}
+
+	CloseCall struct {
+		WasCalled bool
+	}
 }
 
 func NewQueue() *Queue {
@@ -75,6 +108,14 @@ func (q *Queue) Requeue(job *gobble.Job) {
 	q.RequeueCall.Receives.Job = job
 }
 
+func (q *Queue) DeadLetter(job *gobble.Job) {
+	q.DeadLetterCall.Receives.Job = job
+}
+
+func (q *Queue) Release(job *gobble.Job) {
+	q.ReleaseCall.Receives.Job = job
+}
+
 func (q *Queue) Len() (int, error) {
 	return q.LenCall.Returns.Length, q.LenCall.Returns.Error
 }
@@ -85,6 +126,21 @@ func (q *Queue) Reserve(id string) <-chan *gobble.Job {
 	return q.ReserveCall.Returns.Chan
 }
 
+func (q *Queue) ReserveBatch(id string, size int) <-chan []*gobble.Job {
+	q.ReserveBatchCall.Receives.ID = id
+	q.ReserveBatchCall.Receives.Size = size
+
+	return q.ReserveBatchCall.Returns.Chan
+}
+
 func (q *Queue) RetryQueueLengths() (map[int]int, error) {
 	return q.RetryQueueLengthsCall.Returns.Lengths, q.RetryQueueLengthsCall.Returns.Error
 }
+
+func (q *Queue) PriorityQueueLengths() (map[int]int, error) {
+	return q.PriorityQueueLengthsCall.Returns.Lengths, q.PriorityQueueLengthsCall.Returns.Error
+}
+
+func (q *Queue) Close() {
+	q.CloseCall.WasCalled = true
+}
@@ -0,0 +1,78 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v2/collections"
+
+type SuppressionsCollection struct {
+	SetCall struct {
+		Receives struct {
+			Connection  collections.ConnectionInterface
+			Suppression collections.Suppression
+		}
+		Returns struct {
+			Suppression collections.Suppression
+			Error       error
+		}
+	}
+
+	ListCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+		}
+		Returns struct {
+			Suppressions []collections.Suppression
+			Error        error
+		}
+	}
+
+	GetCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			ID         string
+		}
+		Returns struct {
+			Suppression collections.Suppression
+			Error       error
+		}
+	}
+
+	DeleteCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			ID         string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewSuppressionsCollection() *SuppressionsCollection {
+	return &SuppressionsCollection{}
+}
+
+func (c *SuppressionsCollection) Set(conn collections.ConnectionInterface, suppression collections.Suppression) (collections.Suppression, error) {
+	c.SetCall.Receives.Connection = conn
+	c.SetCall.Receives.Suppression = suppression
+
+	return c.SetCall.Returns.Suppression, c.SetCall.Returns.Error
+}
+
+func (c *SuppressionsCollection) List(conn collections.ConnectionInterface) ([]collections.Suppression, error) {
+	c.ListCall.Receives.Connection = conn
+
+	return c.ListCall.Returns.Suppressions, c.ListCall.Returns.Error
+}
+
+func (c *SuppressionsCollection) Get(conn collections.ConnectionInterface, id string) (collections.Suppression, error) {
+	c.GetCall.Receives.Connection = conn
+	c.GetCall.Receives.ID = id
+
+	return c.GetCall.Returns.Suppression, c.GetCall.Returns.Error
+}
+
+func (c *SuppressionsCollection) Delete(conn collections.ConnectionInterface, id string) error {
+	c.DeleteCall.Receives.Connection = conn
+	c.DeleteCall.Receives.ID = id
+
+	return c.DeleteCall.Returns.Error
+}
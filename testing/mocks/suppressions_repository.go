@@ -0,0 +1,112 @@
+package mocks
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+)
+
+type SuppressionsRepository struct {
+	InsertCall struct {
+		CallCount int
+		Receives  struct {
+			Connection   db.ConnectionInterface
+			Suppressions []models.Suppression
+		}
+		Returns struct {
+			Suppression models.Suppression
+			Error       error
+		}
+	}
+
+	GetCall struct {
+		Receives struct {
+			Connection db.ConnectionInterface
+			ID         string
+		}
+		Returns struct {
+			Suppression models.Suppression
+			Error       error
+		}
+	}
+
+	GetByEmailCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection db.ConnectionInterface
+			Email      string
+			Emails     []string
+		}
+		Returns struct {
+			Suppression      models.Suppression
+			Error            error
+			SuppressedEmails []string
+		}
+	}
+
+	ListCall struct {
+		Receives struct {
+			Connection db.ConnectionInterface
+		}
+		Returns struct {
+			Suppressions []models.Suppression
+			Error        error
+		}
+	}
+
+	DeleteCall struct {
+		Receives struct {
+			Connection db.ConnectionInterface
+			ID         string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewSuppressionsRepository() *SuppressionsRepository {
+	return &SuppressionsRepository{}
+}
+
+func (r *SuppressionsRepository) Insert(conn models.ConnectionInterface, suppression models.Suppression) (models.Suppression, error) {
+	r.InsertCall.CallCount++
+	r.InsertCall.Receives.Connection = conn
+	r.InsertCall.Receives.Suppressions = append(r.InsertCall.Receives.Suppressions, suppression)
+
+	return r.InsertCall.Returns.Suppression, r.InsertCall.Returns.Error
+}
+
+func (r *SuppressionsRepository) Get(conn models.ConnectionInterface, id string) (models.Suppression, error) {
+	r.GetCall.Receives.Connection = conn
+	r.GetCall.Receives.ID = id
+
+	return r.GetCall.Returns.Suppression, r.GetCall.Returns.Error
+}
+
+func (r *SuppressionsRepository) GetByEmail(conn models.ConnectionInterface, email string) (models.Suppression, error) {
+	r.GetByEmailCall.WasCalled = true
+	r.GetByEmailCall.Receives.Connection = conn
+	r.GetByEmailCall.Receives.Email = email
+	r.GetByEmailCall.Receives.Emails = append(r.GetByEmailCall.Receives.Emails, email)
+
+	for _, suppressed := range r.GetByEmailCall.Returns.SuppressedEmails {
+		if suppressed == email {
+			return models.Suppression{Email: email}, nil
+		}
+	}
+
+	return r.GetByEmailCall.Returns.Suppression, r.GetByEmailCall.Returns.Error
+}
+
+func (r *SuppressionsRepository) List(conn models.ConnectionInterface) ([]models.Suppression, error) {
+	r.ListCall.Receives.Connection = conn
+
+	return r.ListCall.Returns.Suppressions, r.ListCall.Returns.Error
+}
+
+func (r *SuppressionsRepository) Delete(conn models.ConnectionInterface, id string) error {
+	r.DeleteCall.Receives.Connection = conn
+	r.DeleteCall.Receives.ID = id
+
+	return r.DeleteCall.Returns.Error
+}
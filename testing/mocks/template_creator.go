@@ -7,6 +7,7 @@ type TemplateCreator struct {
 		Receives struct {
 			Connection collections.ConnectionInterface
 			Template   collections.Template
+			ClientID   string
 		}
 		Returns struct {
 			Template collections.Template
@@ -19,9 +20,10 @@ func NewTemplateCreator() *TemplateCreator {
 	return &TemplateCreator{}
 }
 
-func (tc *TemplateCreator) Create(connection collections.ConnectionInterface, template collections.Template) (collections.Template, error) {
+func (tc *TemplateCreator) Create(connection collections.ConnectionInterface, template collections.Template, clientID string) (collections.Template, error) {
 	tc.CreateCall.Receives.Connection = connection
 	tc.CreateCall.Receives.Template = template
+	tc.CreateCall.Receives.ClientID = clientID
 
 	return tc.CreateCall.Returns.Template, tc.CreateCall.Returns.Error
 }
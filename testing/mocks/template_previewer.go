@@ -0,0 +1,27 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/postal/common"
+
+type TemplatePreviewer struct {
+	PreviewCall struct {
+		Receives struct {
+			Templates common.Templates
+			Sample    common.PreviewSample
+		}
+		Returns struct {
+			Preview common.Preview
+			Error   error
+		}
+	}
+}
+
+func NewTemplatePreviewer() *TemplatePreviewer {
+	return &TemplatePreviewer{}
+}
+
+func (p *TemplatePreviewer) Preview(templates common.Templates, sample common.PreviewSample) (common.Preview, error) {
+	p.PreviewCall.Receives.Templates = templates
+	p.PreviewCall.Receives.Sample = sample
+
+	return p.PreviewCall.Returns.Preview, p.PreviewCall.Returns.Error
+}
@@ -11,6 +11,19 @@ type TemplateUpdater struct {
 			Database   services.DatabaseInterface
 			TemplateID string
 			Template   models.Template
+			ClientID   string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	RollbackCall struct {
+		Receives struct {
+			Database   services.DatabaseInterface
+			TemplateID string
+			Version    int
+			ClientID   string
 		}
 		Returns struct {
 			Error error
@@ -22,10 +35,20 @@ func NewTemplateUpdater() *TemplateUpdater {
 	return &TemplateUpdater{}
 }
 
-func (tu *TemplateUpdater) Update(database services.DatabaseInterface, templateID string, template models.Template) error {
+func (tu *TemplateUpdater) Update(database services.DatabaseInterface, templateID string, template models.Template, clientID string) error {
 	tu.UpdateCall.Receives.Database = database
 	tu.UpdateCall.Receives.TemplateID = templateID
 	tu.UpdateCall.Receives.Template = template
+	tu.UpdateCall.Receives.ClientID = clientID
 
 	return tu.UpdateCall.Returns.Error
 }
+
+func (tu *TemplateUpdater) Rollback(database services.DatabaseInterface, templateID string, version int, clientID string) error {
+	tu.RollbackCall.Receives.Database = database
+	tu.RollbackCall.Receives.TemplateID = templateID
+	tu.RollbackCall.Receives.Version = version
+	tu.RollbackCall.Receives.ClientID = clientID
+
+	return tu.RollbackCall.Returns.Error
+}
@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+)
+
+type TemplateVersionFinder struct {
+	ListCall struct {
+		Receives struct {
+			Database   services.DatabaseInterface
+			TemplateID string
+		}
+		Returns struct {
+			Versions []models.TemplateVersion
+			Error    error
+		}
+	}
+
+	FindCall struct {
+		Receives struct {
+			Database   services.DatabaseInterface
+			TemplateID string
+			Version    int
+		}
+		Returns struct {
+			Version models.TemplateVersion
+			Error   error
+		}
+	}
+}
+
+func NewTemplateVersionFinder() *TemplateVersionFinder {
+	return &TemplateVersionFinder{}
+}
+
+func (f *TemplateVersionFinder) List(database services.DatabaseInterface, templateID string) ([]models.TemplateVersion, error) {
+	f.ListCall.Receives.Database = database
+	f.ListCall.Receives.TemplateID = templateID
+
+	return f.ListCall.Returns.Versions, f.ListCall.Returns.Error
+}
+
+func (f *TemplateVersionFinder) Find(database services.DatabaseInterface, templateID string, version int) (models.TemplateVersion, error) {
+	f.FindCall.Receives.Database = database
+	f.FindCall.Receives.TemplateID = templateID
+	f.FindCall.Receives.Version = version
+
+	return f.FindCall.Returns.Version, f.FindCall.Returns.Error
+}
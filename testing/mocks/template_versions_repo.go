@@ -0,0 +1,66 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
+type TemplateVersionsRepo struct {
+	CreateCall struct {
+		CallCount int
+		Receives  struct {
+			Connection models.ConnectionInterface
+			Versions   []models.TemplateVersion
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	FindCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+			Version    int
+		}
+		Returns struct {
+			Version models.TemplateVersion
+			Error   error
+		}
+	}
+
+	ListCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+		}
+		Returns struct {
+			Versions []models.TemplateVersion
+			Error    error
+		}
+	}
+}
+
+func NewTemplateVersionsRepo() *TemplateVersionsRepo {
+	return &TemplateVersionsRepo{}
+}
+
+func (r *TemplateVersionsRepo) Create(conn models.ConnectionInterface, version models.TemplateVersion) (models.TemplateVersion, error) {
+	r.CreateCall.Receives.Connection = conn
+	r.CreateCall.Receives.Versions = append(r.CreateCall.Receives.Versions, version)
+	r.CreateCall.CallCount++
+
+	return version, r.CreateCall.Returns.Error
+}
+
+func (r *TemplateVersionsRepo) Find(conn models.ConnectionInterface, templateID string, version int) (models.TemplateVersion, error) {
+	r.FindCall.Receives.Connection = conn
+	r.FindCall.Receives.TemplateID = templateID
+	r.FindCall.Receives.Version = version
+
+	return r.FindCall.Returns.Version, r.FindCall.Returns.Error
+}
+
+func (r *TemplateVersionsRepo) List(conn models.ConnectionInterface, templateID string) ([]models.TemplateVersion, error) {
+	r.ListCall.Receives.Connection = conn
+	r.ListCall.Receives.TemplateID = templateID
+
+	return r.ListCall.Returns.Versions, r.ListCall.Returns.Error
+}
@@ -0,0 +1,66 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v2/models"
+
+type TemplateVersionsRepository struct {
+	InsertCall struct {
+		CallCount int
+		Receives  struct {
+			Connection models.ConnectionInterface
+			Versions   []models.TemplateVersion
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	ListCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+		}
+		Returns struct {
+			Versions []models.TemplateVersion
+			Error    error
+		}
+	}
+
+	GetCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+			Version    int
+		}
+		Returns struct {
+			Version models.TemplateVersion
+			Error   error
+		}
+	}
+}
+
+func NewTemplateVersionsRepository() *TemplateVersionsRepository {
+	return &TemplateVersionsRepository{}
+}
+
+func (r *TemplateVersionsRepository) Insert(conn models.ConnectionInterface, version models.TemplateVersion) (models.TemplateVersion, error) {
+	r.InsertCall.Receives.Connection = conn
+	r.InsertCall.Receives.Versions = append(r.InsertCall.Receives.Versions, version)
+	r.InsertCall.CallCount++
+
+	return version, r.InsertCall.Returns.Error
+}
+
+func (r *TemplateVersionsRepository) List(conn models.ConnectionInterface, templateID string) ([]models.TemplateVersion, error) {
+	r.ListCall.Receives.Connection = conn
+	r.ListCall.Receives.TemplateID = templateID
+
+	return r.ListCall.Returns.Versions, r.ListCall.Returns.Error
+}
+
+func (r *TemplateVersionsRepository) Get(conn models.ConnectionInterface, templateID string, version int) (models.TemplateVersion, error) {
+	r.GetCall.Receives.Connection = conn
+	r.GetCall.Receives.TemplateID = templateID
+	r.GetCall.Receives.Version = version
+
+	return r.GetCall.Returns.Version, r.GetCall.Returns.Error
+}
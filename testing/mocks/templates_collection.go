@@ -10,6 +10,7 @@ type TemplatesCollection struct {
 		Receives struct {
 			Connection db.ConnectionInterface
 			Template   collections.Template
+			ClientID   string
 		}
 		Returns struct {
 			Template collections.Template
@@ -49,15 +50,54 @@ type TemplatesCollection struct {
 			Error     error
 		}
 	}
+
+	ListVersionsCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+			ClientID   string
+		}
+		Returns struct {
+			Versions []collections.TemplateVersion
+			Error    error
+		}
+	}
+
+	GetVersionCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+			Version    int
+			ClientID   string
+		}
+		Returns struct {
+			Version collections.TemplateVersion
+			Error   error
+		}
+	}
+
+	RollbackCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+			Version    int
+			ClientID   string
+		}
+		Returns struct {
+			Template collections.Template
+			Error    error
+		}
+	}
 }
 
 func NewTemplatesCollection() *TemplatesCollection {
 	return &TemplatesCollection{}
 }
 
-func (c *TemplatesCollection) Set(conn collections.ConnectionInterface, template collections.Template) (collections.Template, error) {
+func (c *TemplatesCollection) Set(conn collections.ConnectionInterface, template collections.Template, clientID string) (collections.Template, error) {
 	c.SetCall.Receives.Connection = conn
 	c.SetCall.Receives.Template = template
+	c.SetCall.Receives.ClientID = clientID
 
 	return c.SetCall.Returns.Template, c.SetCall.Returns.Error
 }
@@ -82,3 +122,29 @@ func (c *TemplatesCollection) List(conn collections.ConnectionInterface, clientI
 	c.ListCall.Receives.ClientID = clientID
 	return c.ListCall.Returns.Templates, c.ListCall.Returns.Error
 }
+
+func (c *TemplatesCollection) ListVersions(conn collections.ConnectionInterface, templateID, clientID string) ([]collections.TemplateVersion, error) {
+	c.ListVersionsCall.Receives.Connection = conn
+	c.ListVersionsCall.Receives.TemplateID = templateID
+	c.ListVersionsCall.Receives.ClientID = clientID
+
+	return c.ListVersionsCall.Returns.Versions, c.ListVersionsCall.Returns.Error
+}
+
+func (c *TemplatesCollection) GetVersion(conn collections.ConnectionInterface, templateID string, version int, clientID string) (collections.TemplateVersion, error) {
+	c.GetVersionCall.Receives.Connection = conn
+	c.GetVersionCall.Receives.TemplateID = templateID
+	c.GetVersionCall.Receives.Version = version
+	c.GetVersionCall.Receives.ClientID = clientID
+
+	return c.GetVersionCall.Returns.Version, c.GetVersionCall.Returns.Error
+}
+
+func (c *TemplatesCollection) Rollback(conn collections.ConnectionInterface, templateID string, version int, clientID string) (collections.Template, error) {
+	c.RollbackCall.Receives.Connection = conn
+	c.RollbackCall.Receives.TemplateID = templateID
+	c.RollbackCall.Receives.Version = version
+	c.RollbackCall.Receives.ClientID = clientID
+
+	return c.RollbackCall.Returns.Template, c.RollbackCall.Returns.Error
+}
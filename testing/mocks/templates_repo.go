@@ -45,6 +45,16 @@ type TemplatesRepo struct {
 		}
 	}
 
+	LockCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
 	UpdateCall struct {
 		Receives struct {
 			Connection models.ConnectionInterface
@@ -89,6 +99,13 @@ func (tr *TemplatesRepo) ListIDsAndNames(conn models.ConnectionInterface) ([]mod
 	return tr.ListIDsAndNamesCall.Returns.Templates, tr.ListIDsAndNamesCall.Returns.Error
 }
 
+func (tr *TemplatesRepo) Lock(conn models.ConnectionInterface, templateID string) error {
+	tr.LockCall.Receives.Connection = conn
+	tr.LockCall.Receives.TemplateID = templateID
+
+	return tr.LockCall.Returns.Error
+}
+
 func (tr *TemplatesRepo) Update(conn models.ConnectionInterface, templateID string, template models.Template) (models.Template, error) {
 	tr.UpdateCall.Receives.Connection = conn
 	tr.UpdateCall.Receives.TemplateID = templateID
@@ -57,6 +57,28 @@ type TemplatesRepository struct {
 			Error     error
 		}
 	}
+
+	ListByPartialsClientIDCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			ClientID   string
+		}
+
+		Returns struct {
+			Templates []models.Template
+			Error     error
+		}
+	}
+
+	LockCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+		}
+		Returns struct {
+			Error error
+		}
+	}
 }
 
 func NewTemplatesRepository() *TemplatesRepository {
@@ -97,3 +119,17 @@ func (r *TemplatesRepository) List(conn models.ConnectionInterface, clientID str
 
 	return r.ListCall.Returns.Templates, r.ListCall.Returns.Error
 }
+
+func (r *TemplatesRepository) ListByPartialsClientID(conn models.ConnectionInterface, clientID string) ([]models.Template, error) {
+	r.ListByPartialsClientIDCall.Receives.Connection = conn
+	r.ListByPartialsClientIDCall.Receives.ClientID = clientID
+
+	return r.ListByPartialsClientIDCall.Returns.Templates, r.ListByPartialsClientIDCall.Returns.Error
+}
+
+func (r *TemplatesRepository) Lock(conn models.ConnectionInterface, templateID string) error {
+	r.LockCall.Receives.Connection = conn
+	r.LockCall.Receives.TemplateID = templateID
+
+	return r.LockCall.Returns.Error
+}
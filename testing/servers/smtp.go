@@ -3,6 +3,7 @@ package servers
 import (
 	"net"
 	"os"
+	"sync"
 
 	"bitbucket.org/chrj/smtpd"
 )
@@ -12,6 +13,9 @@ type SMTP struct {
 	server     *smtpd.Server
 	Deliveries []smtpd.Envelope
 
+	mutex       sync.Mutex
+	connections []net.Conn
+
 	HandlerCall struct {
 		Callback func()
 		Returns  struct {
@@ -38,7 +42,7 @@ func (s *SMTP) Boot() {
 
 	s.server.Handler = s.Handler
 
-	go s.server.Serve(s.listener)
+	go s.server.Serve(trackingListener{s.listener, s})
 
 	addr := s.listener.Addr().String()
 	host, port, err := net.SplitHostPort(addr)
@@ -64,6 +68,47 @@ func (s *SMTP) Reset() {
 	s.HandlerCall.Callback = nil
 }
 
+func (s *SMTP) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// ConnectionCount returns the number of connections the server has accepted.
+func (s *SMTP) ConnectionCount() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return len(s.connections)
+}
+
+// DropConnections closes every accepted connection without notifying the
+// client, as a server would when an idle connection times out.
+func (s *SMTP) DropConnections() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, conn := range s.connections {
+		conn.Close()
+	}
+}
+
 func (s *SMTP) Close() {
 	s.listener.Close()
 }
+
+type trackingListener struct {
+	net.Listener
+	server *SMTP
+}
+
+func (l trackingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return conn, err
+	}
+
+	l.server.mutex.Lock()
+	l.server.connections = append(l.server.connections, conn)
+	l.server.mutex.Unlock()
+
+	return conn, nil
+}
@@ -70,6 +70,17 @@ var _ = Describe("Templates CRUD", func() {
 		Expect(status).To(Equal(422))
 	})
 
+	It("errors when an unknown field is referenced", func() {
+		status, _, err := client.Templates.Create(clientToken.Access, support.Template{
+			Name:    "Unknown Field Template",
+			Subject: "This is a bad template",
+			HTML:    "<p>{{.Mesage}}</p>",
+			Text:    "hello {{.Text}}",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(422))
+	})
+
 	It("allows a user to retrieve a template", func() {
 		var templateID string
 
@@ -3,6 +3,7 @@ package collections
 import (
 	"fmt"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 )
 
@@ -40,6 +41,10 @@ type templatesRepository interface {
 	Destroy(connection models.ConnectionInterface, templateID string) error
 }
 
+type partialsRepository interface {
+	FindAllByClientID(connection models.ConnectionInterface, clientID string) (map[string]string, error)
+}
+
 type TemplateAssociation struct {
 	ClientID       string
 	NotificationID string
@@ -58,13 +63,15 @@ type TemplatesCollection struct {
 	clientsRepo   clientsRepository
 	kindsRepo     kindsRepository
 	templatesRepo templatesRepository
+	partialsRepo  partialsRepository
 }
 
-func NewTemplatesCollection(clientsRepo clientsRepository, kindsRepo kindsRepository, templatesRepo templatesRepository) TemplatesCollection {
+func NewTemplatesCollection(clientsRepo clientsRepository, kindsRepo kindsRepository, templatesRepo templatesRepository, partialsRepo partialsRepository) TemplatesCollection {
 	return TemplatesCollection{
 		clientsRepo:   clientsRepo,
 		kindsRepo:     kindsRepo,
 		templatesRepo: templatesRepo,
+		partialsRepo:  partialsRepo,
 	}
 }
 
@@ -173,13 +180,28 @@ func (c TemplatesCollection) ListAssociations(conn ConnectionInterface, template
 	return associations, nil
 }
 
-func (c TemplatesCollection) Create(connection ConnectionInterface, template Template) (Template, error) {
+// Create saves a new template. The partials the template includes are
+// checked against the partials owned by clientID, the client creating it,
+// and are resolved against them whichever client sends with the template.
+func (c TemplatesCollection) Create(connection ConnectionInterface, template Template, clientID string) (Template, error) {
+	partials, err := c.partialsRepo.FindAllByClientID(connection, clientID)
+	if err != nil {
+		return Template{}, err
+	}
+
+	err = common.ValidateTemplates(template.Subject, template.Text, template.HTML, partials)
+	if err != nil {
+		return Template{}, err
+	}
+
 	tmpl, err := c.templatesRepo.Create(connection, models.Template{
 		Name:     template.Name,
 		Text:     template.Text,
 		HTML:     template.HTML,
 		Subject:  template.Subject,
 		Metadata: template.Metadata,
+
+		PartialsClientID: clientID,
 	})
 	if err != nil {
 		return Template{}, err
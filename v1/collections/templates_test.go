@@ -3,6 +3,7 @@ package collections_test
 import (
 	"errors"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
@@ -16,6 +17,7 @@ var _ = Describe("TemplatesCollection", func() {
 		kindsRepo     *mocks.KindsRepo
 		clientsRepo   *mocks.ClientsRepository
 		templatesRepo *mocks.TemplatesRepo
+		partialsRepo  *mocks.PartialsRepo
 		conn          *mocks.Connection
 
 		collection collections.TemplatesCollection
@@ -27,8 +29,9 @@ var _ = Describe("TemplatesCollection", func() {
 		clientsRepo = mocks.NewClientsRepository()
 		kindsRepo = mocks.NewKindsRepo()
 		templatesRepo = mocks.NewTemplatesRepo()
+		partialsRepo = mocks.NewPartialsRepo()
 
-		collection = collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo)
+		collection = collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo, partialsRepo)
 	})
 
 	Describe("AssignToClient", func() {
@@ -342,7 +345,7 @@ var _ = Describe("TemplatesCollection", func() {
 				HTML:     "some-html",
 				Subject:  "some-subject",
 				Metadata: "some-metadata",
-			})
+			}, "some-client-id")
 			Expect(err).ToNot(HaveOccurred())
 			Expect(template).To(Equal(collections.Template{
 				ID:       "some-template-guid",
@@ -360,15 +363,72 @@ var _ = Describe("TemplatesCollection", func() {
 				HTML:     "some-html",
 				Subject:  "some-subject",
 				Metadata: "some-metadata",
+
+				PartialsClientID: "some-client-id",
 			}))
 		})
 
 		It("propagates errors from repo", func() {
 			templatesRepo.CreateCall.Returns.Error = errors.New("Boom!")
 
-			_, err := collection.Create(conn, collections.Template{})
+			_, err := collection.Create(conn, collections.Template{}, "some-client-id")
 			Expect(err).To(Equal(errors.New("Boom!")))
 		})
+
+		It("rejects a template that references an unknown field", func() {
+			_, err := collection.Create(conn, collections.Template{
+				Name:    "some-template-name",
+				Text:    "some-text",
+				HTML:    "<p>\n  {{.Mesage}}\n</p>",
+				Subject: "some-subject",
+			}, "some-client-id")
+			Expect(err).To(MatchError(common.TemplateError{
+				Part:    "html",
+				Line:    2,
+				Column:  5,
+				Message: `unknown field "Mesage"`,
+			}))
+
+			Expect(templatesRepo.CreateCall.Receives.Template).To(Equal(models.Template{}))
+		})
+
+		It("validates the partials the template includes against the client's partials", func() {
+			partialsRepo.FindAllByClientIDCall.Returns.Partials = map[string]string{
+				"footer": "<footer>{{.Organization}}</footer>",
+			}
+
+			_, err := collection.Create(conn, collections.Template{
+				Name: "some-template-name",
+				HTML: `<p>some-html</p>{{template "footer" .}}`,
+			}, "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(partialsRepo.FindAllByClientIDCall.Receives.Connection).To(Equal(conn))
+			Expect(partialsRepo.FindAllByClientIDCall.Receives.ClientID).To(Equal("some-client-id"))
+			Expect(templatesRepo.CreateCall.Receives.Template.HTML).To(Equal(`<p>some-html</p>{{template "footer" .}}`))
+		})
+
+		It("rejects a template that includes a partial the client does not have", func() {
+			_, err := collection.Create(conn, collections.Template{
+				Name: "some-template-name",
+				HTML: `<p>some-html</p>{{template "footer" .}}`,
+			}, "some-client-id")
+			Expect(err).To(MatchError(common.TemplateError{
+				Part:    "html",
+				Line:    1,
+				Column:  28,
+				Message: `unknown partial "footer"`,
+			}))
+
+			Expect(templatesRepo.CreateCall.Receives.Template).To(Equal(models.Template{}))
+		})
+
+		It("propagates errors from the partials repo", func() {
+			partialsRepo.FindAllByClientIDCall.Returns.Error = errors.New("Boom!")
+
+			_, err := collection.Create(conn, collections.Template{}, "some-client-id")
+			Expect(err).To(MatchError(errors.New("Boom!")))
+		})
 	})
 
 	Describe("Delete", func() {
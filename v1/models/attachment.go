@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+type Attachment struct {
+	ID          string    `db:"id"`
+	DispatchID  string    `db:"dispatch_id"`
+	Filename    string    `db:"filename"`
+	ContentType string    `db:"content_type"`
+	Content     []byte    `db:"content"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+func (a *Attachment) PreInsert(s gorp.SqlExecutor) error {
+	a.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// AttachmentsRepo stores the files attached to a notification once, so
+// that each delivery job only needs to carry their IDs.
+type AttachmentsRepo struct {
+	generateID IDGeneratorFunc
+}
+
+func NewAttachmentsRepo(guidGenerator IDGeneratorFunc) AttachmentsRepo {
+	return AttachmentsRepo{
+		generateID: guidGenerator,
+	}
+}
+
+func (repo AttachmentsRepo) Create(conn ConnectionInterface, attachment Attachment) (Attachment, error) {
+	var err error
+	attachment.ID, err = repo.generateID()
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	err = conn.Insert(&attachment)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+func (repo AttachmentsRepo) Delete(conn ConnectionInterface, id string) error {
+	_, err := conn.Exec("DELETE FROM `attachments` WHERE `id` = ?", id)
+	return err
+}
+
+// DeleteBefore removes attachments created before the threshold. The
+// attachments of a campaign are kept while any of its messages are still
+// queued or waiting to be retried, since those deliveries load them at
+// send time. The attachments of a v1 dispatch are kept the same way; a
+// failed v1 message is still retried, so it counts as pending until the
+// message itself is collected.
+func (repo AttachmentsRepo) DeleteBefore(conn ConnectionInterface, threshold time.Time) (int, error) {
+	result, err := conn.Exec("DELETE FROM `attachments` WHERE `created_at` < ? AND `campaign_id` NOT IN "+
+		"(SELECT `campaign_id` FROM `messages` WHERE `campaign_id` != '' AND `status` IN ('queued', 'retry')) AND `dispatch_id` NOT IN "+
+		"(SELECT `dispatch_id` FROM `messages` WHERE `dispatch_id` != '' AND `status` IN ('queued', 'failed'))", threshold.UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
@@ -0,0 +1,121 @@
+package models_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AttachmentsRepo", func() {
+	var (
+		repo          models.AttachmentsRepo
+		conn          db.ConnectionInterface
+		guidGenerator *mocks.IDGenerator
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+
+		guidGenerator = mocks.NewIDGenerator()
+		guidGenerator.GenerateCall.Returns.IDs = []string{"first-random-guid"}
+
+		repo = models.NewAttachmentsRepo(guidGenerator.Generate)
+	})
+
+	Describe("Create", func() {
+		It("inserts an attachment into the database", func() {
+			attachment, err := repo.Create(conn, models.Attachment{
+				Filename:    "report.csv",
+				ContentType: "text/csv",
+				Content:     []byte("a,b,c"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attachment.ID).To(Equal("first-random-guid"))
+			Expect(attachment.CreatedAt).To(BeTemporally("~", time.Now(), time.Minute))
+
+			var found models.Attachment
+			err = conn.SelectOne(&found, "SELECT * FROM `attachments` WHERE `id` = ?", attachment.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found.Filename).To(Equal("report.csv"))
+			Expect(found.ContentType).To(Equal("text/csv"))
+			Expect(found.Content).To(Equal([]byte("a,b,c")))
+		})
+
+		It("returns an error when the guid generator errors", func() {
+			guidGenerator.GenerateCall.Returns.Error = errors.New("something bad")
+
+			_, err := repo.Create(conn, models.Attachment{Filename: "report.csv"})
+			Expect(err).To(MatchError(errors.New("something bad")))
+		})
+	})
+
+	Describe("Delete", func() {
+		It("removes the attachment", func() {
+			attachment, err := repo.Create(conn, models.Attachment{Filename: "report.csv"})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.Delete(conn, attachment.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			count, err := conn.GetDbMap().SelectInt("SELECT COUNT(*) FROM `attachments`")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(int64(0)))
+		})
+	})
+
+	Describe("DeleteBefore", func() {
+		BeforeEach(func() {
+			guidGenerator.GenerateCall.Returns.IDs = []string{"v1-attachment", "delivered-dispatch-attachment", "retrying-dispatch-attachment"}
+
+			_, err := repo.Create(conn, models.Attachment{Filename: "report.csv"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Create(conn, models.Attachment{DispatchID: "delivered-dispatch", Filename: "c.csv"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Create(conn, models.Attachment{DispatchID: "retrying-dispatch", Filename: "d.csv"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = conn.Exec("INSERT INTO `attachments` (`id`, `campaign_id`, `filename`, `content`, `created_at`) VALUES "+
+				"('campaign-attachment', 'completed-campaign', 'a.csv', '', ?), ('sending-campaign-attachment', 'sending-campaign', 'b.csv', '', ?)",
+				time.Now().UTC(), time.Now().UTC())
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = conn.Exec("INSERT INTO `messages` (`id`, `campaign_id`, `status`, `updated_at`) VALUES "+
+				"('message-1', 'completed-campaign', 'delivered', ?), ('message-2', 'sending-campaign', 'retry', ?)",
+				time.Now().UTC(), time.Now().UTC())
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = conn.Exec("INSERT INTO `messages` (`id`, `dispatch_id`, `status`, `updated_at`) VALUES "+
+				"('message-3', 'delivered-dispatch', 'delivered', ?), ('message-4', 'retrying-dispatch', 'failed', ?)",
+				time.Now().UTC(), time.Now().UTC())
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("deletes attachments older than the threshold, keeping those of campaigns and dispatches that are still sending", func() {
+			deleted, err := repo.DeleteBefore(conn, time.Now().Add(1*time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal(3))
+
+			var ids []string
+			_, err = conn.Select(&ids, "SELECT `id` FROM `attachments` ORDER BY `id`")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids).To(Equal([]string{"retrying-dispatch-attachment", "sending-campaign-attachment"}))
+		})
+
+		It("does not delete attachments younger than the threshold", func() {
+			deleted, err := repo.DeleteBefore(conn, time.Now().Add(-1*time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal(0))
+		})
+	})
+})
@@ -9,6 +9,7 @@ import (
 type Message struct {
 	ID         string    `db:"id"`
 	CampaignID string    `db:"campaign_id"`
+	DispatchID string    `db:"dispatch_id"`
 	Status     string    `db:"status"`
 	UpdatedAt  time.Time `db:"updated_at"`
 }
@@ -55,13 +55,19 @@ func (repo MessagesRepo) Update(conn ConnectionInterface, message Message) (Mess
 	return repo.FindByID(conn, message.ID)
 }
 
+// Upsert creates the message, or updates it if it already exists. An update
+// that does not set a DispatchID keeps the one the message was created with,
+// so that status updates do not release the attachments of its dispatch.
 func (repo MessagesRepo) Upsert(conn ConnectionInterface, message Message) (Message, error) {
-	_, err := repo.FindByID(conn, message.ID)
+	existing, err := repo.FindByID(conn, message.ID)
 
 	switch err.(type) {
 	case NotFoundError:
 		return repo.Create(conn, message)
 	case nil:
+		if message.DispatchID == "" {
+			message.DispatchID = existing.DispatchID
+		}
 		return repo.Update(conn, message)
 	default:
 		return message, err
@@ -110,6 +110,23 @@ var _ = Describe("MessagesRepo", func() {
 				Expect(messageFound.ID).To(Equal(message.ID))
 				Expect(messageFound.Status).To(Equal(message.Status))
 			})
+
+			It("keeps the dispatch ID when the update does not set one", func() {
+				message.DispatchID = "some-dispatch-id"
+				message, err := repo.Create(conn, message)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = repo.Upsert(conn, models.Message{
+					ID:     message.ID,
+					Status: common.StatusFailed,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				messageFound, err := repo.FindByID(conn, message.ID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(messageFound.Status).To(Equal(common.StatusFailed))
+				Expect(messageFound.DispatchID).To(Equal("some-dispatch-id"))
+			})
 		})
 	})
 
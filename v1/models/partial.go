@@ -0,0 +1,11 @@
+package models
+
+// Partial is a named piece of a template owned by a client. Partials are
+// managed through the v2 API; v1 only reads them so that the templates of
+// a client can include them with {{template "name" .}}.
+type Partial struct {
+	ID       string `db:"id"`
+	ClientID string `db:"client_id"`
+	Name     string `db:"name"`
+	Body     string `db:"body"`
+}
@@ -0,0 +1,24 @@
+package models
+
+type PartialsRepo struct{}
+
+func NewPartialsRepo() PartialsRepo {
+	return PartialsRepo{}
+}
+
+// FindAllByClientID returns the partials owned by a client, keyed by name
+// the way the packager expects them.
+func (repo PartialsRepo) FindAllByClientID(conn ConnectionInterface, clientID string) (map[string]string, error) {
+	partials := []Partial{}
+	_, err := conn.Select(&partials, "SELECT * FROM `partials` WHERE `client_id` = ?", clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	bodies := map[string]string{}
+	for _, partial := range partials {
+		bodies[partial.Name] = partial.Body
+	}
+
+	return bodies, nil
+}
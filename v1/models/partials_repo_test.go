@@ -0,0 +1,49 @@
+package models_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PartialsRepo", func() {
+	var (
+		repo models.PartialsRepo
+		conn db.ConnectionInterface
+	)
+
+	BeforeEach(func() {
+		repo = models.NewPartialsRepo()
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+	})
+
+	Describe("FindAllByClientID", func() {
+		It("returns the bodies of the client's partials keyed by name", func() {
+			insert := "INSERT INTO `partials` (`id`, `client_id`, `name`, `body`) VALUES (?, ?, ?, ?)"
+			_, err := conn.Exec(insert, "footer-id", "some-client-id", "footer", "<footer/>")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = conn.Exec(insert, "header-id", "some-client-id", "header", "<header/>")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = conn.Exec(insert, "other-footer-id", "other-client-id", "footer", "<footer>other</footer>")
+			Expect(err).NotTo(HaveOccurred())
+
+			partials, err := repo.FindAllByClientID(conn, "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partials).To(Equal(map[string]string{
+				"footer": "<footer/>",
+				"header": "<header/>",
+			}))
+		})
+
+		It("returns an empty map when the client has no partials", func() {
+			partials, err := repo.FindAllByClientID(conn, "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partials).To(BeEmpty())
+		})
+	})
+})
@@ -24,6 +24,11 @@ type Template struct {
 	CreatedAt  time.Time `db:"created_at"`
 	UpdatedAt  time.Time `db:"updated_at"`
 	Overridden bool      `db:"overridden"`
+
+	// PartialsClientID is the client whose partials the template includes,
+	// the client that last saved it. Templates are shared by every client
+	// that sends with them, so they do not use the partials of the sender.
+	PartialsClientID string `db:"partials_client_id"`
 }
 
 func (t *Template) PreInsert(s gorp.SqlExecutor) error {
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// TemplateVersion is a copy of a template as it was saved by an update,
+// along with the client that saved it.
+type TemplateVersion struct {
+	Primary    int       `db:"primary"`
+	TemplateID string    `db:"template_id"`
+	Version    int       `db:"version"`
+	Name       string    `db:"name"`
+	Subject    string    `db:"subject"`
+	Text       string    `db:"text"`
+	HTML       string    `db:"html"`
+	Metadata   string    `db:"metadata"`
+	ClientID   string    `db:"client_id"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+func NewTemplateVersion(template Template, clientID string) TemplateVersion {
+	return TemplateVersion{
+		TemplateID: template.ID,
+		Name:       template.Name,
+		Subject:    template.Subject,
+		Text:       template.Text,
+		HTML:       template.HTML,
+		Metadata:   template.Metadata,
+		ClientID:   clientID,
+	}
+}
+
+// Template returns the template content recorded by the version.
+func (v TemplateVersion) Template() Template {
+	return Template{
+		ID:       v.TemplateID,
+		Name:     v.Name,
+		Subject:  v.Subject,
+		Text:     v.Text,
+		HTML:     v.HTML,
+		Metadata: v.Metadata,
+	}
+}
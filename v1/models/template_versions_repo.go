@@ -0,0 +1,59 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type TemplateVersionsRepo struct{}
+
+func NewTemplateVersionsRepo() TemplateVersionsRepo {
+	return TemplateVersionsRepo{}
+}
+
+// Create records version as the next version of its template. CreatedAt is
+// set to the current time unless the caller has already set it.
+func (repo TemplateVersionsRepo) Create(conn ConnectionInterface, version TemplateVersion) (TemplateVersion, error) {
+	var latest int
+	err := conn.SelectOne(&latest, "SELECT COALESCE(MAX(`version`), 0) FROM `template_versions` WHERE `template_id` = ?", version.TemplateID)
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+
+	version.Version = latest + 1
+	if (version.CreatedAt == time.Time{}) {
+		version.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+	}
+
+	err = conn.Insert(&version)
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+
+	return version, nil
+}
+
+// List returns the versions of a template, newest first.
+func (repo TemplateVersionsRepo) List(conn ConnectionInterface, templateID string) ([]TemplateVersion, error) {
+	versions := []TemplateVersion{}
+	_, err := conn.Select(&versions, "SELECT * FROM `template_versions` WHERE `template_id` = ? ORDER BY `version` DESC", templateID)
+	if err != nil {
+		return []TemplateVersion{}, err
+	}
+
+	return versions, nil
+}
+
+func (repo TemplateVersionsRepo) Find(conn ConnectionInterface, templateID string, version int) (TemplateVersion, error) {
+	templateVersion := TemplateVersion{}
+	err := conn.SelectOne(&templateVersion, "SELECT * FROM `template_versions` WHERE `template_id` = ? AND `version` = ?", templateID, version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return templateVersion, NotFoundError{fmt.Errorf("Version %d of template with ID %q could not be found", version, templateID)}
+		}
+		return templateVersion, err
+	}
+
+	return templateVersion, nil
+}
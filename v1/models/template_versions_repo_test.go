@@ -0,0 +1,136 @@
+package models_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplateVersionsRepo", func() {
+	var (
+		repo models.TemplateVersionsRepo
+		conn db.ConnectionInterface
+	)
+
+	BeforeEach(func() {
+		repo = models.NewTemplateVersionsRepo()
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+	})
+
+	Describe("Create", func() {
+		It("numbers the versions of each template from 1", func() {
+			first, err := repo.Create(conn, models.TemplateVersion{
+				TemplateID: "raptor_template",
+				Name:       "Raptors On The Run",
+				Text:       "run and hide",
+				ClientID:   "some-client-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(first.Version).To(Equal(1))
+			Expect(first.CreatedAt).To(BeTemporally("~", time.Now(), 10*time.Second))
+
+			second, err := repo.Create(conn, models.TemplateVersion{
+				TemplateID: "raptor_template",
+				Name:       "Raptors Caught",
+				Text:       "come out",
+				ClientID:   "other-client-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second.Version).To(Equal(2))
+
+			other, err := repo.Create(conn, models.TemplateVersion{
+				TemplateID: "dinosaur_template",
+				Name:       "Dinosaurs",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(other.Version).To(Equal(1))
+		})
+
+		It("keeps a creation time that has already been set", func() {
+			createdAt := time.Now().Add(-24 * time.Hour).Truncate(1 * time.Second).UTC()
+
+			version, err := repo.Create(conn, models.TemplateVersion{
+				TemplateID: "raptor_template",
+				Name:       "Raptors On The Run",
+				CreatedAt:  createdAt,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			found, err := repo.Find(conn, "raptor_template", version.Version)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found.CreatedAt).To(Equal(createdAt))
+		})
+	})
+
+	Describe("List", func() {
+		It("returns the versions of a template, newest first", func() {
+			for _, name := range []string{"first", "second", "third"} {
+				_, err := repo.Create(conn, models.TemplateVersion{
+					TemplateID: "raptor_template",
+					Name:       name,
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			_, err := repo.Create(conn, models.TemplateVersion{
+				TemplateID: "dinosaur_template",
+				Name:       "unrelated",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			versions, err := repo.List(conn, "raptor_template")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(HaveLen(3))
+			Expect(versions[0].Version).To(Equal(3))
+			Expect(versions[0].Name).To(Equal("third"))
+			Expect(versions[2].Version).To(Equal(1))
+			Expect(versions[2].Name).To(Equal("first"))
+		})
+
+		It("returns an empty list when the template has no versions", func() {
+			versions, err := repo.List(conn, "raptor_template")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(BeEmpty())
+		})
+	})
+
+	Describe("Find", func() {
+		It("returns the requested version", func() {
+			_, err := repo.Create(conn, models.TemplateVersion{
+				TemplateID: "raptor_template",
+				Name:       "Raptors On The Run",
+				Subject:    "{{.Subject}}",
+				Text:       "run and hide",
+				HTML:       "<h1>containment unit breached!</h1>",
+				Metadata:   "{}",
+				ClientID:   "some-client-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			version, err := repo.Find(conn, "raptor_template", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version.Template()).To(Equal(models.Template{
+				ID:       "raptor_template",
+				Name:     "Raptors On The Run",
+				Subject:  "{{.Subject}}",
+				Text:     "run and hide",
+				HTML:     "<h1>containment unit breached!</h1>",
+				Metadata: "{}",
+			}))
+			Expect(version.ClientID).To(Equal("some-client-id"))
+		})
+
+		It("returns a not found error when the version does not exist", func() {
+			_, err := repo.Find(conn, "raptor_template", 4)
+			Expect(err).To(MatchError(models.NotFoundError{errors.New(`Version 4 of template with ID "raptor_template" could not be found`)}))
+		})
+	})
+})
@@ -44,6 +44,13 @@ func (repo TemplatesRepo) Update(conn ConnectionInterface, templateID string, te
 	return template, nil
 }
 
+// Lock holds the row of the template until the transaction conn belongs to
+// ends, so that concurrent updates of the template take turns.
+func (repo TemplatesRepo) Lock(conn ConnectionInterface, templateID string) error {
+	_, err := conn.Select(&[]Template{}, "SELECT * FROM `templates` WHERE `id`=? FOR UPDATE", templateID)
+	return err
+}
+
 func (repo TemplatesRepo) ListIDsAndNames(conn ConnectionInterface) ([]Template, error) {
 	templates := []Template{}
 	_, err := conn.Select(&templates, "SELECT ID, Name FROM `templates`")
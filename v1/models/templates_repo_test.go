@@ -132,6 +132,24 @@ var _ = Describe("TemplatesRepo", func() {
 		})
 	})
 
+	Describe("Lock", func() {
+		It("locks the template within a transaction", func() {
+			transaction := conn.Transaction()
+			err := transaction.Begin()
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.Lock(transaction, template.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(transaction.Commit()).To(Succeed())
+		})
+
+		It("does not fail when the template does not exist", func() {
+			err := repo.Lock(conn, "a-bad-id")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
 	Describe("#ListIDsAndNames", func() {
 		Context("there are templates in the database", func() {
 			It("returns a list of templates - ID and Name only", func() {
@@ -2,7 +2,11 @@ package services
 
 import "time"
 
+// Dispatch is a single notify request. Its ID is recorded on the
+// attachments and messages it creates, so that the attachments are kept
+// while any of its messages are still being delivered.
 type Dispatch struct {
+	ID         string
 	JobType    string
 	GUID       string
 	Role       string
@@ -31,10 +35,15 @@ type DispatchVCAPRequest struct {
 
 type DispatchMessage struct {
 	To      string
+	Cc      []string
+	Bcc     []string
 	ReplyTo string
 	Subject string
 	Text    string
 	HTML    HTML
+	Headers map[string]string
+
+	AttachmentIDs []string
 }
 
 type DispatchClient struct {
@@ -45,4 +54,5 @@ type DispatchClient struct {
 type DispatchKind struct {
 	ID          string
 	Description string
+	Critical    bool
 }
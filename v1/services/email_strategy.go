@@ -35,10 +35,13 @@ func NewEmailStrategy(enqueuer enqueuer) EmailStrategy {
 func (strategy EmailStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
 	options := Options{
 		To:                dispatch.Message.To,
+		Cc:                dispatch.Message.Cc,
+		Bcc:               dispatch.Message.Bcc,
 		ReplyTo:           dispatch.Message.ReplyTo,
 		Subject:           dispatch.Message.Subject,
 		KindID:            dispatch.Kind.ID,
 		KindDescription:   dispatch.Kind.Description,
+		Critical:          dispatch.Kind.Critical,
 		SourceDescription: dispatch.Client.Description,
 		Endorsement:       EmailEndorsement,
 		Text:              dispatch.Message.Text,
@@ -49,6 +52,9 @@ func (strategy EmailStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
 			Head:           dispatch.Message.HTML.Head,
 			Doctype:        dispatch.Message.HTML.Doctype,
 		},
+		Headers:       dispatch.Message.Headers,
+		AttachmentIDs: dispatch.Message.AttachmentIDs,
+		DispatchID:    dispatch.ID,
 	}
 
 	users := []User{{Email: dispatch.Message.To}}
@@ -90,6 +90,47 @@ var _ = Describe("EmailStrategy", func() {
 				Expect(enqueuer.EnqueueCall.Receives.RequestReceived).To(Equal(requestReceived))
 				Expect(enqueuer.EnqueueCall.Receives.UAAHost).To(Equal("uaahost"))
 			})
+
+			It("passes the Cc and Bcc recipients along", func() {
+				emailStrategy.Dispatch(services.Dispatch{
+					Connection: conn,
+					Message: services.DispatchMessage{
+						To:  "dr@strangelove.com",
+						Cc:  []string{"shared@example.com"},
+						Bcc: []string{"audit@example.com"},
+					},
+				})
+
+				Expect(enqueuer.EnqueueCall.Receives.Users).To(Equal([]services.User{{Email: "dr@strangelove.com"}}))
+				Expect(enqueuer.EnqueueCall.Receives.Options.Cc).To(Equal([]string{"shared@example.com"}))
+				Expect(enqueuer.EnqueueCall.Receives.Options.Bcc).To(Equal([]string{"audit@example.com"}))
+			})
+
+			It("passes the custom headers along", func() {
+				emailStrategy.Dispatch(services.Dispatch{
+					Connection: conn,
+					Message: services.DispatchMessage{
+						To:      "dr@strangelove.com",
+						Headers: map[string]string{"X-Ticket-ID": "TICKET-1234"},
+					},
+				})
+
+				Expect(enqueuer.EnqueueCall.Receives.Options.Headers).To(Equal(map[string]string{"X-Ticket-ID": "TICKET-1234"}))
+			})
+
+			It("passes the attachments and the dispatch that owns them along", func() {
+				emailStrategy.Dispatch(services.Dispatch{
+					ID:         "some-dispatch-id",
+					Connection: conn,
+					Message: services.DispatchMessage{
+						To:            "dr@strangelove.com",
+						AttachmentIDs: []string{"attachment-1"},
+					},
+				})
+
+				Expect(enqueuer.EnqueueCall.Receives.Options.AttachmentIDs).To(Equal([]string{"attachment-1"}))
+				Expect(enqueuer.EnqueueCall.Receives.Options.DispatchID).To(Equal("some-dispatch-id"))
+			})
 		})
 	})
 })
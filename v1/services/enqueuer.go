@@ -21,9 +21,15 @@ type Options struct {
 	HTML              HTML
 	KindID            string
 	To                string
+	Cc                []string
+	Bcc               []string
 	Role              string
 	Endorsement       string
 	TemplateID        string
+	Critical          bool
+	Headers           map[string]string
+	AttachmentIDs     []string
+	DispatchID        string
 }
 
 type Delivery struct {
@@ -89,7 +95,8 @@ func (enqueuer Enqueuer) Enqueue(
 
 	for _, user := range users {
 		message, err := enqueuer.messagesRepo.Upsert(transaction, models.Message{
-			Status: StatusQueued,
+			Status:     StatusQueued,
+			DispatchID: options.DispatchID,
 		})
 		if err != nil {
 			transaction.Rollback()
@@ -110,6 +117,10 @@ func (enqueuer Enqueuer) Enqueue(
 			RequestReceived: reqReceived,
 		})
 
+		if options.Critical {
+			job.Priority = gobble.PriorityCritical
+		}
+
 		_, err = enqueuer.queue.Enqueue(job, transaction)
 		if err != nil {
 			transaction.Rollback()
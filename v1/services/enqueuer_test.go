@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
@@ -177,6 +178,28 @@ var _ = Describe("Enqueuer", func() {
 			}))
 		})
 
+		It("enqueues jobs with a normal priority", func() {
+			users := []services.User{{GUID: "user-1"}, {GUID: "user-2"}}
+			enqueuer.Enqueue(conn, users, services.Options{}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+
+			Expect(queue.EnqueueCall.Receives.Jobs).To(HaveLen(2))
+			for _, job := range queue.EnqueueCall.Receives.Jobs {
+				Expect(job.Priority).To(Equal(gobble.PriorityNormal))
+			}
+		})
+
+		Context("when the kind is critical", func() {
+			It("enqueues jobs with a critical priority", func() {
+				users := []services.User{{GUID: "user-1"}, {GUID: "user-2"}}
+				enqueuer.Enqueue(conn, users, services.Options{Critical: true}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+
+				Expect(queue.EnqueueCall.Receives.Jobs).To(HaveLen(2))
+				for _, job := range queue.EnqueueCall.Receives.Jobs {
+					Expect(job.Priority).To(Equal(gobble.PriorityCritical))
+				}
+			})
+		})
+
 		It("upserts a StatusQueued for each of the jobs", func() {
 			users := []services.User{{GUID: "user-1"}, {GUID: "user-2"}, {GUID: "user-3"}, {GUID: "user-4"}}
 			enqueuer.Enqueue(conn, users, services.Options{}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
@@ -191,6 +214,16 @@ var _ = Describe("Enqueuer", func() {
 			}))
 		})
 
+		It("records the dispatch on each of the messages", func() {
+			users := []services.User{{GUID: "user-1"}, {GUID: "user-2"}}
+			enqueuer.Enqueue(conn, users, services.Options{DispatchID: "some-dispatch-id"}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+
+			Expect(messagesRepo.UpsertCall.Receives.Messages).To(Equal([]models.Message{
+				{Status: services.StatusQueued, DispatchID: "some-dispatch-id"},
+				{Status: services.StatusQueued, DispatchID: "some-dispatch-id"},
+			}))
+		})
+
 		Context("using a transaction", func() {
 			var users []services.User
 
@@ -36,6 +36,7 @@ func (strategy EveryoneStrategy) Dispatch(dispatch Dispatch) ([]Response, error)
 		Endorsement:       EveryoneEndorsement,
 		KindID:            dispatch.Kind.ID,
 		KindDescription:   dispatch.Kind.Description,
+		Critical:          dispatch.Kind.Critical,
 		SourceDescription: dispatch.Client.Description,
 		Text:              dispatch.Message.Text,
 		TemplateID:        dispatch.TemplateID,
@@ -45,6 +46,9 @@ func (strategy EveryoneStrategy) Dispatch(dispatch Dispatch) ([]Response, error)
 			Head:           dispatch.Message.HTML.Head,
 			Doctype:        dispatch.Message.HTML.Doctype,
 		},
+		Headers:       dispatch.Message.Headers,
+		AttachmentIDs: dispatch.Message.AttachmentIDs,
+		DispatchID:    dispatch.ID,
 	}
 
 	token, err := strategy.tokenLoader.Load(dispatch.UAAHost)
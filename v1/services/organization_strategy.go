@@ -39,6 +39,7 @@ func (strategy OrganizationStrategy) Dispatch(dispatch Dispatch) ([]Response, er
 		Subject:           dispatch.Message.Subject,
 		KindID:            dispatch.Kind.ID,
 		KindDescription:   dispatch.Kind.Description,
+		Critical:          dispatch.Kind.Critical,
 		SourceDescription: dispatch.Client.Description,
 		Endorsement:       OrganizationEndorsement,
 		Text:              dispatch.Message.Text,
@@ -50,6 +51,9 @@ func (strategy OrganizationStrategy) Dispatch(dispatch Dispatch) ([]Response, er
 			Head:           dispatch.Message.HTML.Head,
 			Doctype:        dispatch.Message.HTML.Doctype,
 		},
+		Headers:       dispatch.Message.Headers,
+		AttachmentIDs: dispatch.Message.AttachmentIDs,
+		DispatchID:    dispatch.ID,
 	}
 
 	if dispatch.Role != "" {
@@ -27,19 +27,27 @@ func (updater PreferenceUpdater) Update(conn ConnectionInterface, preferences []
 	}
 
 	for _, preference := range preferences {
-		kind, err := updater.kindsRepo.Find(conn, preference.KindID, preference.ClientID)
-		if err != nil {
-			return MissingKindOrClientError{fmt.Errorf("The kind '%s' cannot be found for client '%s'", preference.KindID, preference.ClientID)}
-		}
-
-		if kind.Critical {
-			return CriticalKindError{fmt.Errorf("The kind '%s' for the '%s' client is critical and cannot be unsubscribed from", preference.KindID, preference.ClientID)}
-		}
-
-		err = updater.unsubscribesRepo.Set(conn, userID, preference.ClientID, preference.KindID, !preference.Email)
+		err = updater.set(conn, userID, preference.ClientID, preference.KindID, !preference.Email)
 		if err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+func (updater PreferenceUpdater) Unsubscribe(conn ConnectionInterface, userID, clientID, kindID string) error {
+	return updater.set(conn, userID, clientID, kindID, true)
+}
+
+func (updater PreferenceUpdater) set(conn ConnectionInterface, userID, clientID, kindID string, unsubscribe bool) error {
+	kind, err := updater.kindsRepo.Find(conn, kindID, clientID)
+	if err != nil {
+		return MissingKindOrClientError{fmt.Errorf("The kind '%s' cannot be found for client '%s'", kindID, clientID)}
+	}
+
+	if kind.Critical {
+		return CriticalKindError{fmt.Errorf("The kind '%s' for the '%s' client is critical and cannot be unsubscribed from", kindID, clientID)}
+	}
+
+	return updater.unsubscribesRepo.Set(conn, userID, clientID, kindID, unsubscribe)
+}
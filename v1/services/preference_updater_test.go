@@ -218,4 +218,69 @@ var _ = Describe("PreferenceUpdater", func() {
 			})
 		})
 	})
+
+	Describe("Unsubscribe", func() {
+		var (
+			unsubscribesRepo *mocks.UnsubscribesRepo
+			kindsRepo        *mocks.KindsRepo
+			conn             *mocks.Connection
+			updater          services.PreferenceUpdater
+		)
+
+		BeforeEach(func() {
+			conn = mocks.NewConnection()
+			unsubscribesRepo = mocks.NewUnsubscribesRepo()
+			kindsRepo = mocks.NewKindsRepo()
+			updater = services.NewPreferenceUpdater(mocks.NewGlobalUnsubscribesRepo(), unsubscribesRepo, kindsRepo)
+
+			kindsRepo.FindCall.Returns.Kinds = []models.Kind{
+				{
+					ID:       "door-open",
+					ClientID: "raptors",
+				},
+			}
+		})
+
+		It("unsubscribes the user from the kind", func() {
+			err := updater.Unsubscribe(conn, "the-user", "raptors", "door-open")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(kindsRepo.FindCall.Receives.KindID).To(Equal("door-open"))
+			Expect(kindsRepo.FindCall.Receives.ClientID).To(Equal("raptors"))
+
+			Expect(unsubscribesRepo.SetCall.Receives.Connection).To(Equal(conn))
+			Expect(unsubscribesRepo.SetCall.Receives.UserID).To(Equal("the-user"))
+			Expect(unsubscribesRepo.SetCall.Receives.ClientID).To(Equal("raptors"))
+			Expect(unsubscribesRepo.SetCall.Receives.KindID).To(Equal("door-open"))
+			Expect(unsubscribesRepo.SetCall.Receives.Unsubscribe).To(BeTrue())
+		})
+
+		Context("when the kind cannot be found", func() {
+			It("returns a MissingKindOrClientError", func() {
+				kindsRepo.FindCall.Returns.Error = errors.New("something bad happened")
+
+				err := updater.Unsubscribe(conn, "the-user", "raptors", "dead")
+				Expect(err).To(MatchError(services.MissingKindOrClientError{errors.New("The kind 'dead' cannot be found for client 'raptors'")}))
+			})
+		})
+
+		Context("when the kind is critical", func() {
+			It("returns a CriticalKindError", func() {
+				kindsRepo.FindCall.Returns.Kinds[0].Critical = true
+
+				err := updater.Unsubscribe(conn, "the-user", "raptors", "door-open")
+				Expect(err).To(MatchError(services.CriticalKindError{errors.New("The kind 'door-open' for the 'raptors' client is critical and cannot be unsubscribed from")}))
+				Expect(unsubscribesRepo.SetCall.Receives.UserID).To(BeEmpty())
+			})
+		})
+
+		Context("when the unsubscribes repo errors", func() {
+			It("returns the error", func() {
+				unsubscribesRepo.SetCall.Returns.Error = errors.New("unsubscribe db error")
+
+				err := updater.Unsubscribe(conn, "the-user", "raptors", "door-open")
+				Expect(err).To(MatchError(errors.New("unsubscribe db error")))
+			})
+		})
+	})
 })
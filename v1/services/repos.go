@@ -28,9 +28,20 @@ type TemplatesRepo interface {
 	Destroy(connection models.ConnectionInterface, templateID string) error
 	FindByID(connection models.ConnectionInterface, templateID string) (models.Template, error)
 	ListIDsAndNames(connection models.ConnectionInterface) ([]models.Template, error)
+	Lock(connection models.ConnectionInterface, templateID string) error
 	Update(connection models.ConnectionInterface, templateID string, template models.Template) (models.Template, error)
 }
 
+type TemplateVersionsRepo interface {
+	Create(connection models.ConnectionInterface, version models.TemplateVersion) (models.TemplateVersion, error)
+	Find(connection models.ConnectionInterface, templateID string, version int) (models.TemplateVersion, error)
+	List(connection models.ConnectionInterface, templateID string) ([]models.TemplateVersion, error)
+}
+
+type PartialsRepo interface {
+	FindAllByClientID(connection models.ConnectionInterface, clientID string) (map[string]string, error)
+}
+
 type UnsubscribesRepo interface {
 	Set(connection models.ConnectionInterface, userID string, clientID string, kindID string, unsubscribe bool) error
 }
@@ -39,6 +39,7 @@ func (strategy SpaceStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
 		Subject:           dispatch.Message.Subject,
 		KindID:            dispatch.Kind.ID,
 		KindDescription:   dispatch.Kind.Description,
+		Critical:          dispatch.Kind.Critical,
 		SourceDescription: dispatch.Client.Description,
 		Endorsement:       SpaceEndorsement,
 		Text:              dispatch.Message.Text,
@@ -50,6 +51,9 @@ func (strategy SpaceStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
 			Head:           dispatch.Message.HTML.Head,
 			Doctype:        dispatch.Message.HTML.Doctype,
 		},
+		Headers:       dispatch.Message.Headers,
+		AttachmentIDs: dispatch.Message.AttachmentIDs,
+		DispatchID:    dispatch.ID,
 	}
 
 	token, err := strategy.tokenLoader.Load(dispatch.UAAHost)
@@ -1,21 +1,109 @@
 package services
 
-import "github.com/cloudfoundry-incubator/notifications/v1/models"
+import (
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
 
 type TemplateUpdater struct {
 	templatesRepo TemplatesRepo
+	versionsRepo  TemplateVersionsRepo
+	partialsRepo  PartialsRepo
 }
 
-func NewTemplateUpdater(templatesRepo TemplatesRepo) TemplateUpdater {
+func NewTemplateUpdater(templatesRepo TemplatesRepo, versionsRepo TemplateVersionsRepo, partialsRepo PartialsRepo) TemplateUpdater {
 	return TemplateUpdater{
 		templatesRepo: templatesRepo,
+		versionsRepo:  versionsRepo,
+		partialsRepo:  partialsRepo,
 	}
 }
 
-func (updater TemplateUpdater) Update(database DatabaseInterface, templateID string, template models.Template) error {
-	_, err := updater.templatesRepo.Update(database.Connection(), templateID, template)
+// Update saves the template and records the result as a new version,
+// attributed to clientID. The partials the template includes are checked
+// against the partials owned by clientID, and are resolved against them
+// whichever client sends with the template. The template is locked while it
+// is saved, so that concurrent updates are numbered one after the other.
+func (updater TemplateUpdater) Update(database DatabaseInterface, templateID string, template models.Template, clientID string) error {
+	conn := database.Connection()
+
+	partials, err := updater.partialsRepo.FindAllByClientID(conn, clientID)
+	if err != nil {
+		return err
+	}
+
+	err = common.ValidateTemplates(template.Subject, template.Text, template.HTML, partials)
+	if err != nil {
+		return err
+	}
+
+	template.PartialsClientID = clientID
+
+	transaction := conn.Transaction()
+	err = transaction.Begin()
+	if err != nil {
+		return err
+	}
+
+	err = updater.templatesRepo.Lock(transaction, templateID)
+	if err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	err = updater.recordOriginalVersion(transaction, templateID)
+	if err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	updated, err := updater.templatesRepo.Update(transaction, templateID, template)
+	if err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	_, err = updater.versionsRepo.Create(transaction, models.NewTemplateVersion(updated, clientID))
+	if err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	return transaction.Commit()
+}
+
+// Rollback restores the template to the content of an earlier version. The
+// rollback is itself recorded as a new version, so it can be undone.
+func (updater TemplateUpdater) Rollback(database DatabaseInterface, templateID string, version int, clientID string) error {
+	templateVersion, err := updater.versionsRepo.Find(database.Connection(), templateID, version)
 	if err != nil {
 		return err
 	}
-	return nil
+
+	return updater.Update(database, templateID, templateVersion.Template(), clientID)
+}
+
+// recordOriginalVersion saves the content a template had before its first
+// tracked update, so that the first update can be rolled back too. Who made
+// that content is not known.
+func (updater TemplateUpdater) recordOriginalVersion(conn models.ConnectionInterface, templateID string) error {
+	versions, err := updater.versionsRepo.List(conn, templateID)
+	if err != nil {
+		return err
+	}
+
+	if len(versions) > 0 {
+		return nil
+	}
+
+	existing, err := updater.templatesRepo.FindByID(conn, templateID)
+	if err != nil {
+		return err
+	}
+
+	original := models.NewTemplateVersion(existing, "")
+	original.CreatedAt = existing.UpdatedAt
+
+	_, err = updater.versionsRepo.Create(conn, original)
+	return err
 }
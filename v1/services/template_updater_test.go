@@ -2,7 +2,9 @@ package services_test
 
 import (
 	"errors"
+	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
@@ -12,45 +14,338 @@ import (
 )
 
 var _ = Describe("Updater", func() {
-	Describe("Update", func() {
-		var (
-			conn          *mocks.Connection
-			database      *mocks.Database
-			templatesRepo *mocks.TemplatesRepo
-			updater       services.TemplateUpdater
-		)
+	var (
+		conn          *mocks.Connection
+		transaction   *mocks.Transaction
+		database      *mocks.Database
+		templatesRepo *mocks.TemplatesRepo
+		versionsRepo  *mocks.TemplateVersionsRepo
+		partialsRepo  *mocks.PartialsRepo
+		updater       services.TemplateUpdater
+	)
 
-		BeforeEach(func() {
-			conn = mocks.NewConnection()
-			database = mocks.NewDatabase()
-			database.ConnectionCall.Returns.Connection = conn
-			templatesRepo = mocks.NewTemplatesRepo()
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+		transaction = mocks.NewTransaction()
+		conn.TransactionCall.Returns.Transaction = transaction
+		templatesRepo = mocks.NewTemplatesRepo()
+		versionsRepo = mocks.NewTemplateVersionsRepo()
+		partialsRepo = mocks.NewPartialsRepo()
 
-			updater = services.NewTemplateUpdater(templatesRepo)
-		})
+		updater = services.NewTemplateUpdater(templatesRepo, versionsRepo, partialsRepo)
+	})
 
+	Describe("Update", func() {
 		It("Inserts templates into the templates repo", func() {
 			err := updater.Update(database, "my-awesome-id", models.Template{
 				Name: "gobble template",
 				Text: "gobble",
 				HTML: "<p>gobble</p>",
-			})
+			}, "some-client-id")
 			Expect(err).ToNot(HaveOccurred())
 
-			Expect(templatesRepo.UpdateCall.Receives.Connection).To(Equal(conn))
+			Expect(transaction.BeginCall.WasCalled).To(BeTrue())
+			Expect(templatesRepo.LockCall.Receives.Connection).To(Equal(transaction))
+			Expect(templatesRepo.LockCall.Receives.TemplateID).To(Equal("my-awesome-id"))
+			Expect(templatesRepo.UpdateCall.Receives.Connection).To(Equal(transaction))
 			Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(Equal("my-awesome-id"))
 			Expect(templatesRepo.UpdateCall.Receives.Template).To(Equal(models.Template{
 				Name: "gobble template",
 				Text: "gobble",
 				HTML: "<p>gobble</p>",
+
+				PartialsClientID: "some-client-id",
 			}))
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
 		})
 
 		It("propagates errors from repo", func() {
 			templatesRepo.UpdateCall.Returns.Error = errors.New("Boom!")
 
-			err := updater.Update(database, "unimportant", models.Template{})
+			err := updater.Update(database, "unimportant", models.Template{}, "some-client-id")
+			Expect(err).To(MatchError(errors.New("Boom!")))
+
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+
+		It("propagates errors locking the template", func() {
+			templatesRepo.LockCall.Returns.Error = errors.New("lock wait timeout")
+
+			err := updater.Update(database, "my-awesome-id", models.Template{}, "some-client-id")
+			Expect(err).To(MatchError(errors.New("lock wait timeout")))
+
+			Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(BeEmpty())
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+		})
+
+		It("propagates errors beginning the transaction", func() {
+			transaction.BeginCall.Returns.Error = errors.New("cannot begin")
+
+			err := updater.Update(database, "my-awesome-id", models.Template{}, "some-client-id")
+			Expect(err).To(MatchError(errors.New("cannot begin")))
+
+			Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(BeEmpty())
+		})
+
+		It("propagates errors committing the transaction", func() {
+			transaction.CommitCall.Returns.Error = errors.New("cannot commit")
+
+			err := updater.Update(database, "my-awesome-id", models.Template{}, "some-client-id")
+			Expect(err).To(MatchError(errors.New("cannot commit")))
+		})
+
+		It("rejects a template with invalid syntax", func() {
+			err := updater.Update(database, "my-awesome-id", models.Template{
+				Name:    "gobble template",
+				Subject: "{{if .Subject}}gobble",
+				Text:    "gobble",
+				HTML:    "<p>gobble</p>",
+			}, "some-client-id")
+			Expect(err).To(BeAssignableToTypeOf(common.TemplateError{}))
+			Expect(err.Error()).To(HavePrefix("subject template is invalid at line 1"))
+
+			Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(BeEmpty())
+		})
+
+		It("accepts a template that includes a partial owned by the client", func() {
+			partialsRepo.FindAllByClientIDCall.Returns.Partials = map[string]string{
+				"footer": "<footer>{{.Organization}}</footer>",
+			}
+
+			err := updater.Update(database, "my-awesome-id", models.Template{
+				Name: "gobble template",
+				Text: "gobble",
+				HTML: `<p>gobble</p>{{template "footer" .}}`,
+			}, "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(partialsRepo.FindAllByClientIDCall.Receives.Connection).To(Equal(conn))
+			Expect(partialsRepo.FindAllByClientIDCall.Receives.ClientID).To(Equal("some-client-id"))
+			Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(Equal("my-awesome-id"))
+		})
+
+		It("rejects a template that includes a partial the client does not have", func() {
+			err := updater.Update(database, "my-awesome-id", models.Template{
+				Name: "gobble template",
+				Text: "gobble",
+				HTML: `<p>gobble</p>{{template "footer" .}}`,
+			}, "some-client-id")
+			Expect(err).To(BeAssignableToTypeOf(common.TemplateError{}))
+			Expect(err.Error()).To(ContainSubstring(`unknown partial "footer"`))
+
+			Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(BeEmpty())
+		})
+
+		It("propagates errors from the partials repo", func() {
+			partialsRepo.FindAllByClientIDCall.Returns.Error = errors.New("Boom!")
+
+			err := updater.Update(database, "my-awesome-id", models.Template{}, "some-client-id")
 			Expect(err).To(MatchError(errors.New("Boom!")))
+
+			Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(BeEmpty())
+		})
+
+		Context("when the template already has versions", func() {
+			BeforeEach(func() {
+				versionsRepo.ListCall.Returns.Versions = []models.TemplateVersion{
+					{TemplateID: "my-awesome-id", Version: 1},
+				}
+
+				templatesRepo.UpdateCall.Returns.Template = models.Template{
+					ID:       "my-awesome-id",
+					Name:     "gobble template",
+					Subject:  "{{.Subject}}",
+					Text:     "gobble",
+					HTML:     "<p>gobble</p>",
+					Metadata: "{}",
+				}
+			})
+
+			It("records the updated template as a new version", func() {
+				err := updater.Update(database, "my-awesome-id", models.Template{
+					Name: "gobble template",
+					Text: "gobble",
+					HTML: "<p>gobble</p>",
+				}, "some-client-id")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(versionsRepo.ListCall.Receives.TemplateID).To(Equal("my-awesome-id"))
+				Expect(versionsRepo.ListCall.Receives.Connection).To(Equal(transaction))
+				Expect(versionsRepo.CreateCall.Receives.Connection).To(Equal(transaction))
+				Expect(versionsRepo.CreateCall.Receives.Versions).To(Equal([]models.TemplateVersion{
+					{
+						TemplateID: "my-awesome-id",
+						Name:       "gobble template",
+						Subject:    "{{.Subject}}",
+						Text:       "gobble",
+						HTML:       "<p>gobble</p>",
+						Metadata:   "{}",
+						ClientID:   "some-client-id",
+					},
+				}))
+			})
+
+			It("propagates errors from the versions repo", func() {
+				versionsRepo.CreateCall.Returns.Error = errors.New("versions are broken")
+
+				err := updater.Update(database, "my-awesome-id", models.Template{}, "some-client-id")
+				Expect(err).To(MatchError(errors.New("versions are broken")))
+			})
+		})
+
+		Context("when the template has no versions yet", func() {
+			var updatedAt time.Time
+
+			BeforeEach(func() {
+				updatedAt = time.Now().Add(-24 * time.Hour).Truncate(1 * time.Second).UTC()
+
+				templatesRepo.FindByIDCall.Returns.Template = models.Template{
+					ID:        "my-awesome-id",
+					Name:      "original template",
+					Subject:   "{{.Subject}}",
+					Text:      "original",
+					HTML:      "<p>original</p>",
+					Metadata:  "{}",
+					UpdatedAt: updatedAt,
+				}
+
+				templatesRepo.UpdateCall.Returns.Template = models.Template{
+					ID:       "my-awesome-id",
+					Name:     "gobble template",
+					Subject:  "{{.Subject}}",
+					Text:     "gobble",
+					HTML:     "<p>gobble</p>",
+					Metadata: "{}",
+				}
+			})
+
+			It("records the original template before the update", func() {
+				err := updater.Update(database, "my-awesome-id", models.Template{
+					Name: "gobble template",
+					Text: "gobble",
+					HTML: "<p>gobble</p>",
+				}, "some-client-id")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(templatesRepo.FindByIDCall.Receives.TemplateID).To(Equal("my-awesome-id"))
+				Expect(versionsRepo.CreateCall.Receives.Versions).To(Equal([]models.TemplateVersion{
+					{
+						TemplateID: "my-awesome-id",
+						Name:       "original template",
+						Subject:    "{{.Subject}}",
+						Text:       "original",
+						HTML:       "<p>original</p>",
+						Metadata:   "{}",
+						CreatedAt:  updatedAt,
+					},
+					{
+						TemplateID: "my-awesome-id",
+						Name:       "gobble template",
+						Subject:    "{{.Subject}}",
+						Text:       "gobble",
+						HTML:       "<p>gobble</p>",
+						Metadata:   "{}",
+						ClientID:   "some-client-id",
+					},
+				}))
+			})
+
+			It("propagates errors finding the original template", func() {
+				templatesRepo.FindByIDCall.Returns.Error = models.NotFoundError{errors.New("not found")}
+
+				err := updater.Update(database, "my-awesome-id", models.Template{}, "some-client-id")
+				Expect(err).To(MatchError(models.NotFoundError{errors.New("not found")}))
+
+				Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(BeEmpty())
+			})
+		})
+
+		It("propagates errors listing the versions", func() {
+			versionsRepo.ListCall.Returns.Error = errors.New("cannot list")
+
+			err := updater.Update(database, "my-awesome-id", models.Template{}, "some-client-id")
+			Expect(err).To(MatchError(errors.New("cannot list")))
+
+			Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(BeEmpty())
+		})
+	})
+
+	Describe("Rollback", func() {
+		BeforeEach(func() {
+			versionsRepo.ListCall.Returns.Versions = []models.TemplateVersion{
+				{TemplateID: "my-awesome-id", Version: 2},
+				{TemplateID: "my-awesome-id", Version: 1},
+			}
+
+			versionsRepo.FindCall.Returns.Version = models.TemplateVersion{
+				TemplateID: "my-awesome-id",
+				Version:    1,
+				Name:       "original template",
+				Subject:    "{{.Subject}}",
+				Text:       "original",
+				HTML:       "<p>original</p>",
+				Metadata:   "{}",
+				ClientID:   "some-other-client-id",
+			}
+
+			templatesRepo.UpdateCall.Returns.Template = models.Template{
+				ID:       "my-awesome-id",
+				Name:     "original template",
+				Subject:  "{{.Subject}}",
+				Text:     "original",
+				HTML:     "<p>original</p>",
+				Metadata: "{}",
+			}
+		})
+
+		It("restores the template to the content of the version", func() {
+			err := updater.Rollback(database, "my-awesome-id", 1, "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(versionsRepo.FindCall.Receives.Connection).To(Equal(conn))
+			Expect(versionsRepo.FindCall.Receives.TemplateID).To(Equal("my-awesome-id"))
+			Expect(versionsRepo.FindCall.Receives.Version).To(Equal(1))
+
+			Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(Equal("my-awesome-id"))
+			Expect(templatesRepo.UpdateCall.Receives.Template).To(Equal(models.Template{
+				ID:       "my-awesome-id",
+				Name:     "original template",
+				Subject:  "{{.Subject}}",
+				Text:     "original",
+				HTML:     "<p>original</p>",
+				Metadata: "{}",
+
+				PartialsClientID: "some-client-id",
+			}))
+		})
+
+		It("records the rollback as a new version made by the client", func() {
+			err := updater.Rollback(database, "my-awesome-id", 1, "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(versionsRepo.CreateCall.Receives.Versions).To(Equal([]models.TemplateVersion{
+				{
+					TemplateID: "my-awesome-id",
+					Name:       "original template",
+					Subject:    "{{.Subject}}",
+					Text:       "original",
+					HTML:       "<p>original</p>",
+					Metadata:   "{}",
+					ClientID:   "some-client-id",
+				},
+			}))
+		})
+
+		It("returns an error when the version cannot be found", func() {
+			versionsRepo.FindCall.Returns.Error = models.NotFoundError{errors.New("no such version")}
+
+			err := updater.Rollback(database, "my-awesome-id", 7, "some-client-id")
+			Expect(err).To(MatchError(models.NotFoundError{errors.New("no such version")}))
+
+			Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(BeEmpty())
 		})
 	})
 })
@@ -0,0 +1,32 @@
+package services
+
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
+type TemplateVersionFinder struct {
+	templatesRepo TemplatesRepo
+	versionsRepo  TemplateVersionsRepo
+}
+
+func NewTemplateVersionFinder(templatesRepo TemplatesRepo, versionsRepo TemplateVersionsRepo) TemplateVersionFinder {
+	return TemplateVersionFinder{
+		templatesRepo: templatesRepo,
+		versionsRepo:  versionsRepo,
+	}
+}
+
+// List returns the recorded versions of a template, newest first. A
+// template that has never been updated has no versions.
+func (finder TemplateVersionFinder) List(database DatabaseInterface, templateID string) ([]models.TemplateVersion, error) {
+	conn := database.Connection()
+
+	_, err := finder.templatesRepo.FindByID(conn, templateID)
+	if err != nil {
+		return []models.TemplateVersion{}, err
+	}
+
+	return finder.versionsRepo.List(conn, templateID)
+}
+
+func (finder TemplateVersionFinder) Find(database DatabaseInterface, templateID string, version int) (models.TemplateVersion, error) {
+	return finder.versionsRepo.Find(database.Connection(), templateID, version)
+}
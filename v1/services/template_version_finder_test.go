@@ -0,0 +1,96 @@
+package services_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplateVersionFinder", func() {
+	var (
+		finder        services.TemplateVersionFinder
+		templatesRepo *mocks.TemplatesRepo
+		versionsRepo  *mocks.TemplateVersionsRepo
+		database      *mocks.Database
+		conn          *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		templatesRepo = mocks.NewTemplatesRepo()
+		versionsRepo = mocks.NewTemplateVersionsRepo()
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		finder = services.NewTemplateVersionFinder(templatesRepo, versionsRepo)
+	})
+
+	Describe("List", func() {
+		It("returns the versions of the template", func() {
+			versionsRepo.ListCall.Returns.Versions = []models.TemplateVersion{
+				{TemplateID: "some-template-id", Version: 2, Name: "second"},
+				{TemplateID: "some-template-id", Version: 1, Name: "first"},
+			}
+
+			versions, err := finder.List(database, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(Equal([]models.TemplateVersion{
+				{TemplateID: "some-template-id", Version: 2, Name: "second"},
+				{TemplateID: "some-template-id", Version: 1, Name: "first"},
+			}))
+
+			Expect(templatesRepo.FindByIDCall.Receives.Connection).To(Equal(conn))
+			Expect(templatesRepo.FindByIDCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(versionsRepo.ListCall.Receives.Connection).To(Equal(conn))
+			Expect(versionsRepo.ListCall.Receives.TemplateID).To(Equal("some-template-id"))
+		})
+
+		It("returns an error when the template does not exist", func() {
+			templatesRepo.FindByIDCall.Returns.Error = models.NotFoundError{errors.New("not found")}
+
+			_, err := finder.List(database, "missing-template-id")
+			Expect(err).To(MatchError(models.NotFoundError{errors.New("not found")}))
+		})
+
+		It("propagates errors from the versions repo", func() {
+			versionsRepo.ListCall.Returns.Error = errors.New("cannot list")
+
+			_, err := finder.List(database, "some-template-id")
+			Expect(err).To(MatchError(errors.New("cannot list")))
+		})
+	})
+
+	Describe("Find", func() {
+		It("returns the requested version", func() {
+			versionsRepo.FindCall.Returns.Version = models.TemplateVersion{
+				TemplateID: "some-template-id",
+				Version:    3,
+				Name:       "third",
+			}
+
+			version, err := finder.Find(database, "some-template-id", 3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal(models.TemplateVersion{
+				TemplateID: "some-template-id",
+				Version:    3,
+				Name:       "third",
+			}))
+
+			Expect(versionsRepo.FindCall.Receives.Connection).To(Equal(conn))
+			Expect(versionsRepo.FindCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(versionsRepo.FindCall.Receives.Version).To(Equal(3))
+		})
+
+		It("propagates errors from the versions repo", func() {
+			versionsRepo.FindCall.Returns.Error = models.NotFoundError{errors.New("no such version")}
+
+			_, err := finder.Find(database, "some-template-id", 3)
+			Expect(err).To(MatchError(models.NotFoundError{errors.New("no such version")}))
+		})
+	})
+})
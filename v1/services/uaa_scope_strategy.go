@@ -33,6 +33,7 @@ func (strategy UAAScopeStrategy) Dispatch(dispatch Dispatch) ([]Response, error)
 		Endorsement:       ScopeEndorsement,
 		KindID:            dispatch.Kind.ID,
 		KindDescription:   dispatch.Kind.Description,
+		Critical:          dispatch.Kind.Critical,
 		SourceDescription: dispatch.Client.Description,
 		Text:              dispatch.Message.Text,
 		TemplateID:        dispatch.TemplateID,
@@ -42,6 +43,9 @@ func (strategy UAAScopeStrategy) Dispatch(dispatch Dispatch) ([]Response, error)
 			Head:           dispatch.Message.HTML.Head,
 			Doctype:        dispatch.Message.HTML.Doctype,
 		},
+		Headers:       dispatch.Message.Headers,
+		AttachmentIDs: dispatch.Message.AttachmentIDs,
+		DispatchID:    dispatch.ID,
 	}
 
 	if strategy.scopeIsDefault(dispatch.GUID) {
@@ -50,6 +50,7 @@ var _ = Describe("UserStrategy", func() {
 					Kind: services.DispatchKind{
 						ID:          "forgot_waterbottle",
 						Description: "Water Bottle Reminder",
+						Critical:    true,
 					},
 					Client: services.DispatchClient{
 						ID:          "mister-client",
@@ -82,6 +83,7 @@ var _ = Describe("UserStrategy", func() {
 						Doctype:        "<html>",
 					},
 					Endorsement: services.UserEndorsement,
+					Critical:    true,
 				}))
 				Expect(enqueuer.EnqueueCall.Receives.Space).To(Equal(cf.CloudControllerSpace{}))
 				Expect(enqueuer.EnqueueCall.Receives.Org).To(Equal(cf.CloudControllerOrganization{}))
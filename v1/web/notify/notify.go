@@ -24,15 +24,24 @@ type registrar interface {
 	Prune(services.ConnectionInterface, models.Client, []models.Kind) error
 }
 
+type attachmentsRepo interface {
+	Create(models.ConnectionInterface, models.Attachment) (models.Attachment, error)
+	Delete(models.ConnectionInterface, string) error
+}
+
 type Notify struct {
-	finder    clientAndKindFinder
-	registrar registrar
+	finder      clientAndKindFinder
+	registrar   registrar
+	attachments attachmentsRepo
+	generateID  models.IDGeneratorFunc
 }
 
-func NewNotify(finder clientAndKindFinder, registrar registrar) Notify {
+func NewNotify(finder clientAndKindFinder, registrar registrar, attachments attachmentsRepo, guidGenerator models.IDGeneratorFunc) Notify {
 	return Notify{
-		finder:    finder,
-		registrar: registrar,
+		finder:      finder,
+		registrar:   registrar,
+		attachments: attachments,
+		generateID:  guidGenerator,
 	}
 }
 
@@ -79,9 +88,37 @@ func (h Notify) Execute(connection ConnectionInterface, req *http.Request, conte
 		return []byte{}, err
 	}
 
+	// The attachments are owned by the dispatch, which records its ID on
+	// every message it creates, so that they outlive the retries of those
+	// messages.
+	var dispatchID string
+	if len(parameters.Attachments) > 0 {
+		dispatchID, err = h.generateID()
+		if err != nil {
+			return []byte{}, err
+		}
+	}
+
+	var attachmentIDs []string
+	for _, attachment := range parameters.Attachments {
+		created, err := h.attachments.Create(connection, models.Attachment{
+			DispatchID:  dispatchID,
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			Content:     attachment.Content,
+		})
+		if err != nil {
+			h.deleteAttachments(connection, attachmentIDs)
+			return []byte{}, err
+		}
+
+		attachmentIDs = append(attachmentIDs, created.ID)
+	}
+
 	var responses []services.Response
 
 	responses, err = strategy.Dispatch(services.Dispatch{
+		ID:         dispatchID,
 		GUID:       guid,
 		Connection: connection,
 		Role:       parameters.Role,
@@ -92,6 +129,7 @@ func (h Notify) Execute(connection ConnectionInterface, req *http.Request, conte
 		Kind: services.DispatchKind{
 			ID:          parameters.KindID,
 			Description: kind.Description,
+			Critical:    kind.Critical,
 		},
 		UAAHost: uaaHost,
 		VCAPRequest: services.DispatchVCAPRequest{
@@ -99,7 +137,9 @@ func (h Notify) Execute(connection ConnectionInterface, req *http.Request, conte
 			ReceiptTime: requestReceivedTime,
 		},
 		Message: services.DispatchMessage{
-			To:      parameters.To,
+			To:      strings.Join(parameters.To, ", "),
+			Cc:      parameters.Cc,
+			Bcc:     parameters.Bcc,
 			ReplyTo: parameters.ReplyTo,
 			Subject: parameters.Subject,
 			Text:    parameters.Text,
@@ -109,9 +149,12 @@ func (h Notify) Execute(connection ConnectionInterface, req *http.Request, conte
 				Head:           parameters.ParsedHTML.Head,
 				Doctype:        parameters.ParsedHTML.Doctype,
 			},
+			Headers:       parameters.Headers,
+			AttachmentIDs: attachmentIDs,
 		},
 	})
 	if err != nil {
+		h.deleteAttachments(connection, attachmentIDs)
 		return []byte{}, err
 	}
 
@@ -123,6 +166,15 @@ func (h Notify) Execute(connection ConnectionInterface, req *http.Request, conte
 	return output, nil
 }
 
+// deleteAttachments removes the attachments of a notification that was not
+// dispatched, since no delivery will ever load them. Any that cannot be
+// deleted are left for the message GC.
+func (h Notify) deleteAttachments(connection ConnectionInterface, ids []string) {
+	for _, id := range ids {
+		h.attachments.Delete(connection, id)
+	}
+}
+
 func (h Notify) hasCriticalNotificationsWriteScope(elements interface{}) bool {
 	for _, elem := range elements.([]interface{}) {
 		if elem.(string) == "critical_notifications.write" {
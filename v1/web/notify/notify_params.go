@@ -24,15 +24,52 @@ type NotifyParams struct {
 	Text    string `json:"text"`
 	RawHTML string `json:"html"`
 	KindID  string `json:"kind_id"`
-	To      string `json:"to"`
 	Role    string `json:"role"`
 
+	To          EmailList         `json:"to"`
+	Cc          []string          `json:"cc"`
+	Bcc         []string          `json:"bcc"`
+	Headers     map[string]string `json:"headers"`
+	Attachments []Attachment      `json:"attachments"`
+
 	ParsedHTML        HTML
 	KindDescription   string
 	SourceDescription string
 	Errors            []string
 }
 
+// EmailList holds the "to" addresses, which can be given as a single address
+// or as a list of addresses.
+type EmailList []string
+
+func (list *EmailList) UnmarshalJSON(data []byte) error {
+	var email string
+	if err := json.Unmarshal(data, &email); err == nil {
+		*list = nil
+		if email != "" {
+			*list = EmailList{email}
+		}
+		return nil
+	}
+
+	var emails []string
+	err := json.Unmarshal(data, &emails)
+	if err != nil {
+		return err
+	}
+
+	*list = emails
+	return nil
+}
+
+// Attachment is a file sent along with the notification. Content is base64
+// encoded in the request body.
+type Attachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Content     []byte `json:"content"`
+}
+
 type HTML struct {
 	BodyContent    string
 	BodyAttributes string
@@ -71,7 +108,15 @@ func (notify *NotifyParams) parseRequestBody(body io.ReadCloser) error {
 }
 
 func (notify *NotifyParams) FormatEmailAndExtractHTML() error {
-	notify.To = EmailFormatter{}.Format(notify.To)
+	for i, email := range notify.To {
+		notify.To[i] = EmailFormatter{}.Format(email)
+	}
+	for i, email := range notify.Cc {
+		notify.Cc[i] = EmailFormatter{}.Format(email)
+	}
+	for i, email := range notify.Bcc {
+		notify.Bcc[i] = EmailFormatter{}.Format(email)
+	}
 
 	doctype, head, bodyContent, bodyAttributes, err := HTMLExtractor{}.Extract(notify.RawHTML)
 	if err != nil {
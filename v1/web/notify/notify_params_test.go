@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/cloudfoundry-incubator/notifications/v1/web/notify"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -30,6 +31,31 @@ var _ = Describe("NotifyParams", func() {
 			Expect(parameters.Text).To(Equal("Contents of the email message"))
 		})
 
+		It("parses the cc and bcc addresses", func() {
+			parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+                "to": "me@example.com",
+                "cc": ["Shared Inbox <shared@example.com>", "not-an-address"],
+                "bcc": ["audit@example.com"]
+            }`)))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(parameters.Cc).To(Equal([]string{"shared@example.com", notify.InvalidEmail}))
+			Expect(parameters.Bcc).To(Equal([]string{"audit@example.com"}))
+		})
+
+		It("decodes base64 attachment content", func() {
+			parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+                "kind_id": "test_email",
+                "text": "Contents of the email message",
+                "attachments": [{"filename": "report.pdf", "content_type": "application/pdf", "content": "JVBERg=="}]
+            }`)))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(parameters.Attachments).To(Equal([]notify.Attachment{
+				{Filename: "report.pdf", ContentType: "application/pdf", Content: []byte("%PDF")},
+			}))
+		})
+
 		It("does not blow up if the request body is empty", func() {
 			Expect(func() {
 				notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader("")))
@@ -42,7 +68,7 @@ var _ = Describe("NotifyParams", func() {
 					"to": "The User <user@example.com>"
 				}`)))
 				Expect(err).NotTo(HaveOccurred())
-				Expect(parameters.To).To(Equal("user@example.com"))
+				Expect(parameters.To).To(Equal(notify.EmailList{"user@example.com"}))
 			})
 
 			It("populates the To field with the parsed email address", func() {
@@ -50,7 +76,7 @@ var _ = Describe("NotifyParams", func() {
                     "to": "user@example.com"
 				}`)))
 				Expect(err).NotTo(HaveOccurred())
-				Expect(parameters.To).To(Equal("user@example.com"))
+				Expect(parameters.To).To(Equal(notify.EmailList{"user@example.com"}))
 			})
 
 			It("sets the to field to InvalidEmail cannot be parsed", func() {
@@ -58,7 +84,7 @@ var _ = Describe("NotifyParams", func() {
                     "to": "<The User"
 				}`)))
 				Expect(err).NotTo(HaveOccurred())
-				Expect(parameters.To).To(Equal(notify.InvalidEmail))
+				Expect(parameters.To).To(Equal(notify.EmailList{notify.InvalidEmail}))
 			})
 
 			It("Sets the To field to empty of if it is not specified", func() {
@@ -66,7 +92,22 @@ var _ = Describe("NotifyParams", func() {
                     "to": ""
 				}`)))
 				Expect(err).NotTo(HaveOccurred())
-				Expect(parameters.To).To(Equal(""))
+				Expect(parameters.To).To(BeEmpty())
+			})
+
+			It("accepts a list of addresses", func() {
+				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+					"to": ["The User <user@example.com>", "other-user@example.com", "<The User"]
+				}`)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.To).To(Equal(notify.EmailList{"user@example.com", "other-user@example.com", notify.InvalidEmail}))
+			})
+
+			It("returns a parse error when the field is neither an address nor a list", func() {
+				_, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+					"to": 42
+				}`)))
+				Expect(err).To(MatchError(webutil.ParseError{}))
 			})
 		})
 
@@ -215,7 +256,7 @@ var _ = Describe("NotifyParams", func() {
 							"text": "Contents of the email message"
 						}`)))
 						Expect(err).NotTo(HaveOccurred())
-						Expect(parameters.To).To(Equal(notify.InvalidEmail))
+						Expect(parameters.To).To(Equal(notify.EmailList{notify.InvalidEmail}))
 					})
 				})
 
@@ -226,7 +267,7 @@ var _ = Describe("NotifyParams", func() {
 							"text": "Contents of the email message"
 						}`)))
 						Expect(err).NotTo(HaveOccurred())
-						Expect(parameters.To).To(Equal(notify.InvalidEmail))
+						Expect(parameters.To).To(Equal(notify.EmailList{notify.InvalidEmail}))
 					})
 				})
 			})
@@ -1,6 +1,10 @@
 package notify
 
-import "regexp"
+import (
+	"regexp"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+)
 
 var kindIDFormat = regexp.MustCompile(`^[0-9a-zA-Z_\-.]+$`)
 
@@ -9,18 +13,29 @@ type EmailValidator struct{}
 func (validator EmailValidator) Validate(notify *NotifyParams) bool {
 	notify.Errors = []string{}
 
-	if notify.To == "" {
+	if len(notify.To) == 0 {
 		notify.Errors = append(notify.Errors, `"to" is a required field`)
 	}
 
-	if notify.To == InvalidEmail {
+	if containsInvalidEmail(notify.To) {
 		notify.Errors = append(notify.Errors, `"to" is improperly formatted`)
 	}
 
+	if containsInvalidEmail(notify.Cc) {
+		notify.Errors = append(notify.Errors, `"cc" is improperly formatted`)
+	}
+
+	if containsInvalidEmail(notify.Bcc) {
+		notify.Errors = append(notify.Errors, `"bcc" is improperly formatted`)
+	}
+
 	if missingTextOrHTMLFields(notify) {
 		notify.Errors = append(notify.Errors, `"text" or "html" fields must be supplied`)
 	}
 
+	checkHeaders(notify)
+	checkAttachments(notify)
+
 	return len(notify.Errors) == 0
 }
 
@@ -39,6 +54,13 @@ func (validator GUIDValidator) Validate(notify *NotifyParams) bool {
 		notify.Errors = append(notify.Errors, `"role" must be "OrgManager", "OrgAuditor", "BillingManager" or unset`)
 	}
 
+	if len(notify.Cc) > 0 || len(notify.Bcc) > 0 {
+		notify.Errors = append(notify.Errors, `"cc" and "bcc" can only be used when sending to an email address`)
+	}
+
+	checkHeaders(notify)
+	checkAttachments(notify)
+
 	return len(notify.Errors) == 0
 }
 
@@ -46,6 +68,39 @@ func missingTextOrHTMLFields(notify *NotifyParams) bool {
 	return notify.Text == "" && notify.ParsedHTML.BodyContent == ""
 }
 
+func containsInvalidEmail(emails []string) bool {
+	for _, email := range emails {
+		if email == "" || email == InvalidEmail {
+			return true
+		}
+	}
+
+	return false
+}
+
+func checkHeaders(notify *NotifyParams) {
+	err := mail.ValidateHeaders(notify.Headers)
+	if err != nil {
+		notify.Errors = append(notify.Errors, `"headers" are invalid: `+err.Error())
+	}
+}
+
+func checkAttachments(notify *NotifyParams) {
+	var attachments []mail.Attachment
+	for _, attachment := range notify.Attachments {
+		attachments = append(attachments, mail.Attachment{
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			Content:     attachment.Content,
+		})
+	}
+
+	err := mail.ValidateAttachments(attachments)
+	if err != nil {
+		notify.Errors = append(notify.Errors, `"attachments" are invalid: `+err.Error())
+	}
+}
+
 func (validator GUIDValidator) invalidRoleField(roleName string) bool {
 	if roleName == "" {
 		return false
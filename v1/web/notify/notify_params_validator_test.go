@@ -17,7 +17,7 @@ var _ = Describe("Validator", func() {
 		BeforeEach(func() {
 			params = &notify.NotifyParams{
 				Text: "my silly text",
-				To:   "bob@example.com",
+				To:   notify.EmailList{"bob@example.com"},
 			}
 			validator = notify.EmailValidator{}
 		})
@@ -27,7 +27,7 @@ var _ = Describe("Validator", func() {
 				Expect(validator.Validate(params)).To(BeTrue())
 				Expect(len(params.Errors)).To(Equal(0))
 
-				params.To = ""
+				params.To = nil
 
 				Expect(validator.Validate(params)).To(BeFalse())
 				Expect(len(params.Errors)).To(Equal(1))
@@ -40,16 +40,49 @@ var _ = Describe("Validator", func() {
 				Expect(params.Errors).To(ContainElement(`"to" is a required field`))
 				Expect(params.Errors).To(ContainElement(`"text" or "html" fields must be supplied`))
 
-				params.To = "otherUser@example.com"
+				params.To = notify.EmailList{"otherUser@example.com"}
 				params.ParsedHTML = notify.HTML{BodyContent: "<p>Contents of this email message</p>"}
 
 				Expect(validator.Validate(params)).To(BeTrue())
 				Expect(len(params.Errors)).To(Equal(0))
 			})
 
+			It("validates the headers", func() {
+				params.Headers = map[string]string{"X-Ticket-ID": "TICKET-1234\nBcc: everyone@example.com"}
+
+				Expect(validator.Validate(params)).To(BeFalse())
+				Expect(params.Errors).To(ConsistOf(`"headers" are invalid: header "X-Ticket-ID" has an invalid value`))
+			})
+
+			It("validates the cc and bcc addresses", func() {
+				params.Cc = []string{"shared@example.com"}
+				params.Bcc = []string{"audit@example.com"}
+
+				Expect(validator.Validate(params)).To(BeTrue())
+				Expect(len(params.Errors)).To(Equal(0))
+
+				params.Cc = []string{notify.InvalidEmail}
+				params.Bcc = []string{""}
+
+				Expect(validator.Validate(params)).To(BeFalse())
+				Expect(params.Errors).To(ConsistOf(`"cc" is improperly formatted`, `"bcc" is improperly formatted`))
+			})
+
+			It("validates the attachments", func() {
+				params.Attachments = []notify.Attachment{{Filename: "report.pdf", Content: []byte("%PDF")}}
+
+				Expect(validator.Validate(params)).To(BeTrue())
+				Expect(len(params.Errors)).To(Equal(0))
+
+				params.Attachments = []notify.Attachment{{Content: []byte("%PDF")}}
+
+				Expect(validator.Validate(params)).To(BeFalse())
+				Expect(params.Errors).To(ConsistOf(`"attachments" are invalid: attachment is missing a filename`))
+			})
+
 			Context("When the notify params object finds an invalid email", func() {
 				It("Reports a validation error", func() {
-					params.To = notify.InvalidEmail
+					params.To = notify.EmailList{"bob@example.com", notify.InvalidEmail}
 
 					Expect(validator.Validate(params)).To(BeFalse())
 					Expect(len(params.Errors)).To(Equal(1))
@@ -124,6 +157,32 @@ var _ = Describe("Validator", func() {
 				Expect(len(params.Errors)).To(Equal(1))
 				Expect(params.Errors).To(ContainElement(`"role" must be "OrgManager", "OrgAuditor", "BillingManager" or unset`))
 			})
+
+			It("validates the headers", func() {
+				params.Headers = map[string]string{"X-Ticket-ID": "TICKET-1234"}
+
+				Expect(validator.Validate(params)).To(BeTrue())
+				Expect(len(params.Errors)).To(Equal(0))
+
+				params.Headers = map[string]string{"Subject": "something else"}
+
+				Expect(validator.Validate(params)).To(BeFalse())
+				Expect(params.Errors).To(ConsistOf(`"headers" are invalid: header "Subject" cannot be set`))
+			})
+
+			It("does not allow cc or bcc addresses", func() {
+				params.Cc = []string{"shared@example.com"}
+
+				Expect(validator.Validate(params)).To(BeFalse())
+				Expect(params.Errors).To(ConsistOf(`"cc" and "bcc" can only be used when sending to an email address`))
+			})
+
+			It("validates the attachments", func() {
+				params.Attachments = []notify.Attachment{{Filename: "report.pdf", ContentType: "not a type", Content: []byte("%PDF")}}
+
+				Expect(validator.Validate(params)).To(BeFalse())
+				Expect(params.Errors).To(ConsistOf(`"attachments" are invalid: attachment "report.pdf" has an invalid content type "not a type"`))
+			})
 		})
 	})
 })
@@ -29,6 +29,8 @@ var _ = Describe("Notify", func() {
 				finder          *mocks.NotificationsFinder
 				validator       *mocks.Validator
 				registrar       *mocks.Registrar
+				attachmentsRepo *mocks.AttachmentsRepo
+				guidGenerator   *mocks.IDGenerator
 				request         *http.Request
 				rawToken        string
 				client          models.Client
@@ -59,6 +61,9 @@ var _ = Describe("Notify", func() {
 				finder.ClientAndKindCall.Returns.Kind = kind
 
 				registrar = mocks.NewRegistrar()
+				attachmentsRepo = mocks.NewAttachmentsRepo()
+				guidGenerator = mocks.NewIDGenerator()
+				guidGenerator.GenerateCall.Returns.IDs = []string{"some-dispatch-id"}
 
 				body, err := json.Marshal(map[string]string{
 					"kind_id":  "test_email",
@@ -108,7 +113,7 @@ var _ = Describe("Notify", func() {
 				validator = mocks.NewValidator()
 				validator.ValidateCall.Returns.Valid = true
 
-				handler = notify.NewNotify(finder, registrar)
+				handler = notify.NewNotify(finder, registrar, attachmentsRepo, guidGenerator.Generate)
 			})
 
 			It("delegates to the strategy", func() {
@@ -126,6 +131,7 @@ var _ = Describe("Notify", func() {
 					Kind: services.DispatchKind{
 						ID:          "test_email",
 						Description: "Instance Down",
+						Critical:    true,
 					},
 					UAAHost: "http://zone-uaa-host",
 					VCAPRequest: services.DispatchVCAPRequest{
@@ -159,6 +165,132 @@ var _ = Describe("Notify", func() {
 				Expect(registrar.RegisterCall.Receives.Kinds).To(ConsistOf([]models.Kind{kind}))
 			})
 
+			It("dispatches the cc and bcc recipients", func() {
+				body, err := json.Marshal(map[string]interface{}{
+					"kind_id": "test_email",
+					"text":    "This is the plain text body of the email",
+					"cc":      []string{"Shared Inbox <shared@example.com>"},
+					"bcc":     []string{"audit@example.com"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err = http.NewRequest("POST", "/emails", bytes.NewBuffer(body))
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = handler.Execute(conn, request, context, "", strategy, validator, vcapRequestID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(strategy.DispatchCalls[0].Receives.Dispatch.Message.Cc).To(Equal([]string{"shared@example.com"}))
+				Expect(strategy.DispatchCalls[0].Receives.Dispatch.Message.Bcc).To(Equal([]string{"audit@example.com"}))
+			})
+
+			It("dispatches every to address in a single message", func() {
+				body, err := json.Marshal(map[string]interface{}{
+					"kind_id": "test_email",
+					"text":    "This is the plain text body of the email",
+					"to":      []string{"The User <user@example.com>", "other-user@example.com"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err = http.NewRequest("POST", "/emails", bytes.NewBuffer(body))
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = handler.Execute(conn, request, context, "", strategy, validator, vcapRequestID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(strategy.DispatchCallsCount).To(Equal(1))
+				Expect(strategy.DispatchCalls[0].Receives.Dispatch.Message.To).To(Equal("user@example.com, other-user@example.com"))
+			})
+
+			It("dispatches the custom headers", func() {
+				body, err := json.Marshal(map[string]interface{}{
+					"kind_id": "test_email",
+					"text":    "This is the plain text body of the email",
+					"headers": map[string]string{"X-Ticket-ID": "TICKET-1234"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err = http.NewRequest("POST", "/spaces/space-001", bytes.NewBuffer(body))
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(strategy.DispatchCalls[0].Receives.Dispatch.Message.Headers).To(Equal(map[string]string{"X-Ticket-ID": "TICKET-1234"}))
+			})
+
+			Context("when the notification has attachments", func() {
+				BeforeEach(func() {
+					body, err := json.Marshal(map[string]interface{}{
+						"kind_id": "test_email",
+						"text":    "This is the plain text body of the email",
+						"attachments": []map[string]string{
+							{"filename": "report.pdf", "content_type": "application/pdf", "content": "JVBERg=="},
+							{"filename": "notes.txt", "content": "bm90ZXM="},
+						},
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					request, err = http.NewRequest("POST", "/spaces/space-001", bytes.NewBuffer(body))
+					Expect(err).NotTo(HaveOccurred())
+
+					attachmentsRepo.CreateCall.Returns.Attachments = []models.Attachment{
+						{ID: "attachment-1"},
+						{ID: "attachment-2"},
+					}
+				})
+
+				It("stores the attachments for the dispatch and dispatches their IDs", func() {
+					_, err := handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(attachmentsRepo.CreateCall.Receives.Connection).To(Equal(conn))
+					Expect(attachmentsRepo.CreateCall.Receives.Attachments).To(Equal([]models.Attachment{
+						{DispatchID: "some-dispatch-id", Filename: "report.pdf", ContentType: "application/pdf", Content: []byte("%PDF")},
+						{DispatchID: "some-dispatch-id", Filename: "notes.txt", Content: []byte("notes")},
+					}))
+
+					Expect(strategy.DispatchCalls[0].Receives.Dispatch.ID).To(Equal("some-dispatch-id"))
+					Expect(strategy.DispatchCalls[0].Receives.Dispatch.Message.AttachmentIDs).To(Equal([]string{"attachment-1", "attachment-2"}))
+				})
+
+				It("returns the error when the dispatch ID cannot be generated", func() {
+					guidGenerator.GenerateCall.Returns.Error = errors.New("BOOM!")
+
+					_, err := handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+					Expect(err).To(Equal(errors.New("BOOM!")))
+					Expect(attachmentsRepo.CreateCall.CallCount).To(Equal(0))
+					Expect(strategy.DispatchCallsCount).To(Equal(0))
+				})
+
+				It("deletes the attachments when the notification cannot be dispatched", func() {
+					strategy.DispatchCalls = []mocks.StrategyDispatchCall{
+						mocks.NewStrategyDispatchCall(nil, errors.New("BOOM!")),
+					}
+
+					_, err := handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+					Expect(err).To(Equal(errors.New("BOOM!")))
+
+					Expect(attachmentsRepo.DeleteCall.Receives.Connection).To(Equal(conn))
+					Expect(attachmentsRepo.DeleteCall.Receives.IDs).To(Equal([]string{"attachment-1", "attachment-2"}))
+				})
+
+				It("does not delete the attachments once the notification is dispatched", func() {
+					_, err := handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(attachmentsRepo.DeleteCall.Receives.IDs).To(BeEmpty())
+				})
+
+				It("returns the error when an attachment cannot be stored", func() {
+					attachmentsRepo.CreateCall.Returns.Error = errors.New("BOOM!")
+
+					_, err := handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+					Expect(err).To(Equal(errors.New("BOOM!")))
+					Expect(strategy.DispatchCallsCount).To(Equal(0))
+				})
+			})
+
 			Context("failure cases", func() {
 				Context("when validating params", func() {
 					It("returns a error response when params are missing", func() {
@@ -3,6 +3,7 @@ package preferences
 import (
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/pivotal-golang/conceal"
 	"github.com/ryanmoran/stack"
 )
 
@@ -12,6 +13,7 @@ type muxer interface {
 
 type preferenceUpdater interface {
 	Update(connection services.ConnectionInterface, preferences []models.Preference, globallyUnsubscribe bool, userID string) error
+	Unsubscribe(connection services.ConnectionInterface, userID, clientID, kindID string) error
 }
 
 type Routes struct {
@@ -26,6 +28,7 @@ type Routes struct {
 	ErrorWriter       errorWriter
 	PreferencesFinder preferencesFinder
 	PreferenceUpdater preferenceUpdater
+	Cloak             conceal.CloakInterface
 }
 
 func (r Routes) Register(m muxer) {
@@ -35,4 +38,5 @@ func (r Routes) Register(m muxer) {
 	m.Handle("PATCH", "/user_preferences", NewUpdatePreferencesHandler(r.PreferenceUpdater, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.CORS, r.NotificationPreferencesWriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("GET", "/user_preferences/{user_id}", NewGetUserPreferencesHandler(r.PreferencesFinder, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.CORS, r.NotificationPreferencesAdminAuthenticator, r.DatabaseAllocator)
 	m.Handle("PATCH", "/user_preferences/{user_id}", NewUpdateUserPreferencesHandler(r.PreferenceUpdater, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.CORS, r.NotificationPreferencesAdminAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/unsubscribe/{unsubscribe_id}", NewUnsubscribeHandler(r.Cloak, r.PreferenceUpdater, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.DatabaseAllocator)
 }
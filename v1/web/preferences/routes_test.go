@@ -103,4 +103,15 @@ var _ = Describe("Routes", func() {
 			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.CORS{})
 		})
 	})
+
+	Describe("/unsubscribe", func() {
+		It("routes POST /unsubscribe/{unsubscribe_id}", func() {
+			request, err := http.NewRequest("POST", "/unsubscribe/some-unsubscribe-id", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(preferences.UnsubscribeHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.DatabaseAllocator{})
+		})
+	})
 })
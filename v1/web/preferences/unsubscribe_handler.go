@@ -0,0 +1,66 @@
+package preferences
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/pivotal-golang/conceal"
+	"github.com/ryanmoran/stack"
+)
+
+type unsubscriber interface {
+	Unsubscribe(connection services.ConnectionInterface, userID, clientID, kindID string) error
+}
+
+// UnsubscribeHandler serves the one-click unsubscribe URL from the
+// List-Unsubscribe header (RFC 8058). Mail providers post to it without a
+// token, so the user, client and kind are read from the cloaked
+// unsubscribe ID in the path.
+type UnsubscribeHandler struct {
+	cloak        conceal.CloakInterface
+	unsubscriber unsubscriber
+	errorWriter  errorWriter
+}
+
+func NewUnsubscribeHandler(cloak conceal.CloakInterface, unsubscriber unsubscriber, errWriter errorWriter) UnsubscribeHandler {
+	return UnsubscribeHandler{
+		cloak:        cloak,
+		unsubscriber: unsubscriber,
+		errorWriter:  errWriter,
+	}
+}
+
+func (h UnsubscribeHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	unsubscribeID := strings.Split(req.URL.Path, "/unsubscribe/")[1]
+
+	plainText, err := h.cloak.Unveil([]byte(unsubscribeID))
+	if err != nil {
+		h.errorWriter.Write(w, webutil.ValidationError{errors.New("The unsubscribe ID is invalid")})
+		return
+	}
+
+	parts := strings.Split(string(plainText), "|")
+	if len(parts) != 3 || parts[0] == "" {
+		h.errorWriter.Write(w, webutil.ValidationError{errors.New("The unsubscribe ID is invalid")})
+		return
+	}
+
+	userID, clientID, kindID := parts[0], parts[1], parts[2]
+
+	database := context.Get("database").(DatabaseInterface)
+	err = h.unsubscriber.Unsubscribe(database.Connection(), userID, clientID, kindID)
+	if err != nil {
+		switch err.(type) {
+		case services.MissingKindOrClientError, services.CriticalKindError:
+			h.errorWriter.Write(w, webutil.ValidationError{err})
+		default:
+			h.errorWriter.Write(w, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,129 @@
+package preferences_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/preferences"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UnsubscribeHandler", func() {
+	var (
+		handler     preferences.UnsubscribeHandler
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		connection  *mocks.Connection
+		context     stack.Context
+		cloak       *mocks.Cloak
+		updater     *mocks.PreferenceUpdater
+		errorWriter *mocks.ErrorWriter
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		connection = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		cloak = mocks.NewCloak()
+		cloak.UnveilCall.Returns.PlainText = []byte("some-user-guid|some-client-id|some-kind-id")
+
+		updater = mocks.NewPreferenceUpdater()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		request, err = http.NewRequest("POST", "/unsubscribe/some-unsubscribe-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = preferences.NewUnsubscribeHandler(cloak, updater, errorWriter)
+	})
+
+	It("unsubscribes the user identified by the unsubscribe ID", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+		Expect(cloak.UnveilCall.Receives.CipherText).To(Equal([]byte("some-unsubscribe-id")))
+
+		Expect(updater.UnsubscribeCall.Receives.Connection).To(Equal(connection))
+		Expect(updater.UnsubscribeCall.Receives.UserID).To(Equal("some-user-guid"))
+		Expect(updater.UnsubscribeCall.Receives.ClientID).To(Equal("some-client-id"))
+		Expect(updater.UnsubscribeCall.Receives.KindID).To(Equal("some-kind-id"))
+	})
+
+	Context("when the unsubscribe ID cannot be unveiled", func() {
+		It("writes a validation error", func() {
+			cloak.UnveilCall.Returns.Error = errors.New("illegal base64 data")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{errors.New("The unsubscribe ID is invalid")}))
+			Expect(updater.UnsubscribeCall.Receives.UserID).To(BeEmpty())
+		})
+	})
+
+	Context("when the unsubscribe ID does not identify a user, client and kind", func() {
+		It("writes a validation error", func() {
+			cloak.UnveilCall.Returns.PlainText = []byte("banana")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{errors.New("The unsubscribe ID is invalid")}))
+			Expect(updater.UnsubscribeCall.Receives.UserID).To(BeEmpty())
+		})
+	})
+
+	Context("when the unsubscribe ID does not identify a user", func() {
+		It("writes a validation error", func() {
+			cloak.UnveilCall.Returns.PlainText = []byte("|some-client-id|some-kind-id")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{errors.New("The unsubscribe ID is invalid")}))
+			Expect(updater.UnsubscribeCall.Receives.ClientID).To(BeEmpty())
+		})
+	})
+
+	Context("when the kind is critical", func() {
+		It("writes a validation error", func() {
+			criticalError := services.CriticalKindError{errors.New("critical")}
+			updater.UnsubscribeCall.Returns.Error = criticalError
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{criticalError}))
+		})
+	})
+
+	Context("when the kind cannot be found", func() {
+		It("writes a validation error", func() {
+			missingError := services.MissingKindOrClientError{errors.New("missing")}
+			updater.UnsubscribeCall.Returns.Error = missingError
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{missingError}))
+		})
+	})
+
+	Context("when the unsubscribe cannot be recorded", func() {
+		It("writes the error", func() {
+			updater.UnsubscribeCall.Returns.Error = errors.New("database error")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("database error")))
+		})
+	})
+})
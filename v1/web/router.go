@@ -4,11 +4,11 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"net/http"
-	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
 	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/metrics"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/uaa"
 	"github.com/cloudfoundry-incubator/notifications/util"
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
@@ -24,6 +24,7 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 	"github.com/gorilla/mux"
+	"github.com/pivotal-golang/conceal"
 	"github.com/pivotal-golang/lager"
 	"github.com/ryanmoran/stack"
 )
@@ -35,17 +36,20 @@ type muxer interface {
 }
 
 type Config struct {
-	UAATokenValidator    *uaa.TokenValidator
-	UAAClientID          string
-	UAAClientSecret      string
-	DefaultUAAScopes     []string
-	VerifySSL            bool
-	CCHost               string
-	DBLoggingEnabled     bool
-	Logger               lager.Logger
-	CORSOrigin           string
-	SQLDB                *sql.DB
-	QueueWaitMaxDuration int
+	UAATokenValidator *uaa.TokenValidator
+	UAAClientID       string
+	UAAClientSecret   string
+	DefaultUAAScopes  []string
+	VerifySSL         bool
+	CCHost            string
+	DBLoggingEnabled  bool
+	Logger            lager.Logger
+	CORSOrigin        string
+	SQLDB             *sql.DB
+	Queue             gobble.QueueInterface
+	EncryptionKey     []byte
+	Sender            string
+	Domain            string
 }
 
 func NewRouter(mx muxer, config Config) http.Handler {
@@ -59,6 +63,9 @@ func NewRouter(mx muxer, config Config) http.Handler {
 	unsubscribesRepo := models.NewUnsubscribesRepo()
 	messagesRepo := models.NewMessagesRepo(guidGenerator.Generate)
 	templatesRepo := models.NewTemplatesRepo()
+	templateVersionsRepo := models.NewTemplateVersionsRepo()
+	partialsRepo := models.NewPartialsRepo()
+	attachmentsRepo := models.NewAttachmentsRepo(guidGenerator.Generate)
 
 	registrar := services.NewRegistrar(clientsRepo, kindsRepo)
 	notificationsFinder := services.NewNotificationsFinder(clientsRepo, kindsRepo)
@@ -67,19 +74,16 @@ func NewRouter(mx muxer, config Config) http.Handler {
 	notificationsUpdater := services.NewNotificationsUpdater(kindsRepo)
 	messageFinder := services.NewMessageFinder(messagesRepo)
 
-	templatesCollection := collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo)
+	templatesCollection := collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo, partialsRepo)
 
 	templateFinder := services.NewTemplateFinder(templatesRepo)
-	templateUpdater := services.NewTemplateUpdater(templatesRepo)
+	templateUpdater := services.NewTemplateUpdater(templatesRepo, templateVersionsRepo, partialsRepo)
+	templateVersionFinder := services.NewTemplateVersionFinder(templatesRepo, templateVersionsRepo)
 	templateLister := services.NewTemplateLister(templatesRepo)
 
-	notifyObj := notify.NewNotify(notificationsFinder, registrar)
+	notifyObj := notify.NewNotify(notificationsFinder, registrar, attachmentsRepo, guidGenerator.Generate)
 
-	gobbleQueue := gobble.NewQueue(gobble.NewDatabase(config.SQLDB), clock, gobble.Config{
-		WaitMaxDuration: time.Duration(config.QueueWaitMaxDuration) * time.Millisecond,
-	})
-
-	v1enqueuer := services.NewEnqueuer(gobbleQueue, messagesRepo, gobble.Initializer{})
+	v1enqueuer := services.NewEnqueuer(config.Queue, messagesRepo, gobble.Initializer{})
 
 	uaaClient := uaa.NewZonedUAAClient(config.UAAClientID, config.UAAClientSecret, config.VerifySSL, config.UAATokenValidator)
 	cloudController := cf.NewCloudController(config.CCHost, !config.VerifySSL)
@@ -98,6 +102,15 @@ func NewRouter(mx muxer, config Config) http.Handler {
 
 	errorWriter := webutil.NewErrorWriter()
 
+	cloak, err := conceal.NewCloak(config.EncryptionKey)
+	if err != nil {
+		panic(err)
+	}
+
+	// The previewer builds its own message context, so its packager never
+	// loads templates or attachments.
+	templatePreviewer := common.NewPreviewer(common.NewPackager(nil, nil, cloak, ""), notify.HTMLExtractor{}, config.Sender, config.Domain)
+
 	requestCounter := middleware.NewRequestCounter(mx.GetRouter(), metrics.DefaultLogger)
 	requestLogging := middleware.NewRequestLogging(config.Logger, clock)
 	databaseAllocator := middleware.NewDatabaseAllocator(config.SQLDB, config.DBLoggingEnabled)
@@ -123,6 +136,7 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		ErrorWriter:       errorWriter,
 		PreferencesFinder: preferencesFinder,
 		PreferenceUpdater: preferenceUpdater,
+		Cloak:             cloak,
 	}.Register(mx)
 
 	clients.Routes{
@@ -160,6 +174,9 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		TemplateDeleter:           templatesCollection,
 		TemplateLister:            templateLister,
 		TemplateAssociationLister: templatesCollection,
+		TemplatePreviewer:         templatePreviewer,
+		PartialsFinder:            partialsRepo,
+		TemplateVersionFinder:     templateVersionFinder,
 	}.Register(mx)
 
 	notifications.Routes{
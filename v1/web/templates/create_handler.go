@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 	"github.com/ryanmoran/stack"
@@ -14,7 +15,7 @@ type errorWriter interface {
 }
 
 type templateCreator interface {
-	Create(connection collections.ConnectionInterface, template collections.Template) (collections.Template, error)
+	Create(connection collections.ConnectionInterface, template collections.Template, clientID string) (collections.Template, error)
 }
 
 type CreateHandler struct {
@@ -44,8 +45,13 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		HTML:     templateParams.HTML,
 		Subject:  templateParams.Subject,
 		Metadata: string(templateParams.Metadata),
-	})
+	}, context.Get("client_id").(string))
 	if err != nil {
+		if _, ok := err.(common.TemplateError); ok {
+			h.errorWriter.Write(w, err)
+			return
+		}
+
 		h.errorWriter.Write(w, webutil.TemplateCreateError{})
 		return
 	}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
@@ -59,6 +60,7 @@ var _ = Describe("CreateHandler", func() {
 
 			context = stack.NewContext()
 			context.Set("database", database)
+			context.Set("client_id", "some-client-id")
 
 			request, err = http.NewRequest("POST", "/templates", body)
 			Expect(err).NotTo(HaveOccurred())
@@ -77,6 +79,7 @@ var _ = Describe("CreateHandler", func() {
 				Subject:  "Raptor Containment Unit Breached",
 				Metadata: "{}",
 			}))
+			Expect(creator.CreateCall.Receives.ClientID).To(Equal("some-client-id"))
 
 			Expect(writer.Code).To(Equal(http.StatusCreated))
 			Expect(writer.Body.String()).To(MatchJSON(`{"template_id":"template-guid"}`))
@@ -107,6 +110,23 @@ var _ = Describe("CreateHandler", func() {
 				Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ParseError{}))
 			})
 
+			It("writes the error when the template is invalid", func() {
+				creator.CreateCall.Returns.Error = common.TemplateError{
+					Part:    "text",
+					Line:    1,
+					Column:  3,
+					Message: `unknown field "Mesage"`,
+				}
+
+				handler.ServeHTTP(writer, request, context)
+				Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(common.TemplateError{
+					Part:    "text",
+					Line:    1,
+					Column:  3,
+					Message: `unknown field "Mesage"`,
+				}))
+			})
+
 			It("returns a 500 for all other error cases", func() {
 				creator.CreateCall.Returns.Error = errors.New("my new error")
 
@@ -0,0 +1,59 @@
+package templates
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/ryanmoran/stack"
+)
+
+type GetVersionHandler struct {
+	finder      templateVersionFinder
+	errorWriter errorWriter
+}
+
+func NewGetVersionHandler(finder templateVersionFinder, errWriter errorWriter) GetVersionHandler {
+	return GetVersionHandler{
+		finder:      finder,
+		errorWriter: errWriter,
+	}
+}
+
+func (h GetVersionHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID, version, err := parseTemplateVersion(req.URL.Path)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	templateVersion, err := h.finder.Find(context.Get("database").(DatabaseInterface), templateID, version)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	output, err := NewTemplateVersionOutput(templateVersion)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, output)
+}
+
+// parseTemplateVersion reads the template ID and version number from a
+// /templates/{template_id}/versions/{version} path. A version that is not a
+// positive number cannot exist, so it is reported as not found.
+func parseTemplateVersion(path string) (string, int, error) {
+	matches := regexp.MustCompile(`\/templates\/(.*)\/versions\/([^\/]*)`).FindStringSubmatch(path)
+
+	version, err := strconv.Atoi(matches[2])
+	if err != nil || version < 1 {
+		return "", 0, models.NotFoundError{fmt.Errorf("Version %q of template with ID %q could not be found", matches[2], matches[1])}
+	}
+
+	return matches[1], version, nil
+}
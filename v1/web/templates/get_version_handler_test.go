@@ -0,0 +1,95 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetVersionHandler", func() {
+	var (
+		handler     templates.GetVersionHandler
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		finder      *mocks.TemplateVersionFinder
+		errorWriter *mocks.ErrorWriter
+		database    *mocks.Database
+		context     stack.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		finder = mocks.NewTemplateVersionFinder()
+		finder.FindCall.Returns.Version = models.TemplateVersion{
+			TemplateID: "banana-template",
+			Version:    3,
+			Name:       "Banana Template",
+			Subject:    "{{.Subject}}",
+			Text:       "bananas {{.Text}}",
+			HTML:       "<p>bananas</p>{{.HTML}}",
+			Metadata:   `{"color": "yellow"}`,
+			ClientID:   "some-client-id",
+			CreatedAt:  time.Date(2015, time.March, 4, 12, 30, 0, 0, time.UTC),
+		}
+
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		request, err = http.NewRequest("GET", "/templates/banana-template/versions/3", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		handler = templates.NewGetVersionHandler(finder, errorWriter)
+	})
+
+	It("returns the requested version of the template", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"version": 3,
+			"name": "Banana Template",
+			"subject": "{{.Subject}}",
+			"text": "bananas {{.Text}}",
+			"html": "<p>bananas</p>{{.HTML}}",
+			"metadata": {"color": "yellow"},
+			"client_id": "some-client-id",
+			"created_at": "2015-03-04T12:30:00Z"
+		}`))
+
+		Expect(finder.FindCall.Receives.Database).To(Equal(database))
+		Expect(finder.FindCall.Receives.TemplateID).To(Equal("banana-template"))
+		Expect(finder.FindCall.Receives.Version).To(Equal(3))
+	})
+
+	Context("when errors occur", func() {
+		It("writes a not found error when the version is not a number", func() {
+			request, err := http.NewRequest("GET", "/templates/banana-template/versions/latest", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(models.NotFoundError{errors.New(`Version "latest" of template with ID "banana-template" could not be found`)}))
+			Expect(finder.FindCall.Receives.TemplateID).To(BeEmpty())
+		})
+
+		It("delegates finder errors to the error writer", func() {
+			finder.FindCall.Returns.Error = models.NotFoundError{errors.New("no such version")}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(models.NotFoundError{errors.New("no such version")}))
+		})
+	})
+})
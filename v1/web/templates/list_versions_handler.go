@@ -0,0 +1,53 @@
+package templates
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/ryanmoran/stack"
+)
+
+type templateVersionFinder interface {
+	List(database services.DatabaseInterface, templateID string) ([]models.TemplateVersion, error)
+	Find(database services.DatabaseInterface, templateID string, version int) (models.TemplateVersion, error)
+}
+
+type ListVersionsHandler struct {
+	finder      templateVersionFinder
+	errorWriter errorWriter
+}
+
+func NewListVersionsHandler(finder templateVersionFinder, errWriter errorWriter) ListVersionsHandler {
+	return ListVersionsHandler{
+		finder:      finder,
+		errorWriter: errWriter,
+	}
+}
+
+func (h ListVersionsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID := regexp.MustCompile(`\/templates\/(.*)\/versions`).FindStringSubmatch(req.URL.Path)[1]
+
+	versions, err := h.finder.List(context.Get("database").(DatabaseInterface), templateID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	output := map[string][]TemplateVersionOutput{
+		"versions": {},
+	}
+
+	for _, version := range versions {
+		versionOutput, err := NewTemplateVersionOutput(version)
+		if err != nil {
+			h.errorWriter.Write(w, err)
+			return
+		}
+
+		output["versions"] = append(output["versions"], versionOutput)
+	}
+
+	writeJSON(w, http.StatusOK, output)
+}
@@ -0,0 +1,127 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListVersionsHandler", func() {
+	var (
+		handler     templates.ListVersionsHandler
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		finder      *mocks.TemplateVersionFinder
+		errorWriter *mocks.ErrorWriter
+		database    *mocks.Database
+		context     stack.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		finder = mocks.NewTemplateVersionFinder()
+		finder.ListCall.Returns.Versions = []models.TemplateVersion{
+			{
+				TemplateID: "banana-template",
+				Version:    2,
+				Name:       "Banana Template",
+				Subject:    "{{.Subject}}",
+				Text:       "bananas {{.Text}}",
+				HTML:       "<p>bananas</p>{{.HTML}}",
+				Metadata:   `{"color": "yellow"}`,
+				ClientID:   "some-client-id",
+				CreatedAt:  time.Date(2015, time.March, 4, 12, 30, 0, 0, time.UTC),
+			},
+			{
+				TemplateID: "banana-template",
+				Version:    1,
+				Name:       "Banana Template",
+				Subject:    "{{.Subject}}",
+				Text:       "{{.Text}}",
+				HTML:       "{{.HTML}}",
+				Metadata:   "{}",
+				CreatedAt:  time.Date(2015, time.March, 1, 9, 0, 0, 0, time.UTC),
+			},
+		}
+
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		request, err = http.NewRequest("GET", "/templates/banana-template/versions", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		handler = templates.NewListVersionsHandler(finder, errorWriter)
+	})
+
+	It("returns the versions of the template", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"versions": [
+				{
+					"version": 2,
+					"name": "Banana Template",
+					"subject": "{{.Subject}}",
+					"text": "bananas {{.Text}}",
+					"html": "<p>bananas</p>{{.HTML}}",
+					"metadata": {"color": "yellow"},
+					"client_id": "some-client-id",
+					"created_at": "2015-03-04T12:30:00Z"
+				},
+				{
+					"version": 1,
+					"name": "Banana Template",
+					"subject": "{{.Subject}}",
+					"text": "{{.Text}}",
+					"html": "{{.HTML}}",
+					"metadata": {},
+					"client_id": "",
+					"created_at": "2015-03-01T09:00:00Z"
+				}
+			]
+		}`))
+
+		Expect(finder.ListCall.Receives.Database).To(Equal(database))
+		Expect(finder.ListCall.Receives.TemplateID).To(Equal("banana-template"))
+	})
+
+	It("returns an empty list when the template has no versions", func() {
+		finder.ListCall.Returns.Versions = []models.TemplateVersion{}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{"versions": []}`))
+	})
+
+	Context("when errors occur", func() {
+		It("delegates finder errors to the error writer", func() {
+			finder.ListCall.Returns.Error = models.NotFoundError{errors.New("Template with ID \"banana-template\" could not be found")}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(models.NotFoundError{errors.New("Template with ID \"banana-template\" could not be found")}))
+		})
+
+		It("delegates metadata that cannot be decoded to the error writer", func() {
+			finder.ListCall.Returns.Versions[0].Metadata = "{not json"
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(HaveOccurred())
+		})
+	})
+})
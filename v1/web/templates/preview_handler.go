@@ -0,0 +1,107 @@
+package templates
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type templatePreviewer interface {
+	Preview(templates common.Templates, sample common.PreviewSample) (common.Preview, error)
+}
+
+type partialsFinder interface {
+	FindAllByClientID(connection models.ConnectionInterface, clientID string) (map[string]string, error)
+}
+
+type PreviewOutput struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+	Source  string `json:"source"`
+}
+
+type PreviewHandler struct {
+	finder       templateFinder
+	partialsRepo partialsFinder
+	previewer    templatePreviewer
+	errorWriter  errorWriter
+}
+
+func NewPreviewHandler(templateFinder templateFinder, partialsRepo partialsFinder, previewer templatePreviewer, errWriter errorWriter) PreviewHandler {
+	return PreviewHandler{
+		finder:       templateFinder,
+		partialsRepo: partialsRepo,
+		previewer:    previewer,
+		errorWriter:  errWriter,
+	}
+}
+
+func (h PreviewHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	splitURL := strings.Split(req.URL.Path, "/")
+	templateID := splitURL[len(splitURL)-2]
+
+	var sample struct {
+		Subject           string `json:"subject"`
+		Text              string `json:"text"`
+		HTML              string `json:"html"`
+		To                string `json:"to"`
+		ReplyTo           string `json:"reply_to"`
+		UserGUID          string `json:"user_guid"`
+		KindDescription   string `json:"kind_description"`
+		SourceDescription string `json:"source_description"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&sample)
+	if err != nil {
+		h.errorWriter.Write(w, webutil.ParseError{})
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+
+	template, err := h.finder.FindByID(database, templateID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	partials, err := h.partialsRepo.FindAllByClientID(database.Connection(), template.PartialsClientID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	preview, err := h.previewer.Preview(common.Templates{
+		Name:     template.Name,
+		Subject:  template.Subject,
+		Text:     template.Text,
+		HTML:     template.HTML,
+		Partials: partials,
+	}, common.PreviewSample{
+		Subject:           sample.Subject,
+		Text:              sample.Text,
+		HTML:              sample.HTML,
+		To:                sample.To,
+		ReplyTo:           sample.ReplyTo,
+		UserGUID:          sample.UserGUID,
+		KindDescription:   sample.KindDescription,
+		SourceDescription: sample.SourceDescription,
+	})
+	if err != nil {
+		h.errorWriter.Write(w, webutil.ValidationError{err})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PreviewOutput{
+		Subject: preview.Subject,
+		Text:    preview.Text,
+		HTML:    preview.HTML,
+		Source:  preview.Source,
+	})
+}
@@ -0,0 +1,152 @@
+package templates_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PreviewHandler", func() {
+	var (
+		handler      templates.PreviewHandler
+		request      *http.Request
+		writer       *httptest.ResponseRecorder
+		context      stack.Context
+		finder       *mocks.TemplateFinder
+		partialsRepo *mocks.PartialsRepo
+		previewer    *mocks.TemplatePreviewer
+		errorWriter  *mocks.ErrorWriter
+		database     *mocks.Database
+		conn         *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		finder = mocks.NewTemplateFinder()
+		finder.FindByIDCall.Returns.Template = models.Template{
+			Name:    "The Name of The Template",
+			Subject: "All about the {{.Subject}}",
+			Text:    "the template {{.Text}}",
+			HTML:    "<p>the template {{.HTML}}</p>",
+
+			PartialsClientID: "some-admin-client-id",
+		}
+
+		partialsRepo = mocks.NewPartialsRepo()
+		partialsRepo.FindAllByClientIDCall.Returns.Partials = map[string]string{
+			"footer": "<footer>{{.Organization}}</footer>",
+		}
+
+		previewer = mocks.NewTemplatePreviewer()
+		previewer.PreviewCall.Returns.Preview = common.Preview{
+			Subject: "All about the sample",
+			Text:    "the template sample text",
+			HTML:    "<p>the template <b>sample</b></p>",
+			Source:  "Subject: All about the sample\n",
+		}
+
+		writer = httptest.NewRecorder()
+		errorWriter = mocks.NewErrorWriter()
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+		context = stack.NewContext()
+		context.Set("database", database)
+		context.Set("client_id", "some-client-id")
+
+		var err error
+		request, err = http.NewRequest("POST", "/templates/some-template-id/preview", bytes.NewBufferString(`{
+			"subject": "sample",
+			"text": "sample text",
+			"html": "<b>sample</b>",
+			"to": "user@example.com",
+			"kind_description": "Outages"
+		}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = templates.NewPreviewHandler(finder, partialsRepo, previewer, errorWriter)
+	})
+
+	It("renders the template against the sample", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(finder.FindByIDCall.Receives.Database).To(Equal(database))
+		Expect(finder.FindByIDCall.Receives.TemplateID).To(Equal("some-template-id"))
+
+		Expect(partialsRepo.FindAllByClientIDCall.Receives.Connection).To(Equal(conn))
+		Expect(partialsRepo.FindAllByClientIDCall.Receives.ClientID).To(Equal("some-admin-client-id"))
+
+		Expect(previewer.PreviewCall.Receives.Templates).To(Equal(common.Templates{
+			Name:    "The Name of The Template",
+			Subject: "All about the {{.Subject}}",
+			Text:    "the template {{.Text}}",
+			HTML:    "<p>the template {{.HTML}}</p>",
+			Partials: map[string]string{
+				"footer": "<footer>{{.Organization}}</footer>",
+			},
+		}))
+		Expect(previewer.PreviewCall.Receives.Sample).To(Equal(common.PreviewSample{
+			Subject:         "sample",
+			Text:            "sample text",
+			HTML:            "<b>sample</b>",
+			To:              "user@example.com",
+			KindDescription: "Outages",
+		}))
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"subject": "All about the sample",
+			"text": "the template sample text",
+			"html": "<p>the template <b>sample</b></p>",
+			"source": "Subject: All about the sample\n"
+		}`))
+	})
+
+	Context("when the request body cannot be parsed", func() {
+		It("writes a parse error", func() {
+			var err error
+			request, err = http.NewRequest("POST", "/templates/some-template-id/preview", bytes.NewBufferString("%%%"))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ParseError{}))
+		})
+	})
+
+	Context("when the finder errors", func() {
+		It("writes the error to the errorWriter", func() {
+			finder.FindByIDCall.Returns.Error = models.NotFoundError{errors.New("not found")}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(models.NotFoundError{errors.New("not found")}))
+		})
+	})
+
+	Context("when the partials repo errors", func() {
+		It("writes the error to the errorWriter", func() {
+			partialsRepo.FindAllByClientIDCall.Returns.Error = errors.New("database failure")
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError("database failure"))
+		})
+	})
+
+	Context("when the template cannot be rendered", func() {
+		It("writes a validation error", func() {
+			previewer.PreviewCall.Returns.Error = errors.New("template: compileTemplate:1: unclosed action")
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{errors.New("template: compileTemplate:1: unclosed action")}))
+		})
+	})
+})
@@ -0,0 +1,37 @@
+package templates
+
+import (
+	"net/http"
+
+	"github.com/ryanmoran/stack"
+)
+
+type RollbackHandler struct {
+	updater     templateUpdater
+	errorWriter errorWriter
+}
+
+func NewRollbackHandler(updater templateUpdater, errWriter errorWriter) RollbackHandler {
+	return RollbackHandler{
+		updater:     updater,
+		errorWriter: errWriter,
+	}
+}
+
+func (h RollbackHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID, version, err := parseTemplateVersion(req.URL.Path)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	clientID := context.Get("client_id").(string)
+
+	err = h.updater.Rollback(context.Get("database").(DatabaseInterface), templateID, version, clientID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,74 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RollbackHandler", func() {
+	var (
+		handler     templates.RollbackHandler
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		updater     *mocks.TemplateUpdater
+		errorWriter *mocks.ErrorWriter
+		database    *mocks.Database
+		context     stack.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		updater = mocks.NewTemplateUpdater()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		request, err = http.NewRequest("POST", "/templates/default/versions/2/rollback", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+		context.Set("client_id", "some-client-id")
+
+		handler = templates.NewRollbackHandler(updater, errorWriter)
+	})
+
+	It("rolls the template back to the requested version", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+
+		Expect(updater.RollbackCall.Receives.Database).To(Equal(database))
+		Expect(updater.RollbackCall.Receives.TemplateID).To(Equal("default"))
+		Expect(updater.RollbackCall.Receives.Version).To(Equal(2))
+		Expect(updater.RollbackCall.Receives.ClientID).To(Equal("some-client-id"))
+	})
+
+	Context("when errors occur", func() {
+		It("writes a not found error when the version is not a number", func() {
+			request, err := http.NewRequest("POST", "/templates/default/versions/0/rollback", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(models.NotFoundError{errors.New(`Version "0" of template with ID "default" could not be found`)}))
+			Expect(updater.RollbackCall.Receives.TemplateID).To(BeEmpty())
+		})
+
+		It("delegates updater errors to the error writer", func() {
+			updater.RollbackCall.Returns.Error = models.NotFoundError{errors.New("no such version")}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(models.NotFoundError{errors.New("no such version")}))
+		})
+	})
+})
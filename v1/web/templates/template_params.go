@@ -2,10 +2,9 @@ package templates
 
 import (
 	"encoding/json"
-	"fmt"
 	"io"
-	"text/template"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 	"github.com/cloudfoundry-incubator/notifications/valiant"
@@ -49,17 +48,23 @@ func NewTemplateParams(body io.ReadCloser) (TemplateParams, error) {
 	return template, nil
 }
 
+// validateSyntax checks each part on its own. The partials a template
+// includes belong to the client saving it, so those references are checked
+// when the template is saved.
 func (t TemplateParams) validateSyntax() error {
-	toValidate := map[string]string{
-		"Subject": t.Subject,
-		"Text":    t.Text,
-		"HTML":    t.HTML,
+	parts := []struct {
+		name   string
+		source string
+	}{
+		{"subject", t.Subject},
+		{"text", t.Text},
+		{"html", t.HTML},
 	}
 
-	for field, contents := range toValidate {
-		_, err := template.New("test").Parse(contents)
+	for _, part := range parts {
+		err := common.ValidateTemplate(part.name, part.source)
 		if err != nil {
-			return webutil.ValidationError{fmt.Errorf("%s syntax is malformed please check your braces", field)}
+			return webutil.ValidationError{err}
 		}
 	}
 
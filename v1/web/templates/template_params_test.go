@@ -3,10 +3,10 @@ package templates_test
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"io"
 	"io/ioutil"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 
@@ -72,7 +72,11 @@ var _ = Describe("TemplateParams", func() {
 							Subject: "{{.bad}",
 						})
 						_, err := templates.NewTemplateParams(ioutil.NopCloser(body))
-						Expect(err).To(MatchError(webutil.ValidationError{errors.New("Subject syntax is malformed please check your braces")}))
+						Expect(err).To(MatchError(webutil.ValidationError{common.TemplateError{
+							Part:    "subject",
+							Line:    1,
+							Message: "bad character U+007D '}'",
+						}}))
 					})
 				})
 
@@ -85,7 +89,11 @@ var _ = Describe("TemplateParams", func() {
 							Subject: "Great Subject",
 						})
 						_, err := templates.NewTemplateParams(ioutil.NopCloser(body))
-						Expect(err).To(MatchError(webutil.ValidationError{errors.New("Text syntax is malformed please check your braces")}))
+						Expect(err).To(MatchError(webutil.ValidationError{common.TemplateError{
+							Part:    "text",
+							Line:    1,
+							Message: "bad character U+007D '}'",
+						}}))
 					})
 				})
 
@@ -98,7 +106,11 @@ var _ = Describe("TemplateParams", func() {
 							Subject: "Great Subject",
 						})
 						_, err := templates.NewTemplateParams(ioutil.NopCloser(body))
-						Expect(err).To(MatchError(webutil.ValidationError{errors.New("HTML syntax is malformed please check your braces")}))
+						Expect(err).To(MatchError(webutil.ValidationError{common.TemplateError{
+							Part:    "html",
+							Line:    1,
+							Message: "bad character U+007D '}'",
+						}}))
 					})
 				})
 			})
@@ -0,0 +1,40 @@
+package templates
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type TemplateVersionOutput struct {
+	Version   int                    `json:"version"`
+	Name      string                 `json:"name"`
+	Subject   string                 `json:"subject"`
+	HTML      string                 `json:"html"`
+	Text      string                 `json:"text"`
+	Metadata  map[string]interface{} `json:"metadata"`
+	ClientID  string                 `json:"client_id"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+func NewTemplateVersionOutput(version models.TemplateVersion) (TemplateVersionOutput, error) {
+	metadata := map[string]interface{}{}
+	if version.Metadata != "" {
+		err := json.Unmarshal([]byte(version.Metadata), &metadata)
+		if err != nil {
+			return TemplateVersionOutput{}, err
+		}
+	}
+
+	return TemplateVersionOutput{
+		Version:   version.Version,
+		Name:      version.Name,
+		Subject:   version.Subject,
+		HTML:      version.HTML,
+		Text:      version.Text,
+		Metadata:  metadata,
+		ClientID:  version.ClientID,
+		CreatedAt: version.CreatedAt,
+	}, nil
+}
@@ -9,7 +9,8 @@ import (
 )
 
 type templateUpdater interface {
-	Update(database services.DatabaseInterface, templateID string, template models.Template) error
+	Update(database services.DatabaseInterface, templateID string, template models.Template, clientID string) error
+	Rollback(database services.DatabaseInterface, templateID string, version int, clientID string) error
 }
 
 type UpdateDefaultHandler struct {
@@ -31,7 +32,9 @@ func (h UpdateDefaultHandler) ServeHTTP(w http.ResponseWriter, req *http.Request
 		return
 	}
 
-	err = h.updater.Update(context.Get("database").(DatabaseInterface), models.DefaultTemplateID, template.ToModel())
+	clientID := context.Get("client_id").(string)
+
+	err = h.updater.Update(context.Get("database").(DatabaseInterface), models.DefaultTemplateID, template.ToModel(), clientID)
 	if err != nil {
 		h.errorWriter.Write(w, err)
 	}
@@ -45,6 +45,7 @@ var _ = Describe("UpdateDefaultHandler", func() {
 		database = mocks.NewDatabase()
 		context = stack.NewContext()
 		context.Set("database", database)
+		context.Set("client_id", "some-client-id")
 
 		handler = templates.NewUpdateDefaultHandler(updater, errorWriter)
 	})
@@ -55,6 +56,7 @@ var _ = Describe("UpdateDefaultHandler", func() {
 		Expect(writer.Code).To(Equal(http.StatusNoContent))
 		Expect(updater.UpdateCall.Receives.Database).To(Equal(database))
 		Expect(updater.UpdateCall.Receives.TemplateID).To(Equal(models.DefaultTemplateID))
+		Expect(updater.UpdateCall.Receives.ClientID).To(Equal("some-client-id"))
 		Expect(updater.UpdateCall.Receives.Template).To(Equal(models.Template{
 			Name:     "Defaultish Template",
 			Subject:  "{{.Subject}}",
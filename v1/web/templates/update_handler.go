@@ -28,7 +28,9 @@ func (h UpdateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		return
 	}
 
-	err = h.updater.Update(context.Get("database").(DatabaseInterface), templateID, templateParams.ToModel())
+	clientID := context.Get("client_id").(string)
+
+	err = h.updater.Update(context.Get("database").(DatabaseInterface), templateID, templateParams.ToModel(), clientID)
 	if err != nil {
 		h.errorWriter.Write(w, err)
 		return
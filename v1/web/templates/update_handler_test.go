@@ -41,6 +41,7 @@ var _ = Describe("UpdateHandler", func() {
 			database = mocks.NewDatabase()
 			context = stack.NewContext()
 			context.Set("database", database)
+			context.Set("client_id", "some-client-id")
 
 			handler = templates.NewUpdateHandler(updater, errorWriter)
 		})
@@ -51,6 +52,7 @@ var _ = Describe("UpdateHandler", func() {
 
 			Expect(updater.UpdateCall.Receives.Database).To(Equal(database))
 			Expect(updater.UpdateCall.Receives.TemplateID).To(Equal("a-template-id"))
+			Expect(updater.UpdateCall.Receives.ClientID).To(Equal("some-client-id"))
 			Expect(updater.UpdateCall.Receives.Template).To(Equal(models.Template{
 				Name:     "An Interesting Template",
 				Subject:  "very interesting subject",
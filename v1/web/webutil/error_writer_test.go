@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
@@ -130,6 +131,19 @@ var _ = Describe("ErrorWriter", func() {
 		}`))
 	})
 
+	It("returns a 422 when a template is invalid", func() {
+		writer.Write(recorder, common.TemplateError{
+			Part:    "text",
+			Line:    3,
+			Column:  7,
+			Message: `unknown field "Mesage"`,
+		})
+		Expect(recorder.Code).To(Equal(422))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["text template is invalid at line 3, column 7: unknown field \"Mesage\""]
+		}`))
+	})
+
 	It("returns a 422 when a user token was expected but is not present", func() {
 		writer.Write(recorder, webutil.MissingUserTokenError{errors.New("Missing user_id from token claims.")})
 		Expect(recorder.Code).To(Equal(422))
@@ -31,6 +31,10 @@ type sendersGetter interface {
 	Get(conn models.ConnectionInterface, senderID string) (models.Sender, error)
 }
 
+type attachmentsInserter interface {
+	Insert(conn models.ConnectionInterface, attachment models.Attachment) (models.Attachment, error)
+}
+
 type Campaign struct {
 	ID             string
 	SendTo         map[string][]string
@@ -40,9 +44,21 @@ type Campaign struct {
 	Subject        string
 	TemplateID     string
 	ReplyTo        string
+	Cc             []string
+	Bcc            []string
 	SenderID       string
 	ClientID       string
 	StartTime      time.Time
+	Critical       bool
+	Headers        map[string]string
+	Attachments    []Attachment
+	AttachmentIDs  []string
+}
+
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
 }
 
 type CampaignsCollection struct {
@@ -51,15 +67,17 @@ type CampaignsCollection struct {
 	campaignTypesRepo campaignTypesGetter
 	templatesRepo     templatesGetter
 	sendersRepo       sendersGetter
+	attachmentsRepo   attachmentsInserter
 }
 
-func NewCampaignsCollection(enqueuer campaignEnqueuer, campaignsRepo campaignsPersister, campaignTypesRepo campaignTypesGetter, templatesRepo templatesGetter, sendersRepo sendersGetter) CampaignsCollection {
+func NewCampaignsCollection(enqueuer campaignEnqueuer, campaignsRepo campaignsPersister, campaignTypesRepo campaignTypesGetter, templatesRepo templatesGetter, sendersRepo sendersGetter, attachmentsRepo attachmentsInserter) CampaignsCollection {
 	return CampaignsCollection{
 		enqueuer:          enqueuer,
 		campaignsRepo:     campaignsRepo,
 		campaignTypesRepo: campaignTypesRepo,
 		templatesRepo:     templatesRepo,
 		sendersRepo:       sendersRepo,
+		attachmentsRepo:   attachmentsRepo,
 	}
 }
 
@@ -145,6 +163,24 @@ func (c CampaignsCollection) Create(conn ConnectionInterface, campaign Campaign,
 
 	campaign.ID = campaignModel.ID
 	campaign.ClientID = clientID
+	campaign.Critical = campaignType.Critical
+
+	// The attachments are stored once for the campaign so that the queued
+	// jobs only carry their IDs rather than the file contents.
+	for _, attachment := range campaign.Attachments {
+		attachmentModel, err := c.attachmentsRepo.Insert(conn, models.Attachment{
+			CampaignID:  campaign.ID,
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			Content:     attachment.Content,
+		})
+		if err != nil {
+			return Campaign{}, PersistenceError{err}
+		}
+
+		campaign.AttachmentIDs = append(campaign.AttachmentIDs, attachmentModel.ID)
+	}
+	campaign.Attachments = nil
 
 	err = c.enqueuer.Enqueue(campaign, "campaign")
 	if err != nil {
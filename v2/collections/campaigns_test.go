@@ -22,6 +22,7 @@ var _ = Describe("CampaignsCollection", func() {
 		campaignTypesRepo *mocks.CampaignTypesRepository
 		templatesRepo     *mocks.TemplatesRepository
 		sendersRepo       *mocks.SendersRepository
+		attachmentsRepo   *mocks.AttachmentsRepository
 	)
 
 	BeforeEach(func() {
@@ -31,12 +32,13 @@ var _ = Describe("CampaignsCollection", func() {
 		campaignTypesRepo = mocks.NewCampaignTypesRepository()
 		templatesRepo = mocks.NewTemplatesRepository()
 		sendersRepo = mocks.NewSendersRepository()
+		attachmentsRepo = mocks.NewAttachmentsRepository()
 
 		var err error
 		startTime, err = time.Parse(time.RFC3339, "2015-09-01T12:34:56-07:00")
 		Expect(err).NotTo(HaveOccurred())
 
-		collection = collections.NewCampaignsCollection(enqueuer, campaignsRepo, campaignTypesRepo, templatesRepo, sendersRepo)
+		collection = collections.NewCampaignsCollection(enqueuer, campaignsRepo, campaignTypesRepo, templatesRepo, sendersRepo, attachmentsRepo)
 	})
 
 	Describe("Create", func() {
@@ -289,6 +291,52 @@ var _ = Describe("CampaignsCollection", func() {
 				})
 			})
 
+			Context("when the campaign has attachments", func() {
+				var campaign collections.Campaign
+
+				BeforeEach(func() {
+					campaign = collections.Campaign{
+						SendTo:         map[string][]string{"users": {"some-user-guid"}},
+						CampaignTypeID: "some-id",
+						Text:           "some-test",
+						Subject:        "some-subject",
+						TemplateID:     "whoa-a-template-id",
+						SenderID:       "some-sender-id",
+						Attachments: []collections.Attachment{
+							{Filename: "report.pdf", ContentType: "application/pdf", Content: []byte("%PDF")},
+							{Filename: "notes.txt", ContentType: "text/plain", Content: []byte("notes")},
+						},
+					}
+
+					attachmentsRepo.InsertCall.Returns.Attachments = []models.Attachment{
+						{ID: "attachment-1"},
+						{ID: "attachment-2"},
+					}
+				})
+
+				It("stores the attachments and enqueues only their IDs", func() {
+					_, err := collection.Create(conn, campaign, "some-client-id", false)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(attachmentsRepo.InsertCall.Receives.Connection).To(Equal(conn))
+					Expect(attachmentsRepo.InsertCall.Receives.Attachments).To(Equal([]models.Attachment{
+						{CampaignID: "a-new-id", Filename: "report.pdf", ContentType: "application/pdf", Content: []byte("%PDF")},
+						{CampaignID: "a-new-id", Filename: "notes.txt", ContentType: "text/plain", Content: []byte("notes")},
+					}))
+
+					Expect(enqueuer.EnqueueCall.Receives.Campaign.Attachments).To(BeNil())
+					Expect(enqueuer.EnqueueCall.Receives.Campaign.AttachmentIDs).To(Equal([]string{"attachment-1", "attachment-2"}))
+				})
+
+				It("returns a persistence error when an attachment cannot be stored", func() {
+					attachmentsRepo.InsertCall.Returns.Error = errors.New("insert failed")
+
+					_, err := collection.Create(conn, campaign, "some-client-id", false)
+					Expect(err).To(Equal(collections.PersistenceError{Err: errors.New("insert failed")}))
+					Expect(enqueuer.EnqueueCall.Receives.JobType).To(BeEmpty())
+				})
+			})
+
 			It("gets the template off of the campaign type if the templateID is blank", func() {
 				campaignTypesRepo.GetCall.Returns.CampaignType = models.CampaignType{
 					TemplateID: "campaign-type-template-id",
@@ -392,6 +440,7 @@ var _ = Describe("CampaignsCollection", func() {
 					SenderID:       "some-sender-id",
 					ClientID:       "some-client-id",
 					StartTime:      startTime,
+					Critical:       true,
 				}))
 			})
 
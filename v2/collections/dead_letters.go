@@ -0,0 +1,176 @@
+package collections
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+	"gopkg.in/gorp.v1"
+)
+
+type DeadLetter struct {
+	ID         int
+	JobID      int
+	Payload    string
+	LastError  string
+	RetryCount int
+	Priority   int
+	ActiveAt   time.Time
+	CreatedAt  time.Time
+}
+
+type deadLettersRepository interface {
+	List(connection models.ConnectionInterface) ([]models.DeadLetter, error)
+	Get(connection models.ConnectionInterface, id int) (models.DeadLetter, error)
+	Delete(connection models.ConnectionInterface, id int) error
+	DeleteAll(connection models.ConnectionInterface) error
+}
+
+type jobEnqueuer interface {
+	Enqueue(job *gobble.Job, connection gobble.ConnectionInterface) (*gobble.Job, error)
+}
+
+type gobbleInitializer interface {
+	InitializeDBMap(*gorp.DbMap)
+}
+
+type DeadLettersCollection struct {
+	repo              deadLettersRepository
+	queue             jobEnqueuer
+	gobbleInitializer gobbleInitializer
+}
+
+func NewDeadLettersCollection(repo deadLettersRepository, queue jobEnqueuer, gobbleInitializer gobbleInitializer) DeadLettersCollection {
+	return DeadLettersCollection{
+		repo:              repo,
+		queue:             queue,
+		gobbleInitializer: gobbleInitializer,
+	}
+}
+
+func (c DeadLettersCollection) List(connection ConnectionInterface) ([]DeadLetter, error) {
+	deadLetters, err := c.repo.List(connection)
+	if err != nil {
+		return nil, PersistenceError{err}
+	}
+
+	list := []DeadLetter{}
+	for _, deadLetter := range deadLetters {
+		list = append(list, newDeadLetter(deadLetter))
+	}
+
+	return list, nil
+}
+
+func (c DeadLettersCollection) Get(connection ConnectionInterface, id string) (DeadLetter, error) {
+	deadLetterID, err := parseDeadLetterID(id)
+	if err != nil {
+		return DeadLetter{}, err
+	}
+
+	deadLetter, err := c.repo.Get(connection, deadLetterID)
+	if err != nil {
+		return DeadLetter{}, translateDeadLetterError(err)
+	}
+
+	return newDeadLetter(deadLetter), nil
+}
+
+func (c DeadLettersCollection) Replay(connection ConnectionInterface, id string) error {
+	deadLetterID, err := parseDeadLetterID(id)
+	if err != nil {
+		return err
+	}
+
+	transaction := connection.Transaction()
+	c.gobbleInitializer.InitializeDBMap(transaction.GetDbMap())
+
+	err = transaction.Begin()
+	if err != nil {
+		return PersistenceError{err}
+	}
+
+	deadLetter, err := c.repo.Get(transaction, deadLetterID)
+	if err != nil {
+		transaction.Rollback()
+		return translateDeadLetterError(err)
+	}
+
+	_, err = c.queue.Enqueue(&gobble.Job{
+		Payload:  deadLetter.Payload,
+		Priority: deadLetter.Priority,
+	}, transaction)
+	if err != nil {
+		transaction.Rollback()
+		return PersistenceError{err}
+	}
+
+	err = c.repo.Delete(transaction, deadLetterID)
+	if err != nil {
+		transaction.Rollback()
+		return translateDeadLetterError(err)
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		return PersistenceError{err}
+	}
+
+	return nil
+}
+
+func (c DeadLettersCollection) Delete(connection ConnectionInterface, id string) error {
+	deadLetterID, err := parseDeadLetterID(id)
+	if err != nil {
+		return err
+	}
+
+	err = c.repo.Delete(connection, deadLetterID)
+	if err != nil {
+		return translateDeadLetterError(err)
+	}
+
+	return nil
+}
+
+func (c DeadLettersCollection) Purge(connection ConnectionInterface) error {
+	err := c.repo.DeleteAll(connection)
+	if err != nil {
+		return PersistenceError{err}
+	}
+
+	return nil
+}
+
+func newDeadLetter(deadLetter models.DeadLetter) DeadLetter {
+	return DeadLetter{
+		ID:         deadLetter.ID,
+		JobID:      deadLetter.JobID,
+		Payload:    deadLetter.Payload,
+		LastError:  deadLetter.LastError,
+		RetryCount: deadLetter.RetryCount,
+		Priority:   deadLetter.Priority,
+		ActiveAt:   deadLetter.ActiveAt,
+		CreatedAt:  deadLetter.CreatedAt,
+	}
+}
+
+func parseDeadLetterID(id string) (int, error) {
+	deadLetterID, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, NotFoundError{fmt.Errorf("Dead letter with id %q could not be found", id)}
+	}
+
+	return deadLetterID, nil
+}
+
+func translateDeadLetterError(err error) error {
+	switch err.(type) {
+	case models.RecordNotFoundError:
+		return NotFoundError{err}
+	default:
+		return PersistenceError{err}
+	}
+}
@@ -0,0 +1,262 @@
+package collections_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+	"gopkg.in/gorp.v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeadLettersCollection", func() {
+	var (
+		deadLettersRepository *mocks.DeadLettersRepository
+		queue                 *mocks.Queue
+		gobbleInitializer     *mocks.GobbleInitializer
+		connection            *mocks.Connection
+		transaction           *mocks.Transaction
+		collection            collections.DeadLettersCollection
+		createdAt             time.Time
+	)
+
+	BeforeEach(func() {
+		deadLettersRepository = mocks.NewDeadLettersRepository()
+		queue = mocks.NewQueue()
+		gobbleInitializer = mocks.NewGobbleInitializer()
+
+		transaction = mocks.NewTransaction()
+		transaction.Connection = mocks.NewConnection()
+		transaction.GetDbMapCall.Returns.DbMap = &gorp.DbMap{}
+
+		connection = mocks.NewConnection()
+		connection.TransactionCall.Returns.Transaction = transaction
+
+		createdAt = time.Now().UTC().Truncate(time.Second)
+
+		collection = collections.NewDeadLettersCollection(deadLettersRepository, queue, gobbleInitializer)
+	})
+
+	Describe("List", func() {
+		It("returns all of the dead letters", func() {
+			deadLettersRepository.ListCall.Returns.DeadLetters = []models.DeadLetter{
+				{
+					ID:         1,
+					JobID:      23,
+					Payload:    `{"some":"payload"}`,
+					LastError:  "some error",
+					RetryCount: 10,
+					Priority:   1,
+					ActiveAt:   createdAt,
+					CreatedAt:  createdAt,
+				},
+			}
+
+			deadLetters, err := collection.List(connection)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadLetters).To(Equal([]collections.DeadLetter{
+				{
+					ID:         1,
+					JobID:      23,
+					Payload:    `{"some":"payload"}`,
+					LastError:  "some error",
+					RetryCount: 10,
+					Priority:   1,
+					ActiveAt:   createdAt,
+					CreatedAt:  createdAt,
+				},
+			}))
+
+			Expect(deadLettersRepository.ListCall.Receives.Connection).To(Equal(connection))
+		})
+
+		Context("when the repository errors", func() {
+			It("returns a persistence error", func() {
+				deadLettersRepository.ListCall.Returns.Error = errors.New("some list error")
+
+				_, err := collection.List(connection)
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("some list error")}))
+			})
+		})
+	})
+
+	Describe("Get", func() {
+		It("returns the dead letter with the given id", func() {
+			deadLettersRepository.GetCall.Returns.DeadLetter = models.DeadLetter{
+				ID:      12,
+				JobID:   23,
+				Payload: `{"some":"payload"}`,
+			}
+
+			deadLetter, err := collection.Get(connection, "12")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadLetter).To(Equal(collections.DeadLetter{
+				ID:      12,
+				JobID:   23,
+				Payload: `{"some":"payload"}`,
+			}))
+
+			Expect(deadLettersRepository.GetCall.Receives.Connection).To(Equal(connection))
+			Expect(deadLettersRepository.GetCall.Receives.ID).To(Equal(12))
+		})
+
+		Context("when the id is not a number", func() {
+			It("returns a not found error", func() {
+				_, err := collection.Get(connection, "banana")
+				Expect(err).To(MatchError(collections.NotFoundError{errors.New(`Dead letter with id "banana" could not be found`)}))
+			})
+		})
+
+		Context("when the dead letter does not exist", func() {
+			It("returns a not found error", func() {
+				deadLettersRepository.GetCall.Returns.Error = models.RecordNotFoundError{errors.New("not found")}
+
+				_, err := collection.Get(connection, "12")
+				Expect(err).To(MatchError(collections.NotFoundError{models.RecordNotFoundError{errors.New("not found")}}))
+			})
+		})
+
+		Context("when the repository errors", func() {
+			It("returns a persistence error", func() {
+				deadLettersRepository.GetCall.Returns.Error = errors.New("some get error")
+
+				_, err := collection.Get(connection, "12")
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("some get error")}))
+			})
+		})
+	})
+
+	Describe("Replay", func() {
+		BeforeEach(func() {
+			deadLettersRepository.GetCall.Returns.DeadLetter = models.DeadLetter{
+				ID:       12,
+				JobID:    23,
+				Payload:  `{"some":"payload"}`,
+				Priority: gobble.PriorityCritical,
+			}
+		})
+
+		It("enqueues the payload as a new job and removes the dead letter", func() {
+			err := collection.Replay(connection, "12")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(gobbleInitializer.InitializeDBMapCall.Receives.DbMap).To(Equal(&gorp.DbMap{}))
+
+			Expect(deadLettersRepository.GetCall.Receives.Connection).To(Equal(transaction))
+			Expect(deadLettersRepository.GetCall.Receives.ID).To(Equal(12))
+
+			Expect(queue.EnqueueCall.Receives.Connection).To(Equal(transaction))
+			Expect(queue.EnqueueCall.Receives.Jobs).To(Equal([]*gobble.Job{
+				{Payload: `{"some":"payload"}`, Priority: gobble.PriorityCritical},
+			}))
+
+			Expect(deadLettersRepository.DeleteCall.Receives.Connection).To(Equal(transaction))
+			Expect(deadLettersRepository.DeleteCall.Receives.ID).To(Equal(12))
+
+			Expect(transaction.BeginCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+			Expect(transaction.RollbackCall.WasCalled).To(BeFalse())
+		})
+
+		Context("when the id is not a number", func() {
+			It("returns a not found error", func() {
+				err := collection.Replay(connection, "banana")
+				Expect(err).To(MatchError(collections.NotFoundError{errors.New(`Dead letter with id "banana" could not be found`)}))
+				Expect(transaction.BeginCall.WasCalled).To(BeFalse())
+			})
+		})
+
+		Context("when the dead letter does not exist", func() {
+			It("returns a not found error and rolls back", func() {
+				deadLettersRepository.GetCall.Returns.Error = models.RecordNotFoundError{errors.New("not found")}
+
+				err := collection.Replay(connection, "12")
+				Expect(err).To(MatchError(collections.NotFoundError{models.RecordNotFoundError{errors.New("not found")}}))
+				Expect(queue.EnqueueCall.Receives.Jobs).To(BeEmpty())
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			})
+		})
+
+		Context("when enqueuing the job fails", func() {
+			It("returns a persistence error and rolls back", func() {
+				queue.EnqueueCall.Returns.Error = errors.New("some enqueue error")
+
+				err := collection.Replay(connection, "12")
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("some enqueue error")}))
+				Expect(deadLettersRepository.DeleteCall.WasCalled).To(BeFalse())
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+				Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+			})
+		})
+
+		Context("when deleting the dead letter fails", func() {
+			It("returns a persistence error and rolls back", func() {
+				deadLettersRepository.DeleteCall.Returns.Error = errors.New("some delete error")
+
+				err := collection.Replay(connection, "12")
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("some delete error")}))
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+				Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+			})
+		})
+
+		Context("when the transaction cannot be committed", func() {
+			It("returns a persistence error", func() {
+				transaction.CommitCall.Returns.Error = errors.New("some commit error")
+
+				err := collection.Replay(connection, "12")
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("some commit error")}))
+			})
+		})
+	})
+
+	Describe("Delete", func() {
+		It("deletes the dead letter with the given id", func() {
+			err := collection.Delete(connection, "12")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(deadLettersRepository.DeleteCall.Receives.Connection).To(Equal(connection))
+			Expect(deadLettersRepository.DeleteCall.Receives.ID).To(Equal(12))
+		})
+
+		Context("when the id is not a number", func() {
+			It("returns a not found error", func() {
+				err := collection.Delete(connection, "banana")
+				Expect(err).To(MatchError(collections.NotFoundError{errors.New(`Dead letter with id "banana" could not be found`)}))
+				Expect(deadLettersRepository.DeleteCall.WasCalled).To(BeFalse())
+			})
+		})
+
+		Context("when the dead letter does not exist", func() {
+			It("returns a not found error", func() {
+				deadLettersRepository.DeleteCall.Returns.Error = models.RecordNotFoundError{errors.New("not found")}
+
+				err := collection.Delete(connection, "12")
+				Expect(err).To(MatchError(collections.NotFoundError{models.RecordNotFoundError{errors.New("not found")}}))
+			})
+		})
+	})
+
+	Describe("Purge", func() {
+		It("deletes all of the dead letters", func() {
+			err := collection.Purge(connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(deadLettersRepository.DeleteAllCall.Receives.Connection).To(Equal(connection))
+		})
+
+		Context("when the repository errors", func() {
+			It("returns a persistence error", func() {
+				deadLettersRepository.DeleteAllCall.Returns.Error = errors.New("some purge error")
+
+				err := collection.Purge(connection)
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("some purge error")}))
+			})
+		})
+	})
+})
@@ -24,6 +24,14 @@ func (e DuplicateRecordError) Error() string {
 	return e.Err.Error()
 }
 
+type ValidationError struct {
+	Err error
+}
+
+func (e ValidationError) Error() string {
+	return e.Err.Error()
+}
+
 type UnknownError struct {
 	Err error
 }
@@ -0,0 +1,226 @@
+package collections
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+)
+
+var partialNamePattern = regexp.MustCompile(`^[A-Za-z0-9_./-]{1,255}$`)
+
+// Partial is a named piece of a template, such as a footer or a layout,
+// that the templates of the client that owns it can include with
+// {{template "name" .}}.
+type Partial struct {
+	ID       string
+	Name     string
+	Body     string
+	ClientID string
+}
+
+type partialsRepository interface {
+	Insert(conn models.ConnectionInterface, partial models.Partial) (insertedPartial models.Partial, err error)
+	Update(conn models.ConnectionInterface, partial models.Partial) (updatedPartial models.Partial, err error)
+	Get(conn models.ConnectionInterface, partialID string) (retrievedPartial models.Partial, err error)
+	List(conn models.ConnectionInterface, clientID string) (partialList []models.Partial, err error)
+	Delete(conn models.ConnectionInterface, partial models.Partial) error
+}
+
+// PartialsCollection manages the partials of a client. A change to a
+// partial is rejected when it would leave partials including each other in
+// a cycle, or leave a template that includes the client's partials
+// including a partial that does not exist.
+type PartialsCollection struct {
+	partials  partialsRepository
+	templates templatesRepository
+}
+
+func NewPartialsCollection(partials partialsRepository, templates templatesRepository) PartialsCollection {
+	return PartialsCollection{
+		partials:  partials,
+		templates: templates,
+	}
+}
+
+func (c PartialsCollection) Set(conn ConnectionInterface, partial Partial) (Partial, error) {
+	err := validatePartial(partial)
+	if err != nil {
+		return Partial{}, ValidationError{err}
+	}
+
+	existing, err := c.partials.List(conn, partial.ClientID)
+	if err != nil {
+		return Partial{}, PersistenceError{err}
+	}
+
+	bodies := map[string]string{}
+	for _, model := range existing {
+		if model.ID != partial.ID {
+			bodies[model.Name] = model.Body
+		}
+	}
+	bodies[partial.Name] = partial.Body
+
+	err = c.validate(conn, partial.ClientID, bodies)
+	if err != nil {
+		return Partial{}, err
+	}
+
+	var model models.Partial
+	if partial.ID == "" {
+		model, err = c.partials.Insert(conn, models.Partial{
+			ClientID: partial.ClientID,
+			Name:     partial.Name,
+			Body:     partial.Body,
+		})
+	} else {
+		model, err = c.partials.Update(conn, models.Partial{
+			ID:       partial.ID,
+			ClientID: partial.ClientID,
+			Name:     partial.Name,
+			Body:     partial.Body,
+		})
+	}
+	if err != nil {
+		switch err.(type) {
+		case models.DuplicateRecordError:
+			return Partial{}, DuplicateRecordError{err}
+		default:
+			return Partial{}, PersistenceError{err}
+		}
+	}
+
+	return newPartial(model), nil
+}
+
+func (c PartialsCollection) Get(conn ConnectionInterface, partialID, clientID string) (Partial, error) {
+	model, err := c.get(conn, partialID, clientID)
+	if err != nil {
+		return Partial{}, err
+	}
+
+	return newPartial(model), nil
+}
+
+func (c PartialsCollection) List(conn ConnectionInterface, clientID string) ([]Partial, error) {
+	partialList := []Partial{}
+
+	models, err := c.partials.List(conn, clientID)
+	if err != nil {
+		return partialList, PersistenceError{err}
+	}
+
+	for _, model := range models {
+		partialList = append(partialList, newPartial(model))
+	}
+
+	return partialList, nil
+}
+
+func (c PartialsCollection) Delete(conn ConnectionInterface, partialID, clientID string) error {
+	partial, err := c.get(conn, partialID, clientID)
+	if err != nil {
+		return err
+	}
+
+	existing, err := c.partials.List(conn, clientID)
+	if err != nil {
+		return PersistenceError{err}
+	}
+
+	bodies := map[string]string{}
+	for _, model := range existing {
+		if model.ID != partial.ID {
+			bodies[model.Name] = model.Body
+		}
+	}
+
+	err = c.validate(conn, clientID, bodies)
+	if err != nil {
+		return err
+	}
+
+	err = c.partials.Delete(conn, partial)
+	if err != nil {
+		return PersistenceError{err}
+	}
+
+	return nil
+}
+
+func (c PartialsCollection) get(conn ConnectionInterface, partialID, clientID string) (models.Partial, error) {
+	model, err := c.partials.Get(conn, partialID)
+	if err != nil {
+		switch err.(type) {
+		case models.RecordNotFoundError:
+			return models.Partial{}, NotFoundError{err}
+		default:
+			return models.Partial{}, PersistenceError{err}
+		}
+	}
+
+	if model.ClientID != clientID {
+		return models.Partial{}, NotFoundError{fmt.Errorf("Partial with id %q could not be found", partialID)}
+	}
+
+	return model, nil
+}
+
+// validate checks the partials a client would be left with, and that every
+// template that includes them can still be compiled with them. Besides the
+// client's own templates, that covers the shared templates it last saved.
+func (c PartialsCollection) validate(conn ConnectionInterface, clientID string, bodies map[string]string) error {
+	err := common.ValidatePartials(bodies)
+	if err != nil {
+		return ValidationError{err}
+	}
+
+	templates, err := c.templates.ListByPartialsClientID(conn, clientID)
+	if err != nil {
+		return PersistenceError{err}
+	}
+
+	for _, template := range templates {
+		err = common.ValidateTemplates(template.Subject, template.Text, template.HTML, bodies)
+		if err != nil {
+			return ValidationError{fmt.Errorf("template %q would be invalid: %s", template.Name, err)}
+		}
+	}
+
+	return nil
+}
+
+func validatePartial(partial Partial) error {
+	if !partialNamePattern.MatchString(partial.Name) {
+		return fmt.Errorf("partial name %q is invalid: it must be 1 to 255 letters, digits, '_', '.', '/' or '-'", partial.Name)
+	}
+
+	if partial.Body == "" {
+		return errors.New("missing partial body")
+	}
+
+	return nil
+}
+
+func newPartial(model models.Partial) Partial {
+	return Partial{
+		ID:       model.ID,
+		Name:     model.Name,
+		Body:     model.Body,
+		ClientID: model.ClientID,
+	}
+}
+
+// partialBodies maps the name of each partial to its body, the way the
+// Packager and template validation expect them.
+func partialBodies(partials []models.Partial) map[string]string {
+	bodies := map[string]string{}
+	for _, partial := range partials {
+		bodies[partial.Name] = partial.Body
+	}
+
+	return bodies
+}
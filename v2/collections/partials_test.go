@@ -0,0 +1,345 @@
+package collections_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PartialsCollection", func() {
+	var (
+		partialsCollection  collections.PartialsCollection
+		partialsRepository  *mocks.PartialsRepository
+		templatesRepository *mocks.TemplatesRepository
+		conn                *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		partialsRepository = mocks.NewPartialsRepository()
+		templatesRepository = mocks.NewTemplatesRepository()
+
+		partialsCollection = collections.NewPartialsCollection(partialsRepository, templatesRepository)
+		conn = mocks.NewConnection()
+	})
+
+	Describe("Set", func() {
+		Context("when no ID is supplied", func() {
+			It("adds a partial to the collection", func() {
+				partialsRepository.InsertCall.Returns.Partial = models.Partial{
+					ID:       "some-partial-id",
+					ClientID: "some-client-id",
+					Name:     "footer",
+					Body:     "<footer>{{.Organization}}</footer>",
+				}
+
+				partial, err := partialsCollection.Set(conn, collections.Partial{
+					Name:     "footer",
+					Body:     "<footer>{{.Organization}}</footer>",
+					ClientID: "some-client-id",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(partial).To(Equal(collections.Partial{
+					ID:       "some-partial-id",
+					Name:     "footer",
+					Body:     "<footer>{{.Organization}}</footer>",
+					ClientID: "some-client-id",
+				}))
+
+				Expect(partialsRepository.InsertCall.Receives.Connection).To(Equal(conn))
+				Expect(partialsRepository.InsertCall.Receives.Partial).To(Equal(models.Partial{
+					ClientID: "some-client-id",
+					Name:     "footer",
+					Body:     "<footer>{{.Organization}}</footer>",
+				}))
+				Expect(partialsRepository.ListCall.Receives.ClientID).To(Equal("some-client-id"))
+				Expect(templatesRepository.ListByPartialsClientIDCall.Receives.ClientID).To(Equal("some-client-id"))
+			})
+
+			It("returns a DuplicateRecordError when the client already has a partial with that name", func() {
+				partialsRepository.InsertCall.Returns.Error = models.DuplicateRecordError{errors.New("duplicate")}
+
+				_, err := partialsCollection.Set(conn, collections.Partial{
+					Name:     "footer",
+					Body:     "some body",
+					ClientID: "some-client-id",
+				})
+				Expect(err).To(MatchError(collections.DuplicateRecordError{models.DuplicateRecordError{errors.New("duplicate")}}))
+			})
+		})
+
+		Context("when an ID is supplied", func() {
+			It("updates the partial", func() {
+				partialsRepository.ListCall.Returns.Partials = []models.Partial{
+					{ID: "some-partial-id", ClientID: "some-client-id", Name: "footer", Body: `{{template "legal" .}}`},
+					{ID: "other-partial-id", ClientID: "some-client-id", Name: "legal", Body: "some legal text"},
+				}
+				partialsRepository.UpdateCall.Returns.Partial = models.Partial{
+					ID:       "some-partial-id",
+					ClientID: "some-client-id",
+					Name:     "legal",
+					Body:     "some new body",
+				}
+
+				partial, err := partialsCollection.Set(conn, collections.Partial{
+					ID:       "other-partial-id",
+					Name:     "legal",
+					Body:     "some new body",
+					ClientID: "some-client-id",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(partial.Body).To(Equal("some new body"))
+
+				Expect(partialsRepository.UpdateCall.Receives.Partial).To(Equal(models.Partial{
+					ID:       "other-partial-id",
+					ClientID: "some-client-id",
+					Name:     "legal",
+					Body:     "some new body",
+				}))
+			})
+
+			It("rejects a change that would make partials include each other", func() {
+				partialsRepository.ListCall.Returns.Partials = []models.Partial{
+					{ID: "some-partial-id", ClientID: "some-client-id", Name: "footer", Body: `{{template "legal" .}}`},
+					{ID: "other-partial-id", ClientID: "some-client-id", Name: "legal", Body: "some legal text"},
+				}
+
+				_, err := partialsCollection.Set(conn, collections.Partial{
+					ID:       "other-partial-id",
+					Name:     "legal",
+					Body:     `{{template "footer" .}}`,
+					ClientID: "some-client-id",
+				})
+				Expect(err).To(BeAssignableToTypeOf(collections.ValidationError{}))
+				Expect(err).To(MatchError(ContainSubstring("partials include each other in a cycle: footer -> legal -> footer")))
+
+				Expect(partialsRepository.UpdateCall.Receives.Partial).To(Equal(models.Partial{}))
+			})
+
+			It("rejects a rename that would leave a template including a missing partial", func() {
+				partialsRepository.ListCall.Returns.Partials = []models.Partial{
+					{ID: "some-partial-id", ClientID: "some-client-id", Name: "footer", Body: "some footer"},
+				}
+				templatesRepository.ListByPartialsClientIDCall.Returns.Templates = []models.Template{
+					{Name: "some-template", HTML: `{{template "footer" .}}`},
+				}
+
+				_, err := partialsCollection.Set(conn, collections.Partial{
+					ID:       "some-partial-id",
+					Name:     "legal",
+					Body:     "some footer",
+					ClientID: "some-client-id",
+				})
+				Expect(err).To(MatchError(collections.ValidationError{errors.New(`template "some-template" would be invalid: html template is invalid at line 1, column 12: unknown partial "footer"`)}))
+
+				Expect(partialsRepository.UpdateCall.Receives.Partial).To(Equal(models.Partial{}))
+			})
+		})
+
+		Context("failure cases", func() {
+			It("returns a ValidationError when the name is invalid", func() {
+				_, err := partialsCollection.Set(conn, collections.Partial{
+					Name:     "a footer",
+					Body:     "some body",
+					ClientID: "some-client-id",
+				})
+				Expect(err).To(BeAssignableToTypeOf(collections.ValidationError{}))
+				Expect(err).To(MatchError(ContainSubstring(`partial name "a footer" is invalid`)))
+			})
+
+			It("returns a ValidationError when the body is missing", func() {
+				_, err := partialsCollection.Set(conn, collections.Partial{
+					Name:     "footer",
+					ClientID: "some-client-id",
+				})
+				Expect(err).To(MatchError(collections.ValidationError{errors.New("missing partial body")}))
+			})
+
+			It("returns a ValidationError when the body cannot be parsed", func() {
+				_, err := partialsCollection.Set(conn, collections.Partial{
+					Name:     "footer",
+					Body:     "{{.Organization",
+					ClientID: "some-client-id",
+				})
+				Expect(err).To(BeAssignableToTypeOf(collections.ValidationError{}))
+				Expect(err).To(MatchError(ContainSubstring(`partial "footer" template is invalid at line 1`)))
+			})
+
+			It("returns a PersistenceError when the partials cannot be listed", func() {
+				partialsRepository.ListCall.Returns.Error = errors.New("failed to list")
+
+				_, err := partialsCollection.Set(conn, collections.Partial{
+					Name:     "footer",
+					Body:     "some body",
+					ClientID: "some-client-id",
+				})
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("failed to list")}))
+			})
+
+			It("returns a PersistenceError when the templates cannot be listed", func() {
+				templatesRepository.ListByPartialsClientIDCall.Returns.Error = errors.New("failed to list")
+
+				_, err := partialsCollection.Set(conn, collections.Partial{
+					Name:     "footer",
+					Body:     "some body",
+					ClientID: "some-client-id",
+				})
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("failed to list")}))
+			})
+
+			It("returns a PersistenceError when the partial cannot be saved", func() {
+				partialsRepository.InsertCall.Returns.Error = errors.New("failed to insert")
+
+				_, err := partialsCollection.Set(conn, collections.Partial{
+					Name:     "footer",
+					Body:     "some body",
+					ClientID: "some-client-id",
+				})
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("failed to insert")}))
+			})
+		})
+	})
+
+	Describe("Get", func() {
+		It("returns the partial", func() {
+			partialsRepository.GetCall.Returns.Partial = models.Partial{
+				ID:       "some-partial-id",
+				ClientID: "some-client-id",
+				Name:     "footer",
+				Body:     "some body",
+			}
+
+			partial, err := partialsCollection.Get(conn, "some-partial-id", "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partial).To(Equal(collections.Partial{
+				ID:       "some-partial-id",
+				Name:     "footer",
+				Body:     "some body",
+				ClientID: "some-client-id",
+			}))
+
+			Expect(partialsRepository.GetCall.Receives.Connection).To(Equal(conn))
+			Expect(partialsRepository.GetCall.Receives.PartialID).To(Equal("some-partial-id"))
+		})
+
+		Context("failure cases", func() {
+			It("returns a NotFoundError when the partial belongs to another client", func() {
+				partialsRepository.GetCall.Returns.Partial = models.Partial{
+					ID:       "some-partial-id",
+					ClientID: "other-client-id",
+				}
+
+				_, err := partialsCollection.Get(conn, "some-partial-id", "some-client-id")
+				Expect(err).To(MatchError(collections.NotFoundError{errors.New(`Partial with id "some-partial-id" could not be found`)}))
+			})
+
+			It("returns a NotFoundError when the partial does not exist", func() {
+				partialsRepository.GetCall.Returns.Error = models.RecordNotFoundError{errors.New("not found")}
+
+				_, err := partialsCollection.Get(conn, "some-partial-id", "some-client-id")
+				Expect(err).To(MatchError(collections.NotFoundError{models.RecordNotFoundError{errors.New("not found")}}))
+			})
+
+			It("returns a PersistenceError for other errors", func() {
+				partialsRepository.GetCall.Returns.Error = errors.New("failed to get")
+
+				_, err := partialsCollection.Get(conn, "some-partial-id", "some-client-id")
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("failed to get")}))
+			})
+		})
+	})
+
+	Describe("List", func() {
+		It("returns the partials of the client", func() {
+			partialsRepository.ListCall.Returns.Partials = []models.Partial{
+				{ID: "some-partial-id", ClientID: "some-client-id", Name: "footer", Body: "some footer"},
+				{ID: "other-partial-id", ClientID: "some-client-id", Name: "header", Body: "some header"},
+			}
+
+			partials, err := partialsCollection.List(conn, "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partials).To(Equal([]collections.Partial{
+				{ID: "some-partial-id", Name: "footer", Body: "some footer", ClientID: "some-client-id"},
+				{ID: "other-partial-id", Name: "header", Body: "some header", ClientID: "some-client-id"},
+			}))
+
+			Expect(partialsRepository.ListCall.Receives.ClientID).To(Equal("some-client-id"))
+		})
+
+		It("returns a PersistenceError when the partials cannot be listed", func() {
+			partialsRepository.ListCall.Returns.Error = errors.New("failed to list")
+
+			_, err := partialsCollection.List(conn, "some-client-id")
+			Expect(err).To(MatchError(collections.PersistenceError{errors.New("failed to list")}))
+		})
+	})
+
+	Describe("Delete", func() {
+		BeforeEach(func() {
+			partialsRepository.GetCall.Returns.Partial = models.Partial{
+				ID:       "some-partial-id",
+				ClientID: "some-client-id",
+				Name:     "footer",
+				Body:     "some footer",
+			}
+			partialsRepository.ListCall.Returns.Partials = []models.Partial{
+				{ID: "some-partial-id", ClientID: "some-client-id", Name: "footer", Body: "some footer"},
+				{ID: "other-partial-id", ClientID: "some-client-id", Name: "header", Body: "some header"},
+			}
+		})
+
+		It("deletes the partial", func() {
+			err := partialsCollection.Delete(conn, "some-partial-id", "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(partialsRepository.DeleteCall.Receives.Connection).To(Equal(conn))
+			Expect(partialsRepository.DeleteCall.Receives.Partial).To(Equal(models.Partial{
+				ID:       "some-partial-id",
+				ClientID: "some-client-id",
+				Name:     "footer",
+				Body:     "some footer",
+			}))
+		})
+
+		It("refuses to delete a partial that one of the client's templates includes", func() {
+			templatesRepository.ListByPartialsClientIDCall.Returns.Templates = []models.Template{
+				{Name: "some-template", HTML: `{{template "header" .}}{{template "footer" .}}`},
+			}
+
+			err := partialsCollection.Delete(conn, "some-partial-id", "some-client-id")
+			Expect(err).To(MatchError(collections.ValidationError{errors.New(`template "some-template" would be invalid: html template is invalid at line 1, column 35: unknown partial "footer"`)}))
+
+			Expect(partialsRepository.DeleteCall.Receives.Partial).To(Equal(models.Partial{}))
+		})
+
+		It("refuses to delete a partial that a shared template the client saved includes", func() {
+			templatesRepository.ListByPartialsClientIDCall.Returns.Templates = []models.Template{
+				{ID: "default", Name: "The Default Template", HTML: `{{template "footer" .}}`, PartialsClientID: "some-client-id"},
+			}
+
+			err := partialsCollection.Delete(conn, "some-partial-id", "some-client-id")
+			Expect(err).To(MatchError(collections.ValidationError{errors.New(`template "The Default Template" would be invalid: html template is invalid at line 1, column 12: unknown partial "footer"`)}))
+
+			Expect(templatesRepository.ListByPartialsClientIDCall.Receives.ClientID).To(Equal("some-client-id"))
+			Expect(partialsRepository.DeleteCall.Receives.Partial).To(Equal(models.Partial{}))
+		})
+
+		Context("failure cases", func() {
+			It("returns a NotFoundError when the partial belongs to another client", func() {
+				err := partialsCollection.Delete(conn, "some-partial-id", "other-client-id")
+				Expect(err).To(MatchError(collections.NotFoundError{errors.New(`Partial with id "some-partial-id" could not be found`)}))
+			})
+
+			It("returns a PersistenceError when the partial cannot be deleted", func() {
+				partialsRepository.DeleteCall.Returns.Error = errors.New("failed to delete")
+
+				err := partialsCollection.Delete(conn, "some-partial-id", "some-client-id")
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("failed to delete")}))
+			})
+		})
+	})
+})
@@ -1,15 +1,21 @@
 package collections
 
 import (
+	"errors"
 	"fmt"
+	netmail "net/mail"
+	"strings"
 
 	"github.com/cloudfoundry-incubator/notifications/v2/models"
 )
 
 type Sender struct {
-	ID       string
-	Name     string
-	ClientID string
+	ID          string
+	Name        string
+	ClientID    string
+	FromAddress string
+	FromName    string
+	ReplyTo     string
 }
 
 type sendersRepository interface {
@@ -21,15 +27,20 @@ type sendersRepository interface {
 	Delete(conn models.ConnectionInterface, sender models.Sender) error
 }
 
+// SendersCollection manages the senders of a client. When allowedDomains
+// is not empty, a sender may only send from an address in one of those
+// domains.
 type SendersCollection struct {
-	senders       sendersRepository
-	campaignTypes campaignTypesRepository
+	senders        sendersRepository
+	campaignTypes  campaignTypesRepository
+	allowedDomains []string
 }
 
-func NewSendersCollection(senders sendersRepository, campaignTypes campaignTypesRepository) SendersCollection {
+func NewSendersCollection(senders sendersRepository, campaignTypes campaignTypesRepository, allowedDomains []string) SendersCollection {
 	return SendersCollection{
-		senders:       senders,
-		campaignTypes: campaignTypes,
+		senders:        senders,
+		campaignTypes:  campaignTypes,
+		allowedDomains: allowedDomains,
 	}
 }
 
@@ -39,10 +50,18 @@ func (sc SendersCollection) Set(conn ConnectionInterface, sender Sender) (Sender
 		err   error
 	)
 
+	err = sc.validate(sender)
+	if err != nil {
+		return Sender{}, ValidationError{err}
+	}
+
 	if sender.ID == "" {
 		model, err = sc.senders.Insert(conn, models.Sender{
-			Name:     sender.Name,
-			ClientID: sender.ClientID,
+			Name:        sender.Name,
+			ClientID:    sender.ClientID,
+			FromAddress: sender.FromAddress,
+			FromName:    sender.FromName,
+			ReplyTo:     sender.ReplyTo,
 		})
 		if err != nil {
 			switch err.(type) {
@@ -57,9 +76,12 @@ func (sc SendersCollection) Set(conn ConnectionInterface, sender Sender) (Sender
 		}
 	} else {
 		model, err = sc.senders.Update(conn, models.Sender{
-			ID:       sender.ID,
-			Name:     sender.Name,
-			ClientID: sender.ClientID,
+			ID:          sender.ID,
+			Name:        sender.Name,
+			ClientID:    sender.ClientID,
+			FromAddress: sender.FromAddress,
+			FromName:    sender.FromName,
+			ReplyTo:     sender.ReplyTo,
 		})
 		if err != nil {
 			switch err.(type) {
@@ -72,11 +94,7 @@ func (sc SendersCollection) Set(conn ConnectionInterface, sender Sender) (Sender
 
 	}
 
-	return Sender{
-		ID:       model.ID,
-		Name:     model.Name,
-		ClientID: model.ClientID,
-	}, nil
+	return newSender(model), nil
 }
 
 func (sc SendersCollection) List(conn ConnectionInterface, clientID string) ([]Sender, error) {
@@ -88,13 +106,7 @@ func (sc SendersCollection) List(conn ConnectionInterface, clientID string) ([]S
 	}
 
 	for _, model := range models {
-		sender := Sender{
-			ID:       model.ID,
-			Name:     model.Name,
-			ClientID: model.ClientID,
-		}
-
-		senderList = append(senderList, sender)
+		senderList = append(senderList, newSender(model))
 	}
 
 	return senderList, nil
@@ -115,11 +127,7 @@ func (sc SendersCollection) Get(conn ConnectionInterface, senderID, clientID str
 		return Sender{}, NotFoundError{fmt.Errorf("Sender with id %q could not be found", senderID)}
 	}
 
-	return Sender{
-		ID:       model.ID,
-		Name:     model.Name,
-		ClientID: model.ClientID,
-	}, nil
+	return newSender(model), nil
 }
 
 func (sc SendersCollection) Delete(conn ConnectionInterface, senderID, clientID string) error {
@@ -156,3 +164,60 @@ func (sc SendersCollection) Delete(conn ConnectionInterface, senderID, clientID
 
 	return nil
 }
+
+func (sc SendersCollection) validate(sender Sender) error {
+	if sender.FromAddress == "" {
+		if sender.FromName != "" {
+			return errors.New("from_name cannot be set without a from_address")
+		}
+	} else {
+		if !isBareAddress(sender.FromAddress) {
+			return fmt.Errorf("from_address %q is not a valid email address", sender.FromAddress)
+		}
+
+		domain := sender.FromAddress[strings.LastIndex(sender.FromAddress, "@")+1:]
+		if !sc.domainAllowed(domain) {
+			return fmt.Errorf("from_address domain %q is not allowed", domain)
+		}
+	}
+
+	if sender.ReplyTo != "" && !isBareAddress(sender.ReplyTo) {
+		return fmt.Errorf("reply_to %q is not a valid email address", sender.ReplyTo)
+	}
+
+	return nil
+}
+
+func (sc SendersCollection) domainAllowed(domain string) bool {
+	if len(sc.allowedDomains) == 0 {
+		return true
+	}
+
+	for _, allowed := range sc.allowedDomains {
+		if strings.EqualFold(domain, allowed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isBareAddress(value string) bool {
+	address, err := netmail.ParseAddress(value)
+	if err != nil {
+		return false
+	}
+
+	return address.Name == "" && address.Address == value
+}
+
+func newSender(model models.Sender) Sender {
+	return Sender{
+		ID:          model.ID,
+		Name:        model.Name,
+		ClientID:    model.ClientID,
+		FromAddress: model.FromAddress,
+		FromName:    model.FromName,
+		ReplyTo:     model.ReplyTo,
+	}
+}
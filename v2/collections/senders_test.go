@@ -23,7 +23,7 @@ var _ = Describe("SendersCollection", func() {
 		sendersRepository = mocks.NewSendersRepository()
 		campaignTypesRepository = mocks.NewCampaignTypesRepository()
 
-		sendersCollection = collections.NewSendersCollection(sendersRepository, campaignTypesRepository)
+		sendersCollection = collections.NewSendersCollection(sendersRepository, campaignTypesRepository, []string{"example.com"})
 		conn = mocks.NewConnection()
 	})
 
@@ -114,6 +114,106 @@ var _ = Describe("SendersCollection", func() {
 			}))
 		})
 
+		It("stores the from address, from name and reply to of the sender", func() {
+			sendersRepository.InsertCall.Returns.Sender = models.Sender{
+				ID:          "some-sender-id",
+				Name:        "some-sender",
+				ClientID:    "some-client-id",
+				FromAddress: "no-reply@EXAMPLE.com",
+				FromName:    "Some Sender",
+				ReplyTo:     "support@example.org",
+			}
+
+			sender, err := sendersCollection.Set(conn, collections.Sender{
+				Name:        "some-sender",
+				ClientID:    "some-client-id",
+				FromAddress: "no-reply@EXAMPLE.com",
+				FromName:    "Some Sender",
+				ReplyTo:     "support@example.org",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sender).To(Equal(collections.Sender{
+				ID:          "some-sender-id",
+				Name:        "some-sender",
+				ClientID:    "some-client-id",
+				FromAddress: "no-reply@EXAMPLE.com",
+				FromName:    "Some Sender",
+				ReplyTo:     "support@example.org",
+			}))
+
+			Expect(sendersRepository.InsertCall.Receives.Sender).To(Equal(models.Sender{
+				Name:        "some-sender",
+				ClientID:    "some-client-id",
+				FromAddress: "no-reply@EXAMPLE.com",
+				FromName:    "Some Sender",
+				ReplyTo:     "support@example.org",
+			}))
+		})
+
+		It("allows any from address domain when no domains are configured", func() {
+			sendersCollection = collections.NewSendersCollection(sendersRepository, campaignTypesRepository, nil)
+
+			_, err := sendersCollection.Set(conn, collections.Sender{
+				Name:        "some-sender",
+				ClientID:    "some-client-id",
+				FromAddress: "no-reply@example.org",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sendersRepository.InsertCall.Receives.Sender.FromAddress).To(Equal("no-reply@example.org"))
+		})
+
+		Context("when the sender is invalid", func() {
+			It("rejects a from address outside of the allowed domains", func() {
+				_, err := sendersCollection.Set(conn, collections.Sender{
+					Name:        "some-sender",
+					ClientID:    "some-client-id",
+					FromAddress: "no-reply@example.org",
+				})
+				Expect(err).To(MatchError(collections.ValidationError{errors.New(`from_address domain "example.org" is not allowed`)}))
+				Expect(sendersRepository.InsertCall.Receives.Sender).To(Equal(models.Sender{}))
+			})
+
+			It("rejects a malformed from address", func() {
+				_, err := sendersCollection.Set(conn, collections.Sender{
+					Name:        "some-sender",
+					ClientID:    "some-client-id",
+					FromAddress: "not-an-address",
+				})
+				Expect(err).To(MatchError(collections.ValidationError{errors.New(`from_address "not-an-address" is not a valid email address`)}))
+				Expect(sendersRepository.InsertCall.Receives.Sender).To(Equal(models.Sender{}))
+			})
+
+			It("rejects a from address with a display name", func() {
+				_, err := sendersCollection.Set(conn, collections.Sender{
+					Name:        "some-sender",
+					ClientID:    "some-client-id",
+					FromAddress: "Me <no-reply@example.com>",
+				})
+				Expect(err).To(MatchError(collections.ValidationError{errors.New(`from_address "Me <no-reply@example.com>" is not a valid email address`)}))
+				Expect(sendersRepository.InsertCall.Receives.Sender).To(Equal(models.Sender{}))
+			})
+
+			It("rejects a from name without a from address", func() {
+				_, err := sendersCollection.Set(conn, collections.Sender{
+					Name:     "some-sender",
+					ClientID: "some-client-id",
+					FromName: "Some Sender",
+				})
+				Expect(err).To(MatchError(collections.ValidationError{errors.New("from_name cannot be set without a from_address")}))
+				Expect(sendersRepository.InsertCall.Receives.Sender).To(Equal(models.Sender{}))
+			})
+
+			It("rejects a malformed reply to", func() {
+				_, err := sendersCollection.Set(conn, collections.Sender{
+					Name:     "some-sender",
+					ClientID: "some-client-id",
+					ReplyTo:  "support",
+				})
+				Expect(err).To(MatchError(collections.ValidationError{errors.New(`reply_to "support" is not a valid email address`)}))
+				Expect(sendersRepository.InsertCall.Receives.Sender).To(Equal(models.Sender{}))
+			})
+		})
+
 		Context("failure cases", func() {
 			Context("when inserting", func() {
 				It("handles unexpected database errors", func() {
@@ -0,0 +1,98 @@
+package collections
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+)
+
+type Suppression struct {
+	ID        string
+	Email     string
+	Reason    string
+	CreatedAt time.Time
+}
+
+type suppressionsRepository interface {
+	Insert(connection models.ConnectionInterface, suppression models.Suppression) (models.Suppression, error)
+	Get(connection models.ConnectionInterface, id string) (models.Suppression, error)
+	List(connection models.ConnectionInterface) ([]models.Suppression, error)
+	Delete(connection models.ConnectionInterface, id string) error
+}
+
+type SuppressionsCollection struct {
+	repo suppressionsRepository
+}
+
+func NewSuppressionsCollection(repo suppressionsRepository) SuppressionsCollection {
+	return SuppressionsCollection{
+		repo: repo,
+	}
+}
+
+func (c SuppressionsCollection) Set(connection ConnectionInterface, suppression Suppression) (Suppression, error) {
+	model, err := c.repo.Insert(connection, models.Suppression{
+		Email:  suppression.Email,
+		Reason: suppression.Reason,
+	})
+	if err != nil {
+		switch err.(type) {
+		case models.DuplicateRecordError:
+			return Suppression{}, DuplicateRecordError{err}
+		default:
+			return Suppression{}, PersistenceError{err}
+		}
+	}
+
+	return newSuppression(model), nil
+}
+
+func (c SuppressionsCollection) List(connection ConnectionInterface) ([]Suppression, error) {
+	suppressions, err := c.repo.List(connection)
+	if err != nil {
+		return nil, PersistenceError{err}
+	}
+
+	list := []Suppression{}
+	for _, suppression := range suppressions {
+		list = append(list, newSuppression(suppression))
+	}
+
+	return list, nil
+}
+
+func (c SuppressionsCollection) Get(connection ConnectionInterface, id string) (Suppression, error) {
+	suppression, err := c.repo.Get(connection, id)
+	if err != nil {
+		return Suppression{}, translateSuppressionError(err)
+	}
+
+	return newSuppression(suppression), nil
+}
+
+func (c SuppressionsCollection) Delete(connection ConnectionInterface, id string) error {
+	err := c.repo.Delete(connection, id)
+	if err != nil {
+		return translateSuppressionError(err)
+	}
+
+	return nil
+}
+
+func newSuppression(suppression models.Suppression) Suppression {
+	return Suppression{
+		ID:        suppression.ID,
+		Email:     suppression.Email,
+		Reason:    suppression.Reason,
+		CreatedAt: suppression.CreatedAt,
+	}
+}
+
+func translateSuppressionError(err error) error {
+	switch err.(type) {
+	case models.RecordNotFoundError:
+		return NotFoundError{err}
+	default:
+		return PersistenceError{err}
+	}
+}
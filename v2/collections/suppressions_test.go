@@ -0,0 +1,179 @@
+package collections_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SuppressionsCollection", func() {
+	var (
+		suppressionsRepository *mocks.SuppressionsRepository
+		connection             *mocks.Connection
+		collection             collections.SuppressionsCollection
+		createdAt              time.Time
+	)
+
+	BeforeEach(func() {
+		suppressionsRepository = mocks.NewSuppressionsRepository()
+		connection = mocks.NewConnection()
+		createdAt = time.Now().UTC().Truncate(time.Second)
+
+		collection = collections.NewSuppressionsCollection(suppressionsRepository)
+	})
+
+	Describe("Set", func() {
+		It("inserts the suppression", func() {
+			suppressionsRepository.InsertCall.Returns.Suppression = models.Suppression{
+				ID:        "some-suppression-id",
+				Email:     "user@example.com",
+				Reason:    "complaint",
+				CreatedAt: createdAt,
+			}
+
+			suppression, err := collection.Set(connection, collections.Suppression{
+				Email:  "User@example.com",
+				Reason: "complaint",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(suppression).To(Equal(collections.Suppression{
+				ID:        "some-suppression-id",
+				Email:     "user@example.com",
+				Reason:    "complaint",
+				CreatedAt: createdAt,
+			}))
+
+			Expect(suppressionsRepository.InsertCall.Receives.Connection).To(Equal(connection))
+			Expect(suppressionsRepository.InsertCall.Receives.Suppressions).To(Equal([]models.Suppression{
+				{
+					Email:  "User@example.com",
+					Reason: "complaint",
+				},
+			}))
+		})
+
+		Context("when the email is already suppressed", func() {
+			It("returns a duplicate record error", func() {
+				suppressionsRepository.InsertCall.Returns.Error = models.DuplicateRecordError{errors.New("duplicate")}
+
+				_, err := collection.Set(connection, collections.Suppression{Email: "user@example.com"})
+				Expect(err).To(MatchError(collections.DuplicateRecordError{models.DuplicateRecordError{errors.New("duplicate")}}))
+			})
+		})
+
+		Context("when the repository errors", func() {
+			It("returns a persistence error", func() {
+				suppressionsRepository.InsertCall.Returns.Error = errors.New("some insert error")
+
+				_, err := collection.Set(connection, collections.Suppression{Email: "user@example.com"})
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("some insert error")}))
+			})
+		})
+	})
+
+	Describe("List", func() {
+		It("returns all of the suppressions", func() {
+			suppressionsRepository.ListCall.Returns.Suppressions = []models.Suppression{
+				{
+					ID:        "some-suppression-id",
+					Email:     "user@example.com",
+					Reason:    "bounce",
+					CreatedAt: createdAt,
+				},
+			}
+
+			suppressions, err := collection.List(connection)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(suppressions).To(Equal([]collections.Suppression{
+				{
+					ID:        "some-suppression-id",
+					Email:     "user@example.com",
+					Reason:    "bounce",
+					CreatedAt: createdAt,
+				},
+			}))
+
+			Expect(suppressionsRepository.ListCall.Receives.Connection).To(Equal(connection))
+		})
+
+		Context("when the repository errors", func() {
+			It("returns a persistence error", func() {
+				suppressionsRepository.ListCall.Returns.Error = errors.New("some list error")
+
+				_, err := collection.List(connection)
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("some list error")}))
+			})
+		})
+	})
+
+	Describe("Get", func() {
+		It("returns the suppression", func() {
+			suppressionsRepository.GetCall.Returns.Suppression = models.Suppression{
+				ID:    "some-suppression-id",
+				Email: "user@example.com",
+			}
+
+			suppression, err := collection.Get(connection, "some-suppression-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(suppression).To(Equal(collections.Suppression{
+				ID:    "some-suppression-id",
+				Email: "user@example.com",
+			}))
+
+			Expect(suppressionsRepository.GetCall.Receives.Connection).To(Equal(connection))
+			Expect(suppressionsRepository.GetCall.Receives.ID).To(Equal("some-suppression-id"))
+		})
+
+		Context("when the suppression cannot be found", func() {
+			It("returns a not found error", func() {
+				suppressionsRepository.GetCall.Returns.Error = models.RecordNotFoundError{errors.New("not found")}
+
+				_, err := collection.Get(connection, "missing-id")
+				Expect(err).To(MatchError(collections.NotFoundError{models.RecordNotFoundError{errors.New("not found")}}))
+			})
+		})
+
+		Context("when the repository errors", func() {
+			It("returns a persistence error", func() {
+				suppressionsRepository.GetCall.Returns.Error = errors.New("some get error")
+
+				_, err := collection.Get(connection, "some-suppression-id")
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("some get error")}))
+			})
+		})
+	})
+
+	Describe("Delete", func() {
+		It("deletes the suppression", func() {
+			err := collection.Delete(connection, "some-suppression-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(suppressionsRepository.DeleteCall.Receives.Connection).To(Equal(connection))
+			Expect(suppressionsRepository.DeleteCall.Receives.ID).To(Equal("some-suppression-id"))
+		})
+
+		Context("when the suppression cannot be found", func() {
+			It("returns a not found error", func() {
+				suppressionsRepository.DeleteCall.Returns.Error = models.RecordNotFoundError{errors.New("not found")}
+
+				err := collection.Delete(connection, "missing-id")
+				Expect(err).To(MatchError(collections.NotFoundError{models.RecordNotFoundError{errors.New("not found")}}))
+			})
+		})
+
+		Context("when the repository errors", func() {
+			It("returns a persistence error", func() {
+				suppressionsRepository.DeleteCall.Returns.Error = errors.New("some delete error")
+
+				err := collection.Delete(connection, "some-suppression-id")
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("some delete error")}))
+			})
+		})
+	})
+})
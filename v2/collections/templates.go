@@ -2,7 +2,9 @@ package collections
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v2/models"
 )
 
@@ -14,6 +16,26 @@ type Template struct {
 	Subject  string
 	Metadata string
 	ClientID string
+
+	// PartialsClientID is the client whose partials the template includes,
+	// the client that last saved it. For the templates of a client it is
+	// that client; the default template is shared by every client, so it
+	// does not use the partials of the client sending with it.
+	PartialsClientID string
+}
+
+// TemplateVersion is a template as it was saved by an update, along with
+// the client that saved it.
+type TemplateVersion struct {
+	TemplateID string
+	Version    int
+	Name       string
+	HTML       string
+	Text       string
+	Subject    string
+	Metadata   string
+	ClientID   string
+	CreatedAt  time.Time
 }
 
 type templatesRepository interface {
@@ -22,19 +44,99 @@ type templatesRepository interface {
 	Get(conn models.ConnectionInterface, templateID string) (retrievedTemplate models.Template, err error)
 	Delete(conn models.ConnectionInterface, templateID string) error
 	List(conn models.ConnectionInterface, clientID string) (templateList []models.Template, err error)
+	ListByPartialsClientID(conn models.ConnectionInterface, clientID string) (templateList []models.Template, err error)
+	Lock(conn models.ConnectionInterface, templateID string) error
+}
+
+type templateVersionsRepository interface {
+	Insert(conn models.ConnectionInterface, version models.TemplateVersion) (insertedVersion models.TemplateVersion, err error)
+	List(conn models.ConnectionInterface, templateID string) (versions []models.TemplateVersion, err error)
+	Get(conn models.ConnectionInterface, templateID string, version int) (retrievedVersion models.TemplateVersion, err error)
 }
 
 type TemplatesCollection struct {
-	repo templatesRepository
+	repo         templatesRepository
+	versionsRepo templateVersionsRepository
+	partialsRepo partialsRepository
 }
 
-func NewTemplatesCollection(repo templatesRepository) TemplatesCollection {
+func NewTemplatesCollection(repo templatesRepository, versionsRepo templateVersionsRepository, partialsRepo partialsRepository) TemplatesCollection {
 	return TemplatesCollection{
-		repo: repo,
+		repo:         repo,
+		versionsRepo: versionsRepo,
+		partialsRepo: partialsRepo,
 	}
 }
 
-func (c TemplatesCollection) Set(conn ConnectionInterface, template Template) (Template, error) {
+// Set creates or updates a template. The template is validated against the
+// partials of clientID, which it then resolves whichever client sends with
+// it, and every update is recorded as a new version of the template,
+// attributed to clientID. The template is locked while it is
+// updated, so that concurrent updates are numbered one after the other.
+func (c TemplatesCollection) Set(conn ConnectionInterface, template Template, clientID string) (Template, error) {
+	partials, err := c.partialsRepo.List(conn, clientID)
+	if err != nil {
+		return Template{}, PersistenceError{err}
+	}
+
+	err = common.ValidateTemplates(template.Subject, template.Text, template.HTML, partialBodies(partials))
+	if err != nil {
+		return Template{}, ValidationError{err}
+	}
+
+	template.PartialsClientID = clientID
+
+	if template.ID == "" {
+		return c.save(conn, template)
+	}
+
+	transaction := conn.Transaction()
+	err = transaction.Begin()
+	if err != nil {
+		return Template{}, PersistenceError{err}
+	}
+
+	err = c.repo.Lock(transaction, template.ID)
+	if err != nil {
+		transaction.Rollback()
+		return Template{}, PersistenceError{err}
+	}
+
+	err = c.recordOriginalVersion(transaction, template.ID)
+	if err != nil {
+		transaction.Rollback()
+		return Template{}, err
+	}
+
+	saved, err := c.save(transaction, template)
+	if err != nil {
+		transaction.Rollback()
+		return Template{}, err
+	}
+
+	_, err = c.versionsRepo.Insert(transaction, models.TemplateVersion{
+		TemplateID: saved.ID,
+		Name:       saved.Name,
+		HTML:       saved.HTML,
+		Text:       saved.Text,
+		Subject:    saved.Subject,
+		Metadata:   saved.Metadata,
+		ClientID:   clientID,
+	})
+	if err != nil {
+		transaction.Rollback()
+		return Template{}, PersistenceError{err}
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		return Template{}, PersistenceError{err}
+	}
+
+	return saved, nil
+}
+
+func (c TemplatesCollection) save(conn ConnectionInterface, template Template) (Template, error) {
 	if template.ID == "" || template.ID == models.DefaultTemplate.ID {
 		model, err := c.repo.Insert(conn, models.Template{
 			ID:       template.ID,
@@ -44,6 +146,8 @@ func (c TemplatesCollection) Set(conn ConnectionInterface, template Template) (T
 			Subject:  template.Subject,
 			Metadata: template.Metadata,
 			ClientID: template.ClientID,
+
+			PartialsClientID: template.PartialsClientID,
 		})
 		if err != nil {
 			switch err.(type) {
@@ -54,20 +158,112 @@ func (c TemplatesCollection) Set(conn ConnectionInterface, template Template) (T
 			}
 		}
 
-		return Template{
-			ID:       model.ID,
-			Name:     model.Name,
-			HTML:     model.HTML,
-			Text:     model.Text,
-			Subject:  model.Subject,
-			Metadata: model.Metadata,
-			ClientID: model.ClientID,
-		}, nil
+		return newTemplate(model), nil
 	}
 
 	return c.updateExistingRecord(conn, template)
 }
 
+// recordOriginalVersion saves the content a template had before its first
+// tracked update, so that the first update can be rolled back too. The
+// content is attributed to the client that owns the template.
+func (c TemplatesCollection) recordOriginalVersion(conn ConnectionInterface, templateID string) error {
+	versions, err := c.versionsRepo.List(conn, templateID)
+	if err != nil {
+		return PersistenceError{err}
+	}
+
+	if len(versions) > 0 {
+		return nil
+	}
+
+	existing, err := c.repo.Get(conn, templateID)
+	if err != nil {
+		switch err.(type) {
+		case models.RecordNotFoundError:
+			return NotFoundError{err}
+		default:
+			return PersistenceError{err}
+		}
+	}
+
+	_, err = c.versionsRepo.Insert(conn, models.TemplateVersion{
+		TemplateID: existing.ID,
+		Name:       existing.Name,
+		HTML:       existing.HTML,
+		Text:       existing.Text,
+		Subject:    existing.Subject,
+		Metadata:   existing.Metadata,
+		ClientID:   existing.ClientID,
+	})
+	if err != nil {
+		return PersistenceError{err}
+	}
+
+	return nil
+}
+
+// ListVersions returns the recorded versions of a template, newest first.
+func (c TemplatesCollection) ListVersions(conn ConnectionInterface, templateID, clientID string) ([]TemplateVersion, error) {
+	_, err := c.Get(conn, templateID, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := c.versionsRepo.List(conn, templateID)
+	if err != nil {
+		return nil, PersistenceError{err}
+	}
+
+	versionList := []TemplateVersion{}
+	for _, version := range versions {
+		versionList = append(versionList, newTemplateVersion(version))
+	}
+
+	return versionList, nil
+}
+
+func (c TemplatesCollection) GetVersion(conn ConnectionInterface, templateID string, version int, clientID string) (TemplateVersion, error) {
+	_, err := c.Get(conn, templateID, clientID)
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+
+	templateVersion, err := c.versionsRepo.Get(conn, templateID, version)
+	if err != nil {
+		switch err.(type) {
+		case models.RecordNotFoundError:
+			return TemplateVersion{}, NotFoundError{err}
+		default:
+			return TemplateVersion{}, PersistenceError{err}
+		}
+	}
+
+	return newTemplateVersion(templateVersion), nil
+}
+
+// Rollback restores a template to the content of an earlier version. The
+// rollback is itself recorded as a new version, so it can be undone.
+func (c TemplatesCollection) Rollback(conn ConnectionInterface, templateID string, version int, clientID string) (Template, error) {
+	template, err := c.Get(conn, templateID, clientID)
+	if err != nil {
+		return Template{}, err
+	}
+
+	templateVersion, err := c.GetVersion(conn, templateID, version, clientID)
+	if err != nil {
+		return Template{}, err
+	}
+
+	template.Name = templateVersion.Name
+	template.HTML = templateVersion.HTML
+	template.Text = templateVersion.Text
+	template.Subject = templateVersion.Subject
+	template.Metadata = templateVersion.Metadata
+
+	return c.Set(conn, template, clientID)
+}
+
 func (c TemplatesCollection) Get(conn ConnectionInterface, templateID, clientID string) (Template, error) {
 	template, err := c.repo.Get(conn, templateID)
 	if err != nil {
@@ -83,15 +279,7 @@ func (c TemplatesCollection) Get(conn ConnectionInterface, templateID, clientID
 		return Template{}, NotFoundError{fmt.Errorf("Template with id %q could not be found", templateID)}
 	}
 
-	return Template{
-		ID:       template.ID,
-		Name:     template.Name,
-		HTML:     template.HTML,
-		Text:     template.Text,
-		Subject:  template.Subject,
-		Metadata: template.Metadata,
-		ClientID: template.ClientID,
-	}, nil
+	return newTemplate(template), nil
 }
 
 func (c TemplatesCollection) Delete(conn ConnectionInterface, templateID string) error {
@@ -117,15 +305,7 @@ func (c TemplatesCollection) List(conn ConnectionInterface, clientID string) ([]
 	}
 
 	for _, template := range templates {
-		templateList = append(templateList, Template{
-			ID:       template.ID,
-			Name:     template.Name,
-			HTML:     template.HTML,
-			Text:     template.Text,
-			Subject:  template.Subject,
-			Metadata: template.Metadata,
-			ClientID: template.ClientID,
-		})
+		templateList = append(templateList, newTemplate(template))
 	}
 
 	return templateList, nil
@@ -140,11 +320,17 @@ func (c TemplatesCollection) updateExistingRecord(conn ConnectionInterface, temp
 		Subject:  template.Subject,
 		Metadata: template.Metadata,
 		ClientID: template.ClientID,
+
+		PartialsClientID: template.PartialsClientID,
 	})
 	if err != nil {
 		return Template{}, PersistenceError{err}
 	}
 
+	return newTemplate(model), nil
+}
+
+func newTemplate(model models.Template) Template {
 	return Template{
 		ID:       model.ID,
 		Name:     model.Name,
@@ -153,5 +339,21 @@ func (c TemplatesCollection) updateExistingRecord(conn ConnectionInterface, temp
 		Subject:  model.Subject,
 		Metadata: model.Metadata,
 		ClientID: model.ClientID,
-	}, nil
+
+		PartialsClientID: model.PartialsClientID,
+	}
+}
+
+func newTemplateVersion(version models.TemplateVersion) TemplateVersion {
+	return TemplateVersion{
+		TemplateID: version.TemplateID,
+		Version:    version.Version,
+		Name:       version.Name,
+		HTML:       version.HTML,
+		Text:       version.Text,
+		Subject:    version.Subject,
+		Metadata:   version.Metadata,
+		ClientID:   version.ClientID,
+		CreatedAt:  version.CreatedAt,
+	}
 }
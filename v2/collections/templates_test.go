@@ -2,7 +2,9 @@ package collections_test
 
 import (
 	"errors"
+	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v2/collections"
 	"github.com/cloudfoundry-incubator/notifications/v2/models"
@@ -15,14 +17,21 @@ var _ = Describe("TemplatesCollection", func() {
 	var (
 		templatesCollection collections.TemplatesCollection
 		templatesRepository *mocks.TemplatesRepository
+		versionsRepository  *mocks.TemplateVersionsRepository
+		partialsRepository  *mocks.PartialsRepository
 		conn                *mocks.Connection
+		transaction         *mocks.Transaction
 	)
 
 	BeforeEach(func() {
 		templatesRepository = mocks.NewTemplatesRepository()
+		versionsRepository = mocks.NewTemplateVersionsRepository()
+		partialsRepository = mocks.NewPartialsRepository()
 
-		templatesCollection = collections.NewTemplatesCollection(templatesRepository)
+		templatesCollection = collections.NewTemplatesCollection(templatesRepository, versionsRepository, partialsRepository)
 		conn = mocks.NewConnection()
+		transaction = mocks.NewTransaction()
+		conn.TransactionCall.Returns.Transaction = transaction
 	})
 
 	Describe("Set", func() {
@@ -43,7 +52,7 @@ var _ = Describe("TemplatesCollection", func() {
 					HTML:     "<h1>My Cool Template</h1>",
 					Subject:  "{{.Subject}}",
 					ClientID: "some-client-id",
-				})
+				}, "some-client-id")
 				Expect(err).NotTo(HaveOccurred())
 				Expect(template).To(Equal(collections.Template{
 					ID:       "some-template-id",
@@ -59,6 +68,8 @@ var _ = Describe("TemplatesCollection", func() {
 					HTML:     "<h1>My Cool Template</h1>",
 					Subject:  "{{.Subject}}",
 					ClientID: "some-client-id",
+
+					PartialsClientID: "some-client-id",
 				}))
 			})
 		})
@@ -81,7 +92,7 @@ var _ = Describe("TemplatesCollection", func() {
 					HTML:     "<h1>My Cool Template</h1>",
 					Subject:  "{{.Subject}}",
 					ClientID: "some-client-id",
-				})
+				}, "some-client-id")
 				Expect(err).NotTo(HaveOccurred())
 				Expect(template).To(Equal(collections.Template{
 					ID:       "existing-id",
@@ -91,16 +102,34 @@ var _ = Describe("TemplatesCollection", func() {
 					ClientID: "some-client-id",
 				}))
 
-				Expect(templatesRepository.UpdateCall.Receives.Connection).To(Equal(conn))
+				Expect(templatesRepository.UpdateCall.Receives.Connection).To(Equal(transaction))
 				Expect(templatesRepository.UpdateCall.Receives.Template).To(Equal(models.Template{
 					ID:       "existing-id",
 					Name:     "new-template",
 					HTML:     "<h1>My Cool Template</h1>",
 					Subject:  "{{.Subject}}",
 					ClientID: "some-client-id",
+
+					PartialsClientID: "some-client-id",
 				}))
 			})
 
+			It("locks the template while it is updated", func() {
+				_, err := templatesCollection.Set(conn, collections.Template{
+					ID:       "existing-id",
+					Name:     "new-template",
+					HTML:     "<h1>My Cool Template</h1>",
+					ClientID: "some-client-id",
+				}, "some-client-id")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(transaction.BeginCall.WasCalled).To(BeTrue())
+				Expect(templatesRepository.LockCall.Receives.Connection).To(Equal(transaction))
+				Expect(templatesRepository.LockCall.Receives.TemplateID).To(Equal("existing-id"))
+				Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+				Expect(transaction.RollbackCall.WasCalled).To(BeFalse())
+			})
+
 			Context("when the default template ID is supplied", func() {
 				It("will create a new record if it does not already exist", func() {
 					_, err := templatesCollection.Set(conn, collections.Template{
@@ -110,10 +139,10 @@ var _ = Describe("TemplatesCollection", func() {
 						HTML:     "new default html",
 						Subject:  "New Default Subject",
 						ClientID: "",
-					})
+					}, "some-client-id")
 					Expect(err).NotTo(HaveOccurred())
 
-					Expect(templatesRepository.InsertCall.Receives.Connection).To(Equal(conn))
+					Expect(templatesRepository.InsertCall.Receives.Connection).To(Equal(transaction))
 					Expect(templatesRepository.InsertCall.Receives.Template).To(Equal(models.Template{
 						ID:       "default",
 						Name:     "updated default",
@@ -121,6 +150,8 @@ var _ = Describe("TemplatesCollection", func() {
 						HTML:     "new default html",
 						Subject:  "New Default Subject",
 						ClientID: "",
+
+						PartialsClientID: "some-client-id",
 					}))
 				})
 
@@ -133,10 +164,10 @@ var _ = Describe("TemplatesCollection", func() {
 						HTML:     "new default html",
 						Subject:  "New Default Subject",
 						ClientID: "",
-					})
+					}, "some-client-id")
 					Expect(err).NotTo(HaveOccurred())
 
-					Expect(templatesRepository.UpdateCall.Receives.Connection).To(Equal(conn))
+					Expect(templatesRepository.UpdateCall.Receives.Connection).To(Equal(transaction))
 					Expect(templatesRepository.UpdateCall.Receives.Template).To(Equal(models.Template{
 						ID:       "default",
 						Name:     "updated default",
@@ -144,6 +175,8 @@ var _ = Describe("TemplatesCollection", func() {
 						HTML:     "new default html",
 						Subject:  "New Default Subject",
 						ClientID: "",
+
+						PartialsClientID: "some-client-id",
 					}))
 				})
 			})
@@ -158,7 +191,7 @@ var _ = Describe("TemplatesCollection", func() {
 						HTML:     "<h1>My Cool Template</h1>",
 						Subject:  "{{.Subject}}",
 						ClientID: "some-client-id",
-					})
+					}, "some-client-id")
 					Expect(err).To(MatchError(collections.PersistenceError{repoError}))
 				})
 
@@ -172,9 +205,500 @@ var _ = Describe("TemplatesCollection", func() {
 						HTML:     "<h1>My Cool Template</h1>",
 						Subject:  "{{.Subject}}",
 						ClientID: "some-client-id",
-					})
+					}, "some-client-id")
 					Expect(err).To(MatchError(collections.PersistenceError{repoError}))
+					Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+					Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+				})
+
+				It("returns a PersistenceError when the template cannot be locked", func() {
+					templatesRepository.LockCall.Returns.Error = errors.New("lock wait timeout")
+
+					_, err := templatesCollection.Set(conn, collections.Template{
+						ID:       "existing-id",
+						Name:     "new-template",
+						HTML:     "<h1>My Cool Template</h1>",
+						ClientID: "some-client-id",
+					}, "some-client-id")
+					Expect(err).To(MatchError(collections.PersistenceError{errors.New("lock wait timeout")}))
+
+					Expect(templatesRepository.UpdateCall.Receives.Template).To(Equal(models.Template{}))
+					Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+				})
+
+				It("returns a PersistenceError when the transaction cannot begin", func() {
+					transaction.BeginCall.Returns.Error = errors.New("cannot begin")
+
+					_, err := templatesCollection.Set(conn, collections.Template{
+						ID:       "existing-id",
+						Name:     "new-template",
+						HTML:     "<h1>My Cool Template</h1>",
+						ClientID: "some-client-id",
+					}, "some-client-id")
+					Expect(err).To(MatchError(collections.PersistenceError{errors.New("cannot begin")}))
+
+					Expect(templatesRepository.UpdateCall.Receives.Template).To(Equal(models.Template{}))
+				})
+
+				It("returns a PersistenceError when the transaction cannot be committed", func() {
+					transaction.CommitCall.Returns.Error = errors.New("cannot commit")
+
+					_, err := templatesCollection.Set(conn, collections.Template{
+						ID:       "existing-id",
+						Name:     "new-template",
+						HTML:     "<h1>My Cool Template</h1>",
+						ClientID: "some-client-id",
+					}, "some-client-id")
+					Expect(err).To(MatchError(collections.PersistenceError{errors.New("cannot commit")}))
+				})
+
+				It("returns a ValidationError when the template has invalid syntax", func() {
+					_, err := templatesCollection.Set(conn, collections.Template{
+						Name:     "some-template",
+						HTML:     "<h1>{{.Subject</h1>",
+						Subject:  "{{.Subject}}",
+						ClientID: "some-client-id",
+					}, "some-client-id")
+					Expect(err).To(BeAssignableToTypeOf(collections.ValidationError{}))
+					Expect(err.Error()).To(HavePrefix("html template is invalid at line 1"))
+
+					Expect(templatesRepository.InsertCall.Receives.Template).To(Equal(models.Template{}))
 				})
+
+				It("returns a ValidationError when the template references an unknown field", func() {
+					_, err := templatesCollection.Set(conn, collections.Template{
+						ID:       "some-template-id",
+						Name:     "some-template",
+						Text:     "Hello {{.UserGUID}},\n{{.Endorsment}}",
+						Subject:  "{{.Subject}}",
+						ClientID: "some-client-id",
+					}, "some-client-id")
+					Expect(err).To(MatchError(collections.ValidationError{common.TemplateError{
+						Part:    "text",
+						Line:    2,
+						Column:  3,
+						Message: `unknown field "Endorsment"`,
+					}}))
+
+					Expect(templatesRepository.UpdateCall.Receives.Template).To(Equal(models.Template{}))
+				})
+
+				It("returns a ValidationError when the template includes a partial the client does not have", func() {
+					partialsRepository.ListCall.Returns.Partials = []models.Partial{
+						{Name: "header", Body: "<header>{{.Subject}}</header>"},
+					}
+
+					_, err := templatesCollection.Set(conn, collections.Template{
+						Name:     "some-template",
+						HTML:     `{{template "header" .}}{{template "footer" .}}`,
+						Subject:  "{{.Subject}}",
+						ClientID: "some-client-id",
+					}, "some-client-id")
+					Expect(err).To(MatchError(collections.ValidationError{common.TemplateError{
+						Part:    "html",
+						Line:    1,
+						Column:  35,
+						Message: `unknown partial "footer"`,
+					}}))
+
+					Expect(templatesRepository.InsertCall.Receives.Template).To(Equal(models.Template{}))
+				})
+
+				It("returns a PersistenceError when the partials cannot be listed", func() {
+					partialsRepository.ListCall.Returns.Error = errors.New("failed to list partials")
+
+					_, err := templatesCollection.Set(conn, collections.Template{
+						Name: "some-template",
+						HTML: "{{.HTML}}",
+					}, "some-client-id")
+					Expect(err).To(MatchError(collections.PersistenceError{errors.New("failed to list partials")}))
+				})
+			})
+		})
+
+		Context("when the template includes partials", func() {
+			It("validates the template against the partials of the client saving it", func() {
+				partialsRepository.ListCall.Returns.Partials = []models.Partial{
+					{Name: "footer", Body: "<footer>{{.Organization}}</footer>"},
+				}
+
+				_, err := templatesCollection.Set(conn, collections.Template{
+					Name:     "some-template",
+					HTML:     `{{.HTML}}{{template "footer" .}}`,
+					Subject:  "{{.Subject}}",
+					ClientID: "some-client-id",
+				}, "some-client-id")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(partialsRepository.ListCall.Receives.Connection).To(Equal(conn))
+				Expect(partialsRepository.ListCall.Receives.ClientID).To(Equal("some-client-id"))
+			})
+		})
+	})
+
+	Describe("versions", func() {
+		Context("when a new template is created", func() {
+			It("does not record a version", func() {
+				_, err := templatesCollection.Set(conn, collections.Template{
+					Name:     "some-template",
+					HTML:     "<h1>My Cool Template</h1>",
+					Subject:  "{{.Subject}}",
+					ClientID: "some-client-id",
+				}, "some-client-id")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(versionsRepository.InsertCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when a template that already has versions is updated", func() {
+			BeforeEach(func() {
+				versionsRepository.ListCall.Returns.Versions = []models.TemplateVersion{
+					{TemplateID: "existing-id", Version: 1},
+				}
+
+				templatesRepository.UpdateCall.Returns.Template = models.Template{
+					ID:       "existing-id",
+					Name:     "new-template",
+					HTML:     "<h1>My Cool Template</h1>",
+					Subject:  "{{.Subject}}",
+					Metadata: "{}",
+					ClientID: "some-client-id",
+				}
+			})
+
+			It("records the updated template as a new version", func() {
+				_, err := templatesCollection.Set(conn, collections.Template{
+					ID:       "existing-id",
+					Name:     "new-template",
+					HTML:     "<h1>My Cool Template</h1>",
+					Subject:  "{{.Subject}}",
+					Metadata: "{}",
+					ClientID: "some-client-id",
+				}, "some-client-id")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(versionsRepository.ListCall.Receives.TemplateID).To(Equal("existing-id"))
+				Expect(versionsRepository.ListCall.Receives.Connection).To(Equal(transaction))
+				Expect(versionsRepository.InsertCall.Receives.Connection).To(Equal(transaction))
+				Expect(versionsRepository.InsertCall.Receives.Versions).To(Equal([]models.TemplateVersion{
+					{
+						TemplateID: "existing-id",
+						Name:       "new-template",
+						HTML:       "<h1>My Cool Template</h1>",
+						Subject:    "{{.Subject}}",
+						Metadata:   "{}",
+						ClientID:   "some-client-id",
+					},
+				}))
+			})
+
+			It("returns a PersistenceError when the version cannot be recorded", func() {
+				versionsRepository.InsertCall.Returns.Error = errors.New("failed to insert")
+
+				_, err := templatesCollection.Set(conn, collections.Template{
+					ID:       "existing-id",
+					Name:     "new-template",
+					HTML:     "<h1>My Cool Template</h1>",
+					ClientID: "some-client-id",
+				}, "some-client-id")
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("failed to insert")}))
+			})
+		})
+
+		Context("when the default template is updated for the first time", func() {
+			BeforeEach(func() {
+				templatesRepository.GetCall.Returns.Template = models.DefaultTemplate
+				templatesRepository.InsertCall.Returns.Template = models.Template{
+					ID:      "default",
+					Name:    "updated default",
+					Text:    "new default text",
+					HTML:    "new default html",
+					Subject: "New Default Subject",
+				}
+			})
+
+			It("records the original template before the update", func() {
+				_, err := templatesCollection.Set(conn, collections.Template{
+					ID:      "default",
+					Name:    "updated default",
+					Text:    "new default text",
+					HTML:    "new default html",
+					Subject: "New Default Subject",
+				}, "some-admin-client-id")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(templatesRepository.GetCall.Receives.TemplateID).To(Equal("default"))
+				Expect(versionsRepository.InsertCall.Receives.Versions).To(Equal([]models.TemplateVersion{
+					{
+						TemplateID: "default",
+						Name:       "The Default Template",
+						Subject:    "{{.Subject}}",
+						Text:       "{{.Text}}",
+						HTML:       "{{.HTML}}",
+						Metadata:   "{}",
+					},
+					{
+						TemplateID: "default",
+						Name:       "updated default",
+						Text:       "new default text",
+						HTML:       "new default html",
+						Subject:    "New Default Subject",
+						ClientID:   "some-admin-client-id",
+					},
+				}))
+			})
+		})
+
+		Context("failure cases", func() {
+			It("returns a PersistenceError when the versions cannot be listed", func() {
+				versionsRepository.ListCall.Returns.Error = errors.New("failed to list")
+
+				_, err := templatesCollection.Set(conn, collections.Template{
+					ID:   "existing-id",
+					Name: "new-template",
+					HTML: "<h1>My Cool Template</h1>",
+				}, "some-client-id")
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("failed to list")}))
+
+				Expect(templatesRepository.UpdateCall.Receives.Template).To(Equal(models.Template{}))
+			})
+
+			It("returns a NotFoundError when the template to update does not exist", func() {
+				templatesRepository.GetCall.Returns.Error = models.RecordNotFoundError{errors.New("not found")}
+
+				_, err := templatesCollection.Set(conn, collections.Template{
+					ID:   "missing-id",
+					Name: "new-template",
+					HTML: "<h1>My Cool Template</h1>",
+				}, "some-client-id")
+				Expect(err).To(MatchError(collections.NotFoundError{models.RecordNotFoundError{errors.New("not found")}}))
+
+				Expect(templatesRepository.UpdateCall.Receives.Template).To(Equal(models.Template{}))
+			})
+		})
+	})
+
+	Describe("ListVersions", func() {
+		var createdAt time.Time
+
+		BeforeEach(func() {
+			createdAt = time.Now().UTC().Truncate(time.Second)
+
+			templatesRepository.GetCall.Returns.Template = models.Template{
+				ID:       "some-template-id",
+				Name:     "some-template",
+				ClientID: "some-client-id",
+			}
+
+			versionsRepository.ListCall.Returns.Versions = []models.TemplateVersion{
+				{
+					ID:         "some-version-guid",
+					TemplateID: "some-template-id",
+					Version:    2,
+					Name:       "some-template",
+					HTML:       "<h1>Version 2</h1>",
+					ClientID:   "some-client-id",
+					CreatedAt:  createdAt,
+				},
+				{
+					ID:         "other-version-guid",
+					TemplateID: "some-template-id",
+					Version:    1,
+					Name:       "some-template",
+					HTML:       "<h1>Version 1</h1>",
+					ClientID:   "some-client-id",
+					CreatedAt:  createdAt.Add(-time.Hour),
+				},
+			}
+		})
+
+		It("returns the versions of the template", func() {
+			versions, err := templatesCollection.ListVersions(conn, "some-template-id", "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(Equal([]collections.TemplateVersion{
+				{
+					TemplateID: "some-template-id",
+					Version:    2,
+					Name:       "some-template",
+					HTML:       "<h1>Version 2</h1>",
+					ClientID:   "some-client-id",
+					CreatedAt:  createdAt,
+				},
+				{
+					TemplateID: "some-template-id",
+					Version:    1,
+					Name:       "some-template",
+					HTML:       "<h1>Version 1</h1>",
+					ClientID:   "some-client-id",
+					CreatedAt:  createdAt.Add(-time.Hour),
+				},
+			}))
+
+			Expect(versionsRepository.ListCall.Receives.Connection).To(Equal(conn))
+			Expect(versionsRepository.ListCall.Receives.TemplateID).To(Equal("some-template-id"))
+		})
+
+		Context("failure cases", func() {
+			It("returns a not found error if the template belongs to a different client ID", func() {
+				_, err := templatesCollection.ListVersions(conn, "some-template-id", "other-client-id")
+				Expect(err).To(MatchError(collections.NotFoundError{errors.New(`Template with id "some-template-id" could not be found`)}))
+			})
+
+			It("returns a persistence error if the versions cannot be listed", func() {
+				versionsRepository.ListCall.Returns.Error = errors.New("failed to list")
+
+				_, err := templatesCollection.ListVersions(conn, "some-template-id", "some-client-id")
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("failed to list")}))
+			})
+		})
+	})
+
+	Describe("GetVersion", func() {
+		BeforeEach(func() {
+			templatesRepository.GetCall.Returns.Template = models.Template{
+				ID:       "some-template-id",
+				Name:     "some-template",
+				ClientID: "some-client-id",
+			}
+
+			versionsRepository.GetCall.Returns.Version = models.TemplateVersion{
+				ID:         "some-version-guid",
+				TemplateID: "some-template-id",
+				Version:    3,
+				Name:       "some-template",
+				Text:       "version 3",
+				ClientID:   "some-client-id",
+			}
+		})
+
+		It("returns the requested version", func() {
+			version, err := templatesCollection.GetVersion(conn, "some-template-id", 3, "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal(collections.TemplateVersion{
+				TemplateID: "some-template-id",
+				Version:    3,
+				Name:       "some-template",
+				Text:       "version 3",
+				ClientID:   "some-client-id",
+			}))
+
+			Expect(versionsRepository.GetCall.Receives.Connection).To(Equal(conn))
+			Expect(versionsRepository.GetCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(versionsRepository.GetCall.Receives.Version).To(Equal(3))
+		})
+
+		Context("failure cases", func() {
+			It("returns a not found error if the template belongs to a different client ID", func() {
+				_, err := templatesCollection.GetVersion(conn, "some-template-id", 3, "other-client-id")
+				Expect(err).To(MatchError(collections.NotFoundError{errors.New(`Template with id "some-template-id" could not be found`)}))
+			})
+
+			It("returns a not found error if the version does not exist", func() {
+				versionsRepository.GetCall.Returns.Error = models.RecordNotFoundError{errors.New("no such version")}
+
+				_, err := templatesCollection.GetVersion(conn, "some-template-id", 9, "some-client-id")
+				Expect(err).To(MatchError(collections.NotFoundError{models.RecordNotFoundError{errors.New("no such version")}}))
+			})
+
+			It("returns a persistence error if one occurs", func() {
+				versionsRepository.GetCall.Returns.Error = errors.New("failed to get")
+
+				_, err := templatesCollection.GetVersion(conn, "some-template-id", 3, "some-client-id")
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("failed to get")}))
+			})
+		})
+	})
+
+	Describe("Rollback", func() {
+		BeforeEach(func() {
+			templatesRepository.GetCall.Returns.Template = models.Template{
+				ID:       "some-template-id",
+				Name:     "bad edit",
+				Text:     "oops",
+				Metadata: "{}",
+				ClientID: "some-client-id",
+			}
+
+			versionsRepository.ListCall.Returns.Versions = []models.TemplateVersion{
+				{TemplateID: "some-template-id", Version: 2},
+				{TemplateID: "some-template-id", Version: 1},
+			}
+
+			versionsRepository.GetCall.Returns.Version = models.TemplateVersion{
+				TemplateID: "some-template-id",
+				Version:    1,
+				Name:       "some-template",
+				Text:       "the good text",
+				Subject:    "{{.Subject}}",
+				Metadata:   `{"good": true}`,
+				ClientID:   "some-client-id",
+			}
+
+			templatesRepository.UpdateCall.Returns.Template = models.Template{
+				ID:       "some-template-id",
+				Name:     "some-template",
+				Text:     "the good text",
+				Subject:  "{{.Subject}}",
+				Metadata: `{"good": true}`,
+				ClientID: "some-client-id",
+			}
+		})
+
+		It("restores the template to the content of the version", func() {
+			template, err := templatesCollection.Rollback(conn, "some-template-id", 1, "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(template).To(Equal(collections.Template{
+				ID:       "some-template-id",
+				Name:     "some-template",
+				Text:     "the good text",
+				Subject:  "{{.Subject}}",
+				Metadata: `{"good": true}`,
+				ClientID: "some-client-id",
+			}))
+
+			Expect(versionsRepository.GetCall.Receives.Version).To(Equal(1))
+			Expect(templatesRepository.UpdateCall.Receives.Template).To(Equal(models.Template{
+				ID:       "some-template-id",
+				Name:     "some-template",
+				Text:     "the good text",
+				Subject:  "{{.Subject}}",
+				Metadata: `{"good": true}`,
+				ClientID: "some-client-id",
+
+				PartialsClientID: "some-client-id",
+			}))
+		})
+
+		It("records the rollback as a new version", func() {
+			_, err := templatesCollection.Rollback(conn, "some-template-id", 1, "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(versionsRepository.InsertCall.Receives.Versions).To(Equal([]models.TemplateVersion{
+				{
+					TemplateID: "some-template-id",
+					Name:       "some-template",
+					Text:       "the good text",
+					Subject:    "{{.Subject}}",
+					Metadata:   `{"good": true}`,
+					ClientID:   "some-client-id",
+				},
+			}))
+		})
+
+		Context("failure cases", func() {
+			It("returns a not found error if the template belongs to a different client ID", func() {
+				_, err := templatesCollection.Rollback(conn, "some-template-id", 1, "other-client-id")
+				Expect(err).To(MatchError(collections.NotFoundError{errors.New(`Template with id "some-template-id" could not be found`)}))
+			})
+
+			It("returns a not found error if the version does not exist", func() {
+				versionsRepository.GetCall.Returns.Error = models.RecordNotFoundError{errors.New("no such version")}
+
+				_, err := templatesCollection.Rollback(conn, "some-template-id", 9, "some-client-id")
+				Expect(err).To(MatchError(collections.NotFoundError{models.RecordNotFoundError{errors.New("no such version")}}))
+
+				Expect(templatesRepository.UpdateCall.Receives.Template).To(Equal(models.Template{}))
 			})
 		})
 	})
@@ -18,6 +18,7 @@ type Unsubscriber struct {
 
 type unsubscribersSetterDeleter interface {
 	Insert(connection models.ConnectionInterface, unsubscriber models.Unsubscriber) (models.Unsubscriber, error)
+	Get(connection models.ConnectionInterface, userGUID, campaignTypeID string) (models.Unsubscriber, error)
 	Delete(connection models.ConnectionInterface, unsubscriber models.Unsubscriber) error
 }
 
@@ -63,6 +64,9 @@ func (c UnsubscribersCollection) Set(connection ConnectionInterface, unsubscribe
 		CampaignTypeID: unsubscriber.CampaignTypeID,
 		UserGUID:       unsubscriber.UserGUID,
 	})
+	if _, ok := err.(models.DuplicateRecordError); ok {
+		unsub, err = c.unsubscribersRepository.Get(connection, unsubscriber.UserGUID, unsubscriber.CampaignTypeID)
+	}
 	if err != nil {
 		return Unsubscriber{}, err
 	}
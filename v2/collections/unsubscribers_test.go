@@ -61,6 +61,34 @@ var _ = Describe("UnsubscribersCollection", func() {
 			})
 		})
 
+		Context("when the unsubscriber already exists", func() {
+			It("returns the existing unsubscriber", func() {
+				unsubscribersRepository.InsertCall.Returns.Unsubscriber = models.Unsubscriber{}
+				unsubscribersRepository.InsertCall.Returns.Error = models.DuplicateRecordError{errors.New("duplicate")}
+				unsubscribersRepository.GetCall.Returns.Unsubscriber = models.Unsubscriber{
+					ID:             "existing-id",
+					CampaignTypeID: "some-campaign-type-id",
+					UserGUID:       "some-user-guid",
+				}
+
+				unsubscriber, err := unsubscribersCollection.Set(connection, collections.Unsubscriber{
+					CampaignTypeID: "some-campaign-type-id",
+					UserGUID:       "some-user-guid",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(unsubscribersRepository.GetCall.Receives.Connection).To(Equal(connection))
+				Expect(unsubscribersRepository.GetCall.Receives.UserGUID).To(Equal("some-user-guid"))
+				Expect(unsubscribersRepository.GetCall.Receives.CampaignTypeID).To(Equal("some-campaign-type-id"))
+
+				Expect(unsubscriber).To(Equal(collections.Unsubscriber{
+					ID:             "existing-id",
+					CampaignTypeID: "some-campaign-type-id",
+					UserGUID:       "some-user-guid",
+				}))
+			})
+		})
+
 		Context("when an error occurs", func() {
 			Describe("when the user does not exist", func() {
 				It("returns a record not found error", func() {
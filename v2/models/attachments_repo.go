@@ -0,0 +1,59 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type Attachment struct {
+	ID          string    `db:"id"`
+	CampaignID  string    `db:"campaign_id"`
+	DispatchID  string    `db:"dispatch_id"`
+	Filename    string    `db:"filename"`
+	ContentType string    `db:"content_type"`
+	Content     []byte    `db:"content"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+type AttachmentsRepository struct {
+	generateGUID guidGeneratorFunc
+	clock        clock
+}
+
+func NewAttachmentsRepository(guidGenerator guidGeneratorFunc, clock clock) AttachmentsRepository {
+	return AttachmentsRepository{
+		generateGUID: guidGenerator,
+		clock:        clock,
+	}
+}
+
+func (r AttachmentsRepository) Insert(connection ConnectionInterface, attachment Attachment) (Attachment, error) {
+	var err error
+	attachment.ID, err = r.generateGUID()
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	attachment.CreatedAt = r.clock.Now()
+
+	err = connection.Insert(&attachment)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+func (r AttachmentsRepository) Get(connection ConnectionInterface, id string) (Attachment, error) {
+	attachment := Attachment{}
+	err := connection.SelectOne(&attachment, "SELECT * FROM `attachments` WHERE `id` = ?", id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = RecordNotFoundError{fmt.Errorf("Attachment with id %q could not be found", id)}
+		}
+		return Attachment{}, err
+	}
+
+	return attachment, nil
+}
@@ -0,0 +1,87 @@
+package models_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AttachmentsRepository", func() {
+	var (
+		repo          models.AttachmentsRepository
+		conn          db.ConnectionInterface
+		guidGenerator *mocks.IDGenerator
+		clock         *mocks.Clock
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+
+		guidGenerator = mocks.NewIDGenerator()
+		guidGenerator.GenerateCall.Returns.IDs = []string{"first-random-guid", "second-random-guid"}
+
+		clock = mocks.NewClock()
+		clock.NowCall.Returns.Time = time.Now().UTC().Truncate(time.Second)
+
+		repo = models.NewAttachmentsRepository(guidGenerator.Generate, clock)
+		conn = database.Connection()
+	})
+
+	Describe("Insert", func() {
+		It("returns the inserted record", func() {
+			attachment, err := repo.Insert(conn, models.Attachment{
+				CampaignID:  "some-campaign-id",
+				Filename:    "invoice.pdf",
+				ContentType: "application/pdf",
+				Content:     []byte("%PDF-1.4"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attachment).To(Equal(models.Attachment{
+				ID:          "first-random-guid",
+				CampaignID:  "some-campaign-id",
+				Filename:    "invoice.pdf",
+				ContentType: "application/pdf",
+				Content:     []byte("%PDF-1.4"),
+				CreatedAt:   clock.NowCall.Returns.Time,
+			}))
+		})
+
+		It("returns an error when the guid generator errors", func() {
+			guidGenerator.GenerateCall.Returns.Error = errors.New("something bad")
+
+			_, err := repo.Insert(conn, models.Attachment{Filename: "invoice.pdf"})
+			Expect(err).To(MatchError(errors.New("something bad")))
+		})
+	})
+
+	Describe("Get", func() {
+		It("returns the attachment with its content", func() {
+			inserted, err := repo.Insert(conn, models.Attachment{
+				CampaignID:  "some-campaign-id",
+				Filename:    "invoice.pdf",
+				ContentType: "application/pdf",
+				Content:     []byte("%PDF-1.4"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			attachment, err := repo.Get(conn, inserted.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attachment).To(Equal(inserted))
+		})
+
+		Context("when the attachment does not exist", func() {
+			It("returns a RecordNotFoundError", func() {
+				_, err := repo.Get(conn, "missing-attachment-id")
+				Expect(err).To(MatchError(models.RecordNotFoundError{errors.New(`Attachment with id "missing-attachment-id" could not be found`)}))
+			})
+		})
+	})
+})
@@ -31,7 +31,11 @@ func Setup(database *db.DB) {
 	database.TableMap().AddTableWithName(Sender{}, "senders").SetKeys(false, "ID").SetUniqueTogether("name", "client_id")
 	database.TableMap().AddTableWithName(CampaignType{}, "campaign_types").SetKeys(false, "ID").SetUniqueTogether("name", "sender_id")
 	database.TableMap().AddTableWithName(Template{}, "v2_templates").SetKeys(false, "ID").SetUniqueTogether("name", "client_id")
+	database.TableMap().AddTableWithName(TemplateVersion{}, "v2_template_versions").SetKeys(false, "ID").SetUniqueTogether("template_id", "version")
 	database.TableMap().AddTableWithName(Campaign{}, "campaigns").SetKeys(false, "ID")
 	database.TableMap().AddTableWithName(Message{}, "messages").SetKeys(false, "ID")
 	database.TableMap().AddTableWithName(Unsubscriber{}, "unsubscribers").SetKeys(false, "ID").SetUniqueTogether("campaign_type_id", "user_guid")
+	database.TableMap().AddTableWithName(Suppression{}, "suppressions").SetKeys(false, "ID")
+	database.TableMap().AddTableWithName(Attachment{}, "attachments").SetKeys(false, "ID")
+	database.TableMap().AddTableWithName(Partial{}, "partials").SetKeys(false, "ID").SetUniqueTogether("client_id", "name")
 }
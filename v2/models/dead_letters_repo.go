@@ -0,0 +1,70 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type DeadLetter struct {
+	ID         int       `db:"id"`
+	JobID      int       `db:"job_id"`
+	Payload    string    `db:"payload"`
+	LastError  string    `db:"last_error"`
+	RetryCount int       `db:"retry_count"`
+	Priority   int       `db:"priority"`
+	ActiveAt   time.Time `db:"active_at"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+type DeadLettersRepository struct{}
+
+func NewDeadLettersRepository() DeadLettersRepository {
+	return DeadLettersRepository{}
+}
+
+func (r DeadLettersRepository) List(connection ConnectionInterface) ([]DeadLetter, error) {
+	deadLetters := []DeadLetter{}
+	_, err := connection.Select(&deadLetters, "SELECT * FROM `dead_letters` ORDER BY `id`")
+	if err != nil {
+		return deadLetters, err
+	}
+
+	return deadLetters, nil
+}
+
+func (r DeadLettersRepository) Get(connection ConnectionInterface, id int) (DeadLetter, error) {
+	deadLetter := DeadLetter{}
+	err := connection.SelectOne(&deadLetter, "SELECT * FROM `dead_letters` WHERE `id` = ?", id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = RecordNotFoundError{fmt.Errorf("Dead letter with id %d could not be found", id)}
+		}
+		return DeadLetter{}, err
+	}
+
+	return deadLetter, nil
+}
+
+func (r DeadLettersRepository) Delete(connection ConnectionInterface, id int) error {
+	result, err := connection.Exec("DELETE FROM `dead_letters` WHERE `id` = ?", id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return RecordNotFoundError{fmt.Errorf("Dead letter with id %d could not be found", id)}
+	}
+
+	return nil
+}
+
+func (r DeadLettersRepository) DeleteAll(connection ConnectionInterface) error {
+	_, err := connection.Exec("DELETE FROM `dead_letters`")
+	return err
+}
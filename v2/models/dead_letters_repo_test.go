@@ -0,0 +1,134 @@
+package models_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/application"
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeadLettersRepo", func() {
+	var (
+		repo models.DeadLettersRepository
+		conn db.ConnectionInterface
+		now  time.Time
+	)
+
+	BeforeEach(func() {
+		env, err := application.NewEnvironment()
+		Expect(err).NotTo(HaveOccurred())
+
+		gobble.NewDatabase(sqlDB).Migrate(env.GobbleMigrationsPath)
+
+		database := db.NewDatabase(sqlDB, db.Config{})
+		conn = database.Connection()
+
+		_, err = conn.Exec("DELETE FROM `dead_letters`")
+		Expect(err).NotTo(HaveOccurred())
+
+		now = time.Now().UTC().Truncate(time.Second)
+		repo = models.NewDeadLettersRepository()
+	})
+
+	insertDeadLetter := func(jobID int, payload string) int {
+		result, err := conn.Exec("INSERT INTO `dead_letters` (`job_id`, `payload`, `last_error`, `retry_count`, `active_at`, `created_at`) VALUES (?, ?, ?, ?, ?, ?)",
+			jobID, payload, "some error", 10, now, now)
+		Expect(err).NotTo(HaveOccurred())
+
+		id, err := result.LastInsertId()
+		Expect(err).NotTo(HaveOccurred())
+
+		return int(id)
+	}
+
+	Describe("List", func() {
+		It("returns all of the dead letters", func() {
+			firstID := insertDeadLetter(1, `{"first":true}`)
+			secondID := insertDeadLetter(2, `{"second":true}`)
+
+			deadLetters, err := repo.List(conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadLetters).To(HaveLen(2))
+			Expect(deadLetters[0].ID).To(Equal(firstID))
+			Expect(deadLetters[0].JobID).To(Equal(1))
+			Expect(deadLetters[0].Payload).To(Equal(`{"first":true}`))
+			Expect(deadLetters[0].LastError).To(Equal("some error"))
+			Expect(deadLetters[0].RetryCount).To(Equal(10))
+			Expect(deadLetters[1].ID).To(Equal(secondID))
+		})
+
+		Context("when the select fails", func() {
+			It("returns the error", func() {
+				connection := mocks.NewConnection()
+				connection.SelectCall.Returns.Error = errors.New("some select error")
+
+				_, err := repo.List(connection)
+				Expect(err).To(MatchError(errors.New("some select error")))
+			})
+		})
+	})
+
+	Describe("Get", func() {
+		It("returns the dead letter with the given id", func() {
+			id := insertDeadLetter(3, `{"third":true}`)
+
+			deadLetter, err := repo.Get(conn, id)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadLetter.ID).To(Equal(id))
+			Expect(deadLetter.JobID).To(Equal(3))
+			Expect(deadLetter.Payload).To(Equal(`{"third":true}`))
+			Expect(deadLetter.ActiveAt).To(Equal(now))
+			Expect(deadLetter.CreatedAt).To(Equal(now))
+		})
+
+		Context("when the dead letter does not exist", func() {
+			It("returns a RecordNotFoundError", func() {
+				_, err := repo.Get(conn, 12345)
+				Expect(err).To(MatchError(models.RecordNotFoundError{errors.New("Dead letter with id 12345 could not be found")}))
+			})
+		})
+	})
+
+	Describe("Delete", func() {
+		It("deletes the dead letter with the given id", func() {
+			firstID := insertDeadLetter(1, `{"first":true}`)
+			secondID := insertDeadLetter(2, `{"second":true}`)
+
+			err := repo.Delete(conn, firstID)
+			Expect(err).NotTo(HaveOccurred())
+
+			deadLetters, err := repo.List(conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadLetters).To(HaveLen(1))
+			Expect(deadLetters[0].ID).To(Equal(secondID))
+		})
+
+		Context("when the dead letter does not exist", func() {
+			It("returns a RecordNotFoundError", func() {
+				err := repo.Delete(conn, 12345)
+				Expect(err).To(MatchError(models.RecordNotFoundError{errors.New("Dead letter with id 12345 could not be found")}))
+			})
+		})
+	})
+
+	Describe("DeleteAll", func() {
+		It("deletes every dead letter", func() {
+			insertDeadLetter(1, `{"first":true}`)
+			insertDeadLetter(2, `{"second":true}`)
+
+			err := repo.DeleteAll(conn)
+			Expect(err).NotTo(HaveOccurred())
+
+			deadLetters, err := repo.List(conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadLetters).To(BeEmpty())
+		})
+	})
+})
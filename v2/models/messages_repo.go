@@ -19,6 +19,7 @@ type MessageCounts struct {
 type Message struct {
 	ID         string    `db:"id"`
 	CampaignID string    `db:"campaign_id"`
+	DispatchID string    `db:"dispatch_id"`
 	Status     string    `db:"status"`
 	UpdatedAt  time.Time `db:"updated_at"`
 }
@@ -0,0 +1,211 @@
+package models_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PartialsRepository", func() {
+	var (
+		repo          models.PartialsRepository
+		conn          db.ConnectionInterface
+		guidGenerator *mocks.IDGenerator
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+
+		guidGenerator = mocks.NewIDGenerator()
+		guidGenerator.GenerateCall.Returns.IDs = []string{"first-random-guid", "second-random-guid", "third-random-guid"}
+
+		repo = models.NewPartialsRepository(guidGenerator.Generate)
+		conn = database.Connection()
+	})
+
+	Describe("Insert", func() {
+		It("inserts the record into the database", func() {
+			partial, err := repo.Insert(conn, models.Partial{
+				ClientID: "some-client-id",
+				Name:     "footer",
+				Body:     "<footer>{{.Organization}}</footer>",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partial).To(Equal(models.Partial{
+				ID:       "first-random-guid",
+				ClientID: "some-client-id",
+				Name:     "footer",
+				Body:     "<footer>{{.Organization}}</footer>",
+			}))
+		})
+
+		Context("failure cases", func() {
+			It("returns a duplicate record error when the client already has a partial with that name", func() {
+				partial := models.Partial{
+					ClientID: "some-client-id",
+					Name:     "footer",
+					Body:     "some body",
+				}
+
+				_, err := repo.Insert(conn, partial)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = repo.Insert(conn, partial)
+				Expect(err).To(MatchError(models.DuplicateRecordError{errors.New(`Partial with name "footer" already exists`)}))
+			})
+
+			It("returns the error when the insert call fails", func() {
+				connection := mocks.NewConnection()
+				connection.InsertCall.Returns.Error = errors.New("something bad happened")
+
+				_, err := repo.Insert(connection, models.Partial{Name: "footer"})
+				Expect(err).To(MatchError(errors.New("something bad happened")))
+			})
+
+			It("returns an error when the guid generator blows up", func() {
+				guidGenerator.GenerateCall.Returns.Error = errors.New("failed to generate")
+
+				_, err := repo.Insert(conn, models.Partial{})
+				Expect(err).To(MatchError(errors.New("failed to generate")))
+			})
+		})
+	})
+
+	Describe("Update", func() {
+		BeforeEach(func() {
+			_, err := repo.Insert(conn, models.Partial{
+				ClientID: "some-client-id",
+				Name:     "footer",
+				Body:     "some body",
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("updates the name and body", func() {
+			partial, err := repo.Update(conn, models.Partial{
+				ID:       "first-random-guid",
+				ClientID: "some-client-id",
+				Name:     "legal",
+				Body:     "some other body",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			partial, err = repo.Get(conn, "first-random-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partial).To(Equal(models.Partial{
+				ID:       "first-random-guid",
+				ClientID: "some-client-id",
+				Name:     "legal",
+				Body:     "some other body",
+			}))
+		})
+
+		Context("failure cases", func() {
+			It("returns a duplicate record error when the name is taken", func() {
+				_, err := repo.Insert(conn, models.Partial{
+					ClientID: "some-client-id",
+					Name:     "legal",
+					Body:     "some body",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = repo.Update(conn, models.Partial{
+					ID:       "first-random-guid",
+					ClientID: "some-client-id",
+					Name:     "legal",
+					Body:     "some body",
+				})
+				Expect(err).To(MatchError(models.DuplicateRecordError{errors.New(`Partial with name "legal" already exists`)}))
+			})
+
+			It("returns other errors if they occur", func() {
+				connection := mocks.NewConnection()
+				connection.UpdateCall.Returns.Error = errors.New("potatoes")
+
+				_, err := repo.Update(connection, models.Partial{ID: "first-random-guid"})
+				Expect(err).To(MatchError(errors.New("potatoes")))
+			})
+		})
+	})
+
+	Describe("Get", func() {
+		It("fetches the partial given its id", func() {
+			createdPartial, err := repo.Insert(conn, models.Partial{
+				ClientID: "some-client-id",
+				Name:     "footer",
+				Body:     "some body",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			partial, err := repo.Get(conn, createdPartial.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partial).To(Equal(createdPartial))
+		})
+
+		It("returns a record not found error when the partial does not exist", func() {
+			_, err := repo.Get(conn, "missing-partial-id")
+			Expect(err).To(MatchError(models.RecordNotFoundError{errors.New(`Partial with id "missing-partial-id" could not be found`)}))
+		})
+	})
+
+	Describe("List", func() {
+		It("lists the partials of a client ordered by name", func() {
+			_, err := repo.Insert(conn, models.Partial{ClientID: "some-client-id", Name: "header", Body: "header body"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Insert(conn, models.Partial{ClientID: "other-client-id", Name: "footer", Body: "other body"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Insert(conn, models.Partial{ClientID: "some-client-id", Name: "footer", Body: "footer body"})
+			Expect(err).NotTo(HaveOccurred())
+
+			partials, err := repo.List(conn, "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partials).To(Equal([]models.Partial{
+				{ID: "third-random-guid", ClientID: "some-client-id", Name: "footer", Body: "footer body"},
+				{ID: "first-random-guid", ClientID: "some-client-id", Name: "header", Body: "header body"},
+			}))
+		})
+
+		It("returns any error that was encountered", func() {
+			connection := mocks.NewConnection()
+			connection.SelectCall.Returns.Error = errors.New("potatoes")
+
+			_, err := repo.List(connection, "some-client-id")
+			Expect(err).To(MatchError(errors.New("potatoes")))
+		})
+	})
+
+	Describe("Delete", func() {
+		It("deletes the partial", func() {
+			createdPartial, err := repo.Insert(conn, models.Partial{
+				ClientID: "some-client-id",
+				Name:     "footer",
+				Body:     "some body",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.Delete(conn, createdPartial)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Get(conn, createdPartial.ID)
+			Expect(err).To(MatchError(models.RecordNotFoundError{fmt.Errorf("Partial with id %q could not be found", createdPartial.ID)}))
+		})
+
+		It("returns any error that was encountered", func() {
+			connection := mocks.NewConnection()
+			connection.DeleteCall.Returns.Error = errors.New("potatoes")
+
+			err := repo.Delete(connection, models.Partial{ID: "some-id"})
+			Expect(err).To(MatchError(errors.New("potatoes")))
+		})
+	})
+})
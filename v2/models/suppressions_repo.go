@@ -0,0 +1,105 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Suppression struct {
+	ID        string    `db:"id"`
+	Email     string    `db:"email"`
+	Reason    string    `db:"reason"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type SuppressionsRepository struct {
+	generateGUID guidGeneratorFunc
+	clock        clock
+}
+
+func NewSuppressionsRepository(guidGenerator guidGeneratorFunc, clock clock) SuppressionsRepository {
+	return SuppressionsRepository{
+		generateGUID: guidGenerator,
+		clock:        clock,
+	}
+}
+
+func (r SuppressionsRepository) Insert(connection ConnectionInterface, suppression Suppression) (Suppression, error) {
+	var err error
+	suppression.ID, err = r.generateGUID()
+	if err != nil {
+		return Suppression{}, err
+	}
+
+	suppression.Email = strings.ToLower(suppression.Email)
+	suppression.CreatedAt = r.clock.Now()
+
+	err = connection.Insert(&suppression)
+	if err != nil {
+		if strings.Contains(err.Error(), "Duplicate entry") {
+			err = DuplicateRecordError{fmt.Errorf("Email %q is already suppressed", suppression.Email)}
+		}
+
+		return Suppression{}, err
+	}
+
+	return suppression, nil
+}
+
+func (r SuppressionsRepository) Get(connection ConnectionInterface, id string) (Suppression, error) {
+	suppression := Suppression{}
+	err := connection.SelectOne(&suppression, "SELECT * FROM `suppressions` WHERE `id` = ?", id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = RecordNotFoundError{fmt.Errorf("Suppression with id %q could not be found", id)}
+		}
+		return Suppression{}, err
+	}
+
+	return suppression, nil
+}
+
+func (r SuppressionsRepository) GetByEmail(connection ConnectionInterface, email string) (Suppression, error) {
+	email = strings.ToLower(email)
+
+	suppression := Suppression{}
+	err := connection.SelectOne(&suppression, "SELECT * FROM `suppressions` WHERE `email` = ?", email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = RecordNotFoundError{fmt.Errorf("Suppression for email %q could not be found", email)}
+		}
+		return Suppression{}, err
+	}
+
+	return suppression, nil
+}
+
+func (r SuppressionsRepository) List(connection ConnectionInterface) ([]Suppression, error) {
+	suppressions := []Suppression{}
+	_, err := connection.Select(&suppressions, "SELECT * FROM `suppressions` ORDER BY `email`")
+	if err != nil {
+		return suppressions, err
+	}
+
+	return suppressions, nil
+}
+
+func (r SuppressionsRepository) Delete(connection ConnectionInterface, id string) error {
+	result, err := connection.Exec("DELETE FROM `suppressions` WHERE `id` = ?", id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return RecordNotFoundError{fmt.Errorf("Suppression with id %q could not be found", id)}
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package models_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SuppressionsRepository", func() {
+	var (
+		repo          models.SuppressionsRepository
+		conn          db.ConnectionInterface
+		guidGenerator *mocks.IDGenerator
+		clock         *mocks.Clock
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+
+		guidGenerator = mocks.NewIDGenerator()
+		guidGenerator.GenerateCall.Returns.IDs = []string{"first-random-guid", "second-random-guid"}
+
+		clock = mocks.NewClock()
+		clock.NowCall.Returns.Time = time.Now().UTC().Truncate(time.Second)
+
+		repo = models.NewSuppressionsRepository(guidGenerator.Generate, clock)
+		conn = database.Connection()
+	})
+
+	Describe("Insert", func() {
+		It("returns the inserted record with a lowercased email", func() {
+			suppression, err := repo.Insert(conn, models.Suppression{
+				Email:  "Some-User@Example.com",
+				Reason: "bounce",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(suppression).To(Equal(models.Suppression{
+				ID:        "first-random-guid",
+				Email:     "some-user@example.com",
+				Reason:    "bounce",
+				CreatedAt: clock.NowCall.Returns.Time,
+			}))
+		})
+
+		Context("when the email is already suppressed", func() {
+			It("returns a DuplicateRecordError", func() {
+				_, err := repo.Insert(conn, models.Suppression{Email: "some-user@example.com"})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = repo.Insert(conn, models.Suppression{Email: "SOME-USER@example.com"})
+				Expect(err).To(MatchError(models.DuplicateRecordError{errors.New(`Email "some-user@example.com" is already suppressed`)}))
+			})
+		})
+
+		Context("when an error occurs", func() {
+			It("returns the guid generator error", func() {
+				guidGenerator.GenerateCall.Returns.Error = errors.New("some-guid-error")
+
+				_, err := repo.Insert(conn, models.Suppression{Email: "some-user@example.com"})
+				Expect(err).To(MatchError(errors.New("some-guid-error")))
+			})
+
+			It("returns the database error", func() {
+				connection := mocks.NewConnection()
+				connection.InsertCall.Returns.Error = errors.New("some other error")
+
+				_, err := repo.Insert(connection, models.Suppression{Email: "some-user@example.com"})
+				Expect(err).To(MatchError(errors.New("some other error")))
+			})
+		})
+	})
+
+	Describe("Get", func() {
+		It("returns the suppression with the given id", func() {
+			inserted, err := repo.Insert(conn, models.Suppression{Email: "some-user@example.com", Reason: "bounce"})
+			Expect(err).NotTo(HaveOccurred())
+
+			suppression, err := repo.Get(conn, inserted.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(suppression).To(Equal(inserted))
+		})
+
+		Context("when the suppression does not exist", func() {
+			It("returns a RecordNotFoundError", func() {
+				_, err := repo.Get(conn, "missing-id")
+				Expect(err).To(MatchError(models.RecordNotFoundError{errors.New(`Suppression with id "missing-id" could not be found`)}))
+			})
+		})
+	})
+
+	Describe("GetByEmail", func() {
+		It("finds the suppression regardless of case", func() {
+			inserted, err := repo.Insert(conn, models.Suppression{Email: "some-user@example.com", Reason: "bounce"})
+			Expect(err).NotTo(HaveOccurred())
+
+			suppression, err := repo.GetByEmail(conn, "Some-User@Example.COM")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(suppression).To(Equal(inserted))
+		})
+
+		Context("when the email is not suppressed", func() {
+			It("returns a RecordNotFoundError", func() {
+				_, err := repo.GetByEmail(conn, "other-user@example.com")
+				Expect(err).To(MatchError(models.RecordNotFoundError{errors.New(`Suppression for email "other-user@example.com" could not be found`)}))
+			})
+		})
+	})
+
+	Describe("List", func() {
+		It("returns all suppressions ordered by email", func() {
+			second, err := repo.Insert(conn, models.Suppression{Email: "zed@example.com"})
+			Expect(err).NotTo(HaveOccurred())
+
+			first, err := repo.Insert(conn, models.Suppression{Email: "amy@example.com"})
+			Expect(err).NotTo(HaveOccurred())
+
+			suppressions, err := repo.List(conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(suppressions).To(Equal([]models.Suppression{first, second}))
+		})
+	})
+
+	Describe("Delete", func() {
+		It("removes the suppression", func() {
+			inserted, err := repo.Insert(conn, models.Suppression{Email: "some-user@example.com"})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.Delete(conn, inserted.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Get(conn, inserted.ID)
+			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError{}))
+		})
+
+		Context("when the suppression does not exist", func() {
+			It("returns a RecordNotFoundError", func() {
+				err := repo.Delete(conn, "missing-id")
+				Expect(err).To(MatchError(models.RecordNotFoundError{errors.New(`Suppression with id "missing-id" could not be found`)}))
+			})
+		})
+	})
+})
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type TemplateVersion struct {
+	ID         string    `db:"id"`
+	TemplateID string    `db:"template_id"`
+	Version    int       `db:"version"`
+	Name       string    `db:"name"`
+	HTML       string    `db:"html"`
+	Text       string    `db:"text"`
+	Subject    string    `db:"subject"`
+	Metadata   string    `db:"metadata"`
+	ClientID   string    `db:"client_id"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+type TemplateVersionsRepository struct {
+	generateGUID guidGeneratorFunc
+	clock        clock
+}
+
+func NewTemplateVersionsRepository(guidGenerator guidGeneratorFunc, clock clock) TemplateVersionsRepository {
+	return TemplateVersionsRepository{
+		generateGUID: guidGenerator,
+		clock:        clock,
+	}
+}
+
+// Insert records version as the next version of its template.
+func (r TemplateVersionsRepository) Insert(conn ConnectionInterface, version TemplateVersion) (TemplateVersion, error) {
+	var err error
+	version.ID, err = r.generateGUID()
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+
+	var latest int
+	err = conn.SelectOne(&latest, "SELECT COALESCE(MAX(`version`), 0) FROM `v2_template_versions` WHERE `template_id` = ?", version.TemplateID)
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+
+	version.Version = latest + 1
+	version.CreatedAt = r.clock.Now()
+
+	err = conn.Insert(&version)
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+
+	return version, nil
+}
+
+// List returns the versions of a template, newest first.
+func (r TemplateVersionsRepository) List(conn ConnectionInterface, templateID string) ([]TemplateVersion, error) {
+	versions := []TemplateVersion{}
+
+	_, err := conn.Select(&versions, "SELECT * FROM `v2_template_versions` WHERE `template_id` = ? ORDER BY `version` DESC", templateID)
+
+	return versions, err
+}
+
+func (r TemplateVersionsRepository) Get(conn ConnectionInterface, templateID string, version int) (TemplateVersion, error) {
+	templateVersion := TemplateVersion{}
+	err := conn.SelectOne(&templateVersion, "SELECT * FROM `v2_template_versions` WHERE `template_id` = ? AND `version` = ?", templateID, version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = RecordNotFoundError{fmt.Errorf("Version %d of template with id %q could not be found", version, templateID)}
+		}
+
+		return TemplateVersion{}, err
+	}
+
+	return templateVersion, nil
+}
@@ -0,0 +1,130 @@
+package models_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplateVersionsRepository", func() {
+	var (
+		repo          models.TemplateVersionsRepository
+		conn          db.ConnectionInterface
+		guidGenerator *mocks.IDGenerator
+		clock         *mocks.Clock
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+
+		guidGenerator = mocks.NewIDGenerator()
+		guidGenerator.GenerateCall.Returns.IDs = []string{"first-random-guid", "second-random-guid", "third-random-guid"}
+
+		clock = mocks.NewClock()
+		clock.NowCall.Returns.Time = time.Now().UTC().Truncate(time.Second)
+
+		repo = models.NewTemplateVersionsRepository(guidGenerator.Generate, clock)
+		conn = database.Connection()
+	})
+
+	Describe("Insert", func() {
+		It("returns the inserted version, numbered from 1", func() {
+			version, err := repo.Insert(conn, models.TemplateVersion{
+				TemplateID: "some-template-id",
+				Name:       "some-template",
+				HTML:       "<h1>My Cool Template</h1>",
+				Subject:    "{{.Subject}}",
+				Metadata:   "{}",
+				ClientID:   "some-client-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal(models.TemplateVersion{
+				ID:         "first-random-guid",
+				TemplateID: "some-template-id",
+				Version:    1,
+				Name:       "some-template",
+				HTML:       "<h1>My Cool Template</h1>",
+				Subject:    "{{.Subject}}",
+				Metadata:   "{}",
+				ClientID:   "some-client-id",
+				CreatedAt:  clock.NowCall.Returns.Time,
+			}))
+		})
+
+		It("numbers the versions of each template separately", func() {
+			first, err := repo.Insert(conn, models.TemplateVersion{TemplateID: "some-template-id"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(first.Version).To(Equal(1))
+
+			second, err := repo.Insert(conn, models.TemplateVersion{TemplateID: "some-template-id"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second.Version).To(Equal(2))
+
+			other, err := repo.Insert(conn, models.TemplateVersion{TemplateID: "other-template-id"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(other.Version).To(Equal(1))
+		})
+
+		It("returns an error when the guid generator errors", func() {
+			guidGenerator.GenerateCall.Returns.Error = errors.New("something bad")
+
+			_, err := repo.Insert(conn, models.TemplateVersion{TemplateID: "some-template-id"})
+			Expect(err).To(MatchError(errors.New("something bad")))
+		})
+	})
+
+	Describe("List", func() {
+		It("returns the versions of a template, newest first", func() {
+			for _, name := range []string{"first", "second"} {
+				_, err := repo.Insert(conn, models.TemplateVersion{TemplateID: "some-template-id", Name: name})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			_, err := repo.Insert(conn, models.TemplateVersion{TemplateID: "other-template-id", Name: "unrelated"})
+			Expect(err).NotTo(HaveOccurred())
+
+			versions, err := repo.List(conn, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(HaveLen(2))
+			Expect(versions[0].Version).To(Equal(2))
+			Expect(versions[0].Name).To(Equal("second"))
+			Expect(versions[1].Version).To(Equal(1))
+			Expect(versions[1].Name).To(Equal("first"))
+		})
+
+		It("returns an empty list when the template has no versions", func() {
+			versions, err := repo.List(conn, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(BeEmpty())
+		})
+	})
+
+	Describe("Get", func() {
+		It("returns the requested version", func() {
+			inserted, err := repo.Insert(conn, models.TemplateVersion{
+				TemplateID: "some-template-id",
+				Name:       "some-template",
+				Text:       "some text",
+				ClientID:   "some-client-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			version, err := repo.Get(conn, "some-template-id", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal(inserted))
+		})
+
+		It("returns a RecordNotFoundError when the version does not exist", func() {
+			_, err := repo.Get(conn, "some-template-id", 3)
+			Expect(err).To(MatchError(models.RecordNotFoundError{errors.New(`Version 3 of template with id "some-template-id" could not be found`)}))
+		})
+	})
+})
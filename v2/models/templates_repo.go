@@ -22,6 +22,8 @@ type Template struct {
 	Subject  string `db:"subject"`
 	Metadata string `db:"metadata"`
 	ClientID string `db:"client_id"`
+
+	PartialsClientID string `db:"partials_client_id"`
 }
 
 type TemplatesRepository struct {
@@ -91,6 +93,32 @@ func (r TemplatesRepository) Update(conn ConnectionInterface, template Template)
 	return template, nil
 }
 
+// Lock holds the row of the template until the transaction conn belongs to
+// ends, so that concurrent updates of the template take turns.
+func (r TemplatesRepository) Lock(conn ConnectionInterface, templateID string) error {
+	_, err := conn.Select(&[]Template{}, "SELECT * FROM `v2_templates` WHERE `id` = ? FOR UPDATE", templateID)
+	return err
+}
+
+// ListByPartialsClientID returns the templates that include the partials of
+// clientID. v1 templates include partials from the same table, so they are
+// returned too, with an empty ClientID.
+func (r TemplatesRepository) ListByPartialsClientID(conn ConnectionInterface, clientID string) ([]Template, error) {
+	templates := []Template{}
+	_, err := conn.Select(&templates, "SELECT * FROM `v2_templates` WHERE `partials_client_id` = ?", clientID)
+	if err != nil {
+		return []Template{}, err
+	}
+
+	v1Templates := []Template{}
+	_, err = conn.Select(&v1Templates, "SELECT `id`, `name`, `html`, `text`, `subject`, `metadata`, '' AS `client_id`, `partials_client_id` FROM `templates` WHERE `partials_client_id` = ?", clientID)
+	if err != nil {
+		return []Template{}, err
+	}
+
+	return append(templates, v1Templates...), nil
+}
+
 func (r TemplatesRepository) templateWithNameAndClientIDIsPresent(conn ConnectionInterface, name, clientID string) (bool, error) {
 	err := conn.SelectOne(&Template{}, "SELECT * FROM `v2_templates` WHERE `name` = ? AND `client_id` = ?", name, clientID)
 	if err != nil {
@@ -118,6 +118,39 @@ var _ = Describe("TemplatesRepo", func() {
 		})
 	})
 
+	Describe("ListByPartialsClientID", func() {
+		It("returns the templates that include the partials of the client", func() {
+			_, err := repo.Insert(conn, models.Template{
+				Name:             "some-template",
+				ClientID:         "some-client-id",
+				PartialsClientID: "some-other-client-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Insert(conn, models.Template{
+				Name:             "some-other-template",
+				ClientID:         "some-client-id",
+				PartialsClientID: "some-client-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			templates, err := repo.ListByPartialsClientID(conn, "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(templates).To(HaveLen(1))
+			Expect(templates[0].Name).To(Equal("some-other-template"))
+			Expect(templates[0].PartialsClientID).To(Equal("some-client-id"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error if it happens", func() {
+				connection := mocks.NewConnection()
+				connection.SelectCall.Returns.Error = errors.New("an error")
+				_, err := repo.ListByPartialsClientID(connection, "client-id")
+				Expect(err).To(MatchError(errors.New("an error")))
+			})
+		})
+	})
+
 	Describe("Get", func() {
 		It("fetches the template given a template_id", func() {
 			createdTemplate, err := repo.Insert(conn, models.Template{
@@ -168,6 +201,30 @@ var _ = Describe("TemplatesRepo", func() {
 		})
 	})
 
+	Describe("Lock", func() {
+		It("locks the template within a transaction", func() {
+			template, err := repo.Insert(conn, models.Template{
+				Name:     "some-template",
+				ClientID: "some-client-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			transaction := conn.Transaction()
+			err = transaction.Begin()
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.Lock(transaction, template.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(transaction.Commit()).To(Succeed())
+		})
+
+		It("does not fail when the template does not exist", func() {
+			err := repo.Lock(conn, "missing-template-id")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
 	Describe("Update", func() {
 		It("updates the template", func() {
 			createdTemplate, err := repo.Insert(conn, models.Template{
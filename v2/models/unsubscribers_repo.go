@@ -3,6 +3,7 @@ package models
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 )
 
 type Unsubscriber struct {
@@ -30,6 +31,10 @@ func (r UnsubscribersRepository) Insert(connection ConnectionInterface, unsubscr
 
 	err = connection.Insert(&unsubscriber)
 	if err != nil {
+		if strings.Contains(err.Error(), "Duplicate entry") {
+			err = DuplicateRecordError{fmt.Errorf("User %s is already unsubscribed from campaign_type %s", unsubscriber.UserGUID, unsubscriber.CampaignTypeID)}
+		}
+
 		return Unsubscriber{}, err
 	}
 
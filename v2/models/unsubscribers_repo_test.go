@@ -55,6 +55,22 @@ var _ = Describe("UnsubscribersRepo", func() {
 				})
 			})
 
+			Context("when the user is already unsubscribed", func() {
+				It("returns a DuplicateRecordError", func() {
+					_, err := repo.Insert(conn, models.Unsubscriber{
+						CampaignTypeID: "some-campaign-type-id",
+						UserGUID:       "some-user-guid",
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = repo.Insert(conn, models.Unsubscriber{
+						CampaignTypeID: "some-campaign-type-id",
+						UserGUID:       "some-user-guid",
+					})
+					Expect(err).To(BeAssignableToTypeOf(models.DuplicateRecordError{}))
+				})
+			})
+
 			Context("when inserting a database record errors", func() {
 				It("returns an error", func() {
 					connection := mocks.NewConnection()
@@ -43,6 +43,10 @@ func (e CampaignEnqueuer) Enqueue(campaign collections.Campaign, jobType string)
 		Campaign: campaign,
 	})
 
+	if campaign.Critical {
+		job.Priority = gobble.PriorityCritical
+	}
+
 	_, err := e.gobbleQueue.Enqueue(job, connection)
 	if err != nil {
 		return errors.New(fmt.Sprintf("there was an error enqueuing the job: %s", err))
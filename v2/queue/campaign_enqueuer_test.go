@@ -55,6 +55,16 @@ var _ = Describe("CampaignEnqueuer", func() {
 			Expect(isSamePtr).To(BeTrue())
 		})
 
+		It("puts critical campaigns on the queue with a critical priority", func() {
+			campaign.Critical = true
+
+			err := enqueuer.Enqueue(campaign, "campaign")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(gobbleQueue.EnqueueCall.Receives.Jobs).To(HaveLen(1))
+			Expect(gobbleQueue.EnqueueCall.Receives.Jobs[0].Priority).To(Equal(gobble.PriorityCritical))
+		})
+
 		Context("when an enqueuing occurs", func() {
 			BeforeEach(func() {
 				gobbleQueue.EnqueueCall.Returns.Error = errors.New("some-error")
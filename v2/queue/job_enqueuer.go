@@ -34,9 +34,14 @@ type Options struct {
 	HTML              HTML
 	KindID            string
 	To                string
+	Cc                []string
+	Bcc               []string
 	Role              string
 	Endorsement       string
 	TemplateID        string
+	Critical          bool
+	Headers           map[string]string
+	AttachmentIDs     []string
 }
 
 type HTML struct {
@@ -118,6 +123,10 @@ func (enqueuer JobEnqueuer) Enqueue(conn ConnectionInterface, users []User, opti
 			CampaignID:      campaignID,
 		})
 
+		if options.Critical {
+			job.Priority = gobble.PriorityCritical
+		}
+
 		_, err = enqueuer.queue.Enqueue(job, transaction)
 		if err != nil {
 			transaction.Rollback()
@@ -9,6 +9,7 @@ import (
 	"gopkg.in/gorp.v1"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v2/models"
 	"github.com/cloudfoundry-incubator/notifications/v2/queue"
@@ -154,6 +155,28 @@ var _ = Describe("JobEnqueuer", func() {
 			}))
 		})
 
+		It("enqueues jobs with a normal priority", func() {
+			users := []queue.User{{GUID: "user-1"}, {GUID: "user-2"}}
+			enqueuer.Enqueue(conn, users, queue.Options{}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived, "some-campaign")
+
+			Expect(gobbleQueue.EnqueueCall.Receives.Jobs).To(HaveLen(2))
+			for _, job := range gobbleQueue.EnqueueCall.Receives.Jobs {
+				Expect(job.Priority).To(Equal(gobble.PriorityNormal))
+			}
+		})
+
+		Context("when the campaign type is critical", func() {
+			It("enqueues jobs with a critical priority", func() {
+				users := []queue.User{{GUID: "user-1"}, {GUID: "user-2"}}
+				enqueuer.Enqueue(conn, users, queue.Options{Critical: true}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived, "some-campaign")
+
+				Expect(gobbleQueue.EnqueueCall.Receives.Jobs).To(HaveLen(2))
+				for _, job := range gobbleQueue.EnqueueCall.Receives.Jobs {
+					Expect(job.Priority).To(Equal(gobble.PriorityCritical))
+				}
+			})
+		})
+
 		It("Inserts a StatusQueued for each of the jobs", func() {
 			users := []queue.User{{GUID: "user-1"}, {GUID: "user-2"}, {GUID: "user-3"}, {GUID: "user-4"}}
 			enqueuer.Enqueue(conn, users, queue.Options{}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived, "some-campaign")
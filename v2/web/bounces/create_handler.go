@@ -0,0 +1,48 @@
+package bounces
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/pivotal-golang/lager"
+	"github.com/ryanmoran/stack"
+)
+
+type bounceProcessor interface {
+	Process(conn db.ConnectionInterface, dsn mail.DeliveryStatusNotification, logger lager.Logger) error
+}
+
+// CreateHandler accepts a raw RFC 3464 delivery status notification, as
+// forwarded by the mail server, in the request body.
+type CreateHandler struct {
+	processor bounceProcessor
+}
+
+func NewCreateHandler(processor bounceProcessor) CreateHandler {
+	return CreateHandler{
+		processor: processor,
+	}
+}
+
+func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	dsn, err := mail.ParseDSN(req.Body)
+	if err != nil {
+		w.WriteHeader(422)
+		fmt.Fprintf(w, `{ "errors": [%q] }`, err)
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	logger := context.Get("logger").(lager.Logger)
+
+	err = h.processor.Process(database.Connection(), dsn, logger)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{ "errors": [%q] }`, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,101 @@
+package bounces_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/bounces"
+	"github.com/pivotal-golang/lager"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const bounceReport = "Content-Type: multipart/report; report-type=delivery-status; boundary=\"REPORT\"\r\n" +
+	"\r\n" +
+	"--REPORT\r\n" +
+	"Content-Type: message/delivery-status\r\n" +
+	"\r\n" +
+	"Reporting-MTA: dns; mx.example.com\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; missing@example.com\r\n" +
+	"Action: failed\r\n" +
+	"Status: 5.1.1\r\n" +
+	"--REPORT\r\n" +
+	"Content-Type: text/rfc822-headers\r\n" +
+	"\r\n" +
+	"X-CF-Notification-ID: some-message-id\r\n" +
+	"\r\n" +
+	"--REPORT--\r\n"
+
+var _ = Describe("CreateHandler", func() {
+	var (
+		handler   bounces.CreateHandler
+		processor *mocks.BounceProcessor
+		context   stack.Context
+		writer    *httptest.ResponseRecorder
+		request   *http.Request
+		conn      *mocks.Connection
+		logger    lager.Logger
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		logger = lager.NewLogger("notifications")
+
+		context = stack.NewContext()
+		context.Set("database", database)
+		context.Set("logger", logger)
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("POST", "/bounces", strings.NewReader(bounceReport))
+		Expect(err).NotTo(HaveOccurred())
+
+		processor = mocks.NewBounceProcessor()
+		handler = bounces.NewCreateHandler(processor)
+	})
+
+	It("processes the bounce report", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+		Expect(writer.Body.String()).To(BeEmpty())
+
+		Expect(processor.ProcessCall.Receives.Connection).To(Equal(conn))
+		Expect(processor.ProcessCall.Receives.Logger).To(Equal(logger))
+		Expect(processor.ProcessCall.Receives.DSN.NotificationID).To(Equal("some-message-id"))
+		Expect(processor.ProcessCall.Receives.DSN.Recipients).To(HaveLen(1))
+		Expect(processor.ProcessCall.Receives.DSN.Recipients[0].Recipient).To(Equal("missing@example.com"))
+	})
+
+	Context("when an error occurs", func() {
+		It("returns a 422 when the body is not a bounce report", func() {
+			request, err := http.NewRequest("POST", "/bounces", strings.NewReader("Content-Type: text/plain\r\n\r\nhello\r\n"))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["message is not a delivery status notification"]}`))
+			Expect(processor.ProcessCall.CallCount).To(Equal(0))
+		})
+
+		It("returns a 500 when the report cannot be processed", func() {
+			processor.ProcessCall.Returns.Error = errors.New("database failure")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["database failure"]}`))
+		})
+	})
+})
@@ -0,0 +1,7 @@
+package bounces
+
+import "github.com/cloudfoundry-incubator/notifications/db"
+
+type DatabaseInterface interface {
+	db.DatabaseInterface
+}
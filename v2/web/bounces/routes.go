@@ -0,0 +1,18 @@
+package bounces
+
+import "github.com/ryanmoran/stack"
+
+type muxer interface {
+	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
+}
+
+type Routes struct {
+	RequestLogging    stack.Middleware
+	Authenticator     stack.Middleware
+	DatabaseAllocator stack.Middleware
+	BounceProcessor   bounceProcessor
+}
+
+func (r Routes) Register(m muxer) {
+	m.Handle("POST", "/bounces", NewCreateHandler(r.BounceProcessor), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+}
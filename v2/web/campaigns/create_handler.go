@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/mail"
 	"github.com/cloudfoundry-incubator/notifications/v2/collections"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/ryanmoran/stack"
@@ -21,6 +22,8 @@ type clock interface {
 	Now() time.Time
 }
 
+var emailPattern = regexp.MustCompile(`[^@]*@{1}[^@]*`)
+
 type CreateHandler struct {
 	collection collectionCreator
 	clock      clock
@@ -41,6 +44,16 @@ type createRequest struct {
 	Subject        string              `json:"subject"`
 	TemplateID     string              `json:"template_id"`
 	ReplyTo        string              `json:"reply_to"`
+	Cc             []string            `json:"cc"`
+	Bcc            []string            `json:"bcc"`
+	Headers        map[string]string   `json:"headers"`
+	Attachments    []attachmentRequest `json:"attachments"`
+}
+
+type attachmentRequest struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Content     []byte `json:"content"`
 }
 
 func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
@@ -68,6 +81,15 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		}
 	}
 
+	var attachments []collections.Attachment
+	for _, attachment := range request.Attachments {
+		attachments = append(attachments, collections.Attachment{
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			Content:     attachment.Content,
+		})
+	}
+
 	database := context.Get("database").(DatabaseInterface)
 
 	campaign, err := h.collection.Create(database.Connection(), collections.Campaign{
@@ -78,8 +100,12 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		Subject:        request.Subject,
 		TemplateID:     request.TemplateID,
 		ReplyTo:        request.ReplyTo,
+		Cc:             request.Cc,
+		Bcc:            request.Bcc,
+		Headers:        request.Headers,
 		SenderID:       senderID,
 		StartTime:      h.clock.Now(),
+		Attachments:    attachments,
 	}, context.Get("client_id").(string), hasCriticalScope)
 	if err != nil {
 		switch err.(type) {
@@ -106,12 +132,28 @@ func isValid(request createRequest, w http.ResponseWriter, req *http.Request) bo
 		}
 
 		if audienceKey == "emails" {
-			if matches := regexp.MustCompile(`[^@]*@{1}[^@]*`).MatchString(request.SendTo[audienceKey][0]); !matches { // TODO: loop over audiences
+			if matches := emailPattern.MatchString(request.SendTo[audienceKey][0]); !matches { // TODO: loop over audiences
 				return invalidResponse(w, fmt.Sprintf(`%q is not a valid email address`, request.SendTo[audienceKey][0]))
 			}
 		}
 	}
 
+	// Cc and Bcc recipients share a message with each address in the emails
+	// audience, so they cannot be combined with audiences of users.
+	if len(request.Cc) > 0 || len(request.Bcc) > 0 {
+		for audienceKey := range request.SendTo {
+			if audienceKey != "emails" {
+				return invalidResponse(w, "cc and bcc can only be used with the emails audience")
+			}
+		}
+
+		for _, address := range append(append([]string{}, request.Cc...), request.Bcc...) {
+			if !emailPattern.MatchString(address) {
+				return invalidResponse(w, fmt.Sprintf(`%q is not a valid email address`, address))
+			}
+		}
+	}
+
 	if request.CampaignTypeID == "" {
 		return invalidResponse(w, "missing campaign_type_id")
 	}
@@ -124,6 +166,23 @@ func isValid(request createRequest, w http.ResponseWriter, req *http.Request) bo
 		return invalidResponse(w, "missing subject")
 	}
 
+	if err := mail.ValidateHeaders(request.Headers); err != nil {
+		return invalidResponse(w, err.Error())
+	}
+
+	var attachments []mail.Attachment
+	for _, attachment := range request.Attachments {
+		attachments = append(attachments, mail.Attachment{
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			Content:     attachment.Content,
+		})
+	}
+
+	if err := mail.ValidateAttachments(attachments); err != nil {
+		return invalidResponse(w, err.Error())
+	}
+
 	return true
 }
 
@@ -135,6 +135,81 @@ var _ = Describe("CreateHandler", func() {
 		}))
 	})
 
+	It("sends a campaign to a list of emails with cc and bcc recipients", func() {
+		requestBody, err := json.Marshal(map[string]interface{}{
+			"send_to": map[string][]string{
+				"emails": {"test@example.com"},
+			},
+			"cc":               []string{"shared@example.com"},
+			"bcc":              []string{"audit@example.com"},
+			"campaign_type_id": "some-campaign-type-id",
+			"text":             "come see our new stuff",
+			"subject":          "Cool New Stuff",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		request, err = http.NewRequest("POST", "/senders/some-sender-id/campaigns", bytes.NewBuffer(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusAccepted))
+		Expect(campaignsCollection.CreateCall.Receives.Campaign.Cc).To(Equal([]string{"shared@example.com"}))
+		Expect(campaignsCollection.CreateCall.Receives.Campaign.Bcc).To(Equal([]string{"audit@example.com"}))
+	})
+
+	It("sends a campaign with custom headers", func() {
+		requestBody, err := json.Marshal(map[string]interface{}{
+			"send_to": map[string][]string{
+				"users": {"user-123"},
+			},
+			"headers": map[string]string{
+				"X-Ticket-ID": "TICKET-1234",
+				"References":  "<some-message-id@example.com>",
+			},
+			"campaign_type_id": "some-campaign-type-id",
+			"text":             "come see our new stuff",
+			"subject":          "Cool New Stuff",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		request, err = http.NewRequest("POST", "/senders/some-sender-id/campaigns", bytes.NewBuffer(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusAccepted))
+		Expect(campaignsCollection.CreateCall.Receives.Campaign.Headers).To(Equal(map[string]string{
+			"X-Ticket-ID": "TICKET-1234",
+			"References":  "<some-message-id@example.com>",
+		}))
+	})
+
+	It("sends a campaign with attachments", func() {
+		requestBody, err := json.Marshal(map[string]interface{}{
+			"send_to": map[string][]string{
+				"users": {"user-123"},
+			},
+			"campaign_type_id": "some-campaign-type-id",
+			"text":             "come see our new stuff",
+			"subject":          "Cool New Stuff",
+			"attachments": []map[string]string{
+				{"filename": "report.pdf", "content_type": "application/pdf", "content": "JVBERg=="},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		request, err = http.NewRequest("POST", "/senders/some-sender-id/campaigns", bytes.NewBuffer(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusAccepted))
+		Expect(campaignsCollection.CreateCall.Receives.Campaign.Attachments).To(Equal([]collections.Attachment{
+			{Filename: "report.pdf", ContentType: "application/pdf", Content: []byte("%PDF")},
+		}))
+	})
+
 	It("sends a campaign to a list of spaces", func() {
 		campaignsCollection.CreateCall.Returns.Campaign.SendTo = map[string][]string{"spaces": {"space-123", "space-456"}}
 		requestBody, err := json.Marshal(map[string]interface{}{
@@ -428,6 +503,105 @@ var _ = Describe("CreateHandler", func() {
 				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["\"malformed-email\" is not a valid email address"]}`))
 			})
 		})
+
+		Context("when cc or bcc is used with an audience other than emails", func() {
+			BeforeEach(func() {
+				requestBody, err := json.Marshal(map[string]interface{}{
+					"send_to": map[string][]string{
+						"users": {"user-123"},
+					},
+					"cc":               []string{"shared@example.com"},
+					"campaign_type_id": "some-campaign-type-id",
+					"text":             "come see our new stuff",
+					"subject":          "Cool New Stuff",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err = http.NewRequest("POST", "/senders/some-sender-id/campaigns", bytes.NewBuffer(requestBody))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns a 422 and states that cc is only for the emails audience", func() {
+				handler.ServeHTTP(writer, request, context)
+				Expect(writer.Code).To(Equal(422))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["cc and bcc can only be used with the emails audience"]}`))
+			})
+		})
+
+		Context("when a cc or bcc address is invalid", func() {
+			BeforeEach(func() {
+				requestBody, err := json.Marshal(map[string]interface{}{
+					"send_to": map[string][]string{
+						"emails": {"test@example.com"},
+					},
+					"bcc":              []string{"malformed-email"},
+					"campaign_type_id": "some-campaign-type-id",
+					"text":             "come see our new stuff",
+					"subject":          "Cool New Stuff",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err = http.NewRequest("POST", "/senders/some-sender-id/campaigns", bytes.NewBuffer(requestBody))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns a 422 and states the address is invalid", func() {
+				handler.ServeHTTP(writer, request, context)
+				Expect(writer.Code).To(Equal(422))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["\"malformed-email\" is not a valid email address"]}`))
+			})
+		})
+
+		Context("when a header cannot be set", func() {
+			BeforeEach(func() {
+				requestBody, err := json.Marshal(map[string]interface{}{
+					"send_to": map[string][]string{
+						"users": {"user-123"},
+					},
+					"headers":          map[string]string{"Reply-To": "someone@example.com"},
+					"campaign_type_id": "some-campaign-type-id",
+					"text":             "come see our new stuff",
+					"subject":          "Cool New Stuff",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err = http.NewRequest("POST", "/senders/some-sender-id/campaigns", bytes.NewBuffer(requestBody))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns a 422 and states which header was rejected", func() {
+				handler.ServeHTTP(writer, request, context)
+				Expect(writer.Code).To(Equal(422))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["header \"Reply-To\" cannot be set"]}`))
+			})
+		})
+
+		Context("when an attachment is invalid", func() {
+			BeforeEach(func() {
+				requestBody, err := json.Marshal(map[string]interface{}{
+					"send_to": map[string][]string{
+						"users": {"user-123"},
+					},
+					"campaign_type_id": "some-campaign-type-id",
+					"text":             "come see our new stuff",
+					"subject":          "Cool New Stuff",
+					"attachments": []map[string]string{
+						{"content_type": "application/pdf", "content": "JVBERg=="},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err = http.NewRequest("POST", "/senders/some-sender-id/campaigns", bytes.NewBuffer(requestBody))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns a 422 and states why the attachment is invalid", func() {
+				handler.ServeHTTP(writer, request, context)
+				Expect(writer.Code).To(Equal(422))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["attachment is missing a filename"]}`))
+				Expect(campaignsCollection.CreateCall.WasCalled).To(BeFalse())
+			})
+		})
 	})
 
 	Context("when the token does not have the critical scope", func() {
@@ -0,0 +1,46 @@
+package deadletters
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+)
+
+type Link struct {
+	Href string `json:"href"`
+}
+
+type DeadLetterResponseLinks struct {
+	Self   Link `json:"self"`
+	Replay Link `json:"replay"`
+}
+
+type DeadLetterResponse struct {
+	ID         int                     `json:"id"`
+	JobID      int                     `json:"job_id"`
+	Payload    string                  `json:"payload"`
+	LastError  string                  `json:"last_error"`
+	RetryCount int                     `json:"retry_count"`
+	Priority   int                     `json:"priority"`
+	ActiveAt   time.Time               `json:"active_at"`
+	CreatedAt  time.Time               `json:"created_at"`
+	Links      DeadLetterResponseLinks `json:"_links"`
+}
+
+func NewDeadLetterResponse(deadLetter collections.DeadLetter) DeadLetterResponse {
+	return DeadLetterResponse{
+		ID:         deadLetter.ID,
+		JobID:      deadLetter.JobID,
+		Payload:    deadLetter.Payload,
+		LastError:  deadLetter.LastError,
+		RetryCount: deadLetter.RetryCount,
+		Priority:   deadLetter.Priority,
+		ActiveAt:   deadLetter.ActiveAt,
+		CreatedAt:  deadLetter.CreatedAt,
+		Links: DeadLetterResponseLinks{
+			Self:   Link{fmt.Sprintf("/dead_letters/%d", deadLetter.ID)},
+			Replay: Link{fmt.Sprintf("/dead_letters/%d/replay", deadLetter.ID)},
+		},
+	}
+}
@@ -0,0 +1,76 @@
+package deadletters_test
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/deadletters"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeadLetterResponse", func() {
+	var (
+		response  deadletters.DeadLetterResponse
+		createdAt time.Time
+	)
+
+	BeforeEach(func() {
+		var err error
+		createdAt, err = time.Parse(time.RFC3339, "2015-08-27T12:34:56Z")
+		Expect(err).NotTo(HaveOccurred())
+
+		response = deadletters.NewDeadLetterResponse(collections.DeadLetter{
+			ID:         12,
+			JobID:      23,
+			Payload:    `{"some":"payload"}`,
+			LastError:  "some error",
+			RetryCount: 10,
+			Priority:   1,
+			ActiveAt:   createdAt,
+			CreatedAt:  createdAt,
+		})
+	})
+
+	It("provides a JSON representation of a dead letter resource", func() {
+		Expect(response).To(Equal(deadletters.DeadLetterResponse{
+			ID:         12,
+			JobID:      23,
+			Payload:    `{"some":"payload"}`,
+			LastError:  "some error",
+			RetryCount: 10,
+			Priority:   1,
+			ActiveAt:   createdAt,
+			CreatedAt:  createdAt,
+			Links: deadletters.DeadLetterResponseLinks{
+				Self:   deadletters.Link{"/dead_letters/12"},
+				Replay: deadletters.Link{"/dead_letters/12/replay"},
+			},
+		}))
+	})
+
+	It("can marshal into JSON", func() {
+		output, err := json.Marshal(response)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(MatchJSON(`{
+			"id": 12,
+			"job_id": 23,
+			"payload": "{\"some\":\"payload\"}",
+			"last_error": "some error",
+			"retry_count": 10,
+			"priority": 1,
+			"active_at": "2015-08-27T12:34:56Z",
+			"created_at": "2015-08-27T12:34:56Z",
+			"_links": {
+				"self": {
+					"href": "/dead_letters/12"
+				},
+				"replay": {
+					"href": "/dead_letters/12/replay"
+				}
+			}
+		}`))
+	})
+})
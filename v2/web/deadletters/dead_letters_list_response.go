@@ -0,0 +1,27 @@
+package deadletters
+
+import "github.com/cloudfoundry-incubator/notifications/v2/collections"
+
+type DeadLettersListResponseLinks struct {
+	Self Link `json:"self"`
+}
+
+type DeadLettersListResponse struct {
+	DeadLetters []DeadLetterResponse         `json:"dead_letters"`
+	Links       DeadLettersListResponseLinks `json:"_links"`
+}
+
+func NewDeadLettersListResponse(deadLetters []collections.DeadLetter) DeadLettersListResponse {
+	deadLetterResponseList := []DeadLetterResponse{}
+
+	for _, deadLetter := range deadLetters {
+		deadLetterResponseList = append(deadLetterResponseList, NewDeadLetterResponse(deadLetter))
+	}
+
+	return DeadLettersListResponse{
+		DeadLetters: deadLetterResponseList,
+		Links: DeadLettersListResponseLinks{
+			Self: Link{"/dead_letters"},
+		},
+	}
+}
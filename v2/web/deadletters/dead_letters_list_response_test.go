@@ -0,0 +1,44 @@
+package deadletters_test
+
+import (
+	"encoding/json"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/deadletters"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeadLettersListResponse", func() {
+	It("provides a JSON representation of a list of dead letter resources", func() {
+		response := deadletters.NewDeadLettersListResponse([]collections.DeadLetter{
+			{ID: 1, JobID: 10},
+			{ID: 2, JobID: 20},
+		})
+
+		Expect(response.DeadLetters).To(HaveLen(2))
+		Expect(response.DeadLetters[0].ID).To(Equal(1))
+		Expect(response.DeadLetters[0].Links.Self).To(Equal(deadletters.Link{"/dead_letters/1"}))
+		Expect(response.DeadLetters[1].ID).To(Equal(2))
+		Expect(response.DeadLetters[1].Links.Self).To(Equal(deadletters.Link{"/dead_letters/2"}))
+		Expect(response.Links.Self).To(Equal(deadletters.Link{"/dead_letters"}))
+	})
+
+	Context("when the list is empty", func() {
+		It("returns an empty list (not null)", func() {
+			response := deadletters.NewDeadLettersListResponse([]collections.DeadLetter{})
+
+			output, err := json.Marshal(response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(MatchJSON(`{
+				"dead_letters": [],
+				"_links": {
+					"self": {
+						"href": "/dead_letters"
+					}
+				}
+			}`))
+		})
+	})
+})
@@ -0,0 +1,78 @@
+package deadletters_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/deadletters"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeleteHandler", func() {
+	var (
+		handler               deadletters.DeleteHandler
+		deadLettersCollection *mocks.DeadLettersCollection
+		context               stack.Context
+		writer                *httptest.ResponseRecorder
+		request               *http.Request
+		conn                  *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("DELETE", "/dead_letters/12", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		deadLettersCollection = mocks.NewDeadLettersCollection()
+		handler = deadletters.NewDeleteHandler(deadLettersCollection)
+	})
+
+	It("deletes the dead letter", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+		Expect(writer.Body.String()).To(BeEmpty())
+
+		Expect(deadLettersCollection.DeleteCall.Receives.Connection).To(Equal(conn))
+		Expect(deadLettersCollection.DeleteCall.Receives.ID).To(Equal("12"))
+	})
+
+	Context("when an error occurs", func() {
+		It("returns a 404 when the dead letter cannot be found", func() {
+			deadLettersCollection.DeleteCall.Returns.Error = collections.NotFoundError{errors.New("Dead letter with id 12 could not be found")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["Dead letter with id 12 could not be found"]
+			}`))
+		})
+
+		It("returns a 500 when the collection errors", func() {
+			deadLettersCollection.DeleteCall.Returns.Error = collections.PersistenceError{errors.New("some delete error")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["some delete error"]
+			}`))
+		})
+	})
+})
@@ -0,0 +1,46 @@
+package deadletters
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type collectionGetter interface {
+	Get(connection collections.ConnectionInterface, id string) (collections.DeadLetter, error)
+}
+
+type GetHandler struct {
+	collection collectionGetter
+}
+
+func NewGetHandler(collection collectionGetter) GetHandler {
+	return GetHandler{
+		collection: collection,
+	}
+}
+
+func (h GetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	splitURL := strings.Split(req.URL.Path, "/")
+	deadLetterID := splitURL[len(splitURL)-1]
+
+	database := context.Get("database").(DatabaseInterface)
+
+	deadLetter, err := h.collection.Get(database.Connection(), deadLetterID)
+	if err != nil {
+		switch err.(type) {
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprintf(w, `{ "errors": [%q] }`, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(NewDeadLetterResponse(deadLetter))
+}
@@ -0,0 +1,110 @@
+package deadletters_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/deadletters"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetHandler", func() {
+	var (
+		handler               deadletters.GetHandler
+		deadLettersCollection *mocks.DeadLettersCollection
+		context               stack.Context
+		writer                *httptest.ResponseRecorder
+		request               *http.Request
+		conn                  *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("GET", "/dead_letters/12", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		deadLettersCollection = mocks.NewDeadLettersCollection()
+		handler = deadletters.NewGetHandler(deadLettersCollection)
+	})
+
+	It("returns the dead letter", func() {
+		createdAt, err := time.Parse(time.RFC3339, "2015-08-27T12:34:56Z")
+		Expect(err).NotTo(HaveOccurred())
+
+		deadLettersCollection.GetCall.Returns.DeadLetter = collections.DeadLetter{
+			ID:         12,
+			JobID:      23,
+			Payload:    `{"some":"payload"}`,
+			LastError:  "some error",
+			RetryCount: 10,
+			Priority:   1,
+			ActiveAt:   createdAt,
+			CreatedAt:  createdAt,
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"id": 12,
+			"job_id": 23,
+			"payload": "{\"some\":\"payload\"}",
+			"last_error": "some error",
+			"retry_count": 10,
+			"priority": 1,
+			"active_at": "2015-08-27T12:34:56Z",
+			"created_at": "2015-08-27T12:34:56Z",
+			"_links": {
+				"self": {
+					"href": "/dead_letters/12"
+				},
+				"replay": {
+					"href": "/dead_letters/12/replay"
+				}
+			}
+		}`))
+
+		Expect(deadLettersCollection.GetCall.Receives.Connection).To(Equal(conn))
+		Expect(deadLettersCollection.GetCall.Receives.ID).To(Equal("12"))
+	})
+
+	Context("when an error occurs", func() {
+		It("returns a 404 when the dead letter cannot be found", func() {
+			deadLettersCollection.GetCall.Returns.Error = collections.NotFoundError{errors.New("Dead letter with id 12 could not be found")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["Dead letter with id 12 could not be found"]
+			}`))
+		})
+
+		It("returns a 500 when the collection errors", func() {
+			deadLettersCollection.GetCall.Returns.Error = collections.PersistenceError{errors.New("some get error")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["some get error"]
+			}`))
+		})
+	})
+})
@@ -0,0 +1,37 @@
+package deadletters
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type collectionLister interface {
+	List(connection collections.ConnectionInterface) ([]collections.DeadLetter, error)
+}
+
+type ListHandler struct {
+	collection collectionLister
+}
+
+func NewListHandler(collection collectionLister) ListHandler {
+	return ListHandler{
+		collection: collection,
+	}
+}
+
+func (h ListHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	database := context.Get("database").(DatabaseInterface)
+
+	deadLetters, err := h.collection.List(database.Connection())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{ "errors": [%q] }`, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(NewDeadLettersListResponse(deadLetters))
+}
@@ -0,0 +1,109 @@
+package deadletters_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/deadletters"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListHandler", func() {
+	var (
+		handler               deadletters.ListHandler
+		deadLettersCollection *mocks.DeadLettersCollection
+		context               stack.Context
+		writer                *httptest.ResponseRecorder
+		request               *http.Request
+		conn                  *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("GET", "/dead_letters", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		deadLettersCollection = mocks.NewDeadLettersCollection()
+		handler = deadletters.NewListHandler(deadLettersCollection)
+	})
+
+	It("returns a list of dead letters", func() {
+		createdAt, err := time.Parse(time.RFC3339, "2015-08-27T12:34:56Z")
+		Expect(err).NotTo(HaveOccurred())
+
+		deadLettersCollection.ListCall.Returns.DeadLetters = []collections.DeadLetter{
+			{
+				ID:         12,
+				JobID:      23,
+				Payload:    `{"some":"payload"}`,
+				LastError:  "some error",
+				RetryCount: 10,
+				Priority:   1,
+				ActiveAt:   createdAt,
+				CreatedAt:  createdAt,
+			},
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"dead_letters": [
+				{
+					"id": 12,
+					"job_id": 23,
+					"payload": "{\"some\":\"payload\"}",
+					"last_error": "some error",
+					"retry_count": 10,
+					"priority": 1,
+					"active_at": "2015-08-27T12:34:56Z",
+					"created_at": "2015-08-27T12:34:56Z",
+					"_links": {
+						"self": {
+							"href": "/dead_letters/12"
+						},
+						"replay": {
+							"href": "/dead_letters/12/replay"
+						}
+					}
+				}
+			],
+			"_links": {
+				"self": {
+					"href": "/dead_letters"
+				}
+			}
+		}`))
+
+		Expect(deadLettersCollection.ListCall.Receives.Connection).To(Equal(conn))
+	})
+
+	Context("when the collection errors", func() {
+		It("returns a 500 and the error", func() {
+			deadLettersCollection.ListCall.Returns.Error = collections.PersistenceError{errors.New("some list error")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["some list error"]
+			}`))
+		})
+	})
+})
@@ -0,0 +1,36 @@
+package deadletters
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type collectionPurger interface {
+	Purge(connection collections.ConnectionInterface) error
+}
+
+type PurgeHandler struct {
+	collection collectionPurger
+}
+
+func NewPurgeHandler(collection collectionPurger) PurgeHandler {
+	return PurgeHandler{
+		collection: collection,
+	}
+}
+
+func (h PurgeHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	database := context.Get("database").(DatabaseInterface)
+
+	err := h.collection.Purge(database.Connection())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{ "errors": [%q] }`, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
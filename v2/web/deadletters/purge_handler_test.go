@@ -0,0 +1,67 @@
+package deadletters_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/deadletters"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PurgeHandler", func() {
+	var (
+		handler               deadletters.PurgeHandler
+		deadLettersCollection *mocks.DeadLettersCollection
+		context               stack.Context
+		writer                *httptest.ResponseRecorder
+		request               *http.Request
+		conn                  *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("DELETE", "/dead_letters", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		deadLettersCollection = mocks.NewDeadLettersCollection()
+		handler = deadletters.NewPurgeHandler(deadLettersCollection)
+	})
+
+	It("purges all of the dead letters", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+		Expect(writer.Body.String()).To(BeEmpty())
+
+		Expect(deadLettersCollection.PurgeCall.WasCalled).To(BeTrue())
+		Expect(deadLettersCollection.PurgeCall.Receives.Connection).To(Equal(conn))
+	})
+
+	Context("when the collection errors", func() {
+		It("returns a 500 and the error", func() {
+			deadLettersCollection.PurgeCall.Returns.Error = collections.PersistenceError{errors.New("some purge error")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["some purge error"]
+			}`))
+		})
+	})
+})
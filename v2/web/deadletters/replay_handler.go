@@ -0,0 +1,45 @@
+package deadletters
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type collectionReplayer interface {
+	Replay(connection collections.ConnectionInterface, id string) error
+}
+
+type ReplayHandler struct {
+	collection collectionReplayer
+}
+
+func NewReplayHandler(collection collectionReplayer) ReplayHandler {
+	return ReplayHandler{
+		collection: collection,
+	}
+}
+
+func (h ReplayHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	splitURL := strings.Split(req.URL.Path, "/")
+	deadLetterID := splitURL[len(splitURL)-2]
+
+	database := context.Get("database").(DatabaseInterface)
+
+	err := h.collection.Replay(database.Connection(), deadLetterID)
+	if err != nil {
+		switch err.(type) {
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprintf(w, `{ "errors": [%q] }`, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
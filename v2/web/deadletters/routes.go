@@ -0,0 +1,25 @@
+package deadletters
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type muxer interface {
+	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
+}
+
+type Routes struct {
+	RequestLogging        stack.Middleware
+	Authenticator         stack.Middleware
+	DatabaseAllocator     stack.Middleware
+	DeadLettersCollection collections.DeadLettersCollection
+}
+
+func (r Routes) Register(m muxer) {
+	m.Handle("GET", "/dead_letters", NewListHandler(r.DeadLettersCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("DELETE", "/dead_letters", NewPurgeHandler(r.DeadLettersCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/dead_letters/{dead_letter_id}", NewGetHandler(r.DeadLettersCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("DELETE", "/dead_letters/{dead_letter_id}", NewDeleteHandler(r.DeadLettersCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/dead_letters/{dead_letter_id}/replay", NewReplayHandler(r.DeadLettersCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+}
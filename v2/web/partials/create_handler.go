@@ -0,0 +1,59 @@
+package partials
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type collectionSetter interface {
+	Set(conn collections.ConnectionInterface, partial collections.Partial) (createdPartial collections.Partial, err error)
+}
+
+type CreateHandler struct {
+	partials collectionSetter
+}
+
+func NewCreateHandler(partials collectionSetter) CreateHandler {
+	return CreateHandler{
+		partials: partials,
+	}
+}
+
+func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	var createRequest struct {
+		Name string `json:"name"`
+		Body string `json:"body"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&createRequest)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{ "errors": [ "invalid json body" ] }`))
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+
+	partial, err := h.partials.Set(database.Connection(), collections.Partial{
+		Name:     createRequest.Name,
+		Body:     createRequest.Body,
+		ClientID: context.Get("client_id").(string),
+	})
+	if err != nil {
+		switch err.(type) {
+		case collections.DuplicateRecordError, collections.ValidationError:
+			w.WriteHeader(422)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(NewPartialResponse(partial))
+}
@@ -0,0 +1,127 @@
+package partials_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/partials"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CreateHandler", func() {
+	var (
+		handler            partials.CreateHandler
+		partialsCollection *mocks.PartialsCollection
+		context            stack.Context
+		writer             *httptest.ResponseRecorder
+		request            *http.Request
+		conn               *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("client_id", "some-client-id")
+		context.Set("database", database)
+
+		partialsCollection = mocks.NewPartialsCollection()
+		partialsCollection.SetCall.Returns.Partial = collections.Partial{
+			ID:       "some-partial-id",
+			Name:     "footer",
+			Body:     "<footer>{{.Organization}}</footer>",
+			ClientID: "some-client-id",
+		}
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("POST", "/partials", bytes.NewBufferString(`{
+			"name": "footer",
+			"body": "<footer>{{.Organization}}</footer>"
+		}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = partials.NewCreateHandler(partialsCollection)
+	})
+
+	It("creates a partial", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(partialsCollection.SetCall.Receives.Connection).To(Equal(conn))
+		Expect(partialsCollection.SetCall.Receives.Partial).To(Equal(collections.Partial{
+			Name:     "footer",
+			Body:     "<footer>{{.Organization}}</footer>",
+			ClientID: "some-client-id",
+		}))
+
+		Expect(writer.Code).To(Equal(http.StatusCreated))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"id": "some-partial-id",
+			"name": "footer",
+			"body": "<footer>{{.Organization}}</footer>",
+			"_links": {
+				"self": {
+					"href": "/partials/some-partial-id"
+				}
+			}
+		}`))
+	})
+
+	Context("failure cases", func() {
+		It("returns a 400 when the JSON request body cannot be unmarshalled", func() {
+			var err error
+			request, err = http.NewRequest("POST", "/partials", bytes.NewBufferString("%%%"))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusBadRequest))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["invalid json body"]
+			}`))
+		})
+
+		It("returns a 422 when the partial is invalid", func() {
+			partialsCollection.SetCall.Returns.Error = collections.ValidationError{
+				Err: errors.New("partials include each other in a cycle: footer -> footer"),
+			}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["partials include each other in a cycle: footer -> footer"]
+			}`))
+		})
+
+		It("returns a 422 when a partial with the same name already exists", func() {
+			partialsCollection.SetCall.Returns.Error = collections.DuplicateRecordError{
+				Err: errors.New(`Partial with name "footer" already exists`),
+			}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["Partial with name \"footer\" already exists"]
+			}`))
+		})
+
+		It("returns a 500 when the collection indicates a system error", func() {
+			partialsCollection.SetCall.Returns.Error = errors.New("BOOM!")
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["BOOM!"]
+			}`))
+		})
+	})
+})
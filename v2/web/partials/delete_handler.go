@@ -0,0 +1,48 @@
+package partials
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type partialsDeleter interface {
+	Delete(connection collections.ConnectionInterface, partialID, clientID string) error
+}
+
+type DeleteHandler struct {
+	partials partialsDeleter
+}
+
+func NewDeleteHandler(partials partialsDeleter) DeleteHandler {
+	return DeleteHandler{
+		partials: partials,
+	}
+}
+
+func (h DeleteHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	splitURL := strings.Split(req.URL.Path, "/")
+	partialID := splitURL[len(splitURL)-1]
+
+	conn := context.Get("database").(DatabaseInterface).Connection()
+	clientID := context.Get("client_id").(string)
+
+	err := h.partials.Delete(conn, partialID, clientID)
+	if err != nil {
+		switch err.(type) {
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		case collections.ValidationError:
+			w.WriteHeader(422)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
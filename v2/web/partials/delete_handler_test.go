@@ -0,0 +1,93 @@
+package partials_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/partials"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeleteHandler", func() {
+	var (
+		handler            partials.DeleteHandler
+		partialsCollection *mocks.PartialsCollection
+		context            stack.Context
+		writer             *httptest.ResponseRecorder
+		request            *http.Request
+		conn               *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("client_id", "some-client-id")
+		context.Set("database", database)
+
+		partialsCollection = mocks.NewPartialsCollection()
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("DELETE", "/partials/some-partial-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = partials.NewDeleteHandler(partialsCollection)
+	})
+
+	It("deletes a partial", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(partialsCollection.DeleteCall.Receives.Connection).To(Equal(conn))
+		Expect(partialsCollection.DeleteCall.Receives.PartialID).To(Equal("some-partial-id"))
+		Expect(partialsCollection.DeleteCall.Receives.ClientID).To(Equal("some-client-id"))
+
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+		Expect(writer.Body.String()).To(BeEmpty())
+	})
+
+	Context("failure cases", func() {
+		It("returns a 404 when the partial does not exist", func() {
+			partialsCollection.DeleteCall.Returns.Error = collections.NotFoundError{
+				Err: errors.New(`Partial with id "some-partial-id" could not be found`),
+			}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["Partial with id \"some-partial-id\" could not be found"]
+			}`))
+		})
+
+		It("returns a 422 when a template still includes the partial", func() {
+			partialsCollection.DeleteCall.Returns.Error = collections.ValidationError{
+				Err: errors.New(`template "welcome" would be invalid: unknown partial "footer"`),
+			}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["template \"welcome\" would be invalid: unknown partial \"footer\""]
+			}`))
+		})
+
+		It("returns a 500 when the collection indicates a system error", func() {
+			partialsCollection.DeleteCall.Returns.Error = errors.New("BOOM!")
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["BOOM!"]
+			}`))
+		})
+	})
+})
@@ -0,0 +1,104 @@
+package partials_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/partials"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetHandler", func() {
+	var (
+		handler            partials.GetHandler
+		partialsCollection *mocks.PartialsCollection
+		context            stack.Context
+		writer             *httptest.ResponseRecorder
+		request            *http.Request
+		conn               *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("client_id", "some-client-id")
+		context.Set("database", database)
+
+		partialsCollection = mocks.NewPartialsCollection()
+		partialsCollection.GetCall.Returns.Partial = collections.Partial{
+			ID:   "some-partial-id",
+			Name: "footer",
+			Body: "<footer/>",
+		}
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("GET", "/partials/some-partial-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = partials.NewGetHandler(partialsCollection)
+	})
+
+	It("gets a partial", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(partialsCollection.GetCall.Receives.Connection).To(Equal(conn))
+		Expect(partialsCollection.GetCall.Receives.PartialID).To(Equal("some-partial-id"))
+		Expect(partialsCollection.GetCall.Receives.ClientID).To(Equal("some-client-id"))
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"id": "some-partial-id",
+			"name": "footer",
+			"body": "<footer/>",
+			"_links": {
+				"self": {"href": "/partials/some-partial-id"}
+			}
+		}`))
+	})
+
+	Context("failure cases", func() {
+		It("returns a 301 when the partial id is missing", func() {
+			var err error
+			request, err = http.NewRequest("GET", "/partials/", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusMovedPermanently))
+			Expect(writer.Header().Get("Location")).To(Equal("/partials"))
+			Expect(writer.Body.String()).To(BeEmpty())
+		})
+
+		It("returns a 404 when the partial does not exist", func() {
+			partialsCollection.GetCall.Returns.Error = collections.NotFoundError{
+				Err: errors.New(`Partial with id "some-partial-id" could not be found`),
+			}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["Partial with id \"some-partial-id\" could not be found"]
+			}`))
+		})
+
+		It("returns a 500 when the collection indicates a system error", func() {
+			partialsCollection.GetCall.Returns.Error = errors.New("BOOM!")
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["BOOM!"]
+			}`))
+		})
+	})
+})
@@ -0,0 +1,105 @@
+package partials_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/partials"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListHandler", func() {
+	var (
+		handler            partials.ListHandler
+		partialsCollection *mocks.PartialsCollection
+		context            stack.Context
+		writer             *httptest.ResponseRecorder
+		request            *http.Request
+		conn               *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("client_id", "some-client-id")
+		context.Set("database", database)
+
+		partialsCollection = mocks.NewPartialsCollection()
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("GET", "/partials", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = partials.NewListHandler(partialsCollection)
+	})
+
+	It("lists the partials of the client", func() {
+		partialsCollection.ListCall.Returns.Partials = []collections.Partial{
+			{ID: "footer-id", Name: "footer", Body: "<footer/>"},
+			{ID: "header-id", Name: "header", Body: "<header/>"},
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(partialsCollection.ListCall.Receives.Connection).To(Equal(conn))
+		Expect(partialsCollection.ListCall.Receives.ClientID).To(Equal("some-client-id"))
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"partials": [
+				{
+					"id": "footer-id",
+					"name": "footer",
+					"body": "<footer/>",
+					"_links": {
+						"self": {"href": "/partials/footer-id"}
+					}
+				},
+				{
+					"id": "header-id",
+					"name": "header",
+					"body": "<header/>",
+					"_links": {
+						"self": {"href": "/partials/header-id"}
+					}
+				}
+			],
+			"_links": {
+				"self": {"href": "/partials"}
+			}
+		}`))
+	})
+
+	It("returns an empty list when the client has no partials", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"partials": [],
+			"_links": {
+				"self": {"href": "/partials"}
+			}
+		}`))
+	})
+
+	It("returns a 500 when the collection indicates a system error", func() {
+		partialsCollection.ListCall.Returns.Error = errors.New("BOOM!")
+
+		handler.ServeHTTP(writer, request, context)
+		Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"errors": ["BOOM!"]
+		}`))
+	})
+})
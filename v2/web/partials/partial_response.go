@@ -0,0 +1,33 @@
+package partials
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+)
+
+type PartialResponse struct {
+	ID    string               `json:"id"`
+	Name  string               `json:"name"`
+	Body  string               `json:"body"`
+	Links PartialResponseLinks `json:"_links"`
+}
+
+type PartialResponseLinks struct {
+	Self Link `json:"self"`
+}
+
+type Link struct {
+	Href string `json:"href"`
+}
+
+func NewPartialResponse(partial collections.Partial) PartialResponse {
+	return PartialResponse{
+		ID:   partial.ID,
+		Name: partial.Name,
+		Body: partial.Body,
+		Links: PartialResponseLinks{
+			Self: Link{fmt.Sprintf("/partials/%s", partial.ID)},
+		},
+	}
+}
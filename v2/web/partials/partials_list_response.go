@@ -0,0 +1,27 @@
+package partials
+
+import "github.com/cloudfoundry-incubator/notifications/v2/collections"
+
+type PartialsListResponse struct {
+	Partials []PartialResponse         `json:"partials"`
+	Links    PartialsListResponseLinks `json:"_links"`
+}
+
+type PartialsListResponseLinks struct {
+	Self Link `json:"self"`
+}
+
+func NewPartialsListResponse(partialList []collections.Partial) PartialsListResponse {
+	partialResponseList := []PartialResponse{}
+
+	for _, partial := range partialList {
+		partialResponseList = append(partialResponseList, NewPartialResponse(partial))
+	}
+
+	return PartialsListResponse{
+		Partials: partialResponseList,
+		Links: PartialsListResponseLinks{
+			Self: Link{"/partials"},
+		},
+	}
+}
@@ -0,0 +1,87 @@
+package partials
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type collectionSetGetter interface {
+	collectionSetter
+	collectionGetter
+}
+
+type UpdateHandler struct {
+	partials collectionSetGetter
+}
+
+func NewUpdateHandler(partials collectionSetGetter) UpdateHandler {
+	return UpdateHandler{
+		partials: partials,
+	}
+}
+
+func (h UpdateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	splitURL := strings.Split(req.URL.Path, "/")
+	partialID := splitURL[len(splitURL)-1]
+
+	// The fields are pointers so that a request that leaves one out keeps
+	// the value the partial already has.
+	var updateRequest struct {
+		Name *string `json:"name"`
+		Body *string `json:"body"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&updateRequest)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{ "errors": [ "invalid json body" ] }`))
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	clientID := context.Get("client_id").(string)
+
+	existing, err := h.partials.Get(database.Connection(), partialID, clientID)
+	if err != nil {
+		switch err.(type) {
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	partial, err := h.partials.Set(database.Connection(), collections.Partial{
+		ID:       partialID,
+		Name:     valueOrDefault(updateRequest.Name, existing.Name),
+		Body:     valueOrDefault(updateRequest.Body, existing.Body),
+		ClientID: clientID,
+	})
+	if err != nil {
+		switch err.(type) {
+		case collections.DuplicateRecordError, collections.ValidationError:
+			w.WriteHeader(422)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(NewPartialResponse(partial))
+}
+
+func valueOrDefault(value *string, defaultValue string) string {
+	if value == nil {
+		return defaultValue
+	}
+
+	return *value
+}
@@ -0,0 +1,147 @@
+package partials_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/partials"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UpdateHandler", func() {
+	var (
+		handler            partials.UpdateHandler
+		partialsCollection *mocks.PartialsCollection
+		context            stack.Context
+		writer             *httptest.ResponseRecorder
+		conn               *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("client_id", "some-client-id")
+		context.Set("database", database)
+
+		partialsCollection = mocks.NewPartialsCollection()
+		partialsCollection.GetCall.Returns.Partial = collections.Partial{
+			ID:       "some-partial-id",
+			Name:     "footer",
+			Body:     "<footer/>",
+			ClientID: "some-client-id",
+		}
+		partialsCollection.SetCall.Returns.Partial = collections.Partial{
+			ID:       "some-partial-id",
+			Name:     "new-footer",
+			Body:     "<footer>new</footer>",
+			ClientID: "some-client-id",
+		}
+
+		writer = httptest.NewRecorder()
+
+		handler = partials.NewUpdateHandler(partialsCollection)
+	})
+
+	newRequest := func(body string) *http.Request {
+		request, err := http.NewRequest("PUT", "/partials/some-partial-id", bytes.NewBufferString(body))
+		Expect(err).NotTo(HaveOccurred())
+
+		return request
+	}
+
+	It("updates a partial", func() {
+		handler.ServeHTTP(writer, newRequest(`{
+			"name": "new-footer",
+			"body": "<footer>new</footer>"
+		}`), context)
+
+		Expect(partialsCollection.GetCall.Receives.Connection).To(Equal(conn))
+		Expect(partialsCollection.GetCall.Receives.PartialID).To(Equal("some-partial-id"))
+		Expect(partialsCollection.GetCall.Receives.ClientID).To(Equal("some-client-id"))
+
+		Expect(partialsCollection.SetCall.Receives.Connection).To(Equal(conn))
+		Expect(partialsCollection.SetCall.Receives.Partial).To(Equal(collections.Partial{
+			ID:       "some-partial-id",
+			Name:     "new-footer",
+			Body:     "<footer>new</footer>",
+			ClientID: "some-client-id",
+		}))
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"id": "some-partial-id",
+			"name": "new-footer",
+			"body": "<footer>new</footer>",
+			"_links": {
+				"self": {"href": "/partials/some-partial-id"}
+			}
+		}`))
+	})
+
+	It("keeps the existing values of fields left out of the request", func() {
+		handler.ServeHTTP(writer, newRequest(`{
+			"body": "<footer>new</footer>"
+		}`), context)
+
+		Expect(partialsCollection.SetCall.Receives.Partial).To(Equal(collections.Partial{
+			ID:       "some-partial-id",
+			Name:     "footer",
+			Body:     "<footer>new</footer>",
+			ClientID: "some-client-id",
+		}))
+	})
+
+	Context("failure cases", func() {
+		It("returns a 400 when the JSON request body cannot be unmarshalled", func() {
+			handler.ServeHTTP(writer, newRequest("%%%"), context)
+			Expect(writer.Code).To(Equal(http.StatusBadRequest))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["invalid json body"]
+			}`))
+		})
+
+		It("returns a 404 when the partial does not exist", func() {
+			partialsCollection.GetCall.Returns.Error = collections.NotFoundError{
+				Err: errors.New(`Partial with id "some-partial-id" could not be found`),
+			}
+
+			handler.ServeHTTP(writer, newRequest(`{"body": "<footer/>"}`), context)
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["Partial with id \"some-partial-id\" could not be found"]
+			}`))
+		})
+
+		It("returns a 422 when the change would leave a template invalid", func() {
+			partialsCollection.SetCall.Returns.Error = collections.ValidationError{
+				Err: errors.New(`template "welcome" would be invalid: unknown partial "footer"`),
+			}
+
+			handler.ServeHTTP(writer, newRequest(`{"name": "new-footer"}`), context)
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["template \"welcome\" would be invalid: unknown partial \"footer\""]
+			}`))
+		})
+
+		It("returns a 500 when the collection indicates a system error", func() {
+			partialsCollection.SetCall.Returns.Error = errors.New("BOOM!")
+
+			handler.ServeHTTP(writer, newRequest(`{"body": "<footer/>"}`), context)
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["BOOM!"]
+			}`))
+		})
+	})
+})
@@ -8,21 +8,31 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/db"
 	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/metrics"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/postal/v2"
 	"github.com/cloudfoundry-incubator/notifications/uaa"
 	"github.com/cloudfoundry-incubator/notifications/util"
+	v1models "github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/notify"
 	"github.com/cloudfoundry-incubator/notifications/v2/collections"
 	"github.com/cloudfoundry-incubator/notifications/v2/models"
 	"github.com/cloudfoundry-incubator/notifications/v2/queue"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/bounces"
 	"github.com/cloudfoundry-incubator/notifications/v2/web/campaigns"
 	"github.com/cloudfoundry-incubator/notifications/v2/web/campaigntypes"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/deadletters"
 	"github.com/cloudfoundry-incubator/notifications/v2/web/info"
 	"github.com/cloudfoundry-incubator/notifications/v2/web/middleware"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/partials"
 	"github.com/cloudfoundry-incubator/notifications/v2/web/root"
 	"github.com/cloudfoundry-incubator/notifications/v2/web/senders"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/suppressions"
 	"github.com/cloudfoundry-incubator/notifications/v2/web/templates"
 	"github.com/cloudfoundry-incubator/notifications/v2/web/unsubscribers"
 	"github.com/gorilla/mux"
 	"github.com/pivotal-cf-experimental/warrant"
+	"github.com/pivotal-golang/conceal"
 	"github.com/pivotal-golang/lager"
 	"github.com/ryanmoran/stack"
 )
@@ -53,6 +63,11 @@ type Config struct {
 	UAAClientID       string
 	UAAClientSecret   string
 	CCHost            string
+	EncryptionKey     []byte
+	Sender            string
+	Domain            string
+
+	SenderAllowedDomains []string
 }
 
 func NewRouter(mx muxer, config Config) http.Handler {
@@ -66,6 +81,11 @@ func NewRouter(mx muxer, config Config) http.Handler {
 	unsubscribesAuthenticator := middleware.NewUnsubscribesAuthenticator(config.UAATokenValidator)
 	databaseAllocator := middleware.NewDatabaseAllocator(config.SQLDB, config.DBLoggingEnabled)
 
+	cloak, err := conceal.NewCloak(config.EncryptionKey)
+	if err != nil {
+		panic(err)
+	}
+
 	warrantConfig := warrant.Config{
 		Host:          config.UAAHost,
 		SkipVerifySSL: config.SkipVerifySSL,
@@ -81,16 +101,30 @@ func NewRouter(mx muxer, config Config) http.Handler {
 	sendersRepository := models.NewSendersRepository(guidGenerator.Generate)
 	campaignTypesRepository := models.NewCampaignTypesRepository(guidGenerator.Generate)
 	templatesRepository := models.NewTemplatesRepository(guidGenerator.Generate)
+	templateVersionsRepository := models.NewTemplateVersionsRepository(guidGenerator.Generate, clock)
+	partialsRepository := models.NewPartialsRepository(guidGenerator.Generate)
 	campaignsRepository := models.NewCampaignsRepository(guidGenerator.Generate, clock)
 	messagesRepository := models.NewMessagesRepository(clock, guidGenerator.Generate)
 	unsubscribersRepository := models.NewUnsubscribersRepository(guidGenerator.Generate)
+	deadLettersRepository := models.NewDeadLettersRepository()
+	suppressionsRepository := models.NewSuppressionsRepository(guidGenerator.Generate, clock)
+	attachmentsRepository := models.NewAttachmentsRepository(guidGenerator.Generate, clock)
 
-	sendersCollection := collections.NewSendersCollection(sendersRepository, campaignTypesRepository)
-	templatesCollection := collections.NewTemplatesCollection(templatesRepository)
+	sendersCollection := collections.NewSendersCollection(sendersRepository, campaignTypesRepository, config.SenderAllowedDomains)
+	templatesCollection := collections.NewTemplatesCollection(templatesRepository, templateVersionsRepository, partialsRepository)
+	partialsCollection := collections.NewPartialsCollection(partialsRepository, templatesRepository)
 	campaignTypesCollection := collections.NewCampaignTypesCollection(campaignTypesRepository, sendersRepository, templatesRepository)
-	campaignsCollection := collections.NewCampaignsCollection(campaignEnqueuer, campaignsRepository, campaignTypesRepository, templatesRepository, sendersRepository)
+	campaignsCollection := collections.NewCampaignsCollection(campaignEnqueuer, campaignsRepository, campaignTypesRepository, templatesRepository, sendersRepository, attachmentsRepository)
 	campaignStatusesCollection := collections.NewCampaignStatusesCollection(campaignsRepository, sendersRepository, messagesRepository)
 	unsubscribersCollection := collections.NewUnsubscribersCollection(unsubscribersRepository, campaignTypesRepository, userFinder)
+	deadLettersCollection := collections.NewDeadLettersCollection(deadLettersRepository, config.Queue, gobble.Initializer{})
+	suppressionsCollection := collections.NewSuppressionsCollection(suppressionsRepository)
+
+	// The previewer builds its own message context, so its packager never
+	// loads templates or attachments.
+	templatePreviewer := common.NewPreviewer(common.NewPackager(nil, nil, cloak, ""), notify.HTMLExtractor{}, config.Sender, config.Domain)
+
+	bounceProcessor := postal.NewBounceProcessor(v1models.NewMessagesRepo(guidGenerator.Generate), suppressionsRepository, v2.NewV2MessageStatusUpdater(messagesRepository))
 
 	root.Routes{
 		RequestLogging: requestLogging,
@@ -108,6 +142,13 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		SendersCollection: sendersCollection,
 	}.Register(mx)
 
+	partials.Routes{
+		RequestLogging:     requestLogging,
+		Authenticator:      notificationsWriteAuthenticator,
+		DatabaseAllocator:  databaseAllocator,
+		PartialsCollection: partialsCollection,
+	}.Register(mx)
+
 	campaigntypes.Routes{
 		RequestLogging:          requestLogging,
 		Authenticator:           notificationsWriteAuthenticator,
@@ -121,6 +162,8 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		AdminAuthenticator:  notificationsAdminAuthenticator,
 		DatabaseAllocator:   databaseAllocator,
 		TemplatesCollection: templatesCollection,
+		PartialsCollection:  partialsCollection,
+		TemplatePreviewer:   templatePreviewer,
 	}.Register(mx)
 
 	campaigns.Routes{
@@ -137,6 +180,28 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		Authenticator:           unsubscribesAuthenticator,
 		DatabaseAllocator:       databaseAllocator,
 		UnsubscribersCollection: unsubscribersCollection,
+		Cloak:                   cloak,
+	}.Register(mx)
+
+	deadletters.Routes{
+		RequestLogging:        requestLogging,
+		Authenticator:         notificationsAdminAuthenticator,
+		DatabaseAllocator:     databaseAllocator,
+		DeadLettersCollection: deadLettersCollection,
+	}.Register(mx)
+
+	suppressions.Routes{
+		RequestLogging:         requestLogging,
+		Authenticator:          notificationsAdminAuthenticator,
+		DatabaseAllocator:      databaseAllocator,
+		SuppressionsCollection: suppressionsCollection,
+	}.Register(mx)
+
+	bounces.Routes{
+		RequestLogging:    requestLogging,
+		Authenticator:     notificationsAdminAuthenticator,
+		DatabaseAllocator: databaseAllocator,
+		BounceProcessor:   bounceProcessor,
 	}.Register(mx)
 
 	return mx
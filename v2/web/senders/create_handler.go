@@ -25,7 +25,10 @@ func NewCreateHandler(senders collectionSetter) CreateHandler {
 
 func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
 	var createRequest struct {
-		Name string `json:"name"`
+		Name        string `json:"name"`
+		FromAddress string `json:"from_address"`
+		FromName    string `json:"from_name"`
+		ReplyTo     string `json:"reply_to"`
 	}
 
 	err := json.NewDecoder(req.Body).Decode(&createRequest)
@@ -51,12 +54,21 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 	}
 
 	sender, err := h.senders.Set(database.Connection(), collections.Sender{
-		Name:     createRequest.Name,
-		ClientID: context.Get("client_id").(string),
+		Name:        createRequest.Name,
+		ClientID:    context.Get("client_id").(string),
+		FromAddress: createRequest.FromAddress,
+		FromName:    createRequest.FromName,
+		ReplyTo:     createRequest.ReplyTo,
 	})
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		fmt.Fprintf(w, `{ "errors": [ "%s" ] }`, err)
+		switch err.(type) {
+		case collections.ValidationError:
+			w.WriteHeader(422)
+			fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, `{ "errors": [ "%s" ] }`, err)
+		}
 		return
 	}
 
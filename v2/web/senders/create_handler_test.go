@@ -83,6 +83,55 @@ var _ = Describe("CreateHandler", func() {
 		}`))
 	})
 
+	It("creates a sender with a from address", func() {
+		var err error
+		request, err = http.NewRequest("POST", "/senders", strings.NewReader(`{
+			"name": "some-sender",
+			"from_address": "no-reply@example.com",
+			"from_name": "Some Sender",
+			"reply_to": "support@example.com"
+		}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		sendersCollection.SetCall.Returns.Sender = collections.Sender{
+			ID:          "some-sender-id",
+			Name:        "some-sender",
+			FromAddress: "no-reply@example.com",
+			FromName:    "Some Sender",
+			ReplyTo:     "support@example.com",
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(sendersCollection.SetCall.Receives.Sender).To(Equal(collections.Sender{
+			Name:        "some-sender",
+			ClientID:    "some-client-id",
+			FromAddress: "no-reply@example.com",
+			FromName:    "Some Sender",
+			ReplyTo:     "support@example.com",
+		}))
+
+		Expect(writer.Code).To(Equal(http.StatusCreated))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"id": "some-sender-id",
+			"name": "some-sender",
+			"from_address": "no-reply@example.com",
+			"from_name": "Some Sender",
+			"reply_to": "support@example.com",
+			"_links": {
+				"self": {
+					"href": "/senders/some-sender-id"
+				},
+				"campaign_types": {
+					"href": "/senders/some-sender-id/campaign_types"
+				},
+				"campaigns": {
+					"href": "/senders/some-sender-id/campaigns"
+				}
+			}
+		}`))
+	})
+
 	Context("failure cases", func() {
 		It("returns a 400 when the JSON cannot be unmarshalled", func() {
 			var err error
@@ -124,6 +173,18 @@ var _ = Describe("CreateHandler", func() {
 			}`))
 		})
 
+		It("returns a 422 when the collection rejects the sender", func() {
+			sendersCollection.SetCall.Returns.Error = collections.ValidationError{errors.New(`from_address domain "example.org" is not allowed`)}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": [
+					"from_address domain \"example.org\" is not allowed"
+				]
+			}`))
+		})
+
 		It("returns a 500 when the collection indicates a system error", func() {
 			sendersCollection.SetCall.Returns.Error = errors.New("BOOM!")
 
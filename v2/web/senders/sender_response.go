@@ -7,9 +7,12 @@ import (
 )
 
 type SenderResponse struct {
-	ID    string              `json:"id"`
-	Name  string              `json:"name"`
-	Links SenderResponseLinks `json:"_links"`
+	ID          string              `json:"id"`
+	Name        string              `json:"name"`
+	FromAddress string              `json:"from_address,omitempty"`
+	FromName    string              `json:"from_name,omitempty"`
+	ReplyTo     string              `json:"reply_to,omitempty"`
+	Links       SenderResponseLinks `json:"_links"`
 }
 
 type SenderResponseLinks struct {
@@ -24,8 +27,11 @@ type Link struct {
 
 func NewSenderResponse(sender collections.Sender) SenderResponse {
 	return SenderResponse{
-		ID:   sender.ID,
-		Name: sender.Name,
+		ID:          sender.ID,
+		Name:        sender.Name,
+		FromAddress: sender.FromAddress,
+		FromName:    sender.FromName,
+		ReplyTo:     sender.ReplyTo,
 		Links: SenderResponseLinks{
 			Self:          Link{fmt.Sprintf("/senders/%s", sender.ID)},
 			CampaignTypes: Link{fmt.Sprintf("/senders/%s/campaign_types", sender.ID)},
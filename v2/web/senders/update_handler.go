@@ -29,8 +29,13 @@ func (h UpdateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 	splitURL := strings.Split(req.URL.Path, "/")
 	senderID := splitURL[len(splitURL)-1]
 
+	// The from fields are pointers so that a request that leaves them out
+	// keeps the values the sender already has.
 	var updateRequest struct {
-		Name string `json:"name"`
+		Name        string  `json:"name"`
+		FromAddress *string `json:"from_address"`
+		FromName    *string `json:"from_name"`
+		ReplyTo     *string `json:"reply_to"`
 	}
 
 	err := json.NewDecoder(req.Body).Decode(&updateRequest)
@@ -43,7 +48,7 @@ func (h UpdateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 	database := context.Get("database").(DatabaseInterface)
 	clientID := context.Get("client_id").(string)
 
-	_, err = h.senders.Get(database.Connection(), senderID, clientID)
+	existing, err := h.senders.Get(database.Connection(), senderID, clientID)
 	if err != nil {
 		switch err.(type) {
 		case collections.NotFoundError:
@@ -56,13 +61,16 @@ func (h UpdateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 	}
 
 	sender, err := h.senders.Set(database.Connection(), collections.Sender{
-		ID:       senderID,
-		Name:     updateRequest.Name,
-		ClientID: clientID,
+		ID:          senderID,
+		Name:        updateRequest.Name,
+		ClientID:    clientID,
+		FromAddress: valueOrDefault(updateRequest.FromAddress, existing.FromAddress),
+		FromName:    valueOrDefault(updateRequest.FromName, existing.FromName),
+		ReplyTo:     valueOrDefault(updateRequest.ReplyTo, existing.ReplyTo),
 	})
 	if err != nil {
 		switch err.(type) {
-		case collections.DuplicateRecordError:
+		case collections.DuplicateRecordError, collections.ValidationError:
 			w.WriteHeader(422)
 		default:
 			w.WriteHeader(http.StatusInternalServerError)
@@ -73,3 +81,11 @@ func (h UpdateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 
 	json.NewEncoder(w).Encode(NewSenderResponse(sender))
 }
+
+func valueOrDefault(value *string, defaultValue string) string {
+	if value == nil {
+		return defaultValue
+	}
+
+	return *value
+}
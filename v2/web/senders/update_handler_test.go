@@ -82,6 +82,64 @@ var _ = Describe("UpdateHandler", func() {
 		}`))
 	})
 
+	Context("when the sender has a from address", func() {
+		BeforeEach(func() {
+			sendersCollection.GetCall.Returns.Sender = collections.Sender{
+				ID:          "some-sender-id",
+				Name:        "some-sender",
+				ClientID:    "some-client-id",
+				FromAddress: "no-reply@example.com",
+				FromName:    "Some Sender",
+				ReplyTo:     "support@example.com",
+			}
+		})
+
+		It("keeps the from fields that are left out of the request", func() {
+			requestBody, err := json.Marshal(map[string]string{
+				"name":      "changed-sender",
+				"from_name": "Changed Sender",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err := http.NewRequest("PUT", "/senders/some-sender-id", bytes.NewBuffer(requestBody))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(sendersCollection.SetCall.Receives.Sender).To(Equal(collections.Sender{
+				ID:          "some-sender-id",
+				Name:        "changed-sender",
+				ClientID:    "some-client-id",
+				FromAddress: "no-reply@example.com",
+				FromName:    "Changed Sender",
+				ReplyTo:     "support@example.com",
+			}))
+		})
+
+		It("clears the from fields that are set to empty strings", func() {
+			requestBody, err := json.Marshal(map[string]string{
+				"name":         "changed-sender",
+				"from_address": "",
+				"from_name":    "",
+				"reply_to":     "",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err := http.NewRequest("PUT", "/senders/some-sender-id", bytes.NewBuffer(requestBody))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(sendersCollection.SetCall.Receives.Sender).To(Equal(collections.Sender{
+				ID:       "some-sender-id",
+				Name:     "changed-sender",
+				ClientID: "some-client-id",
+			}))
+		})
+	})
+
 	Context("failure cases", func() {
 		Context("when the sender cannot be got", func() {
 			It("returns a 404 and a not found error", func() {
@@ -176,5 +234,25 @@ var _ = Describe("UpdateHandler", func() {
 				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["duplicate record"]}`))
 			})
 		})
+
+		Context("when the from address is invalid", func() {
+			It("returns a 422 with an error message", func() {
+				sendersCollection.SetCall.Returns.Error = collections.ValidationError{errors.New(`from_address "nope" is not a valid email address`)}
+
+				requestBody, err := json.Marshal(map[string]string{
+					"name":         "changed-sender",
+					"from_address": "nope",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err := http.NewRequest("PUT", "/senders/some-sender-id", bytes.NewBuffer(requestBody))
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(writer.Code).To(Equal(422))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["from_address \"nope\" is not a valid email address"]}`))
+			})
+		})
 	})
 })
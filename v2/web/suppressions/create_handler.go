@@ -0,0 +1,65 @@
+package suppressions
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type collectionSetter interface {
+	Set(connection collections.ConnectionInterface, suppression collections.Suppression) (collections.Suppression, error)
+}
+
+type CreateHandler struct {
+	collection collectionSetter
+}
+
+func NewCreateHandler(collection collectionSetter) CreateHandler {
+	return CreateHandler{
+		collection: collection,
+	}
+}
+
+func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	var createRequest struct {
+		Email  string `json:"email"`
+		Reason string `json:"reason"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&createRequest)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{ "errors": [ "invalid json body" ] }`))
+		return
+	}
+
+	if !strings.Contains(createRequest.Email, "@") {
+		w.WriteHeader(422)
+		w.Write([]byte(`{ "errors": [ "missing or invalid email" ] }`))
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+
+	suppression, err := h.collection.Set(database.Connection(), collections.Suppression{
+		Email:  strings.TrimSpace(createRequest.Email),
+		Reason: createRequest.Reason,
+	})
+	if err != nil {
+		switch err.(type) {
+		case collections.DuplicateRecordError:
+			w.WriteHeader(http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprintf(w, `{ "errors": [%q] }`, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(NewSuppressionResponse(suppression))
+}
@@ -0,0 +1,123 @@
+package suppressions_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/suppressions"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CreateHandler", func() {
+	var (
+		handler                suppressions.CreateHandler
+		suppressionsCollection *mocks.SuppressionsCollection
+		context                stack.Context
+		writer                 *httptest.ResponseRecorder
+		request                *http.Request
+		conn                   *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("POST", "/suppressions", strings.NewReader(`{
+			"email": "user@example.com",
+			"reason": "legal takedown"
+		}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		suppressionsCollection = mocks.NewSuppressionsCollection()
+		handler = suppressions.NewCreateHandler(suppressionsCollection)
+	})
+
+	It("creates a suppression", func() {
+		createdAt, err := time.Parse(time.RFC3339, "2015-08-27T12:34:56Z")
+		Expect(err).NotTo(HaveOccurred())
+
+		suppressionsCollection.SetCall.Returns.Suppression = collections.Suppression{
+			ID:        "some-suppression-id",
+			Email:     "user@example.com",
+			Reason:    "legal takedown",
+			CreatedAt: createdAt,
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusCreated))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"id": "some-suppression-id",
+			"email": "user@example.com",
+			"reason": "legal takedown",
+			"created_at": "2015-08-27T12:34:56Z",
+			"_links": {
+				"self": {
+					"href": "/suppressions/some-suppression-id"
+				}
+			}
+		}`))
+
+		Expect(suppressionsCollection.SetCall.Receives.Connection).To(Equal(conn))
+		Expect(suppressionsCollection.SetCall.Receives.Suppression).To(Equal(collections.Suppression{
+			Email:  "user@example.com",
+			Reason: "legal takedown",
+		}))
+	})
+
+	Context("when an error occurs", func() {
+		It("returns a 400 when the JSON is invalid", func() {
+			request, err := http.NewRequest("POST", "/suppressions", strings.NewReader("%%%"))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusBadRequest))
+			Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["invalid json body"]}`))
+		})
+
+		It("returns a 422 when the email is missing", func() {
+			request, err := http.NewRequest("POST", "/suppressions", strings.NewReader(`{"reason": "complaint"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["missing or invalid email"]}`))
+			Expect(suppressionsCollection.SetCall.Receives.Suppression).To(Equal(collections.Suppression{}))
+		})
+
+		It("returns a 409 when the email is already suppressed", func() {
+			suppressionsCollection.SetCall.Returns.Error = collections.DuplicateRecordError{errors.New(`Email "user@example.com" is already suppressed`)}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusConflict))
+			Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["Email \"user@example.com\" is already suppressed"]}`))
+		})
+
+		It("returns a 500 when the collection errors", func() {
+			suppressionsCollection.SetCall.Returns.Error = errors.New("some error")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["some error"]}`))
+		})
+	})
+})
@@ -0,0 +1,74 @@
+package suppressions_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/suppressions"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeleteHandler", func() {
+	var (
+		handler                suppressions.DeleteHandler
+		suppressionsCollection *mocks.SuppressionsCollection
+		context                stack.Context
+		writer                 *httptest.ResponseRecorder
+		request                *http.Request
+		conn                   *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("DELETE", "/suppressions/some-suppression-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		suppressionsCollection = mocks.NewSuppressionsCollection()
+		handler = suppressions.NewDeleteHandler(suppressionsCollection)
+	})
+
+	It("deletes the suppression", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+		Expect(writer.Body.String()).To(BeEmpty())
+
+		Expect(suppressionsCollection.DeleteCall.Receives.Connection).To(Equal(conn))
+		Expect(suppressionsCollection.DeleteCall.Receives.ID).To(Equal("some-suppression-id"))
+	})
+
+	Context("when an error occurs", func() {
+		It("returns a 404 when the suppression cannot be found", func() {
+			suppressionsCollection.DeleteCall.Returns.Error = collections.NotFoundError{errors.New("not found")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["not found"]}`))
+		})
+
+		It("returns a 500 when the collection errors", func() {
+			suppressionsCollection.DeleteCall.Returns.Error = errors.New("some delete error")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["some delete error"]}`))
+		})
+	})
+})
@@ -0,0 +1,95 @@
+package suppressions_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/suppressions"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetHandler", func() {
+	var (
+		handler                suppressions.GetHandler
+		suppressionsCollection *mocks.SuppressionsCollection
+		context                stack.Context
+		writer                 *httptest.ResponseRecorder
+		request                *http.Request
+		conn                   *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("GET", "/suppressions/some-suppression-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		suppressionsCollection = mocks.NewSuppressionsCollection()
+		handler = suppressions.NewGetHandler(suppressionsCollection)
+	})
+
+	It("returns the suppression", func() {
+		createdAt, err := time.Parse(time.RFC3339, "2015-08-27T12:34:56Z")
+		Expect(err).NotTo(HaveOccurred())
+
+		suppressionsCollection.GetCall.Returns.Suppression = collections.Suppression{
+			ID:        "some-suppression-id",
+			Email:     "user@example.com",
+			Reason:    "bounce",
+			CreatedAt: createdAt,
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"id": "some-suppression-id",
+			"email": "user@example.com",
+			"reason": "bounce",
+			"created_at": "2015-08-27T12:34:56Z",
+			"_links": {
+				"self": {
+					"href": "/suppressions/some-suppression-id"
+				}
+			}
+		}`))
+
+		Expect(suppressionsCollection.GetCall.Receives.Connection).To(Equal(conn))
+		Expect(suppressionsCollection.GetCall.Receives.ID).To(Equal("some-suppression-id"))
+	})
+
+	Context("when an error occurs", func() {
+		It("returns a 404 when the suppression cannot be found", func() {
+			suppressionsCollection.GetCall.Returns.Error = collections.NotFoundError{errors.New(`Suppression with id "some-suppression-id" could not be found`)}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["Suppression with id \"some-suppression-id\" could not be found"]}`))
+		})
+
+		It("returns a 500 when the collection errors", func() {
+			suppressionsCollection.GetCall.Returns.Error = errors.New("some get error")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["some get error"]}`))
+		})
+	})
+})
@@ -0,0 +1,96 @@
+package suppressions_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/suppressions"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListHandler", func() {
+	var (
+		handler                suppressions.ListHandler
+		suppressionsCollection *mocks.SuppressionsCollection
+		context                stack.Context
+		writer                 *httptest.ResponseRecorder
+		request                *http.Request
+		conn                   *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("GET", "/suppressions", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		suppressionsCollection = mocks.NewSuppressionsCollection()
+		handler = suppressions.NewListHandler(suppressionsCollection)
+	})
+
+	It("returns a list of suppressions", func() {
+		createdAt, err := time.Parse(time.RFC3339, "2015-08-27T12:34:56Z")
+		Expect(err).NotTo(HaveOccurred())
+
+		suppressionsCollection.ListCall.Returns.Suppressions = []collections.Suppression{
+			{
+				ID:        "some-suppression-id",
+				Email:     "user@example.com",
+				Reason:    "bounce",
+				CreatedAt: createdAt,
+			},
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"suppressions": [
+				{
+					"id": "some-suppression-id",
+					"email": "user@example.com",
+					"reason": "bounce",
+					"created_at": "2015-08-27T12:34:56Z",
+					"_links": {
+						"self": {
+							"href": "/suppressions/some-suppression-id"
+						}
+					}
+				}
+			],
+			"_links": {
+				"self": {
+					"href": "/suppressions"
+				}
+			}
+		}`))
+
+		Expect(suppressionsCollection.ListCall.Receives.Connection).To(Equal(conn))
+	})
+
+	Context("when the collection errors", func() {
+		It("returns a 500", func() {
+			suppressionsCollection.ListCall.Returns.Error = errors.New("some list error")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["some list error"]}`))
+		})
+	})
+})
@@ -0,0 +1,24 @@
+package suppressions
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type muxer interface {
+	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
+}
+
+type Routes struct {
+	RequestLogging         stack.Middleware
+	Authenticator          stack.Middleware
+	DatabaseAllocator      stack.Middleware
+	SuppressionsCollection collections.SuppressionsCollection
+}
+
+func (r Routes) Register(m muxer) {
+	m.Handle("POST", "/suppressions", NewCreateHandler(r.SuppressionsCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/suppressions", NewListHandler(r.SuppressionsCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/suppressions/{suppression_id}", NewGetHandler(r.SuppressionsCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("DELETE", "/suppressions/{suppression_id}", NewDeleteHandler(r.SuppressionsCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+}
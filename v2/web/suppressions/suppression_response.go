@@ -0,0 +1,36 @@
+package suppressions
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+)
+
+type Link struct {
+	Href string `json:"href"`
+}
+
+type SuppressionResponseLinks struct {
+	Self Link `json:"self"`
+}
+
+type SuppressionResponse struct {
+	ID        string                   `json:"id"`
+	Email     string                   `json:"email"`
+	Reason    string                   `json:"reason"`
+	CreatedAt time.Time                `json:"created_at"`
+	Links     SuppressionResponseLinks `json:"_links"`
+}
+
+func NewSuppressionResponse(suppression collections.Suppression) SuppressionResponse {
+	return SuppressionResponse{
+		ID:        suppression.ID,
+		Email:     suppression.Email,
+		Reason:    suppression.Reason,
+		CreatedAt: suppression.CreatedAt,
+		Links: SuppressionResponseLinks{
+			Self: Link{fmt.Sprintf("/suppressions/%s", suppression.ID)},
+		},
+	}
+}
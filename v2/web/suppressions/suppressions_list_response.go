@@ -0,0 +1,27 @@
+package suppressions
+
+import "github.com/cloudfoundry-incubator/notifications/v2/collections"
+
+type SuppressionsListResponseLinks struct {
+	Self Link `json:"self"`
+}
+
+type SuppressionsListResponse struct {
+	Suppressions []SuppressionResponse         `json:"suppressions"`
+	Links        SuppressionsListResponseLinks `json:"_links"`
+}
+
+func NewSuppressionsListResponse(suppressions []collections.Suppression) SuppressionsListResponse {
+	suppressionResponseList := []SuppressionResponse{}
+
+	for _, suppression := range suppressions {
+		suppressionResponseList = append(suppressionResponseList, NewSuppressionResponse(suppression))
+	}
+
+	return SuppressionsListResponse{
+		Suppressions: suppressionResponseList,
+		Links: SuppressionsListResponseLinks{
+			Self: Link{"/suppressions"},
+		},
+	}
+}
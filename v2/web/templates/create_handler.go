@@ -10,7 +10,7 @@ import (
 )
 
 type collectionSetter interface {
-	Set(conn collections.ConnectionInterface, template collections.Template) (createdTemplate collections.Template, err error)
+	Set(conn collections.ConnectionInterface, template collections.Template, clientID string) (createdTemplate collections.Template, err error)
 }
 
 type CreateHandler struct {
@@ -77,11 +77,13 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		Subject:  createRequest.Subject,
 		Metadata: string(*createRequest.Metadata),
 		ClientID: clientID,
-	})
+	}, clientID)
 	if err != nil {
 		switch err.(type) {
 		case collections.DuplicateRecordError:
 			w.WriteHeader(http.StatusConflict)
+		case collections.ValidationError:
+			w.WriteHeader(422)
 		default:
 			w.WriteHeader(http.StatusInternalServerError)
 		}
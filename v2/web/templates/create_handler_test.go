@@ -103,6 +103,7 @@ var _ = Describe("CreateHandler", func() {
 
 		Expect(templatesCollection.SetCall.Receives.Template.Subject).To(Equal("{{.Subject}}"))
 		Expect(templatesCollection.SetCall.Receives.Template.Metadata).To(Equal("{}"))
+		Expect(templatesCollection.SetCall.Receives.ClientID).To(Equal("some-client-id"))
 
 		Expect(writer.Code).To(Equal(http.StatusCreated))
 		Expect(writer.Body.String()).To(MatchJSON(`{
@@ -309,6 +310,13 @@ var _ = Describe("CreateHandler", func() {
 			Expect(writer.Code).To(Equal(http.StatusConflict))
 		})
 
+		It("returns a 422 when the template is invalid", func() {
+			templatesCollection.SetCall.Returns.Error = collections.ValidationError{errors.New("html template is invalid at line 1, column 5: unknown field \"Mesage\"")}
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": ["html template is invalid at line 1, column 5: unknown field \"Mesage\""] }`))
+		})
+
 		It("returns a 500 when the collection indicates a system error", func() {
 			templatesCollection.SetCall.Returns.Error = errors.New("The database is bad")
 			handler.ServeHTTP(writer, request, context)
@@ -0,0 +1,66 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type versionGetter interface {
+	GetVersion(conn collections.ConnectionInterface, templateID string, version int, clientID string) (collections.TemplateVersion, error)
+}
+
+type GetVersionHandler struct {
+	collection versionGetter
+}
+
+func NewGetVersionHandler(collection versionGetter) GetVersionHandler {
+	return GetVersionHandler{
+		collection: collection,
+	}
+}
+
+func (h GetVersionHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID, version, err := parseTemplateVersion(req.URL.Path)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `{"errors": [%q]}`, err)
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	clientID := context.Get("client_id").(string)
+
+	templateVersion, err := h.collection.GetVersion(database.Connection(), templateID, version, clientID)
+	if err != nil {
+		switch err.(type) {
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprintf(w, `{"errors": [%q]}`, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(NewTemplateVersionResponse(templateVersion))
+}
+
+// parseTemplateVersion reads the template ID and version number from a
+// /templates/{template_id}/versions/{version} path. A version that is not a
+// positive number cannot exist.
+func parseTemplateVersion(path string) (string, int, error) {
+	matches := regexp.MustCompile(`\/templates\/(.*)\/versions\/([^\/]*)`).FindStringSubmatch(path)
+
+	version, err := strconv.Atoi(matches[2])
+	if err != nil || version < 1 {
+		return "", 0, fmt.Errorf("Version %q of template with id %q could not be found", matches[2], matches[1])
+	}
+
+	return matches[1], version, nil
+}
@@ -0,0 +1,114 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/templates"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetVersionHandler", func() {
+	var (
+		handler    templates.GetVersionHandler
+		context    stack.Context
+		conn       *mocks.Connection
+		database   *mocks.Database
+		writer     *httptest.ResponseRecorder
+		request    *http.Request
+		collection *mocks.TemplatesCollection
+	)
+
+	BeforeEach(func() {
+		context = stack.NewContext()
+
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+		context.Set("database", database)
+
+		context.Set("client_id", "some-client-id")
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("GET", "/templates/some-template-id/versions/3", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		collection = mocks.NewTemplatesCollection()
+
+		handler = templates.NewGetVersionHandler(collection)
+	})
+
+	It("gets a version of a template", func() {
+		collection.GetVersionCall.Returns.Version = collections.TemplateVersion{
+			TemplateID: "some-template-id",
+			Version:    3,
+			Name:       "an interesting template",
+			Text:       "template text",
+			HTML:       "template html",
+			Subject:    "template subject",
+			Metadata:   `{"template": "metadata"}`,
+			ClientID:   "some-client-id",
+			CreatedAt:  time.Date(2015, time.March, 1, 0, 0, 0, 0, time.UTC),
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"version": 3,
+			"name": "an interesting template",
+			"text": "template text",
+			"html": "template html",
+			"subject": "template subject",
+			"metadata": {"template": "metadata"},
+			"client_id": "some-client-id",
+			"created_at": "2015-03-01T00:00:00Z",
+			"_links": {
+				"self": {"href": "/templates/some-template-id/versions/3"},
+				"template": {"href": "/templates/some-template-id"}
+			}
+		}`))
+
+		Expect(collection.GetVersionCall.Receives.Connection).To(Equal(conn))
+		Expect(collection.GetVersionCall.Receives.TemplateID).To(Equal("some-template-id"))
+		Expect(collection.GetVersionCall.Receives.Version).To(Equal(3))
+		Expect(collection.GetVersionCall.Receives.ClientID).To(Equal("some-client-id"))
+	})
+
+	Describe("error cases", func() {
+		It("responds with 404 if the version is not a number", func() {
+			var err error
+			request, err = http.NewRequest("GET", "/templates/some-template-id/versions/banana", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body).To(MatchJSON(`{"errors": ["Version \"banana\" of template with id \"some-template-id\" could not be found"]}`))
+		})
+
+		It("responds with 404 if the collection GetVersion returns not found", func() {
+			collection.GetVersionCall.Returns.Error = collections.NotFoundError{errors.New("it was not found")}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body).To(MatchJSON(`{"errors": ["it was not found"]}`))
+		})
+
+		It("responds with 500 if the collection GetVersion fails", func() {
+			collection.GetVersionCall.Returns.Error = errors.New("an unknown error")
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body).To(MatchJSON(`{"errors": ["an unknown error"]}`))
+		})
+	})
+})
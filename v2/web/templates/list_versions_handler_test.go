@@ -0,0 +1,151 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/templates"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListVersionsHandler", func() {
+	var (
+		handler    templates.ListVersionsHandler
+		context    stack.Context
+		conn       *mocks.Connection
+		database   *mocks.Database
+		writer     *httptest.ResponseRecorder
+		request    *http.Request
+		collection *mocks.TemplatesCollection
+	)
+
+	BeforeEach(func() {
+		context = stack.NewContext()
+
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+		context.Set("database", database)
+
+		context.Set("client_id", "some-client-id")
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("GET", "/templates/some-template-id/versions", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		collection = mocks.NewTemplatesCollection()
+
+		handler = templates.NewListVersionsHandler(collection)
+	})
+
+	It("lists the versions of a template", func() {
+		collection.ListVersionsCall.Returns.Versions = []collections.TemplateVersion{
+			{
+				TemplateID: "some-template-id",
+				Version:    2,
+				Name:       "an interesting template",
+				Text:       "new text",
+				HTML:       "new html",
+				Subject:    "new subject",
+				Metadata:   `{"template": "metadata"}`,
+				ClientID:   "some-client-id",
+				CreatedAt:  time.Date(2015, time.March, 2, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				TemplateID: "some-template-id",
+				Version:    1,
+				Name:       "an interesting template",
+				Text:       "old text",
+				HTML:       "old html",
+				Subject:    "old subject",
+				Metadata:   `{}`,
+				ClientID:   "some-client-id",
+				CreatedAt:  time.Date(2015, time.March, 1, 0, 0, 0, 0, time.UTC),
+			},
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"versions": [
+				{
+					"version": 2,
+					"name": "an interesting template",
+					"text": "new text",
+					"html": "new html",
+					"subject": "new subject",
+					"metadata": {"template": "metadata"},
+					"client_id": "some-client-id",
+					"created_at": "2015-03-02T00:00:00Z",
+					"_links": {
+						"self": {"href": "/templates/some-template-id/versions/2"},
+						"template": {"href": "/templates/some-template-id"}
+					}
+				},
+				{
+					"version": 1,
+					"name": "an interesting template",
+					"text": "old text",
+					"html": "old html",
+					"subject": "old subject",
+					"metadata": {},
+					"client_id": "some-client-id",
+					"created_at": "2015-03-01T00:00:00Z",
+					"_links": {
+						"self": {"href": "/templates/some-template-id/versions/1"},
+						"template": {"href": "/templates/some-template-id"}
+					}
+				}
+			],
+			"_links": {
+				"self": {"href": "/templates/some-template-id/versions"},
+				"template": {"href": "/templates/some-template-id"}
+			}
+		}`))
+
+		Expect(collection.ListVersionsCall.Receives.Connection).To(Equal(conn))
+		Expect(collection.ListVersionsCall.Receives.TemplateID).To(Equal("some-template-id"))
+		Expect(collection.ListVersionsCall.Receives.ClientID).To(Equal("some-client-id"))
+	})
+
+	It("responds with an empty list when there are no versions", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"versions": [],
+			"_links": {
+				"self": {"href": "/templates/some-template-id/versions"},
+				"template": {"href": "/templates/some-template-id"}
+			}
+		}`))
+	})
+
+	Describe("error cases", func() {
+		It("responds with 404 if the template cannot be found", func() {
+			collection.ListVersionsCall.Returns.Error = collections.NotFoundError{errors.New("it was not found")}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body).To(MatchJSON(`{"errors": ["it was not found"]}`))
+		})
+
+		It("responds with 500 if the collection fails", func() {
+			collection.ListVersionsCall.Returns.Error = errors.New("an unknown error")
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body).To(MatchJSON(`{"errors": ["an unknown error"]}`))
+		})
+	})
+})
@@ -0,0 +1,121 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type templatePreviewer interface {
+	Preview(templates common.Templates, sample common.PreviewSample) (common.Preview, error)
+}
+
+type partialsLister interface {
+	List(conn collections.ConnectionInterface, clientID string) ([]collections.Partial, error)
+}
+
+type PreviewResponse struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+	Source  string `json:"source"`
+}
+
+type PreviewHandler struct {
+	collection collectionGetter
+	partials   partialsLister
+	previewer  templatePreviewer
+}
+
+func NewPreviewHandler(collection collectionGetter, partials partialsLister, previewer templatePreviewer) PreviewHandler {
+	return PreviewHandler{
+		collection: collection,
+		partials:   partials,
+		previewer:  previewer,
+	}
+}
+
+func (h PreviewHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	splitURL := strings.Split(req.URL.Path, "/")
+	templateID := splitURL[len(splitURL)-2]
+
+	var previewRequest struct {
+		Subject           string `json:"subject"`
+		Text              string `json:"text"`
+		HTML              string `json:"html"`
+		To                string `json:"to"`
+		ReplyTo           string `json:"reply_to"`
+		UserGUID          string `json:"user_guid"`
+		KindDescription   string `json:"kind_description"`
+		SourceDescription string `json:"source_description"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&previewRequest)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errors": ["invalid json body"]}`))
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	clientID := context.Get("client_id").(string)
+
+	template, err := h.collection.Get(database.Connection(), templateID, clientID)
+	if err != nil {
+		switch err.(type) {
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprintf(w, `{"errors": [%q]}`, err)
+		return
+	}
+
+	partials, err := h.partials.List(database.Connection(), template.PartialsClientID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"errors": [%q]}`, err)
+		return
+	}
+
+	bodies := map[string]string{}
+	for _, partial := range partials {
+		bodies[partial.Name] = partial.Body
+	}
+
+	preview, err := h.previewer.Preview(common.Templates{
+		Name:     template.Name,
+		Subject:  template.Subject,
+		Text:     template.Text,
+		HTML:     template.HTML,
+		Partials: bodies,
+	}, common.PreviewSample{
+		ClientID:          clientID,
+		Subject:           previewRequest.Subject,
+		Text:              previewRequest.Text,
+		HTML:              previewRequest.HTML,
+		To:                previewRequest.To,
+		ReplyTo:           previewRequest.ReplyTo,
+		UserGUID:          previewRequest.UserGUID,
+		KindDescription:   previewRequest.KindDescription,
+		SourceDescription: previewRequest.SourceDescription,
+	})
+	if err != nil {
+		w.WriteHeader(422)
+		fmt.Fprintf(w, `{"errors": [%q]}`, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(PreviewResponse{
+		Subject: preview.Subject,
+		Text:    preview.Text,
+		HTML:    preview.HTML,
+		Source:  preview.Source,
+	})
+}
@@ -0,0 +1,175 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/templates"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PreviewHandler", func() {
+	var (
+		handler    templates.PreviewHandler
+		context    stack.Context
+		conn       *mocks.Connection
+		database   *mocks.Database
+		writer     *httptest.ResponseRecorder
+		request    *http.Request
+		collection *mocks.TemplatesCollection
+		partials   *mocks.PartialsCollection
+		previewer  *mocks.TemplatePreviewer
+	)
+
+	BeforeEach(func() {
+		context = stack.NewContext()
+
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+		context.Set("database", database)
+
+		context.Set("client_id", "some-client-id")
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("POST", "/templates/some-template-id/preview", strings.NewReader(`{
+			"subject": "sample",
+			"text": "sample text",
+			"html": "<b>sample</b>",
+			"to": "user@example.com",
+			"reply_to": "support@example.com"
+		}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		collection = mocks.NewTemplatesCollection()
+		collection.GetCall.Returns.Template = collections.Template{
+			ID:      "some-template-id",
+			Name:    "an interesting template",
+			Text:    "template {{.Text}}",
+			HTML:    "template {{.HTML}}",
+			Subject: "template {{.Subject}}",
+
+			PartialsClientID: "some-client-id",
+		}
+
+		partials = mocks.NewPartialsCollection()
+		partials.ListCall.Returns.Partials = []collections.Partial{
+			{Name: "footer", Body: "<footer>{{.Organization}}</footer>"},
+		}
+
+		previewer = mocks.NewTemplatePreviewer()
+		previewer.PreviewCall.Returns.Preview = common.Preview{
+			Subject: "template sample",
+			Text:    "template sample text",
+			HTML:    "template <b>sample</b>",
+			Source:  "Subject: template sample\n",
+		}
+
+		handler = templates.NewPreviewHandler(collection, partials, previewer)
+	})
+
+	It("renders the template against the sample", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(collection.GetCall.Receives.Connection).To(Equal(conn))
+		Expect(collection.GetCall.Receives.TemplateID).To(Equal("some-template-id"))
+		Expect(collection.GetCall.Receives.ClientID).To(Equal("some-client-id"))
+
+		Expect(partials.ListCall.Receives.Connection).To(Equal(conn))
+		Expect(partials.ListCall.Receives.ClientID).To(Equal("some-client-id"))
+
+		Expect(previewer.PreviewCall.Receives.Templates).To(Equal(common.Templates{
+			Name:    "an interesting template",
+			Text:    "template {{.Text}}",
+			HTML:    "template {{.HTML}}",
+			Subject: "template {{.Subject}}",
+			Partials: map[string]string{
+				"footer": "<footer>{{.Organization}}</footer>",
+			},
+		}))
+		Expect(previewer.PreviewCall.Receives.Sample).To(Equal(common.PreviewSample{
+			ClientID: "some-client-id",
+			Subject:  "sample",
+			Text:     "sample text",
+			HTML:     "<b>sample</b>",
+			To:       "user@example.com",
+			ReplyTo:  "support@example.com",
+		}))
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"subject": "template sample",
+			"text": "template sample text",
+			"html": "template <b>sample</b>",
+			"source": "Subject: template sample\n"
+		}`))
+	})
+
+	It("renders a shared template with the partials of the client that saved it", func() {
+		collection.GetCall.Returns.Template.PartialsClientID = "some-admin-client-id"
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(partials.ListCall.Receives.ClientID).To(Equal("some-admin-client-id"))
+		Expect(writer.Code).To(Equal(http.StatusOK))
+	})
+
+	Context("failure cases", func() {
+		It("returns a 400 when the JSON cannot be unmarshalled", func() {
+			var err error
+			request, err = http.NewRequest("POST", "/templates/some-template-id/preview", strings.NewReader("%%%"))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusBadRequest))
+			Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["invalid json body"]}`))
+		})
+
+		It("returns a 404 when the template does not exist", func() {
+			collection.GetCall.Returns.Error = collections.NotFoundError{errors.New("Template with id \"some-template-id\" could not be found")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["Template with id \"some-template-id\" could not be found"]}`))
+		})
+
+		It("returns a 500 when the template cannot be retrieved", func() {
+			collection.GetCall.Returns.Error = errors.New("database failure")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["database failure"]}`))
+		})
+
+		It("returns a 500 when the partials cannot be retrieved", func() {
+			partials.ListCall.Returns.Error = errors.New("database failure")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["database failure"]}`))
+		})
+
+		It("returns a 422 when the template cannot be rendered", func() {
+			previewer.PreviewCall.Returns.Error = errors.New("template: compileTemplate:1: unclosed action")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["template: compileTemplate:1: unclosed action"]}`))
+		})
+	})
+})
@@ -0,0 +1,52 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type templateRollbacker interface {
+	Rollback(conn collections.ConnectionInterface, templateID string, version int, clientID string) (collections.Template, error)
+}
+
+type RollbackHandler struct {
+	collection templateRollbacker
+}
+
+func NewRollbackHandler(collection templateRollbacker) RollbackHandler {
+	return RollbackHandler{
+		collection: collection,
+	}
+}
+
+func (h RollbackHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID, version, err := parseTemplateVersion(req.URL.Path)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `{"errors": [%q]}`, err)
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	clientID := context.Get("client_id").(string)
+
+	template, err := h.collection.Rollback(database.Connection(), templateID, version, clientID)
+	if err != nil {
+		switch err.(type) {
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		case collections.ValidationError:
+			w.WriteHeader(422)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprintf(w, `{"errors": [%q]}`, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(NewTemplateResponse(template))
+}
@@ -0,0 +1,131 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/templates"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RollbackHandler", func() {
+	var (
+		handler    templates.RollbackHandler
+		context    stack.Context
+		conn       *mocks.Connection
+		database   *mocks.Database
+		writer     *httptest.ResponseRecorder
+		request    *http.Request
+		collection *mocks.TemplatesCollection
+	)
+
+	BeforeEach(func() {
+		context = stack.NewContext()
+
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+		context.Set("database", database)
+
+		context.Set("client_id", "some-client-id")
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("POST", "/templates/some-template-id/versions/2/rollback", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		collection = mocks.NewTemplatesCollection()
+
+		handler = templates.NewRollbackHandler(collection)
+	})
+
+	It("rolls the template back to the given version", func() {
+		collection.RollbackCall.Returns.Template = collections.Template{
+			ID:       "some-template-id",
+			Name:     "an interesting template",
+			Text:     "old text",
+			HTML:     "old html",
+			Subject:  "old subject",
+			Metadata: `{"template": "metadata"}`,
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"id": "some-template-id",
+			"name": "an interesting template",
+			"text": "old text",
+			"html": "old html",
+			"subject": "old subject",
+			"metadata": {"template": "metadata"},
+			"_links": {
+				"self": {"href": "/templates/some-template-id"}
+			}
+		}`))
+
+		Expect(collection.RollbackCall.Receives.Connection).To(Equal(conn))
+		Expect(collection.RollbackCall.Receives.TemplateID).To(Equal("some-template-id"))
+		Expect(collection.RollbackCall.Receives.Version).To(Equal(2))
+		Expect(collection.RollbackCall.Receives.ClientID).To(Equal("some-client-id"))
+	})
+
+	It("rolls back the default template", func() {
+		var err error
+		request, err = http.NewRequest("POST", "/templates/default/versions/2/rollback", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		collection.RollbackCall.Returns.Template = collections.Template{
+			ID:       "default",
+			Metadata: "{}",
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(collection.RollbackCall.Receives.TemplateID).To(Equal("default"))
+	})
+
+	Describe("error cases", func() {
+		It("responds with 404 if the version is not a positive number", func() {
+			var err error
+			request, err = http.NewRequest("POST", "/templates/some-template-id/versions/0/rollback", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body).To(MatchJSON(`{"errors": ["Version \"0\" of template with id \"some-template-id\" could not be found"]}`))
+		})
+
+		It("responds with 404 if the template or version cannot be found", func() {
+			collection.RollbackCall.Returns.Error = collections.NotFoundError{errors.New("it was not found")}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body).To(MatchJSON(`{"errors": ["it was not found"]}`))
+		})
+
+		It("responds with 422 if the version no longer validates", func() {
+			collection.RollbackCall.Returns.Error = collections.ValidationError{errors.New("template is invalid")}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body).To(MatchJSON(`{"errors": ["template is invalid"]}`))
+		})
+
+		It("responds with 500 if the collection fails", func() {
+			collection.RollbackCall.Returns.Error = errors.New("an unknown error")
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body).To(MatchJSON(`{"errors": ["an unknown error"]}`))
+		})
+	})
+})
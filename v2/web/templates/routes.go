@@ -1,6 +1,7 @@
 package templates
 
 import (
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v2/collections"
 	"github.com/ryanmoran/stack"
 )
@@ -15,13 +16,20 @@ type Routes struct {
 	AdminAuthenticator  stack.Middleware
 	DatabaseAllocator   stack.Middleware
 	TemplatesCollection collections.TemplatesCollection
+	PartialsCollection  collections.PartialsCollection
+	TemplatePreviewer   common.Previewer
 }
 
 func (r Routes) Register(m muxer) {
 	m.Handle("GET", "/templates", NewListHandler(r.TemplatesCollection), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("POST", "/templates", NewCreateHandler(r.TemplatesCollection), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("GET", "/templates/{template_id}", NewGetHandler(r.TemplatesCollection), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/templates/{template_id}/preview", NewPreviewHandler(r.TemplatesCollection, r.PartialsCollection, r.TemplatePreviewer), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("DELETE", "/templates/{template_id}", NewDeleteHandler(r.TemplatesCollection), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("PUT", "/templates/default", NewUpdateDefaultHandler(r.TemplatesCollection), r.RequestLogging, r.AdminAuthenticator, r.DatabaseAllocator)
 	m.Handle("PUT", "/templates/{template_id}", NewUpdateHandler(r.TemplatesCollection), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/templates/{template_id}/versions", NewListVersionsHandler(r.TemplatesCollection), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/templates/{template_id}/versions/{version}", NewGetVersionHandler(r.TemplatesCollection), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/templates/default/versions/{version}/rollback", NewRollbackHandler(r.TemplatesCollection), r.RequestLogging, r.AdminAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/templates/{template_id}/versions/{version}/rollback", NewRollbackHandler(r.TemplatesCollection), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
 }
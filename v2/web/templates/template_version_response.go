@@ -0,0 +1,70 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+)
+
+type TemplateVersionResponseLinks struct {
+	Self     Link `json:"self"`
+	Template Link `json:"template"`
+}
+
+type TemplateVersionResponse struct {
+	Version   int                          `json:"version"`
+	Name      string                       `json:"name"`
+	Text      string                       `json:"text"`
+	HTML      string                       `json:"html"`
+	Subject   string                       `json:"subject"`
+	Metadata  *json.RawMessage             `json:"metadata"`
+	ClientID  string                       `json:"client_id"`
+	CreatedAt time.Time                    `json:"created_at"`
+	Links     TemplateVersionResponseLinks `json:"_links"`
+}
+
+func NewTemplateVersionResponse(version collections.TemplateVersion) TemplateVersionResponse {
+	metadata := json.RawMessage(version.Metadata)
+	return TemplateVersionResponse{
+		Version:   version.Version,
+		Name:      version.Name,
+		Text:      version.Text,
+		HTML:      version.HTML,
+		Subject:   version.Subject,
+		Metadata:  &metadata,
+		ClientID:  version.ClientID,
+		CreatedAt: version.CreatedAt,
+		Links: TemplateVersionResponseLinks{
+			Self:     Link{fmt.Sprintf("/templates/%s/versions/%d", version.TemplateID, version.Version)},
+			Template: Link{fmt.Sprintf("/templates/%s", version.TemplateID)},
+		},
+	}
+}
+
+type TemplateVersionsListResponseLinks struct {
+	Self     Link `json:"self"`
+	Template Link `json:"template"`
+}
+
+type TemplateVersionsListResponse struct {
+	Versions []TemplateVersionResponse         `json:"versions"`
+	Links    TemplateVersionsListResponseLinks `json:"_links"`
+}
+
+func NewTemplateVersionsListResponse(templateID string, versionList []collections.TemplateVersion) TemplateVersionsListResponse {
+	versions := []TemplateVersionResponse{}
+
+	for _, v := range versionList {
+		versions = append(versions, NewTemplateVersionResponse(v))
+	}
+
+	return TemplateVersionsListResponse{
+		Versions: versions,
+		Links: TemplateVersionsListResponseLinks{
+			Self:     Link{fmt.Sprintf("/templates/%s/versions", templateID)},
+			Template: Link{fmt.Sprintf("/templates/%s", templateID)},
+		},
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
 	"github.com/ryanmoran/stack"
 )
 
@@ -79,9 +80,16 @@ func (h UpdateDefaultHandler) ServeHTTP(w http.ResponseWriter, req *http.Request
 		template.Subject = "{{.Subject}}"
 	}
 
-	template, err = h.templatesCollection.Set(database.Connection(), template)
+	clientID := context.Get("client_id").(string)
+
+	template, err = h.templatesCollection.Set(database.Connection(), template, clientID)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		switch err.(type) {
+		case collections.ValidationError:
+			w.WriteHeader(422)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
 		fmt.Fprintf(w, `{ "errors": [%q] }`, err)
 		return
 	}
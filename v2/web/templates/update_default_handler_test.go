@@ -99,6 +99,7 @@ var _ = Describe("UpdateDefaultHandler", func() {
 			Metadata: `{"template":"new"}`,
 			ClientID: "",
 		}))
+		Expect(templatesCollection.SetCall.Receives.ClientID).To(Equal("admin-client"))
 	})
 
 	Context("when omitting fields", func() {
@@ -267,6 +268,19 @@ var _ = Describe("UpdateDefaultHandler", func() {
 			})
 		})
 
+		Context("when the template is invalid", func() {
+			It("returns a 422 error with an error message", func() {
+				templatesCollection.SetCall.Returns.Error = collections.ValidationError{errors.New("subject template is invalid at line 1, column 3: unknown field \"Subjet\"")}
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(writer.Code).To(Equal(422))
+				Expect(writer.Body.String()).To(MatchJSON(`{
+					"errors": ["subject template is invalid at line 1, column 3: unknown field \"Subjet\""]
+				}`))
+			})
+		})
+
 		Context("when the templates repo set call returns an error", func() {
 			It("returns a 500 error with an error message", func() {
 				templatesCollection.SetCall.Returns.Error = errors.New("failed to set")
@@ -11,7 +11,7 @@ import (
 )
 
 type collectionSetGetter interface {
-	Set(conn collections.ConnectionInterface, template collections.Template) (createdTemplate collections.Template, err error)
+	Set(conn collections.ConnectionInterface, template collections.Template, clientID string) (createdTemplate collections.Template, err error)
 	Get(conn collections.ConnectionInterface, templateID, clientID string) (template collections.Template, err error)
 }
 
@@ -96,11 +96,13 @@ func (h UpdateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		return
 	}
 
-	template, err = h.templates.Set(database.Connection(), template)
+	template, err = h.templates.Set(database.Connection(), template, clientID)
 	if err != nil {
 		switch err.(type) {
 		case collections.NotFoundError:
 			w.WriteHeader(http.StatusNotFound)
+		case collections.ValidationError:
+			w.WriteHeader(422)
 		default:
 			w.WriteHeader(http.StatusInternalServerError)
 		}
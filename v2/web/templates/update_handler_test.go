@@ -100,6 +100,7 @@ var _ = Describe("UpdateHandler", func() {
 			Metadata: `{"template":"metadata"}`,
 			ClientID: "some-client-id",
 		}))
+		Expect(templatesCollection.SetCall.Receives.ClientID).To(Equal("some-client-id"))
 	})
 
 	Context("when omitting fields", func() {
@@ -299,6 +300,17 @@ var _ = Describe("UpdateHandler", func() {
 			}`))
 		})
 
+		It("returns a 422 with an error message if the template is invalid", func() {
+			templatesCollection.SetCall.Returns.Error = collections.ValidationError{errors.New("text template is invalid at line 2: unexpected EOF")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["text template is invalid at line 2: unexpected EOF"]
+			}`))
+		})
+
 		It("returns a 500 with an error message if setting the template fails", func() {
 			templatesCollection.SetCall.Returns.Error = errors.New("failed to talk to the db")
 
@@ -0,0 +1,65 @@
+package unsubscribers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/pivotal-golang/conceal"
+	"github.com/ryanmoran/stack"
+)
+
+// UnsubscribeHandler serves the one-click unsubscribe URL from the
+// List-Unsubscribe header (RFC 8058). Mail providers post to it without a
+// token, so the user and campaign type are read from the cloaked
+// unsubscribe ID in the path.
+type UnsubscribeHandler struct {
+	cloak      conceal.CloakInterface
+	collection unsubscribersGetSetter
+}
+
+func NewUnsubscribeHandler(cloak conceal.CloakInterface, collection unsubscribersGetSetter) UnsubscribeHandler {
+	return UnsubscribeHandler{
+		cloak:      cloak,
+		collection: collection,
+	}
+}
+
+func (h UnsubscribeHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	unsubscribeID := strings.Split(req.URL.Path, "/unsubscribe/")[1]
+
+	plainText, err := h.cloak.Unveil([]byte(unsubscribeID))
+	if err != nil {
+		w.WriteHeader(422)
+		w.Write([]byte(`{"errors": ["The unsubscribe ID is invalid"]}`))
+		return
+	}
+
+	parts := strings.Split(string(plainText), "|")
+	if len(parts) != 3 || parts[0] == "" {
+		w.WriteHeader(422)
+		w.Write([]byte(`{"errors": ["The unsubscribe ID is invalid"]}`))
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	_, err = h.collection.Set(database.Connection(), collections.Unsubscriber{
+		CampaignTypeID: parts[2],
+		UserGUID:       parts[0],
+	})
+	if err != nil {
+		switch err.(type) {
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		case collections.PermissionsError:
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		w.Write([]byte(fmt.Sprintf(`{"errors": [%q]}`, err)))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
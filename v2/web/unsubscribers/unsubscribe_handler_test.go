@@ -0,0 +1,133 @@
+package unsubscribers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/unsubscribers"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UnsubscribeHandler", func() {
+	var (
+		handler                 unsubscribers.UnsubscribeHandler
+		writer                  *httptest.ResponseRecorder
+		request                 *http.Request
+		context                 stack.Context
+		cloak                   *mocks.Cloak
+		unsubscribersCollection *mocks.UnsubscribersCollection
+		database                *mocks.Database
+		connection              *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		cloak = mocks.NewCloak()
+		cloak.UnveilCall.Returns.PlainText = []byte("some-user-guid|some-client-id|some-campaign-type-id")
+
+		unsubscribersCollection = mocks.NewUnsubscribersCollection()
+		handler = unsubscribers.NewUnsubscribeHandler(cloak, unsubscribersCollection)
+
+		connection = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		writer = httptest.NewRecorder()
+
+		request, err = http.NewRequest("POST", "/unsubscribe/some-unsubscribe-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("unsubscribes the user identified by the unsubscribe ID", func() {
+		handler.ServeHTTP(writer, request, context)
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+		Expect(writer.Body.String()).To(BeEmpty())
+
+		Expect(cloak.UnveilCall.Receives.CipherText).To(Equal([]byte("some-unsubscribe-id")))
+		Expect(unsubscribersCollection.SetCall.Receives.Unsubscriber).To(Equal(collections.Unsubscriber{
+			CampaignTypeID: "some-campaign-type-id",
+			UserGUID:       "some-user-guid",
+		}))
+		Expect(unsubscribersCollection.SetCall.Receives.Connection).To(Equal(connection))
+	})
+
+	Context("when an error occurs", func() {
+		Context("when the unsubscribe ID cannot be unveiled", func() {
+			It("returns a 422 with the error message in JSON", func() {
+				cloak.UnveilCall.Returns.Error = errors.New("illegal base64 data")
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(writer.Code).To(Equal(422))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["The unsubscribe ID is invalid"]}`))
+				Expect(unsubscribersCollection.SetCall.Receives.Unsubscriber).To(Equal(collections.Unsubscriber{}))
+			})
+		})
+
+		Context("when the unsubscribe ID does not identify a user and campaign type", func() {
+			It("returns a 422 with the error message in JSON", func() {
+				cloak.UnveilCall.Returns.PlainText = []byte("banana")
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(writer.Code).To(Equal(422))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["The unsubscribe ID is invalid"]}`))
+			})
+		})
+
+		Context("when the unsubscribe ID does not identify a user", func() {
+			It("returns a 422 with the error message in JSON", func() {
+				cloak.UnveilCall.Returns.PlainText = []byte("|some-client-id|some-campaign-type-id")
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(writer.Code).To(Equal(422))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["The unsubscribe ID is invalid"]}`))
+				Expect(unsubscribersCollection.SetCall.Receives.Unsubscriber).To(Equal(collections.Unsubscriber{}))
+			})
+		})
+
+		Context("when the Set call returns a NotFoundError", func() {
+			It("returns a 404 with the error message in JSON", func() {
+				unsubscribersCollection.SetCall.Returns.Error = collections.NotFoundError{errors.New("some-error")}
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(writer.Code).To(Equal(http.StatusNotFound))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["some-error"]}`))
+			})
+		})
+
+		Context("when the Set call returns a PermissionsError", func() {
+			It("returns a 403 with the error message in JSON", func() {
+				unsubscribersCollection.SetCall.Returns.Error = collections.PermissionsError{errors.New("some-error")}
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(writer.Code).To(Equal(http.StatusForbidden))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["some-error"]}`))
+			})
+		})
+
+		Context("when the Set call returns any other error", func() {
+			It("returns a 500 status", func() {
+				unsubscribersCollection.SetCall.Returns.Error = errors.New("some-bad-error")
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["some-bad-error"]}`))
+			})
+		})
+	})
+})
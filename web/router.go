@@ -24,6 +24,10 @@ func NewRouter(mother MotherInterface, config Config) http.Handler {
 		CCHost:            config.CCHost,
 		CORSOrigin:        config.CORSOrigin,
 		SQLDB:             config.SQLDB,
+		Queue:             mother.Queue(),
+		EncryptionKey:     config.EncryptionKey,
+		Sender:            config.Sender,
+		Domain:            config.Domain,
 	})
 
 	v2 := v2web.NewRouter(NewMuxer(), v2web.Config{
@@ -37,6 +41,11 @@ func NewRouter(mother MotherInterface, config Config) http.Handler {
 		UAAClientID:       config.UAAClientID,
 		UAAClientSecret:   config.UAAClientSecret,
 		CCHost:            config.CCHost,
+		EncryptionKey:     config.EncryptionKey,
+		Sender:            config.Sender,
+		Domain:            config.Domain,
+
+		SenderAllowedDomains: config.SenderAllowedDomains,
 	})
 
 	return VersionRouter{
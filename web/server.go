@@ -1,9 +1,12 @@
 package web
 
 import (
+	"context"
 	"database/sql"
+	"net"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/uaa"
 	"github.com/pivotal-golang/lager"
@@ -15,6 +18,7 @@ type Config struct {
 	Port                 int
 	CORSOrigin           string
 	QueueWaitMaxDuration int
+	EncryptionKey        []byte
 	SQLDB                *sql.DB
 	Logger               lager.Logger
 
@@ -24,18 +28,59 @@ type Config struct {
 	UAAClientSecret   string
 	DefaultUAAScopes  []string
 	CCHost            string
+	Sender            string
+	Domain            string
+
+	SenderAllowedDomains []string
 }
 
-type Server struct{}
+type Server struct {
+	server *http.Server
+}
 
-func NewServer() Server {
-	return Server{}
+func NewServer() *Server {
+	return &Server{
+		server: &http.Server{},
+	}
 }
 
-func (s Server) Run(mother MotherInterface, config Config) {
+func (s *Server) Run(mother MotherInterface, config Config) error {
 	config.Logger.Info("listen-and-serve", lager.Data{
 		"port": config.Port,
 	})
 
-	http.ListenAndServe(":"+strconv.Itoa(config.Port), NewRouter(mother, config))
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(config.Port))
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(listener, NewRouter(mother, config))
+}
+
+func (s *Server) Serve(listener net.Listener, handler http.Handler) error {
+	s.server.Handler = handler
+
+	err := s.server.Serve(listener)
+	if err == http.ErrServerClosed {
+		return nil
+	}
+
+	return err
+}
+
+// Shutdown stops the server from accepting new requests, closes idle
+// connections and waits for the requests that are in flight to complete.
+// Connections that are still active when the timeout elapses are closed,
+// and it returns false.
+func (s *Server) Shutdown(timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := s.server.Shutdown(ctx)
+	if err != nil {
+		s.server.Close()
+		return false
+	}
+
+	return true
 }
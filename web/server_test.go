@@ -0,0 +1,126 @@
+package web_test
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/web"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func blockingHandler(started chan struct{}, hold chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		started <- struct{}{}
+		<-hold
+		w.WriteHeader(http.StatusTeapot)
+	})
+}
+
+var _ = Describe("Server", func() {
+	var (
+		server   *web.Server
+		listener net.Listener
+		address  string
+		started  chan struct{}
+		hold     chan struct{}
+		served   chan error
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		listener, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		address = "http://" + listener.Addr().String()
+
+		started = make(chan struct{}, 1)
+		hold = make(chan struct{})
+		handler := blockingHandler(started, hold)
+
+		server = web.NewServer()
+		served = make(chan error, 1)
+		go func(server *web.Server, listener net.Listener, served chan error) {
+			served <- server.Serve(listener, handler)
+		}(server, listener, served)
+	})
+
+	Describe("Shutdown", func() {
+		It("stops accepting new requests", func() {
+			close(hold)
+
+			response, err := http.Get(address)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.StatusCode).To(Equal(http.StatusTeapot))
+			response.Body.Close()
+
+			Expect(server.Shutdown(1 * time.Second)).To(BeTrue())
+			Eventually(served).Should(Receive(BeNil()))
+
+			_, err = net.Dial("tcp", listener.Addr().String())
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("closes idle keep-alive connections", func() {
+			close(hold)
+
+			conn, err := net.Dial("tcp", listener.Addr().String())
+			Expect(err).NotTo(HaveOccurred())
+			defer conn.Close()
+
+			_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\n\r\n"))
+			Expect(err).NotTo(HaveOccurred())
+
+			reader := bufio.NewReader(conn)
+			response, err := http.ReadResponse(reader, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.StatusCode).To(Equal(http.StatusTeapot))
+			response.Body.Close()
+
+			Expect(server.Shutdown(1 * time.Second)).To(BeTrue())
+
+			conn.SetReadDeadline(time.Now().Add(1 * time.Second))
+			_, err = reader.ReadByte()
+			Expect(err).To(Equal(io.EOF))
+		})
+
+		It("waits for in-flight requests to complete", func() {
+			responses := make(chan *http.Response, 1)
+			go func() {
+				defer GinkgoRecover()
+
+				response, err := http.Get(address)
+				Expect(err).NotTo(HaveOccurred())
+				responses <- response
+			}()
+			Eventually(started).Should(Receive())
+
+			shutdown := make(chan bool, 1)
+			go func() {
+				shutdown <- server.Shutdown(10 * time.Second)
+			}()
+			Consistently(shutdown).ShouldNot(Receive())
+
+			close(hold)
+
+			Eventually(shutdown).Should(Receive(BeTrue()))
+
+			var response *http.Response
+			Eventually(responses).Should(Receive(&response))
+			Expect(response.StatusCode).To(Equal(http.StatusTeapot))
+		})
+
+		It("gives up on in-flight requests once the timeout elapses", func() {
+			go http.Get(address)
+			Eventually(started).Should(Receive())
+
+			Expect(server.Shutdown(1 * time.Millisecond)).To(BeFalse())
+
+			close(hold)
+		})
+	})
+})
@@ -9,6 +9,10 @@ type VersionRouter map[int]http.Handler
 
 func (vr VersionRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	versionHeader := req.Header.Get("X-NOTIFICATIONS-VERSION")
+	if versionHeader == "" {
+		versionHeader = req.URL.Query().Get("version")
+	}
+
 	if versionHeader == "" {
 		versionHeader = "1"
 	}
@@ -52,6 +52,27 @@ var _ = Describe("VersionRouter", func() {
 		Expect(v3Called).To(BeFalse())
 	})
 
+	It("uses the version query parameter when the X-NOTIFICATIONS-VERSION header is absent", func() {
+		request, err := http.NewRequest("POST", "/unsubscribe/some-id?version=3", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		router.ServeHTTP(writer, request)
+
+		Expect(v3Called).To(BeTrue())
+		Expect(v1Called).To(BeFalse())
+	})
+
+	It("prefers the X-NOTIFICATIONS-VERSION header over the version query parameter", func() {
+		request, err := http.NewRequest("POST", "/unsubscribe/some-id?version=3", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		request.Header.Set("X-NOTIFICATIONS-VERSION", "1")
+		router.ServeHTTP(writer, request)
+
+		Expect(v1Called).To(BeTrue())
+		Expect(v3Called).To(BeFalse())
+	})
+
 	It("returns a 404 if the version number is not an integer", func() {
 		request.Header.Set("X-NOTIFICATIONS-VERSION", "banana")
 		router.ServeHTTP(writer, request)